@@ -17,7 +17,7 @@
 // @securityDefinitions.apikey ApiKeyAuth
 // @in header
 // @name X-API-Key
-// @description Legacy Admin API Key for backward compatibility
+// @description Client API key issued by a user with the apikey:manage permission. Format: hk_...
 
 // @securityDefinitions.apikey BearerAuth
 // @in header
@@ -35,12 +35,18 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // Embedded zone database for REMINDER_TIMEZONE on minimal images
 
 	_ "github.com/ilramdhan/holidayapi/docs" // Import for swagger docs
 	"github.com/ilramdhan/holidayapi/internal/config"
 	"github.com/ilramdhan/holidayapi/internal/database"
+	"github.com/ilramdhan/holidayapi/internal/events"
 	"github.com/ilramdhan/holidayapi/internal/handlers"
+	"github.com/ilramdhan/holidayapi/internal/metrics"
+	"github.com/ilramdhan/holidayapi/internal/middleware"
+	"github.com/ilramdhan/holidayapi/internal/notify"
 	"github.com/ilramdhan/holidayapi/internal/repository"
+	"github.com/ilramdhan/holidayapi/internal/scheduler"
 	"github.com/ilramdhan/holidayapi/internal/services"
 )
 
@@ -49,7 +55,15 @@ func main() {
 	cfg := config.Load()
 
 	// Initialize database
-	db, err := database.NewConnection(cfg.Database.Path)
+	driver, err := database.ParseDriver(cfg.Database.Driver)
+	if err != nil {
+		log.Fatalf("Invalid database configuration: %v", err)
+	}
+	if err := cfg.Jobs.Validate(); err != nil {
+		log.Fatalf("Invalid job configuration: %v", err)
+	}
+
+	db, err := database.NewConnection(driver, cfg.Database.DSN())
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
@@ -61,18 +75,114 @@ func main() {
 	}
 
 	// Initialize repositories
-	holidayRepo := repository.NewHolidayRepository(db.DB)
-	userRepo := repository.NewUserRepository(db.DB)
-	auditRepo := repository.NewAuditRepository(db.DB)
+	holidayRepo := repository.NewHolidayRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	holidayChangeRepo := repository.NewHolidayChangeRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	reminderRepo := repository.NewReminderRepository(db)
+	distributionListRepo := repository.NewDistributionListRepository(db)
+	mfaRepo := repository.NewMFARepository(db)
+	loginLockoutRepo := repository.NewLoginLockoutRepository(db)
+	passwordResetRepo := repository.NewPasswordResetRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+
+	// In-process event bus for the holiday change stream; a subscriber may fall 64 changes behind
+	changeBus := events.NewBus(64)
 
 	// Initialize services
 	jwtService := services.NewJWTService(cfg.JWT.SecretKey, cfg.JWT.AccessTokenTTL, cfg.JWT.RefreshTokenTTL)
 	auditService := services.NewAuditService(auditRepo)
-	authService := services.NewAuthService(userRepo, auditRepo, jwtService)
-	holidayService := services.NewHolidayService(holidayRepo)
+	loginLockoutService := services.NewLoginLockoutService(loginLockoutRepo, auditRepo, cfg.Lockout)
+	authService := services.NewAuthService(userRepo, auditRepo, refreshTokenRepo, mfaRepo, roleRepo, loginLockoutService, jwtService, cfg.MFA)
+	roleService := services.NewRoleService(roleRepo, auditRepo)
+	mfaService := services.NewMFAService(mfaRepo, userRepo, roleRepo, auditRepo, cfg.MFA)
+	holidayChangeService := services.NewHolidayChangeService(holidayChangeRepo, changeBus)
+	holidayService := services.NewHolidayService(holidayRepo, holidayChangeService)
+	workdayService := services.NewWorkdayService(holidayRepo)
+	longWeekendService := services.NewLongWeekendService(holidayRepo)
+	forecastService := services.NewForecastService(holidayRepo)
+	apiKeyService := services.NewAPIKeyService(apiKeyRepo, userRepo, roleRepo, auditRepo)
+	webhookService := services.NewWebhookService(webhookRepo, auditRepo, cfg.Webhook)
+
+	notifier, err := notify.New(cfg.Notifier)
+	if err != nil {
+		log.Fatalf("Invalid notifier configuration: %v", err)
+	}
+
+	reminderService, err := services.NewReminderService(reminderRepo, distributionListRepo, holidayRepo, auditRepo, notifier, cfg.Reminder)
+	if err != nil {
+		log.Fatalf("Invalid reminder configuration: %v", err)
+	}
+
+	passwordResetService, err := services.NewPasswordResetService(passwordResetRepo, userRepo, refreshTokenRepo, auditRepo, notifier, cfg.PasswordReset)
+	if err != nil {
+		log.Fatalf("Invalid password reset configuration: %v", err)
+	}
+
+	// Background jobs run until shutdown
+	jobs := scheduler.New()
+	jobService := services.NewJobService(jobs, auditRepo)
+	rateLimiter := middleware.NewRateLimiter(cfg.RateLimit.RequestsPerMinute, cfg.RateLimit.BurstSize)
+
+	jobs.Add(scheduler.Job{
+		Name:     "webhook-delivery",
+		Interval: cfg.Webhook.PollInterval,
+		Run: func(ctx context.Context) (string, error) {
+			delivered, err := webhookService.DeliverDue(ctx)
+			return fmt.Sprintf("delivered %d webhooks", delivered), err
+		},
+	})
+	jobs.Add(scheduler.Job{
+		Name:     "holiday-reminders",
+		Interval: cfg.Reminder.CheckInterval,
+		Run: func(ctx context.Context) (string, error) {
+			sent, err := reminderService.SendDue(ctx, time.Now())
+			return fmt.Sprintf("sent %d reminders", sent), err
+		},
+	})
+
+	// Maintenance jobs record each run in the audit log
+	jobService.AddMaintenanceJob(scheduler.Job{
+		Name:     "rate-limit-cleanup",
+		Interval: cfg.Jobs.RateLimitCleanupInterval,
+		Run: func(ctx context.Context) (string, error) {
+			return fmt.Sprintf("forgot %d idle clients", rateLimiter.CleanupStale()), nil
+		},
+	})
+	jobService.AddMaintenanceJob(scheduler.Job{
+		Name:     "audit-retention",
+		Interval: cfg.Jobs.AuditRetentionInterval,
+		Run: func(ctx context.Context) (string, error) {
+			deleted, err := auditService.CleanupOldLogs(cfg.Jobs.AuditRetentionDays)
+			return fmt.Sprintf("deleted %d audit logs older than %d days", deleted, cfg.Jobs.AuditRetentionDays), err
+		},
+	})
+	jobService.AddMaintenanceJob(scheduler.Job{
+		Name:     "token-purge",
+		Interval: cfg.Jobs.TokenPurgeInterval,
+		Run: func(ctx context.Context) (string, error) {
+			deleted, err := authService.PurgeExpiredTokens()
+			if err != nil {
+				return fmt.Sprintf("deleted %d expired refresh tokens", deleted), err
+			}
+			resets, err := passwordResetService.PurgeExpiredTokens()
+			return fmt.Sprintf("deleted %d expired refresh tokens and %d expired password reset tokens", deleted, resets), err
+		},
+	})
+	jobService.AddMaintenanceJob(scheduler.Job{
+		Name:     "login-attempt-purge",
+		Interval: cfg.Jobs.LoginAttemptPurgeInterval,
+		Run: func(ctx context.Context) (string, error) {
+			deleted, err := loginLockoutService.PurgeStale()
+			return fmt.Sprintf("deleted %d stale failed login records", deleted), err
+		},
+	})
 
 	// Setup router
-	router := handlers.SetupRouter(cfg, holidayService, authService, jwtService, auditService)
+	router := handlers.SetupRouter(cfg, holidayService, authService, jwtService, auditService, workdayService, apiKeyService, longWeekendService, forecastService, holidayChangeService, webhookService, reminderService, jobService, mfaService, loginLockoutService, passwordResetService, roleService, rateLimiter)
 
 	// Create HTTP server
 	server := &http.Server{
@@ -83,9 +193,15 @@ func main() {
 		IdleTimeout:  cfg.Server.IdleTimeout,
 	}
 
+	// End open change streams when shutdown begins instead of waiting for them to time out
+	server.RegisterOnShutdown(changeBus.Close)
+
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	jobs.Start(jobsCtx)
+
 	// Start server in a goroutine
 	go func() {
-		log.Printf("Starting server on %s:%s", cfg.Server.Host, cfg.Server.Port)
+		log.Printf("Starting server %s (commit %s) on %s:%s", metrics.Version, metrics.Commit, cfg.Server.Host, cfg.Server.Port)
 		log.Printf("Swagger documentation available at: http://%s:%s/swagger/index.html", cfg.Server.Host, cfg.Server.Port)
 
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -108,5 +224,9 @@ func main() {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
+	// Pending webhook deliveries stay in the outbox and are retried on the next start
+	stopJobs()
+	jobs.Wait()
+
 	log.Println("Server exited")
 }
@@ -0,0 +1,83 @@
+// Package main provides a command that verifies the audit log hash chain.
+// It reads the same environment variables as the server and exits with status 1
+// when the chain is broken, so it can run from cron or a CI job.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/ilramdhan/holidayapi/internal/config"
+	"github.com/ilramdhan/holidayapi/internal/database"
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/repository"
+	"github.com/ilramdhan/holidayapi/internal/services"
+)
+
+func main() {
+	asJSON := flag.Bool("json", false, "print the verification result as JSON")
+	flag.Parse()
+
+	cfg := config.Load()
+
+	driver, err := database.ParseDriver(cfg.Database.Driver)
+	if err != nil {
+		log.Fatalf("Invalid database configuration: %v", err)
+	}
+
+	db, err := database.NewConnection(driver, cfg.Database.DSN())
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	auditService := services.NewAuditService(repository.NewAuditRepository(db))
+
+	result, err := auditService.VerifyChain()
+	if err != nil {
+		log.Fatalf("Failed to verify audit chain: %v", err)
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(result); err != nil {
+			log.Fatalf("Failed to encode result: %v", err)
+		}
+	} else {
+		printResult(result)
+	}
+
+	if !result.Valid {
+		db.Close()
+		os.Exit(1)
+	}
+}
+
+// printResult writes a human-readable summary of a verification
+func printResult(result *models.AuditChainVerification) {
+	fmt.Printf("Unchained entries (before the hash chain): %d\n", result.Unchained)
+	fmt.Printf("Chained entries verified: %d\n", result.Checked)
+	if result.Checkpoint != nil {
+		fmt.Printf("Retention checkpoint: entries up to %d removed\n", result.Checkpoint.LastDeletedID)
+	}
+	if result.FirstID != nil {
+		fmt.Printf("Chain starts at entry: %d\n", *result.FirstID)
+	}
+	if result.LastHash != "" {
+		fmt.Printf("Last verified hash: %s\n", result.LastHash)
+	}
+
+	if result.Valid {
+		fmt.Println("Audit chain is intact")
+		return
+	}
+	if result.BrokenAtID == nil {
+		fmt.Printf("Audit chain is broken: %s\n", result.Reason)
+		return
+	}
+	fmt.Printf("Audit chain is broken at entry %d: %s\n", *result.BrokenAtID, result.Reason)
+}
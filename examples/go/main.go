@@ -39,13 +39,15 @@ func main() {
 		}
 	}
 
-	// Example 3: Get today's holiday
-	fmt.Println("\n=== Today's Holiday ===")
-	today, err := c.GetTodayHoliday(ctx)
+	// Example 3: Get today's holidays
+	fmt.Println("\n=== Today's Holidays ===")
+	today, err := c.GetTodayHolidays(ctx)
 	if err != nil {
-		log.Printf("Error getting today's holiday: %v", err)
-	} else if today != nil {
-		fmt.Printf("Today is a holiday: %s\n", today.Name)
+		log.Printf("Error getting today's holidays: %v", err)
+	} else if len(today) > 0 {
+		for _, h := range today {
+			fmt.Printf("Today is a holiday: %s\n", h.Name)
+		}
 	} else {
 		fmt.Println("Today is not a holiday")
 	}
@@ -0,0 +1,299 @@
+// Package calendar projects Indonesian public holidays for any year from the
+// rules of the calendars they are based on. The results are estimates: official
+// dates are set each year by joint ministerial decree (SKB) and may differ by a
+// day or more, especially for holidays that depend on moon sighting.
+package calendar
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// System identifies the calendar a holiday date is derived from
+type System string
+
+const (
+	// Gregorian holidays fall on the same date every year
+	Gregorian System = "gregorian"
+	// Hijri holidays follow the Islamic lunar calendar
+	Hijri System = "hijri"
+	// Chinese holidays follow the Chinese lunisolar calendar
+	Chinese System = "chinese"
+	// Saka holidays follow the Balinese Saka calendar
+	Saka System = "saka"
+	// Buddhist holidays follow the full moon of the Vesak month
+	Buddhist System = "buddhist"
+	// Easter holidays are derived from the date of Easter (computus)
+	Easter System = "easter"
+)
+
+// Holiday is a computed holiday date
+type Holiday struct {
+	Name        string
+	Date        time.Time
+	Description string
+	System      System
+}
+
+var (
+	// wib is Western Indonesian Time (UTC+7), used for Hijri month and Vesak sighting
+	wib = time.FixedZone("WIB", 7*60*60)
+	// chinaTime is China Standard Time (UTC+8), which defines the Chinese calendar day
+	chinaTime = time.FixedZone("CST", 8*60*60)
+)
+
+// minCrescentAge is the time past conjunction after which the new crescent is
+// taken to be visible at sunset
+const minCrescentAge = 7*time.Hour + 30*time.Minute
+
+// fixedHoliday is a holiday on the same Gregorian date every year
+type fixedHoliday struct {
+	month       time.Month
+	day         int
+	name        string
+	description func(year int) string
+}
+
+var fixedHolidays = []fixedHoliday{
+	{time.January, 1, "Tahun Baru Masehi", func(year int) string {
+		return fmt.Sprintf("Hari libur nasional Tahun Baru Masehi %d", year)
+	}},
+	{time.May, 1, "Hari Buruh Internasional", func(int) string {
+		return "Hari libur nasional Hari Buruh Internasional"
+	}},
+	{time.June, 1, "Hari Lahir Pancasila", func(int) string {
+		return "Hari libur nasional Hari Lahir Pancasila"
+	}},
+	{time.August, 17, "Hari Kemerdekaan Republik Indonesia", func(year int) string {
+		return fmt.Sprintf("Hari libur nasional HUT ke-%d Kemerdekaan Republik Indonesia", year-1945)
+	}},
+	{time.December, 25, "Hari Raya Natal", func(int) string {
+		return "Hari libur nasional Hari Raya Natal"
+	}},
+}
+
+// hijriHoliday is a holiday on a fixed day of a Hijri month
+type hijriHoliday struct {
+	month int
+	day   int
+	name  string
+	label string
+}
+
+var hijriHolidays = []hijriHoliday{
+	{1, 1, "Tahun Baru Islam", "Tahun Baru Islam %d Hijriah"},
+	{3, 12, "Maulid Nabi Muhammad SAW", "Maulid Nabi Muhammad SAW %d Hijriah"},
+	{7, 27, "Isra Mikraj Nabi Muhammad SAW", "Isra Mikraj Nabi Muhammad SAW %d Hijriah"},
+	{10, 1, "Hari Raya Idul Fitri", "Hari Raya Idul Fitri %d Hijriah"},
+	{10, 2, "Hari Raya Idul Fitri (Hari Kedua)", "Hari Raya Idul Fitri %d Hijriah hari kedua"},
+	{12, 10, "Hari Raya Idul Adha", "Hari Raya Idul Adha %d Hijriah"},
+}
+
+// Holidays returns the projected national holidays of a Gregorian year, sorted by date
+func Holidays(year int) []Holiday {
+	var holidays []Holiday
+
+	for _, h := range fixedHolidays {
+		holidays = append(holidays, Holiday{
+			Name:        h.name,
+			Date:        date(year, h.month, h.day),
+			Description: h.description(year),
+			System:      Gregorian,
+		})
+	}
+
+	for _, h := range hijriHolidays {
+		// A Hijri year is about 11 days shorter than a Gregorian one, so the same
+		// Hijri date can occur twice in one Gregorian year
+		for hijriYear := approxHijriYear(year) - 1; hijriYear <= approxHijriYear(year)+1; hijriYear++ {
+			d := HijriDate(hijriYear, h.month, h.day)
+			if d.Year() != year {
+				continue
+			}
+			holidays = append(holidays, Holiday{
+				Name:        h.name,
+				Date:        d,
+				Description: "Hari libur nasional " + fmt.Sprintf(h.label, hijriYear),
+				System:      Hijri,
+			})
+		}
+	}
+
+	holidays = append(holidays,
+		Holiday{
+			Name:        "Tahun Baru Imlek",
+			Date:        ChineseNewYear(year),
+			Description: fmt.Sprintf("Hari libur nasional Tahun Baru Imlek %d Kongzili", year+551),
+			System:      Chinese,
+		},
+		Holiday{
+			Name:        "Hari Raya Nyepi (Tahun Baru Saka)",
+			Date:        Nyepi(year),
+			Description: fmt.Sprintf("Hari libur nasional Hari Suci Nyepi Tahun Baru Saka %d", year-78),
+			System:      Saka,
+		},
+		Holiday{
+			Name:        "Hari Raya Waisak",
+			Date:        Waisak(year),
+			Description: fmt.Sprintf("Hari libur nasional Hari Raya Waisak %d BE", year+544),
+			System:      Buddhist,
+		},
+	)
+
+	easter := EasterSunday(year)
+	holidays = append(holidays,
+		Holiday{
+			Name:        "Wafat Isa Almasih",
+			Date:        easter.AddDate(0, 0, -2),
+			Description: "Hari libur nasional Wafat Isa Almasih",
+			System:      Easter,
+		},
+		Holiday{
+			Name:        "Kebangkitan Yesus Kristus (Paskah)",
+			Date:        easter,
+			Description: "Hari libur nasional Kebangkitan Yesus Kristus (Paskah)",
+			System:      Easter,
+		},
+		Holiday{
+			Name:        "Kenaikan Isa Almasih",
+			Date:        easter.AddDate(0, 0, 39),
+			Description: "Hari libur nasional Kenaikan Isa Almasih",
+			System:      Easter,
+		},
+	)
+
+	sort.SliceStable(holidays, func(i, j int) bool {
+		return holidays[i].Date.Before(holidays[j].Date)
+	})
+
+	return holidays
+}
+
+// EasterSunday returns the date of Western Easter using the anonymous Gregorian computus
+func EasterSunday(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+
+	return date(year, time.Month(month), day)
+}
+
+// HijriDate returns the Gregorian date of a day in the Hijri calendar.
+//
+// Months start on the day after the evening the new crescent is considered
+// visible from Indonesia. Visibility is approximated by the age of the moon at
+// sunset in Jakarta (see minCrescentAge), which tracks the MABIMS criteria used
+// for the SKB but can be a day off near the threshold.
+func HijriDate(hijriYear, month, day int) time.Time {
+	approx := tabularHijriDate(hijriYear, month, 1)
+
+	// The tabular calendar is within a couple of days of the observed month
+	// start, so the nearest lunation is the one that opens the month
+	k := lunationNear(approx)
+	start := hijriMonthStart(newMoon(k))
+	for _, candidate := range []int{k - 1, k + 1} {
+		s := hijriMonthStart(newMoon(candidate))
+		if absDays(s.Sub(approx)) < absDays(start.Sub(approx)) {
+			start = s
+		}
+	}
+
+	return start.AddDate(0, 0, day-1)
+}
+
+// hijriMonthStart returns the first day of the Hijri month opened by a conjunction
+func hijriMonthStart(conjunction time.Time) time.Time {
+	local := conjunction.In(wib)
+	sunset := time.Date(local.Year(), local.Month(), local.Day(), 18, 0, 0, 0, wib)
+	day := date(local.Year(), local.Month(), local.Day())
+
+	if sunset.Sub(conjunction) >= minCrescentAge {
+		return day.AddDate(0, 0, 1)
+	}
+	return day.AddDate(0, 0, 2)
+}
+
+// tabularHijriDate converts a date of the arithmetic (tabular) Islamic calendar
+func tabularHijriDate(hijriYear, month, day int) time.Time {
+	jdn := day + (59*(month-1)+1)/2 + (hijriYear-1)*354 + (3+11*hijriYear)/30 + 1948439
+	return julianDayToTime(float64(jdn) + 0.5)
+}
+
+// approxHijriYear returns the Hijri year that begins in the given Gregorian year, give or take one
+func approxHijriYear(year int) int {
+	return int(float64(year-622) * 33 / 32)
+}
+
+// ChineseNewYear returns the first day of the Chinese year that starts in the given Gregorian year.
+// It is the day of the new moon, in China Standard Time, that falls between January 21 and February 20.
+func ChineseNewYear(year int) time.Time {
+	earliest := date(year, time.January, 21)
+	k := lunationNear(earliest)
+	for {
+		d := dateIn(newMoon(k), chinaTime)
+		if !d.Before(earliest) {
+			return d
+		}
+		k++
+	}
+}
+
+// Nyepi returns the Balinese Day of Silence, the day after Tilem Kesanga (the
+// new moon of the ninth Saka month). The Saka calendar has its own intercalation
+// rules, so this uses the day after the first new moon in March and can be a day off.
+func Nyepi(year int) time.Time {
+	earliest := date(year, time.March, 1)
+	k := lunationNear(earliest)
+	for {
+		d := dateIn(newMoon(k), time.UTC)
+		if !d.Before(earliest) {
+			return d.AddDate(0, 0, 1)
+		}
+		k++
+	}
+}
+
+// Waisak returns the Vesak day observed in Indonesia, the first full moon on or
+// after May 7 in Western Indonesian Time
+func Waisak(year int) time.Time {
+	earliest := date(year, time.May, 7)
+	k := lunationNear(earliest) - 1
+	for {
+		d := dateIn(fullMoon(k), wib)
+		if !d.Before(earliest) {
+			return d
+		}
+		k++
+	}
+}
+
+// date returns midnight UTC of a calendar date, matching how holiday dates are stored
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// dateIn returns the calendar date of an instant in the given zone
+func dateIn(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	return date(local.Year(), local.Month(), local.Day())
+}
+
+// absDays returns the absolute length of a duration in days
+func absDays(d time.Duration) float64 {
+	if d < 0 {
+		d = -d
+	}
+	return d.Hours() / 24
+}
@@ -0,0 +1,98 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMoon(t *testing.T) {
+	// Meeus, Astronomical Algorithms example 49.a: new moon of 1977 February 18, 03:37:42 TT
+	k := lunationNear(time.Date(1977, 2, 15, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, -283, k)
+
+	expected := time.Date(1977, 2, 18, 3, 37, 42, 0, time.UTC).Add(-69 * time.Second)
+	assert.WithinDuration(t, expected, newMoon(k), time.Minute)
+}
+
+func TestEasterSunday(t *testing.T) {
+	tests := map[int]string{
+		2024: "2024-03-31",
+		2025: "2025-04-20",
+		2026: "2026-04-05",
+		2038: "2038-04-25",
+	}
+
+	for year, expected := range tests {
+		assert.Equal(t, expected, EasterSunday(year).Format("2006-01-02"), "year %d", year)
+	}
+}
+
+func TestMovableHolidays(t *testing.T) {
+	// Dates from the SKB decrees for 2023-2026
+	tests := []struct {
+		name     string
+		date     time.Time
+		expected string
+	}{
+		{"Isra Mikraj 1445", HijriDate(1445, 7, 27), "2024-02-08"},
+		{"Isra Mikraj 1446", HijriDate(1446, 7, 27), "2025-01-27"},
+		{"Idul Fitri 1444", HijriDate(1444, 10, 1), "2023-04-22"},
+		{"Idul Fitri 1445", HijriDate(1445, 10, 1), "2024-04-10"},
+		{"Idul Fitri 1446", HijriDate(1446, 10, 1), "2025-03-31"},
+		{"Idul Fitri 1447", HijriDate(1447, 10, 1), "2026-03-20"},
+		{"Idul Adha 1445", HijriDate(1445, 12, 10), "2024-06-17"},
+		{"Idul Adha 1446", HijriDate(1446, 12, 10), "2025-06-06"},
+		{"Tahun Baru Islam 1446", HijriDate(1446, 1, 1), "2024-07-07"},
+		{"Imlek 2023", ChineseNewYear(2023), "2023-01-22"},
+		{"Imlek 2024", ChineseNewYear(2024), "2024-02-10"},
+		{"Imlek 2025", ChineseNewYear(2025), "2025-01-29"},
+		{"Imlek 2026", ChineseNewYear(2026), "2026-02-17"},
+		{"Nyepi 2023", Nyepi(2023), "2023-03-22"},
+		{"Nyepi 2024", Nyepi(2024), "2024-03-11"},
+		{"Waisak 2023", Waisak(2023), "2023-06-04"},
+		{"Waisak 2024", Waisak(2024), "2024-05-23"},
+		{"Waisak 2025", Waisak(2025), "2025-05-12"},
+		{"Waisak 2026", Waisak(2026), "2026-05-31"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.date.Format("2006-01-02"))
+		})
+	}
+}
+
+func TestHolidays(t *testing.T) {
+	holidays := Holidays(2024)
+	require.Len(t, holidays, 17)
+
+	for i := 1; i < len(holidays); i++ {
+		assert.False(t, holidays[i].Date.Before(holidays[i-1].Date), "holidays must be sorted by date")
+	}
+
+	byName := map[string]Holiday{}
+	for _, h := range holidays {
+		byName[h.Name] = h
+	}
+
+	assert.Equal(t, "2024-03-29", byName["Wafat Isa Almasih"].Date.Format("2006-01-02"))
+	assert.Equal(t, "2024-05-09", byName["Kenaikan Isa Almasih"].Date.Format("2006-01-02"))
+	assert.Equal(t, "2024-04-11", byName["Hari Raya Idul Fitri (Hari Kedua)"].Date.Format("2006-01-02"))
+	assert.Equal(t, Hijri, byName["Hari Raya Idul Adha"].System)
+	assert.Equal(t, "Hari libur nasional HUT ke-79 Kemerdekaan Republik Indonesia", byName["Hari Kemerdekaan Republik Indonesia"].Description)
+	assert.Equal(t, "Hari libur nasional Tahun Baru Imlek 2575 Kongzili", byName["Tahun Baru Imlek"].Description)
+}
+
+func TestHolidays_HijriDateTwiceInYear(t *testing.T) {
+	// Idul Fitri falls both in January and December of 2033
+	count := 0
+	for _, h := range Holidays(2033) {
+		if h.Name == "Hari Raya Idul Fitri" {
+			count++
+		}
+	}
+	assert.Equal(t, 2, count)
+}
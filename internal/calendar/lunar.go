@@ -0,0 +1,125 @@
+package calendar
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// synodicMonth is the mean length of a lunation in days
+	synodicMonth = 29.530588861
+	// lunationEpoch is the Julian Ephemeris Day of the first new moon of 2000 (lunation 0)
+	lunationEpoch = 2451550.09766
+	// unixEpochJD is the Julian Day of 1970-01-01T00:00:00Z
+	unixEpochJD = 2440587.5
+	// deltaT approximates TT - UT in days for the current era (about 69 seconds)
+	deltaT = 69.0 / 86400
+)
+
+// moonPhase selects the lunar phase computed by phaseTime
+type moonPhase float64
+
+const (
+	newMoonPhase  moonPhase = 0
+	fullMoonPhase moonPhase = 0.5
+)
+
+// newMoonTerms and fullMoonTerms are the periodic corrections of Meeus, Astronomical
+// Algorithms ch. 49: coefficient, power of E, and multiples of M, M', F and Ω
+var newMoonTerms = [][6]float64{
+	{-0.40720, 0, 0, 1, 0, 0}, {0.17241, 1, 1, 0, 0, 0}, {0.01608, 0, 0, 2, 0, 0},
+	{0.01039, 0, 0, 0, 2, 0}, {0.00739, 1, -1, 1, 0, 0}, {-0.00514, 1, 1, 1, 0, 0},
+	{0.00208, 2, 2, 0, 0, 0}, {-0.00111, 0, 0, 1, -2, 0}, {-0.00057, 0, 0, 1, 2, 0},
+	{0.00056, 1, 1, 2, 0, 0}, {-0.00042, 0, 0, 3, 0, 0}, {0.00042, 1, 1, 0, 2, 0},
+	{0.00038, 1, 1, 0, -2, 0}, {-0.00024, 1, -1, 2, 0, 0}, {-0.00017, 0, 0, 0, 0, 1},
+	{-0.00007, 0, 2, 1, 0, 0}, {0.00004, 0, 0, 2, -2, 0}, {0.00004, 0, 3, 0, 0, 0},
+	{0.00003, 0, 1, 1, -2, 0}, {0.00003, 0, 0, 2, 2, 0}, {-0.00003, 0, 1, 1, 2, 0},
+	{0.00003, 0, -1, 1, 2, 0}, {-0.00002, 0, -1, 1, -2, 0}, {-0.00002, 0, 1, 3, 0, 0},
+	{0.00002, 0, 0, 4, 0, 0},
+}
+
+var fullMoonTerms = [][6]float64{
+	{-0.40614, 0, 0, 1, 0, 0}, {0.17302, 1, 1, 0, 0, 0}, {0.01614, 0, 0, 2, 0, 0},
+	{0.01043, 0, 0, 0, 2, 0}, {0.00734, 1, -1, 1, 0, 0}, {-0.00515, 1, 1, 1, 0, 0},
+	{0.00209, 2, 2, 0, 0, 0}, {-0.00111, 0, 0, 1, -2, 0}, {-0.00057, 0, 0, 1, 2, 0},
+	{0.00056, 1, 1, 2, 0, 0}, {-0.00042, 0, 0, 3, 0, 0}, {0.00042, 1, 1, 0, 2, 0},
+	{0.00038, 1, 1, 0, -2, 0}, {-0.00024, 1, -1, 2, 0, 0}, {-0.00017, 0, 0, 0, 0, 1},
+	{-0.00007, 0, 2, 1, 0, 0}, {0.00004, 0, 0, 2, -2, 0}, {0.00004, 0, 3, 0, 0, 0},
+	{0.00003, 0, 1, 1, -2, 0}, {0.00003, 0, 0, 2, 2, 0}, {-0.00003, 0, 1, 1, 2, 0},
+	{0.00003, 0, -1, 1, 2, 0}, {-0.00002, 0, -1, 1, -2, 0}, {-0.00002, 0, 1, 3, 0, 0},
+	{0.00002, 0, 0, 4, 0, 0},
+}
+
+// planetaryTerms are the additional corrections shared by all phases: coefficient,
+// and the constant, k and T² parts of the argument in degrees
+var planetaryTerms = [][4]float64{
+	{0.000325, 299.77, 0.107408, -0.009173}, {0.000165, 251.88, 0.016321, 0},
+	{0.000164, 251.83, 26.651886, 0}, {0.000126, 349.42, 36.412478, 0},
+	{0.000110, 84.66, 18.206239, 0}, {0.000062, 141.74, 53.303771, 0},
+	{0.000060, 207.14, 2.453732, 0}, {0.000056, 154.84, 7.306860, 0},
+	{0.000047, 34.52, 27.261239, 0}, {0.000042, 207.19, 0.121824, 0},
+	{0.000040, 291.34, 1.844379, 0}, {0.000037, 161.72, 24.198154, 0},
+	{0.000035, 239.56, 25.513099, 0}, {0.000023, 331.55, 3.592518, 0},
+}
+
+// phaseTime returns the instant of the given phase in lunation k (0 is January 2000),
+// accurate to within a few minutes for several centuries around 2000
+func phaseTime(k int, phase moonPhase) time.Time {
+	kf := float64(k) + float64(phase)
+	t := kf / 1236.85
+	t2, t3, t4 := t*t, t*t*t, t*t*t*t
+
+	jde := lunationEpoch + synodicMonth*kf + 0.00015437*t2 - 0.000000150*t3 + 0.00000000073*t4
+
+	e := 1 - 0.002516*t - 0.0000074*t2
+	m := radians(2.5534 + 29.10535670*kf - 0.0000014*t2 - 0.00000011*t3)
+	mp := radians(201.5643 + 385.81693528*kf + 0.0107582*t2 + 0.00001238*t3 - 0.000000058*t4)
+	f := radians(160.7108 + 390.67050284*kf - 0.0016118*t2 - 0.00000227*t3 + 0.000000011*t4)
+	omega := radians(124.7746 - 1.56375588*kf + 0.0020672*t2 + 0.00000215*t3)
+
+	terms := newMoonTerms
+	if phase == fullMoonPhase {
+		terms = fullMoonTerms
+	}
+	for _, term := range terms {
+		arg := term[2]*m + term[3]*mp + term[4]*f + term[5]*omega
+		jde += term[0] * math.Pow(e, term[1]) * math.Sin(arg)
+	}
+
+	for _, term := range planetaryTerms {
+		jde += term[0] * math.Sin(radians(term[1]+term[2]*kf+term[3]*t2))
+	}
+
+	return julianDayToTime(jde - deltaT)
+}
+
+// lunationNear returns the number of the lunation whose new moon is closest to t
+func lunationNear(t time.Time) int {
+	return int(math.Round((timeToJulianDay(t) - lunationEpoch) / synodicMonth))
+}
+
+// newMoon returns the instant of the new moon of lunation k
+func newMoon(k int) time.Time {
+	return phaseTime(k, newMoonPhase)
+}
+
+// fullMoon returns the instant of the full moon following the new moon of lunation k
+func fullMoon(k int) time.Time {
+	return phaseTime(k, fullMoonPhase)
+}
+
+// radians converts degrees to radians, reducing the angle first for precision
+func radians(degrees float64) float64 {
+	return math.Mod(degrees, 360) * math.Pi / 180
+}
+
+// julianDayToTime converts a Julian Day to a UTC time
+func julianDayToTime(jd float64) time.Time {
+	seconds := (jd - unixEpochJD) * 86400
+	return time.Unix(0, int64(seconds*1e9)).UTC()
+}
+
+// timeToJulianDay converts a time to a Julian Day
+func timeToJulianDay(t time.Time) float64 {
+	return float64(t.UnixNano())/1e9/86400 + unixEpochJD
+}
@@ -1,18 +1,27 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // Config holds application configuration
 type Config struct {
-	Server    ServerConfig
-	Database  DatabaseConfig
-	RateLimit RateLimitConfig
-	Admin     AdminConfig
-	JWT       JWTConfig
+	Server        ServerConfig
+	Database      DatabaseConfig
+	RateLimit     RateLimitConfig
+	JWT           JWTConfig
+	Webhook       WebhookConfig
+	Notifier      NotifierConfig
+	Reminder      ReminderConfig
+	Jobs          JobsConfig
+	MFA           MFAConfig
+	Lockout       LockoutConfig
+	PasswordReset PasswordResetConfig
 }
 
 // ServerConfig holds server configuration
@@ -26,7 +35,9 @@ type ServerConfig struct {
 
 // DatabaseConfig holds database configuration
 type DatabaseConfig struct {
-	Path           string
+	Driver         string // sqlite or postgres
+	Path           string // SQLite database file
+	URL            string // PostgreSQL connection URL
 	MigrationsPath string
 }
 
@@ -36,11 +47,6 @@ type RateLimitConfig struct {
 	BurstSize         int
 }
 
-// AdminConfig holds admin configuration
-type AdminConfig struct {
-	APIKey string
-}
-
 // JWTConfig holds JWT configuration
 type JWTConfig struct {
 	SecretKey       string
@@ -48,8 +54,84 @@ type JWTConfig struct {
 	RefreshTokenTTL time.Duration
 }
 
+// WebhookConfig holds outgoing webhook delivery configuration
+type WebhookConfig struct {
+	PollInterval   time.Duration // How often the outbox is checked for due deliveries
+	Timeout        time.Duration // Per-request timeout
+	MaxAttempts    int           // Attempts before a delivery is marked failed
+	RetryBaseDelay time.Duration // Delay before the first retry, doubled for each later one
+}
+
+// NotifierConfig holds outgoing email configuration
+type NotifierConfig struct {
+	Driver string // log, file or smtp
+	File   string // File the file driver appends emails to
+	SMTP   SMTPConfig
+}
+
+// SMTPConfig holds SMTP server configuration
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string // Authentication is skipped when empty
+	Password string
+	From     string
+	Timeout  time.Duration
+}
+
+// ReminderConfig holds holiday reminder scheduling configuration
+type ReminderConfig struct {
+	CheckInterval time.Duration // How often reminder rules are checked
+	SendTime      string        // Time of day (HH:MM) from which the day's reminders are sent
+	Timezone      string        // IANA time zone of SendTime and of "today"
+}
+
+// JobsConfig holds maintenance job configuration. A job whose interval is zero is disabled,
+// except rate limit cleanup, which is the only thing that forgets idle clients (see Validate).
+type JobsConfig struct {
+	AuditRetentionDays        int           // Audit logs older than this many days are deleted; must be positive
+	AuditRetentionInterval    time.Duration // How often old audit logs are deleted
+	TokenPurgeInterval        time.Duration // How often expired refresh tokens are deleted
+	RateLimitCleanupInterval  time.Duration // How often idle rate limit buckets are forgotten
+	LoginAttemptPurgeInterval time.Duration // How often old failed login records are deleted
+}
+
+// MFAConfig holds two-factor authentication configuration
+type MFAConfig struct {
+	Issuer        string        // Account issuer shown by authenticator apps
+	RequiredRoles []string      // Roles that must use MFA; users of these roles enroll on their next login
+	ChallengeTTL  time.Duration // How long the MFA token returned by the first login step stays valid
+}
+
+// RequiredFor reports whether users of a role must use MFA
+func (c MFAConfig) RequiredFor(role string) bool {
+	for _, required := range c.RequiredRoles {
+		if required == role {
+			return true
+		}
+	}
+	return false
+}
+
+// LockoutConfig holds failed login lockout configuration. A limit of zero disables that lockout.
+type LockoutConfig struct {
+	MaxAttempts   int           // Failed logins of one username before it is locked
+	IPMaxAttempts int           // Failed logins from one IP address before it is locked
+	BaseDuration  time.Duration // Length of the first lock, doubled for each later one
+	MaxDuration   time.Duration // Longest a lock can get
+	ResetAfter    time.Duration // Failed logins and locks older than this are forgotten
+}
+
+// PasswordResetConfig holds self-service password reset configuration
+type PasswordResetConfig struct {
+	URL      string        // Page of the client app that sets the new password; the token is added as ?token=
+	TokenTTL time.Duration // How long a reset link stays valid
+}
+
 // Load loads configuration from environment variables
 func Load() *Config {
+	databaseDriver := getEnv("DATABASE_DRIVER", "sqlite")
+
 	return &Config{
 		Server: ServerConfig{
 			Host:         getEnv("SERVER_HOST", "localhost"),
@@ -59,24 +141,88 @@ func Load() *Config {
 			IdleTimeout:  getDurationEnv("SERVER_IDLE_TIMEOUT", 60*time.Second),
 		},
 		Database: DatabaseConfig{
+			Driver:         databaseDriver,
 			Path:           getEnv("DATABASE_PATH", "./data/holidays.db"),
-			MigrationsPath: getEnv("MIGRATIONS_PATH", "./migrations"),
+			URL:            getEnv("DATABASE_URL", ""),
+			MigrationsPath: getEnv("MIGRATIONS_PATH", filepath.Join("./migrations", databaseDriver)),
 		},
 		RateLimit: RateLimitConfig{
 			RequestsPerMinute: getIntEnv("RATE_LIMIT_RPM", 60),
 			BurstSize:         getIntEnv("RATE_LIMIT_BURST", 10),
 		},
-		Admin: AdminConfig{
-			APIKey: getEnv("ADMIN_API_KEY", "admin-secret-key-change-in-production"),
-		},
 		JWT: JWTConfig{
 			SecretKey:       getEnv("JWT_SECRET_KEY", "your-super-secret-jwt-key-change-in-production"),
 			AccessTokenTTL:  getDurationEnv("JWT_ACCESS_TOKEN_TTL", 15*time.Minute),
 			RefreshTokenTTL: getDurationEnv("JWT_REFRESH_TOKEN_TTL", 7*24*time.Hour), // 7 days
 		},
+		Webhook: WebhookConfig{
+			PollInterval:   getDurationEnv("WEBHOOK_POLL_INTERVAL", 5*time.Second),
+			Timeout:        getDurationEnv("WEBHOOK_TIMEOUT", 10*time.Second),
+			MaxAttempts:    getIntEnv("WEBHOOK_MAX_ATTEMPTS", 8),
+			RetryBaseDelay: getDurationEnv("WEBHOOK_RETRY_BASE_DELAY", 30*time.Second),
+		},
+		Notifier: NotifierConfig{
+			Driver: getEnv("NOTIFIER_DRIVER", "log"),
+			File:   getEnv("NOTIFIER_FILE", "./data/emails.log"),
+			SMTP: SMTPConfig{
+				Host:     getEnv("SMTP_HOST", "localhost"),
+				Port:     getEnv("SMTP_PORT", "587"),
+				Username: getEnv("SMTP_USERNAME", ""),
+				Password: getEnv("SMTP_PASSWORD", ""),
+				From:     getEnv("SMTP_FROM", "Holiday API <noreply@localhost>"),
+				Timeout:  getDurationEnv("SMTP_TIMEOUT", 10*time.Second),
+			},
+		},
+		Reminder: ReminderConfig{
+			CheckInterval: getDurationEnv("REMINDER_CHECK_INTERVAL", 5*time.Minute),
+			SendTime:      getEnv("REMINDER_SEND_TIME", "08:00"),
+			Timezone:      getEnv("REMINDER_TIMEZONE", "Asia/Jakarta"),
+		},
+		Jobs: JobsConfig{
+			AuditRetentionDays:        getIntEnv("AUDIT_RETENTION_DAYS", 90),
+			AuditRetentionInterval:    getDurationEnv("AUDIT_RETENTION_INTERVAL", 24*time.Hour),
+			TokenPurgeInterval:        getDurationEnv("TOKEN_PURGE_INTERVAL", time.Hour),
+			RateLimitCleanupInterval:  getDurationEnv("RATE_LIMIT_CLEANUP_INTERVAL", 5*time.Minute),
+			LoginAttemptPurgeInterval: getDurationEnv("LOGIN_ATTEMPT_PURGE_INTERVAL", time.Hour),
+		},
+		MFA: MFAConfig{
+			Issuer:        getEnv("MFA_ISSUER", "Holiday API"),
+			RequiredRoles: getListEnv("MFA_REQUIRED_ROLES"),
+			ChallengeTTL:  getDurationEnv("MFA_CHALLENGE_TTL", 5*time.Minute),
+		},
+		Lockout: LockoutConfig{
+			MaxAttempts:   getIntEnv("LOGIN_MAX_ATTEMPTS", 5),
+			IPMaxAttempts: getIntEnv("LOGIN_IP_MAX_ATTEMPTS", 20),
+			BaseDuration:  getDurationEnv("LOGIN_LOCKOUT_DURATION", time.Minute),
+			MaxDuration:   getDurationEnv("LOGIN_LOCKOUT_MAX_DURATION", time.Hour),
+			ResetAfter:    getDurationEnv("LOGIN_ATTEMPT_RESET_AFTER", 24*time.Hour),
+		},
+		PasswordReset: PasswordResetConfig{
+			URL:      getEnv("PASSWORD_RESET_URL", "http://localhost:8080/reset-password"),
+			TokenTTL: getDurationEnv("PASSWORD_RESET_TOKEN_TTL", time.Hour),
+		},
 	}
 }
 
+// Validate reports settings the maintenance jobs cannot run with
+func (c JobsConfig) Validate() error {
+	if c.AuditRetentionDays <= 0 {
+		return fmt.Errorf("AUDIT_RETENTION_DAYS must be positive, got %d", c.AuditRetentionDays)
+	}
+	if c.RateLimitCleanupInterval <= 0 {
+		return fmt.Errorf("RATE_LIMIT_CLEANUP_INTERVAL must be positive, got %s", c.RateLimitCleanupInterval)
+	}
+	return nil
+}
+
+// DSN returns the connection string for the configured driver
+func (c DatabaseConfig) DSN() string {
+	if c.Driver == "postgres" {
+		return c.URL
+	}
+	return c.Path
+}
+
 // getEnv gets environment variable with default value
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -95,6 +241,17 @@ func getIntEnv(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getListEnv gets a comma-separated environment variable, skipping empty items
+func getListEnv(key string) []string {
+	var items []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // getDurationEnv gets duration environment variable with default value
 func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
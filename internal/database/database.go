@@ -8,26 +8,42 @@ import (
 	"path/filepath"
 
 	"github.com/golang-migrate/migrate/v4"
+	migratedb "github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/pgx/v5"
 	"github.com/golang-migrate/migrate/v4/database/sqlite3"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	_ "github.com/jackc/pgx/v5/stdlib"
 	_ "modernc.org/sqlite"
 )
 
-// DB holds the database connection
+// DB holds the database connection and the dialect of its driver.
+// Exec, Query and QueryRow accept queries written with ? placeholders on every driver.
 type DB struct {
 	*sql.DB
+	Driver Driver
 }
 
-// NewConnection creates a new database connection
-func NewConnection(dbPath string) (*DB, error) {
-	// Create directory if it doesn't exist
-	dir := filepath.Dir(dbPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create database directory: %w", err)
-	}
+// NewConnection creates a new database connection.
+// For SQLite the DSN is the database file path, for PostgreSQL a connection URL.
+func NewConnection(driver Driver, dsn string) (*DB, error) {
+	var db *sql.DB
+	var err error
+
+	switch driver {
+	case SQLite:
+		// Create directory if it doesn't exist
+		dir := filepath.Dir(dsn)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create database directory: %w", err)
+		}
 
-	// Open database connection
-	db, err := sql.Open("sqlite", dbPath+"?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)")
+		// Store timestamps in SQLite's own format so they sort and compare as text
+		db, err = sql.Open("sqlite", dsn+"?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_time_format=sqlite")
+	case Postgres:
+		db, err = sql.Open("pgx", dsn)
+	default:
+		return nil, fmt.Errorf("unsupported database driver: %q", driver)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -41,21 +57,29 @@ func NewConnection(dbPath string) (*DB, error) {
 	db.SetMaxOpenConns(25)
 	db.SetMaxIdleConns(25)
 
-	log.Printf("Database connected successfully: %s", dbPath)
+	log.Printf("Database connected successfully (%s)", driver)
 
-	return &DB{db}, nil
+	return &DB{DB: db, Driver: driver}, nil
 }
 
-// RunMigrations runs database migrations
+// RunMigrations runs database migrations from the driver's migrations directory
 func (db *DB) RunMigrations(migrationsPath string) error {
-	driver, err := sqlite3.WithInstance(db.DB, &sqlite3.Config{})
+	var driver migratedb.Driver
+	var err error
+
+	switch db.Driver {
+	case Postgres:
+		driver, err = pgx.WithInstance(db.DB, &pgx.Config{})
+	default:
+		driver, err = sqlite3.WithInstance(db.DB, &sqlite3.Config{})
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create migration driver: %w", err)
 	}
 
 	m, err := migrate.NewWithDatabaseInstance(
 		fmt.Sprintf("file://%s", migrationsPath),
-		"sqlite3",
+		string(db.Driver),
 		driver,
 	)
 	if err != nil {
@@ -70,6 +94,35 @@ func (db *DB) RunMigrations(migrationsPath string) error {
 	return nil
 }
 
+// Exec executes a query written with ? placeholders
+func (db *DB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return db.DB.Exec(db.Driver.Rebind(query), args...)
+}
+
+// Query runs a query written with ? placeholders
+func (db *DB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return db.DB.Query(db.Driver.Rebind(query), args...)
+}
+
+// QueryRow runs a query written with ? placeholders that returns at most one row
+func (db *DB) QueryRow(query string, args ...interface{}) *sql.Row {
+	return db.DB.QueryRow(db.Driver.Rebind(query), args...)
+}
+
+// Begin starts a transaction whose queries are rebound like the DB's
+func (db *DB) Begin() (*Tx, error) {
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx, driver: db.Driver}, nil
+}
+
+// DatePart returns an integer SQL expression extracting part of a date column
+func (db *DB) DatePart(part DatePart, column string) string {
+	return db.Driver.DatePart(part, column)
+}
+
 // Close closes the database connection
 func (db *DB) Close() error {
 	return db.DB.Close()
@@ -79,3 +132,24 @@ func (db *DB) Close() error {
 func (db *DB) Health() error {
 	return db.Ping()
 }
+
+// Tx is a transaction accepting queries written with ? placeholders
+type Tx struct {
+	*sql.Tx
+	driver Driver
+}
+
+// Exec executes a query written with ? placeholders within the transaction
+func (tx *Tx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return tx.Tx.Exec(tx.driver.Rebind(query), args...)
+}
+
+// Query runs a query written with ? placeholders within the transaction
+func (tx *Tx) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return tx.Tx.Query(tx.driver.Rebind(query), args...)
+}
+
+// QueryRow runs a query written with ? placeholders within the transaction
+func (tx *Tx) QueryRow(query string, args ...interface{}) *sql.Row {
+	return tx.Tx.QueryRow(tx.driver.Rebind(query), args...)
+}
@@ -0,0 +1,81 @@
+package database
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Driver identifies a supported database backend
+type Driver string
+
+const (
+	// SQLite is the embedded default backend (modernc.org/sqlite, no CGO)
+	SQLite Driver = "sqlite"
+	// Postgres is the PostgreSQL backend (pgx)
+	Postgres Driver = "postgres"
+)
+
+// DatePart names a calendar field of a date
+type DatePart string
+
+const (
+	// Year is the calendar year, e.g. 2024
+	Year DatePart = "year"
+	// Month is the month of the year, 1-12
+	Month DatePart = "month"
+	// Day is the day of the month, 1-31
+	Day DatePart = "day"
+)
+
+// strftimeFormats maps date parts to SQLite strftime formats
+var strftimeFormats = map[DatePart]string{
+	Year:  "%Y",
+	Month: "%m",
+	Day:   "%d",
+}
+
+// ParseDriver validates a DATABASE_DRIVER value
+func ParseDriver(value string) (Driver, error) {
+	switch driver := Driver(value); driver {
+	case SQLite, Postgres:
+		return driver, nil
+	}
+	return "", fmt.Errorf("unsupported database driver %q, use sqlite or postgres", value)
+}
+
+// Rebind converts ? placeholders to the driver's placeholder syntax.
+// Question marks inside single-quoted string literals are left alone.
+func (d Driver) Rebind(query string) string {
+	if d != Postgres || !strings.Contains(query, "?") {
+		return query
+	}
+
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+
+	n := 0
+	inLiteral := false
+	for _, r := range query {
+		switch {
+		case r == '\'':
+			inLiteral = !inLiteral
+		case r == '?' && !inLiteral:
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// DatePart returns an integer SQL expression extracting part of a date column
+func (d Driver) DatePart(part DatePart, column string) string {
+	if d == Postgres {
+		return fmt.Sprintf("CAST(EXTRACT(%s FROM %s) AS INTEGER)", strings.ToUpper(string(part)), column)
+	}
+	return fmt.Sprintf("CAST(strftime('%s', %s) AS INTEGER)", strftimeFormats[part], column)
+}
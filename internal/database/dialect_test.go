@@ -0,0 +1,47 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDriver(t *testing.T) {
+	driver, err := ParseDriver("postgres")
+	assert.NoError(t, err)
+	assert.Equal(t, Postgres, driver)
+
+	driver, err = ParseDriver("sqlite")
+	assert.NoError(t, err)
+	assert.Equal(t, SQLite, driver)
+
+	_, err = ParseDriver("mysql")
+	assert.Error(t, err)
+}
+
+func TestDriver_Rebind(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   Driver
+		query    string
+		expected string
+	}{
+		{"sqlite unchanged", SQLite, "SELECT * FROM holidays WHERE id = ? AND type = ?", "SELECT * FROM holidays WHERE id = ? AND type = ?"},
+		{"postgres numbered", Postgres, "SELECT * FROM holidays WHERE id = ? AND type = ?", "SELECT * FROM holidays WHERE id = $1 AND type = $2"},
+		{"postgres skips literals", Postgres, "SELECT '?' FROM holidays WHERE name = ?", "SELECT '?' FROM holidays WHERE name = $1"},
+		{"postgres no placeholders", Postgres, "SELECT 1", "SELECT 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.driver.Rebind(tt.query))
+		})
+	}
+}
+
+func TestDriver_DatePart(t *testing.T) {
+	assert.Equal(t, "CAST(strftime('%Y', date) AS INTEGER)", SQLite.DatePart(Year, "date"))
+	assert.Equal(t, "CAST(strftime('%m', date) AS INTEGER)", SQLite.DatePart(Month, "date"))
+	assert.Equal(t, "CAST(EXTRACT(YEAR FROM date) AS INTEGER)", Postgres.DatePart(Year, "date"))
+	assert.Equal(t, "CAST(EXTRACT(DAY FROM date) AS INTEGER)", Postgres.DatePart(Day, "date"))
+}
@@ -0,0 +1,89 @@
+// Package events provides the in-process event bus that fans holiday changes out to subscribers
+package events
+
+import (
+	"sync"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+// Bus delivers published holiday changes to every current subscriber.
+// Publishing never blocks: a subscriber whose buffer is full is dropped and its channel closed,
+// so a slow client cannot hold up writers. Stream clients then reconnect and catch up from the
+// persisted change feed.
+type Bus struct {
+	mu          sync.Mutex
+	buffer      int
+	closed      bool
+	subscribers map[chan models.HolidayChange]struct{}
+}
+
+// NewBus creates a bus whose subscribers can fall up to buffer changes behind
+func NewBus(buffer int) *Bus {
+	return &Bus{
+		buffer:      buffer,
+		subscribers: make(map[chan models.HolidayChange]struct{}),
+	}
+}
+
+// Subscribe registers a subscriber. The returned function unsubscribes it and is safe to call more than once.
+// The channel is closed when the subscriber is dropped or the bus is closed.
+func (b *Bus) Subscribe() (<-chan models.HolidayChange, func()) {
+	ch := make(chan models.HolidayChange, b.buffer)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	b.subscribers[ch] = struct{}{}
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.remove(ch)
+	}
+}
+
+// Publish delivers a change to every subscriber, dropping those that cannot keep up
+func (b *Bus) Publish(change models.HolidayChange) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- change:
+		default:
+			b.remove(ch)
+		}
+	}
+}
+
+// Subscribers returns the number of current subscribers
+func (b *Bus) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers)
+}
+
+// Close drops every subscriber and rejects new ones. It is used on server shutdown
+// so that open streams end instead of holding the shutdown up.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers {
+		b.remove(ch)
+	}
+	b.closed = true
+}
+
+// remove closes and forgets a subscriber; the caller must hold the lock
+func (b *Bus) remove(ch chan models.HolidayChange) {
+	if _, ok := b.subscribers[ch]; ok {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
@@ -0,0 +1,60 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+func TestBus_PublishFansOut(t *testing.T) {
+	bus := NewBus(4)
+
+	first, unsubscribeFirst := bus.Subscribe()
+	second, unsubscribeSecond := bus.Subscribe()
+	defer unsubscribeSecond()
+
+	bus.Publish(models.HolidayChange{Sequence: 1, Type: models.ChangeHolidayCreated})
+
+	assert.Equal(t, int64(1), (<-first).Sequence)
+	assert.Equal(t, int64(1), (<-second).Sequence)
+
+	unsubscribeFirst()
+	unsubscribeFirst()
+	_, open := <-first
+	assert.False(t, open)
+	assert.Equal(t, 1, bus.Subscribers())
+}
+
+func TestBus_DropsSlowSubscriber(t *testing.T) {
+	bus := NewBus(1)
+
+	slow, unsubscribe := bus.Subscribe()
+	defer unsubscribe()
+
+	bus.Publish(models.HolidayChange{Sequence: 1})
+	bus.Publish(models.HolidayChange{Sequence: 2})
+
+	change, open := <-slow
+	assert.True(t, open)
+	assert.Equal(t, int64(1), change.Sequence)
+
+	_, open = <-slow
+	assert.False(t, open)
+	assert.Equal(t, 0, bus.Subscribers())
+}
+
+func TestBus_Close(t *testing.T) {
+	bus := NewBus(1)
+
+	ch, _ := bus.Subscribe()
+	bus.Close()
+
+	_, open := <-ch
+	assert.False(t, open)
+
+	late, _ := bus.Subscribe()
+	_, open = <-late
+	assert.False(t, open)
+}
@@ -1,41 +1,53 @@
 package handlers
 
 import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 
+	"github.com/ilramdhan/holidayapi/internal/middleware"
 	"github.com/ilramdhan/holidayapi/internal/models"
 	"github.com/ilramdhan/holidayapi/internal/services"
 )
 
 // AdminHandler handles admin-related HTTP requests
 type AdminHandler struct {
-	service   services.HolidayService
-	validator *validator.Validate
+	service      services.HolidayService
+	auditService services.AuditService
+	validator    *validator.Validate
 }
 
 // NewAdminHandler creates a new admin handler
-func NewAdminHandler(service services.HolidayService) *AdminHandler {
+func NewAdminHandler(service services.HolidayService, auditService services.AuditService) *AdminHandler {
 	return &AdminHandler{
-		service:   service,
-		validator: validator.New(),
+		service:      service,
+		auditService: auditService,
+		validator:    validator.New(),
 	}
 }
 
 // CreateHoliday godoc
-// @Summary Create a new holiday (Admin only)
+// @Summary Create a new holiday (requires holiday:create)
 // @Description Create a new holiday entry
 // @Tags admin
 // @Accept json
 // @Produce json
-// @Param X-API-Key header string true "Admin API Key"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param holiday body models.CreateHolidayRequest true "Holiday data"
 // @Success 201 {object} models.APIResponse{data=models.Holiday}
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /api/v1/admin/holidays [post]
 func (h *AdminHandler) CreateHoliday(c *gin.Context) {
@@ -61,7 +73,15 @@ func (h *AdminHandler) CreateHoliday(c *gin.Context) {
 
 	holiday, err := h.service.CreateHoliday(req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		details := models.NewHolidayAuditDetails(nil, nil)
+		details.Error = err.Error()
+		h.logHolidayAction(c, models.ActionHolidayCreate, nil, details, false)
+
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "already exists") {
+			status = http.StatusConflict
+		}
+		c.JSON(status, models.ErrorResponse{
 			Success: false,
 			Message: "Failed to create holiday",
 			Error:   err.Error(),
@@ -69,6 +89,8 @@ func (h *AdminHandler) CreateHoliday(c *gin.Context) {
 		return
 	}
 
+	h.logHolidayAction(c, models.ActionHolidayCreate, &holiday.ID, models.NewHolidayAuditDetails(nil, holiday), true)
+
 	c.JSON(http.StatusCreated, models.APIResponse{
 		Success: true,
 		Message: "Holiday created successfully",
@@ -77,12 +99,13 @@ func (h *AdminHandler) CreateHoliday(c *gin.Context) {
 }
 
 // GetHoliday godoc
-// @Summary Get holiday by ID (Admin only)
+// @Summary Get holiday by ID (requires holiday:read)
 // @Description Get a specific holiday by ID
 // @Tags admin
 // @Accept json
 // @Produce json
-// @Param X-API-Key header string true "Admin API Key"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param id path int true "Holiday ID"
 // @Success 200 {object} models.APIResponse{data=models.Holiday}
 // @Failure 400 {object} models.ErrorResponse
@@ -120,18 +143,21 @@ func (h *AdminHandler) GetHoliday(c *gin.Context) {
 }
 
 // UpdateHoliday godoc
-// @Summary Update holiday (Admin only)
-// @Description Update an existing holiday
+// @Summary Update holiday (requires holiday:update)
+// @Description Update an existing holiday. Status changes follow provisional → official → revised (repeatable),
+// @Description with cancelled reachable from any other status, and require decree_number and decree_date.
 // @Tags admin
 // @Accept json
 // @Produce json
-// @Param X-API-Key header string true "Admin API Key"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param id path int true "Holiday ID"
 // @Param holiday body models.UpdateHolidayRequest true "Holiday update data"
 // @Success 200 {object} models.APIResponse{data=models.Holiday}
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /api/v1/admin/holidays/{id} [put]
 func (h *AdminHandler) UpdateHoliday(c *gin.Context) {
@@ -166,9 +192,25 @@ func (h *AdminHandler) UpdateHoliday(c *gin.Context) {
 		return
 	}
 
+	// Snapshot the current state for the audit diff
+	before, _ := h.service.GetHolidayByID(id)
+
 	holiday, err := h.service.UpdateHoliday(id, req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		details := models.NewHolidayAuditDetails(before, before)
+		details.Error = err.Error()
+		h.logHolidayAction(c, models.ActionHolidayUpdate, &id, details, false)
+
+		status := http.StatusInternalServerError
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			status = http.StatusNotFound
+		case strings.Contains(err.Error(), "already exists"):
+			status = http.StatusConflict
+		case strings.Contains(err.Error(), "status"), strings.Contains(err.Error(), "invalid"):
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, models.ErrorResponse{
 			Success: false,
 			Message: "Failed to update holiday",
 			Error:   err.Error(),
@@ -176,6 +218,8 @@ func (h *AdminHandler) UpdateHoliday(c *gin.Context) {
 		return
 	}
 
+	h.logHolidayAction(c, models.ActionHolidayUpdate, &id, models.NewHolidayAuditDetails(before, holiday), true)
+
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
 		Message: "Holiday updated successfully",
@@ -184,12 +228,13 @@ func (h *AdminHandler) UpdateHoliday(c *gin.Context) {
 }
 
 // DeleteHoliday godoc
-// @Summary Delete holiday (Admin only)
+// @Summary Delete holiday (requires holiday:delete)
 // @Description Soft delete a holiday
 // @Tags admin
 // @Accept json
 // @Produce json
-// @Param X-API-Key header string true "Admin API Key"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param id path int true "Holiday ID"
 // @Success 200 {object} models.APIResponse
 // @Failure 400 {object} models.ErrorResponse
@@ -209,7 +254,14 @@ func (h *AdminHandler) DeleteHoliday(c *gin.Context) {
 		return
 	}
 
+	// Snapshot the current state for the audit diff
+	before, _ := h.service.GetHolidayByID(id)
+
 	if err := h.service.DeleteHoliday(id); err != nil {
+		details := models.NewHolidayAuditDetails(before, before)
+		details.Error = err.Error()
+		h.logHolidayAction(c, models.ActionHolidayDelete, &id, details, false)
+
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
 			Message: "Failed to delete holiday",
@@ -218,8 +270,376 @@ func (h *AdminHandler) DeleteHoliday(c *gin.Context) {
 		return
 	}
 
+	h.logHolidayAction(c, models.ActionHolidayDelete, &id, models.NewHolidayAuditDetails(before, nil), true)
+
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
 		Message: "Holiday deleted successfully",
 	})
 }
+
+const (
+	// maxImportRows limits the number of rows accepted by a single bulk import
+	maxImportRows = 1000
+	// maxImportBodyBytes limits the size of a bulk import request body
+	maxImportBodyBytes = 2 << 20
+)
+
+// ImportHolidays godoc
+// @Summary Bulk import holidays (requires holiday:create)
+// @Description Import holidays from CSV (name,date,type,description,province) or a JSON array in a single transaction.
+// @Description With dry_run=true nothing is saved and the per-row diff (create, update, unchanged, conflict, invalid) is returned.
+// @Description The import is rejected as a whole if any row is invalid or conflicting.
+// @Tags admin
+// @Accept json
+// @Accept text/csv
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param dry_run query bool false "Only compute the diff without saving" default(false)
+// @Param holidays body []models.CreateHolidayRequest true "Holidays to import"
+// @Success 200 {object} models.APIResponse{data=models.HolidayImportResult}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 422 {object} models.APIResponse{data=models.HolidayImportResult}
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/holidays/import [post]
+func (h *AdminHandler) ImportHolidays(c *gin.Context) {
+	dryRun := false
+	if dryRunStr := c.Query("dry_run"); dryRunStr != "" {
+		parsed, err := strconv.ParseBool(dryRunStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: "Invalid dry_run parameter",
+				Error:   "dry_run must be true or false",
+			})
+			return
+		}
+		dryRun = parsed
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBodyBytes)
+
+	var rows []models.CreateHolidayRequest
+	var err error
+	if isCSVRequest(c) {
+		rows, err = parseHolidayCSV(body)
+	} else {
+		err = json.NewDecoder(body).Decode(&rows)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if len(rows) == 0 || len(rows) > maxImportRows {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   fmt.Sprintf("import must contain between 1 and %d rows", maxImportRows),
+		})
+		return
+	}
+
+	result, err := h.service.ImportHolidays(rows, dryRun)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to import holidays",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if !result.DryRun && !result.Committed {
+		c.JSON(http.StatusUnprocessableEntity, models.APIResponse{
+			Success: false,
+			Message: "Import rejected",
+			Error:   "Some rows are invalid or conflicting; nothing was saved",
+			Data:    result,
+		})
+		return
+	}
+
+	message := "Holidays imported successfully"
+	if result.DryRun {
+		message = "Import dry run completed"
+	} else {
+		h.logImportedHolidays(c, result)
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    result,
+	})
+}
+
+// GetHolidayHistory godoc
+// @Summary Get holiday revision history (requires holiday:read)
+// @Description Get every recorded revision of a holiday, oldest first, including deletions and rollbacks
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Holiday ID"
+// @Success 200 {object} models.APIResponse{data=[]models.HolidayRevision}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/holidays/{id}/history [get]
+func (h *AdminHandler) GetHolidayHistory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid holiday ID",
+			Error:   "ID must be a valid integer",
+		})
+		return
+	}
+
+	revisions, err := h.service.GetHolidayHistory(id)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to get holiday history",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Holiday history retrieved successfully",
+		Data:    revisions,
+	})
+}
+
+// RollbackHoliday godoc
+// @Summary Roll back holiday to a revision (requires holiday:update)
+// @Description Restore a holiday to the state recorded by an earlier revision. The rollback is itself recorded as a new revision.
+// @Description Rolling back to a revision taken before a deletion restores the holiday.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Holiday ID"
+// @Param rollback body models.RollbackHolidayRequest true "Revision to restore"
+// @Success 200 {object} models.APIResponse{data=models.Holiday}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/holidays/{id}/rollback [post]
+func (h *AdminHandler) RollbackHoliday(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid holiday ID",
+			Error:   "ID must be a valid integer",
+		})
+		return
+	}
+
+	var req models.RollbackHolidayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Validation failed",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Snapshot the current state for the audit diff; nil when the holiday is deleted
+	before, _ := h.service.GetHolidayByID(id)
+
+	holiday, err := h.service.RollbackHoliday(id, req.Revision)
+	if err != nil {
+		details := models.NewHolidayAuditDetails(before, before)
+		details.Error = err.Error()
+		h.logHolidayAction(c, models.ActionHolidayRollback, &id, details, false)
+
+		status := http.StatusInternalServerError
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			status = http.StatusNotFound
+		case strings.Contains(err.Error(), "already exists"):
+			status = http.StatusConflict
+		}
+		c.JSON(status, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to roll back holiday",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	after := holiday
+	if !holiday.IsActive {
+		after = nil
+	}
+	details := models.NewHolidayAuditDetails(before, after)
+	details.Source = fmt.Sprintf("rollback to revision %d", req.Revision)
+	h.logHolidayAction(c, models.ActionHolidayRollback, &id, details, true)
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Holiday rolled back successfully",
+		Data:    holiday,
+	})
+}
+
+// logImportedHolidays records one audit entry per holiday created or updated by an import
+func (h *AdminHandler) logImportedHolidays(c *gin.Context, result *models.HolidayImportResult) {
+	for _, row := range result.Rows {
+		var details models.HolidayAuditDetails
+		var action models.AuditAction
+
+		switch row.Action {
+		case models.ImportCreate:
+			action = models.ActionHolidayCreate
+			date, _ := time.Parse("2006-01-02", row.Holiday.Date)
+			details = models.NewHolidayAuditDetails(nil, &models.Holiday{
+				ID:          derefInt(row.HolidayID),
+				Name:        row.Holiday.Name,
+				Date:        date,
+				Type:        row.Holiday.Type,
+				Province:    row.Holiday.Province,
+				Description: row.Holiday.Description,
+				IsActive:    true,
+			})
+		case models.ImportUpdate:
+			// Only the changed fields are known for import updates
+			action = models.ActionHolidayUpdate
+			details = models.HolidayAuditDetails{Changes: row.Changes}
+		default:
+			continue
+		}
+
+		details.Source = "import"
+		h.logHolidayAction(c, action, row.HolidayID, details, true)
+	}
+}
+
+// logHolidayAction records a holiday mutation by the current user in the audit log
+func (h *AdminHandler) logHolidayAction(c *gin.Context, action models.AuditAction, holidayID *int, details models.HolidayAuditDetails, success bool) {
+	var userID *int
+	var username string
+	if currentUser, err := middleware.GetCurrentUser(c); err == nil {
+		userID = &currentUser.UserID
+		username = currentUser.Username
+	}
+
+	payload, err := json.Marshal(details)
+	if err != nil {
+		payload = []byte(fmt.Sprintf(`{"error":%q}`, err.Error()))
+	}
+
+	if err := h.auditService.LogAction(userID, username, action, models.ResourceHoliday, holidayID,
+		string(payload), c.ClientIP(), c.GetHeader("User-Agent"), success); err != nil {
+		fmt.Printf("Failed to create audit log: %v\n", err)
+	}
+}
+
+// derefInt returns the value of an optional int, or zero
+func derefInt(value *int) int {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
+
+// isCSVRequest reports whether the import body is CSV rather than JSON
+func isCSVRequest(c *gin.Context) bool {
+	if strings.EqualFold(c.Query("format"), "csv") {
+		return true
+	}
+	contentType := strings.ToLower(c.GetHeader("Content-Type"))
+	return strings.Contains(contentType, "text/csv") || strings.Contains(contentType, "application/csv")
+}
+
+// parseHolidayCSV parses CSV rows with a header line naming the name, date, type and description columns
+func parseHolidayCSV(r io.Reader) ([]models.CreateHolidayRequest, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("CSV body is empty")
+		}
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		columns[column] = i
+	}
+
+	for _, required := range []string{"name", "date", "type"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV header must contain a %q column", required)
+		}
+	}
+
+	field := func(record []string, column string) string {
+		if i, ok := columns[column]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var rows []models.CreateHolidayRequest
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		row := models.CreateHolidayRequest{
+			Name:        field(record, "name"),
+			Date:        field(record, "date"),
+			Type:        models.HolidayType(field(record, "type")),
+			Description: field(record, "description"),
+		}
+		if province := field(record, "province"); province != "" {
+			row.Province = &province
+		}
+		rows = append(rows, row)
+
+		if len(rows) > maxImportRows {
+			break
+		}
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+// MockAuditService is a mock implementation of AuditService
+type MockAuditService struct {
+	mock.Mock
+}
+
+func (m *MockAuditService) LogAction(userID *int, username string, action models.AuditAction, resource models.AuditResource, resourceID *int, details, ipAddress, userAgent string, success bool) error {
+	args := m.Called(userID, username, action, resource, resourceID, details, ipAddress, userAgent, success)
+	return args.Error(0)
+}
+
+func (m *MockAuditService) GetAuditLogs(filter models.AuditLogFilter) (*models.AuditLogResponse, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AuditLogResponse), args.Error(1)
+}
+
+func (m *MockAuditService) GetUserAuditLogs(userID int, limit, offset int) ([]models.AuditLog, error) {
+	args := m.Called(userID, limit, offset)
+	return args.Get(0).([]models.AuditLog), args.Error(1)
+}
+
+func (m *MockAuditService) CleanupOldLogs(daysToKeep int) (int64, error) {
+	args := m.Called(daysToKeep)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAuditService) ExportAuditLogs(filter models.AuditLogFilter, format string, exportedBy *models.User, ipAddress, userAgent string, write func(log *models.AuditLog) error) (int, error) {
+	args := m.Called(filter, format, exportedBy, ipAddress, userAgent, write)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAuditService) VerifyChain() (*models.AuditChainVerification, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AuditChainVerification), args.Error(1)
+}
+
+// withCurrentUser simulates the JWT middleware for handler tests
+func withCurrentUser(userID int, username string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Set("username", username)
+		c.Set("user_role", models.AdminRole)
+		c.Next()
+	}
+}
+
+func TestAdminHandler_UpdateHolidayAudited(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	before := &models.Holiday{ID: 5, Name: "Hari Raya Idul Adha", Date: time.Date(2025, 6, 6, 0, 0, 0, 0, time.UTC), Type: models.NationalHoliday, IsActive: true}
+	after := *before
+	after.Date = time.Date(2025, 6, 7, 0, 0, 0, 0, time.UTC)
+
+	mockService := new(MockHolidayService)
+	mockService.On("GetHolidayByID", 5).Return(before, nil)
+	mockService.On("UpdateHoliday", 5, mock.Anything).Return(&after, nil)
+
+	mockAudit := new(MockAuditService)
+	mockAudit.On("LogAction", mock.Anything, "editor", models.ActionHolidayUpdate, models.ResourceHoliday,
+		mock.Anything, mock.Anything, mock.Anything, "test-agent", true).Return(nil)
+
+	handler := NewAdminHandler(mockService, mockAudit)
+
+	router := gin.New()
+	router.PUT("/admin/holidays/:id", withCurrentUser(7, "editor"), handler.UpdateHoliday)
+
+	req, _ := http.NewRequest("PUT", "/admin/holidays/5", strings.NewReader(`{"date":"2025-06-07"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "test-agent")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockAudit.AssertExpectations(t)
+
+	call := mockAudit.Calls[0]
+	assert.Equal(t, 7, *call.Arguments.Get(0).(*int))
+	assert.Equal(t, 5, *call.Arguments.Get(4).(*int))
+
+	var details models.HolidayAuditDetails
+	assert.NoError(t, json.Unmarshal([]byte(call.Arguments.String(5)), &details))
+	assert.Equal(t, map[string]models.FieldChange{
+		"date": {From: "2025-06-06", To: "2025-06-07"},
+	}, details.Changes)
+	assert.Equal(t, "Hari Raya Idul Adha", details.Before.Name)
+	assert.Equal(t, "Hari Raya Idul Adha", details.After.Name)
+}
+
+func TestAdminHandler_DeleteHolidayFailureAudited(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockHolidayService)
+	mockService.On("GetHolidayByID", 9).Return((*models.Holiday)(nil), assert.AnError)
+	mockService.On("DeleteHoliday", 9).Return(assert.AnError)
+
+	mockAudit := new(MockAuditService)
+	mockAudit.On("LogAction", mock.Anything, "editor", models.ActionHolidayDelete, models.ResourceHoliday,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything, false).Return(nil)
+
+	handler := NewAdminHandler(mockService, mockAudit)
+
+	router := gin.New()
+	router.DELETE("/admin/holidays/:id", withCurrentUser(7, "editor"), handler.DeleteHoliday)
+
+	req, _ := http.NewRequest("DELETE", "/admin/holidays/9", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	mockAudit.AssertExpectations(t)
+	assert.Contains(t, mockAudit.Calls[0].Arguments.String(5), assert.AnError.Error())
+}
+
+func TestParseHolidayCSV(t *testing.T) {
+	body := "\ufeffName,Date,Type,Description\n" +
+		"Hari Raya Idul Fitri,2025-03-31,national,\"Idul Fitri 1446 H, hari pertama\"\n" +
+		"Cuti Bersama Idul Fitri,2025-04-02,collective_leave,\n"
+
+	rows, err := parseHolidayCSV(strings.NewReader(body))
+
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "Hari Raya Idul Fitri", rows[0].Name)
+	assert.Equal(t, "Idul Fitri 1446 H, hari pertama", rows[0].Description)
+	assert.Equal(t, models.CollectiveLeave, rows[1].Type)
+}
+
+func TestParseHolidayCSVMissingColumn(t *testing.T) {
+	_, err := parseHolidayCSV(strings.NewReader("name,type\nTahun Baru,national\n"))
+	assert.Error(t, err)
+}
+
+func TestAdminHandler_ImportHolidaysRejected(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockHolidayService)
+	mockService.On("ImportHolidays", mock.Anything, false).Return(&models.HolidayImportResult{
+		Total:     1,
+		Conflicts: 1,
+	}, nil)
+
+	handler := NewAdminHandler(mockService, new(MockAuditService))
+
+	router := gin.New()
+	router.POST("/admin/holidays/import", handler.ImportHolidays)
+
+	req, _ := http.NewRequest("POST", "/admin/holidays/import", strings.NewReader("name,date,type\nTahun Baru Masehi,2025-01-01,national\n"))
+	req.Header.Set("Content-Type", "text/csv")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestAdminHandler_RollbackHoliday(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	current := &models.Holiday{ID: 5, Name: "Hari Raya Idul Adha", Date: time.Date(2025, 6, 7, 0, 0, 0, 0, time.UTC), Type: models.NationalHoliday, IsActive: true}
+	restored := *current
+	restored.Date = time.Date(2025, 6, 6, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMocks     func(service *MockHolidayService, audit *MockAuditService)
+		expectedStatus int
+	}{
+		{
+			name: "restores the revision",
+			body: `{"revision":1}`,
+			setupMocks: func(service *MockHolidayService, audit *MockAuditService) {
+				service.On("GetHolidayByID", 5).Return(current, nil)
+				service.On("RollbackHoliday", 5, 1).Return(&restored, nil)
+				audit.On("LogAction", mock.Anything, "editor", models.ActionHolidayRollback, models.ResourceHoliday,
+					mock.Anything, mock.MatchedBy(func(details string) bool {
+						return strings.Contains(details, `"rollback to revision 1"`) && strings.Contains(details, `"from":"2025-06-07"`)
+					}), mock.Anything, mock.Anything, true).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "unknown revision",
+			body: `{"revision":9}`,
+			setupMocks: func(service *MockHolidayService, audit *MockAuditService) {
+				service.On("GetHolidayByID", 5).Return(current, nil)
+				service.On("RollbackHoliday", 5, 9).Return(nil, errors.New("holiday revision not found"))
+				audit.On("LogAction", mock.Anything, "editor", models.ActionHolidayRollback, models.ResourceHoliday,
+					mock.Anything, mock.Anything, mock.Anything, mock.Anything, false).Return(nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "missing revision",
+			body:           `{}`,
+			setupMocks:     func(service *MockHolidayService, audit *MockAuditService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockHolidayService)
+			mockAudit := new(MockAuditService)
+			tt.setupMocks(mockService, mockAudit)
+
+			handler := NewAdminHandler(mockService, mockAudit)
+
+			router := gin.New()
+			router.POST("/admin/holidays/:id/rollback", withCurrentUser(7, "editor"), handler.RollbackHoliday)
+
+			req, _ := http.NewRequest("POST", "/admin/holidays/5/rollback", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+			mockAudit.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/ilramdhan/holidayapi/internal/middleware"
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/services"
+)
+
+// APIKeyHandler handles API key management HTTP requests
+type APIKeyHandler struct {
+	apiKeyService services.APIKeyService
+	validator     *validator.Validate
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(apiKeyService services.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+		validator:     validator.New(),
+	}
+}
+
+// CreateAPIKey godoc
+// @Summary Issue an API key (requires apikey:manage)
+// @Description Issue a client API key for a user. The key is only returned once; store it securely.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param apiKey body models.CreateAPIKeyRequest true "API key data"
+// @Success 201 {object} models.APIResponse{data=models.CreateAPIKeyResponse}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/auth/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req models.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Validation failed",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Get current user from context
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Message: "Unauthorized",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	createdBy := &models.User{
+		ID:       currentUser.UserID,
+		Username: currentUser.Username,
+		Role:     currentUser.Role,
+	}
+
+	created, err := h.apiKeyService.CreateAPIKey(req, createdBy, c.ClientIP(), c.GetHeader("User-Agent"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case strings.Contains(err.Error(), "user not found"):
+			status = http.StatusNotFound
+		case strings.Contains(err.Error(), "expires_at"):
+			status = http.StatusBadRequest
+		case strings.Contains(err.Error(), "which you do not have"):
+			status = http.StatusForbidden
+		}
+		c.JSON(status, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to create API key",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "API key created successfully. Store the key now, it will not be shown again",
+		Data:    created,
+	})
+}
+
+// GetAPIKeys godoc
+// @Summary List API keys (requires apikey:manage)
+// @Description List all API keys with owner, scopes, expiry and last use. Key values are never returned.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=[]models.APIKey}
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/auth/api-keys [get]
+func (h *APIKeyHandler) GetAPIKeys(c *gin.Context) {
+	keys, err := h.apiKeyService.ListAPIKeys()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to get API keys",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "API keys retrieved successfully",
+		Data:    keys,
+	})
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke an API key (requires apikey:manage)
+// @Description Revoke an API key so it can no longer be used
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "API key ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/auth/api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid API key ID",
+			Error:   "ID must be a valid integer",
+		})
+		return
+	}
+
+	// Get current user from context
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Message: "Unauthorized",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	revokedBy := &models.User{
+		ID:       currentUser.UserID,
+		Username: currentUser.Username,
+		Role:     currentUser.Role,
+	}
+
+	if err := h.apiKeyService.RevokeAPIKey(id, revokedBy, c.ClientIP(), c.GetHeader("User-Agent")); err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			status = http.StatusNotFound
+		case strings.Contains(err.Error(), "already revoked"):
+			status = http.StatusConflict
+		}
+		c.JSON(status, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to revoke API key",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "API key revoked successfully",
+	})
+}
@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -12,6 +16,21 @@ import (
 	"github.com/ilramdhan/holidayapi/internal/services"
 )
 
+// auditExportFlushEvery is how many exported rows are written between flushes of the response
+const auditExportFlushEvery = 500
+
+// auditExportContentTypes maps the supported export formats to their content types
+var auditExportContentTypes = map[string]string{
+	"csv":    "text/csv; charset=utf-8",
+	"ndjson": "application/x-ndjson",
+}
+
+// auditExportCSVHeader is the header row of CSV audit log exports
+var auditExportCSVHeader = []string{
+	"id", "created_at", "user_id", "username", "action", "resource", "resource_id",
+	"success", "ip_address", "user_agent", "details", "prev_hash", "hash",
+}
+
 // AuditHandler handles audit-related HTTP requests
 type AuditHandler struct {
 	auditService services.AuditService
@@ -25,12 +44,13 @@ func NewAuditHandler(auditService services.AuditService) *AuditHandler {
 }
 
 // GetAuditLogs godoc
-// @Summary Get audit logs (Admin only)
+// @Summary Get audit logs (requires audit:read)
 // @Description Get audit logs with optional filters
 // @Tags audit
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param user_id query int false "Filter by user ID"
 // @Param action query string false "Filter by action"
 // @Param resource query string false "Filter by resource"
@@ -46,81 +66,127 @@ func NewAuditHandler(auditService services.AuditService) *AuditHandler {
 // @Failure 500 {object} models.ErrorResponse
 // @Router /api/v1/admin/audit-logs [get]
 func (h *AuditHandler) GetAuditLogs(c *gin.Context) {
-	filter := models.AuditLogFilter{}
+	filter := parseAuditLogFilter(c)
 
-	// Parse query parameters
-	if userIDStr := c.Query("user_id"); userIDStr != "" {
-		if userID, err := strconv.Atoi(userIDStr); err == nil {
-			filter.UserID = &userID
-		}
+	response, err := h.auditService.GetAuditLogs(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to get audit logs",
+			Error:   err.Error(),
+		})
+		return
 	}
 
-	if actionStr := c.Query("action"); actionStr != "" {
-		action := models.AuditAction(actionStr)
-		filter.Action = &action
-	}
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Audit logs retrieved successfully",
+		Data:    response,
+	})
+}
 
-	if resourceStr := c.Query("resource"); resourceStr != "" {
-		resource := models.AuditResource(resourceStr)
-		filter.Resource = &resource
+// ExportAuditLogs godoc
+// @Summary Export audit logs (requires audit:read)
+// @Description Stream every audit log matching the filters, oldest first, as CSV or newline-delimited JSON. Unlike the list endpoint there is no page size cap; limit and offset are applied only when given. The export is recorded as an AUDIT_EXPORT audit event.
+// @Tags audit
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param format query string true "Export format" Enums(csv, ndjson)
+// @Param user_id query int false "Filter by user ID"
+// @Param action query string false "Filter by action"
+// @Param resource query string false "Filter by resource"
+// @Param success query bool false "Filter by success status"
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param limit query int false "Limit results (no limit when omitted)"
+// @Param offset query int false "Offset"
+// @Success 200 {string} string "CSV with a header row, or one models.AuditLog JSON object per line"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /api/v1/admin/audit-logs/export [get]
+func (h *AuditHandler) ExportAuditLogs(c *gin.Context) {
+	format := strings.ToLower(c.Query("format"))
+	contentType, ok := auditExportContentTypes[format]
+	if !ok {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid export format",
+			Error:   "format must be csv or ndjson",
+		})
+		return
 	}
 
-	if successStr := c.Query("success"); successStr != "" {
-		if success, err := strconv.ParseBool(successStr); err == nil {
-			filter.Success = &success
-		}
+	exportedBy, ok := currentUser(c)
+	if !ok {
+		return
 	}
 
-	if startDateStr := c.Query("start_date"); startDateStr != "" {
-		if startDate, err := time.Parse("2006-01-02", startDateStr); err == nil {
-			filter.StartDate = &startDate
-		}
-	}
+	filter := parseAuditLogFilter(c)
 
-	if endDateStr := c.Query("end_date"); endDateStr != "" {
-		if endDate, err := time.Parse("2006-01-02", endDateStr); err == nil {
-			// Set to end of day
-			endDate = endDate.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
-			filter.EndDate = &endDate
-		}
-	}
+	// Large exports outlive the server's write timeout
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
 
-	if limitStr := c.Query("limit"); limitStr != "" {
-		if limit, err := strconv.Atoi(limitStr); err == nil {
-			filter.Limit = limit
-		}
-	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="audit-logs-%s.%s"`, time.Now().Format("20060102-150405"), format))
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
 
-	if offsetStr := c.Query("offset"); offsetStr != "" {
-		if offset, err := strconv.Atoi(offsetStr); err == nil {
-			filter.Offset = offset
+	var write func(log *models.AuditLog) error
+	var flush func() error
+	switch format {
+	case "csv":
+		writer := csv.NewWriter(c.Writer)
+		if err := writer.Write(auditExportCSVHeader); err != nil {
+			return
+		}
+		write = func(log *models.AuditLog) error { return writer.Write(auditLogCSVRecord(log)) }
+		flush = func() error {
+			writer.Flush()
+			return writer.Error()
 		}
+	default:
+		encoder := json.NewEncoder(c.Writer)
+		write = func(log *models.AuditLog) error { return encoder.Encode(log) }
+		flush = func() error { return nil }
 	}
 
-	response, err := h.auditService.GetAuditLogs(filter)
+	// Rows go out as they are read; the response is flushed every auditExportFlushEvery rows
+	written := 0
+	_, err := h.auditService.ExportAuditLogs(filter, format, exportedBy, c.ClientIP(), c.GetHeader("User-Agent"), func(log *models.AuditLog) error {
+		if err := write(log); err != nil {
+			return err
+		}
+		written++
+		if written%auditExportFlushEvery == 0 {
+			if err := flush(); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+		}
+		return nil
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Success: false,
-			Message: "Failed to get audit logs",
-			Error:   err.Error(),
-		})
+		// The status line has been sent, so a failed export can only end early
+		fmt.Printf("Audit log export ended early: %v\n", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, models.APIResponse{
-		Success: true,
-		Message: "Audit logs retrieved successfully",
-		Data:    response,
-	})
+	if err := flush(); err == nil {
+		c.Writer.Flush()
+	}
 }
 
 // GetUserAuditLogs godoc
-// @Summary Get user audit logs (Admin only)
+// @Summary Get user audit logs (requires audit:read)
 // @Description Get audit logs for a specific user
 // @Tags audit
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param id path int true "User ID"
 // @Param limit query int false "Limit results" default(50)
 // @Param offset query int false "Offset for pagination" default(0)
@@ -173,6 +239,41 @@ func (h *AuditHandler) GetUserAuditLogs(c *gin.Context) {
 	})
 }
 
+// VerifyAuditChain godoc
+// @Summary Verify the audit log hash chain (requires audit:verify)
+// @Description Walk the audit log from the oldest entry, checking that every entry links to its predecessor and matches its own hash. Reports the first broken link; valid is false when there is one.
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=models.AuditChainVerification}
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/audit-logs/verify [get]
+func (h *AuditHandler) VerifyAuditChain(c *gin.Context) {
+	result, err := h.auditService.VerifyChain()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to verify audit chain",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	message := "Audit chain is intact"
+	if !result.Valid {
+		message = "Audit chain is broken"
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    result,
+	})
+}
+
 // GetMyAuditLogs godoc
 // @Summary Get current user's audit logs
 // @Description Get audit logs for the currently authenticated user
@@ -228,3 +329,84 @@ func (h *AuditHandler) GetMyAuditLogs(c *gin.Context) {
 		Data:    logs,
 	})
 }
+
+// parseAuditLogFilter reads the audit log filter query parameters, ignoring invalid values
+func parseAuditLogFilter(c *gin.Context) models.AuditLogFilter {
+	filter := models.AuditLogFilter{}
+
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		if userID, err := strconv.Atoi(userIDStr); err == nil {
+			filter.UserID = &userID
+		}
+	}
+
+	if actionStr := c.Query("action"); actionStr != "" {
+		action := models.AuditAction(actionStr)
+		filter.Action = &action
+	}
+
+	if resourceStr := c.Query("resource"); resourceStr != "" {
+		resource := models.AuditResource(resourceStr)
+		filter.Resource = &resource
+	}
+
+	if successStr := c.Query("success"); successStr != "" {
+		if success, err := strconv.ParseBool(successStr); err == nil {
+			filter.Success = &success
+		}
+	}
+
+	if startDateStr := c.Query("start_date"); startDateStr != "" {
+		if startDate, err := time.Parse("2006-01-02", startDateStr); err == nil {
+			filter.StartDate = &startDate
+		}
+	}
+
+	if endDateStr := c.Query("end_date"); endDateStr != "" {
+		if endDate, err := time.Parse("2006-01-02", endDateStr); err == nil {
+			// Set to end of day
+			endDate = endDate.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
+			filter.EndDate = &endDate
+		}
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil {
+			filter.Limit = limit
+		}
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if offset, err := strconv.Atoi(offsetStr); err == nil {
+			filter.Offset = offset
+		}
+	}
+
+	return filter
+}
+
+// auditLogCSVRecord returns the CSV record of an audit log in auditExportCSVHeader order
+func auditLogCSVRecord(log *models.AuditLog) []string {
+	optionalInt := func(value *int) string {
+		if value == nil {
+			return ""
+		}
+		return strconv.Itoa(*value)
+	}
+
+	return []string{
+		strconv.Itoa(log.ID),
+		log.CreatedAt.UTC().Format(time.RFC3339Nano),
+		optionalInt(log.UserID),
+		log.Username,
+		string(log.Action),
+		string(log.Resource),
+		optionalInt(log.ResourceID),
+		strconv.FormatBool(log.Success),
+		log.IPAddress,
+		log.UserAgent,
+		log.Details,
+		log.PrevHash,
+		log.Hash,
+	}
+}
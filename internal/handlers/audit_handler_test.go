@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+// exportedAuditLogs are the entries the mock audit service passes to the export writer
+var exportedAuditLogs = []models.AuditLog{
+	{ID: 1, Username: "admin", Action: models.ActionLogin, Resource: models.ResourceAuth, Success: true,
+		CreatedAt: time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC), Hash: "aa"},
+	{ID: 2, Username: "admin", Action: models.ActionHolidayUpdate, Resource: models.ResourceHoliday, Success: true,
+		Details: `{"changes":{"name":{"from":"A, B","to":"C"}}}`, CreatedAt: time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC), PrevHash: "aa", Hash: "bb"},
+}
+
+func TestAuditHandler_ExportAuditLogs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		contentType    string
+		body           []string
+	}{
+		{
+			name:           "csv",
+			query:          "format=csv&action=LOGIN&limit=5000",
+			expectedStatus: http.StatusOK,
+			contentType:    "text/csv; charset=utf-8",
+			body: []string{
+				"id,created_at,user_id,username,action,resource,resource_id,success,ip_address,user_agent,details,prev_hash,hash",
+				"1,2026-10-01T08:00:00Z,,admin,LOGIN,auth,,true,,,,,aa",
+				`2,2026-10-01T09:00:00Z,,admin,HOLIDAY_UPDATE,holiday,,true,,,"{""changes"":{""name"":{""from"":""A, B"",""to"":""C""}}}",aa,bb`,
+			},
+		},
+		{
+			name:           "ndjson",
+			query:          "format=ndjson&action=LOGIN&limit=5000",
+			expectedStatus: http.StatusOK,
+			contentType:    "application/x-ndjson",
+			body: []string{
+				`{"id":1,"username":"admin","action":"LOGIN","resource":"auth","details":"","ip_address":"","user_agent":"","success":true,"created_at":"2026-10-01T08:00:00Z","hash":"aa"}`,
+				`{"id":2,"username":"admin","action":"HOLIDAY_UPDATE","resource":"holiday","details":"{\"changes\":{\"name\":{\"from\":\"A, B\",\"to\":\"C\"}}}","ip_address":"","user_agent":"","success":true,"created_at":"2026-10-01T09:00:00Z","prev_hash":"aa","hash":"bb"}`,
+			},
+		},
+		{
+			name:           "unknown format",
+			query:          "format=xlsx",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAuditService)
+			if tt.expectedStatus == http.StatusOK {
+				action := models.ActionLogin
+				mockService.On("ExportAuditLogs", models.AuditLogFilter{Action: &action, Limit: 5000}, mock.Anything, mock.AnythingOfType("*models.User"), mock.Anything, mock.Anything, mock.Anything).
+					Run(func(args mock.Arguments) {
+						write := args.Get(5).(func(log *models.AuditLog) error)
+						for i := range exportedAuditLogs {
+							assert.NoError(t, write(&exportedAuditLogs[i]))
+						}
+					}).
+					Return(len(exportedAuditLogs), nil)
+			}
+
+			handler := NewAuditHandler(mockService)
+			router := gin.New()
+			router.GET("/admin/audit-logs/export", withCurrentUser(1, "admin"), handler.ExportAuditLogs)
+
+			req, _ := http.NewRequest("GET", "/admin/audit-logs/export?"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.body != nil {
+				assert.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+				assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+				assert.Equal(t, tt.body, strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n"))
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
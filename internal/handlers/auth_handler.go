@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"errors"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -28,14 +31,16 @@ func NewAuthHandler(authService services.AuthService) *AuthHandler {
 
 // Login godoc
 // @Summary User login
-// @Description Authenticate user and return JWT tokens
+// @Description Authenticate user and return JWT tokens. Users with MFA enabled, or whose role requires it, get an MFA challenge instead; complete it at /api/v1/auth/login/mfa. After too many failed attempts the username or client IP is locked for a while and 429 is returned with a Retry-After header.
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param credentials body models.LoginRequest true "Login credentials"
 // @Success 200 {object} models.APIResponse{data=models.AuthResponse}
+// @Success 202 {object} models.APIResponse{data=models.MFAChallenge}
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /api/v1/auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
@@ -64,16 +69,67 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	userAgent := c.GetHeader("User-Agent")
 
 	// Authenticate user
-	authResponse, err := h.authService.Login(req, ipAddress, userAgent)
+	authResponse, challenge, err := h.authService.Login(req, ipAddress, userAgent)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+		loginFailed(c, err)
+		return
+	}
+
+	if challenge != nil {
+		c.JSON(http.StatusAccepted, models.APIResponse{
+			Success: true,
+			Message: "MFA verification required",
+			Data:    challenge,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Login successful",
+		Data:    authResponse,
+	})
+}
+
+// VerifyMFALogin godoc
+// @Summary Complete an MFA login
+// @Description Answer the MFA challenge of a login with a 6-digit authenticator code or a recovery code and get JWT tokens. When the challenge required enrollment, the code confirms the secret from /api/v1/auth/login/mfa/enroll and the response includes the new recovery codes.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param mfa body models.MFALoginRequest true "MFA token and code"
+// @Success 200 {object} models.APIResponse{data=models.AuthResponse}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse
+// @Router /api/v1/auth/login/mfa [post]
+func (h *AuthHandler) VerifyMFALogin(c *gin.Context) {
+	var req models.MFALoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
-			Message: "Authentication failed",
+			Message: "Invalid request body",
 			Error:   err.Error(),
 		})
 		return
 	}
 
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Validation failed",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	authResponse, err := h.authService.CompleteMFALogin(req, c.ClientIP(), c.GetHeader("User-Agent"))
+	if err != nil {
+		loginFailed(c, err)
+		return
+	}
+
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
 		Message: "Login successful",
@@ -81,9 +137,78 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	})
 }
 
+// loginFailed writes the response for a failed login step, telling locked out clients when to retry
+func loginFailed(c *gin.Context, err error) {
+	var locked *services.LoginLockedError
+	if errors.As(err, &locked) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, models.ErrorResponse{
+			Success: false,
+			Message: "Authentication failed",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+		Success: false,
+		Message: "Authentication failed",
+		Error:   err.Error(),
+	})
+}
+
+// EnrollMFALogin godoc
+// @Summary Enroll in MFA during login
+// @Description Generate an authenticator secret for a login whose challenge has enrollment_required set. Add it to an authenticator app (the provisioning URI can be rendered as a QR code), then complete the login at /api/v1/auth/login/mfa.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param mfa body models.MFATokenRequest true "MFA token from the login response"
+// @Success 200 {object} models.APIResponse{data=models.MFAEnrollment}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /api/v1/auth/login/mfa/enroll [post]
+func (h *AuthHandler) EnrollMFALogin(c *gin.Context) {
+	var req models.MFATokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Validation failed",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	enrollment, err := h.authService.EnrollMFALogin(req, c.ClientIP(), c.GetHeader("User-Agent"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Message: "MFA enrollment failed",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Scan the provisioning URI with an authenticator app, then complete the login with a code",
+		Data:    enrollment,
+	})
+}
+
 // Register godoc
-// @Summary Register new user (Super Admin only)
-// @Description Create a new user account
+// @Summary Register new user (requires user:manage)
+// @Description Create a new user account. The role must exist and grant no permission the creator lacks.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -137,7 +262,11 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	// Register user
 	user, err := h.authService.Register(req, createdBy)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		status := http.StatusBadRequest
+		if strings.Contains(err.Error(), "which you do not have") {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, models.ErrorResponse{
 			Success: false,
 			Message: "Registration failed",
 			Error:   err.Error(),
@@ -154,7 +283,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 // RefreshToken godoc
 // @Summary Refresh access token
-// @Description Get new access token using refresh token
+// @Description Exchange a refresh token for a new token pair. The presented refresh token is revoked (rotation); replaying an already rotated token revokes the whole session.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -185,8 +314,12 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
+	// Get client info
+	ipAddress := c.ClientIP()
+	userAgent := c.GetHeader("User-Agent")
+
 	// Refresh tokens
-	authResponse, err := h.authService.RefreshToken(req)
+	authResponse, err := h.authService.RefreshToken(req, ipAddress, userAgent)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
 			Success: false,
@@ -203,6 +336,65 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	})
 }
 
+// Logout godoc
+// @Summary Logout
+// @Description Revoke the session the given refresh token belongs to. Access tokens stay valid until they expire.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param logout body models.LogoutRequest true "Refresh token of the session to end"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /api/v1/auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req models.LogoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Validation failed",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Get current user from context
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Message: "Unauthorized",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := h.authService.Logout(currentUser.UserID, req, c.ClientIP(), c.GetHeader("User-Agent")); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Logout failed",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Logged out successfully",
+	})
+}
+
 // GetProfile godoc
 // @Summary Get user profile
 // @Description Get current user's profile information
@@ -306,19 +498,35 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 }
 
 // GetAllUsers godoc
-// @Summary Get all users (Admin only)
-// @Description Get list of all users
+// @Summary Get all users (requires user:read)
+// @Description Get list of all active users, or of all users including deactivated ones
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param include_inactive query bool false "Also list deactivated users" default(false)
 // @Success 200 {object} models.APIResponse{data=[]models.UserResponse}
+// @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
 // @Failure 403 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /api/v1/auth/users [get]
 func (h *AuthHandler) GetAllUsers(c *gin.Context) {
-	users, err := h.authService.GetAllUsers()
+	includeInactive := false
+	if value := c.Query("include_inactive"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: "Invalid include_inactive parameter",
+				Error:   "include_inactive must be true or false",
+			})
+			return
+		}
+		includeInactive = parsed
+	}
+
+	users, err := h.authService.GetAllUsers(includeInactive)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
@@ -336,7 +544,7 @@ func (h *AuthHandler) GetAllUsers(c *gin.Context) {
 }
 
 // DeleteUser godoc
-// @Summary Delete user (Super Admin only)
+// @Summary Delete user (requires user:manage)
 // @Description Soft delete a user account
 // @Tags auth
 // @Accept json
@@ -347,6 +555,8 @@ func (h *AuthHandler) GetAllUsers(c *gin.Context) {
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
 // @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /api/v1/auth/users/{id} [delete]
 func (h *AuthHandler) DeleteUser(c *gin.Context) {
@@ -391,7 +601,7 @@ func (h *AuthHandler) DeleteUser(c *gin.Context) {
 
 	// Delete user
 	if err := h.authService.DeleteUser(id, deletedBy); err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		c.JSON(userManagementStatus(err), models.ErrorResponse{
 			Success: false,
 			Message: "Failed to delete user",
 			Error:   err.Error(),
@@ -404,3 +614,263 @@ func (h *AuthHandler) DeleteUser(c *gin.Context) {
 		Message: "User deleted successfully",
 	})
 }
+
+// RevokeUserSessions godoc
+// @Summary Revoke all sessions of a user (requires user:manage)
+// @Description Revoke every refresh token of a user so they must log in again
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} models.APIResponse{data=models.RevokeSessionsResponse}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/auth/users/{id}/revoke-sessions [post]
+func (h *AuthHandler) RevokeUserSessions(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid user ID",
+			Error:   "ID must be a valid integer",
+		})
+		return
+	}
+
+	// Get current user from context
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Message: "Unauthorized",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Create user object for audit logging
+	revokedBy := &models.User{
+		ID:       currentUser.UserID,
+		Username: currentUser.Username,
+		Role:     currentUser.Role,
+	}
+
+	revoked, err := h.authService.RevokeUserSessions(id, revokedBy, c.ClientIP(), c.GetHeader("User-Agent"))
+	if err != nil {
+		c.JSON(userManagementStatus(err), models.ErrorResponse{
+			Success: false,
+			Message: "Failed to revoke sessions",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Sessions revoked successfully",
+		Data: models.RevokeSessionsResponse{
+			UserID:        id,
+			RevokedTokens: revoked,
+		},
+	})
+}
+
+// GetUser godoc
+// @Summary Get a user (requires user:manage)
+// @Description Get one user by ID, including deactivated accounts
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} models.APIResponse{data=models.UserResponse}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/v1/auth/users/{id} [get]
+func (h *AuthHandler) GetUser(c *gin.Context) {
+	id, ok := parseIDParam(c, "user")
+	if !ok {
+		return
+	}
+
+	user, err := h.authService.GetUser(id)
+	if err != nil {
+		c.JSON(userManagementStatus(err), models.ErrorResponse{
+			Success: false,
+			Message: "Failed to get user",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "User retrieved successfully",
+		Data:    user,
+	})
+}
+
+// UpdateUser godoc
+// @Summary Update a user (requires user:manage)
+// @Description Change the email, role or active state of a user. A new role may grant no permission the caller lacks, and the last active super admin cannot be demoted or deactivated.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param user body models.UpdateUserRequest true "Fields to change"
+// @Success 200 {object} models.APIResponse{data=models.UserResponse}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/auth/users/{id} [patch]
+func (h *AuthHandler) UpdateUser(c *gin.Context) {
+	id, ok := parseIDParam(c, "user")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateUserRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	updatedBy, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.authService.UpdateUserProfile(id, req, updatedBy, c.ClientIP(), c.GetHeader("User-Agent"))
+	if err != nil {
+		c.JSON(userManagementStatus(err), models.ErrorResponse{
+			Success: false,
+			Message: "Failed to update user",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "User updated successfully",
+		Data:    user,
+	})
+}
+
+// ReactivateUser godoc
+// @Summary Reactivate a user (requires user:manage)
+// @Description Restore a deactivated user account
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} models.APIResponse{data=models.UserResponse}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/auth/users/{id}/reactivate [post]
+func (h *AuthHandler) ReactivateUser(c *gin.Context) {
+	id, ok := parseIDParam(c, "user")
+	if !ok {
+		return
+	}
+
+	reactivatedBy, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.authService.ReactivateUser(id, reactivatedBy, c.ClientIP(), c.GetHeader("User-Agent"))
+	if err != nil {
+		c.JSON(userManagementStatus(err), models.ErrorResponse{
+			Success: false,
+			Message: "Failed to reactivate user",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "User reactivated successfully",
+		Data:    user,
+	})
+}
+
+// ResetUserPassword godoc
+// @Summary Set a temporary password for a user (requires user:manage)
+// @Description Replace a user's password with a temporary one and revoke their sessions. Until they change it, their tokens only work for changing the password, reading their profile and logging out.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param password body models.AdminResetPasswordRequest true "Temporary password"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/auth/users/{id}/reset-password [post]
+func (h *AuthHandler) ResetUserPassword(c *gin.Context) {
+	id, ok := parseIDParam(c, "user")
+	if !ok {
+		return
+	}
+
+	var req models.AdminResetPasswordRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	resetBy, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.authService.ResetUserPassword(id, req, resetBy, c.ClientIP(), c.GetHeader("User-Agent")); err != nil {
+		c.JSON(userManagementStatus(err), models.ErrorResponse{
+			Success: false,
+			Message: "Failed to reset password",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Temporary password set, the user must change it at next login",
+	})
+}
+
+// userManagementStatus maps an error from a user management operation to an HTTP status
+func userManagementStatus(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(message, "which you do not have"):
+		return http.StatusForbidden
+	case strings.Contains(message, "already"), strings.Contains(message, "last active super admin"):
+		return http.StatusConflict
+	case strings.HasPrefix(message, "failed to"):
+		return http.StatusInternalServerError
+	default:
+		return http.StatusBadRequest
+	}
+}
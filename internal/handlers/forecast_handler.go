@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/services"
+)
+
+// defaultForecastYears is how many years a forecast covers when to_year is omitted
+const defaultForecastYears = 5
+
+// ForecastHandler handles holiday forecast HTTP requests
+type ForecastHandler struct {
+	service services.ForecastService
+}
+
+// NewForecastHandler creates a new forecast handler
+func NewForecastHandler(service services.ForecastService) *ForecastHandler {
+	return &ForecastHandler{
+		service: service,
+	}
+}
+
+// GetForecast godoc
+// @Summary Get a multi-year holiday forecast
+// @Description Get the recorded holidays of a range of years plus the national holidays projected from their calendars
+// @Description (Hijri, Chinese, Saka, Buddhist, Easter and fixed dates) that have not been recorded yet. Projected entries
+// @Description have computed=true and are provisional: the official dates are set by decree and may differ by a day or more.
+// @Tags holidays
+// @Accept json
+// @Produce json
+// @Param from_year query int false "First year (defaults to the current year)"
+// @Param to_year query int false "Last year, at most 10 years after from_year (defaults to from_year + 4)"
+// @Param province query string false "Province code (ISO 3166-2:ID, e.g. ID-BA) to add its recorded regional holidays"
+// @Param as_of query string false "Use the holidays as recorded at this time (RFC 3339, or YYYY-MM-DD for the end of that day)"
+// @Param status query string false "Comma-separated decree statuses (provisional, official, revised, cancelled); all but cancelled by default"
+// @Success 200 {object} models.APIResponse{data=models.HolidayForecastResponse}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/holidays/forecast [get]
+func (h *ForecastHandler) GetForecast(c *gin.Context) {
+	fromYear := time.Now().Year()
+	if fromStr := c.Query("from_year"); fromStr != "" {
+		parsed, err := strconv.Atoi(fromStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: "Invalid from_year parameter",
+				Error:   "from_year must be a valid integer",
+			})
+			return
+		}
+		fromYear = parsed
+	}
+
+	toYear := fromYear + defaultForecastYears - 1
+	if toStr := c.Query("to_year"); toStr != "" {
+		parsed, err := strconv.Atoi(toStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: "Invalid to_year parameter",
+				Error:   "to_year must be a valid integer",
+			})
+			return
+		}
+		toYear = parsed
+	}
+
+	scope, err := parseHolidayScope(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid query parameter",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.service.ForecastHolidays(fromYear, toYear, scope)
+	if err != nil {
+		// Anything but a storage failure is a rejected year range
+		if !strings.HasPrefix(err.Error(), "failed") {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: "Invalid year range",
+				Error:   err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to forecast holidays",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Holiday forecast retrieved successfully",
+		Data:    response,
+	})
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/services"
+)
+
+const (
+	// changeStreamBatchSize is how many stored changes are replayed per query when a client resumes
+	changeStreamBatchSize = 500
+	// changeStreamHeartbeat is how often a comment line is sent to keep idle connections open
+	changeStreamHeartbeat = 25 * time.Second
+	// changeStreamRetry is the reconnection delay suggested to clients, in milliseconds
+	changeStreamRetry = 5000
+)
+
+// HolidayChangeHandler handles the holiday change stream
+type HolidayChangeHandler struct {
+	service services.HolidayChangeService
+}
+
+// NewHolidayChangeHandler creates a new holiday change handler
+func NewHolidayChangeHandler(service services.HolidayChangeService) *HolidayChangeHandler {
+	return &HolidayChangeHandler{
+		service: service,
+	}
+}
+
+// StreamChanges godoc
+// @Summary Stream holiday changes
+// @Description Server-Sent Events stream of holiday.created, holiday.updated and holiday.deleted events. Each event's id is
+// @Description the change sequence and its data is a models.HolidayChange. Send the last id received in the Last-Event-ID
+// @Description header (or the last_event_id query parameter) to replay the changes made since, then continue live.
+// @Description Without it, only new changes are sent.
+// @Tags holidays
+// @Produce text/event-stream
+// @Param Last-Event-ID header int false "Sequence of the last change received"
+// @Param last_event_id query int false "Same as the Last-Event-ID header, for clients that cannot set headers"
+// @Success 200 {string} string "text/event-stream of models.HolidayChange"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/holidays/changes/stream [get]
+func (h *HolidayChangeHandler) StreamChanges(c *gin.Context) {
+	lastID, resume, err := parseLastEventID(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid Last-Event-ID",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Subscribe before reading the stored changes so that nothing published in between is missed
+	live, unsubscribe := h.service.Subscribe()
+	defer unsubscribe()
+
+	var backlog []models.HolidayChange
+	if resume {
+		backlog, err = h.service.GetChangesSince(lastID, changeStreamBatchSize)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Message: "Failed to get holiday changes",
+				Error:   err.Error(),
+			})
+			return
+		}
+	}
+
+	// The stream outlives the server's write timeout
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	if _, err := fmt.Fprintf(c.Writer, "retry: %d\n\n", changeStreamRetry); err != nil {
+		return
+	}
+	c.Writer.Flush()
+
+	// Replay stored changes page by page
+	for len(backlog) > 0 {
+		for _, change := range backlog {
+			if err := writeChangeEvent(c.Writer, change); err != nil {
+				return
+			}
+			lastID = change.Sequence
+		}
+		c.Writer.Flush()
+
+		if len(backlog) < changeStreamBatchSize {
+			break
+		}
+		if backlog, err = h.service.GetChangesSince(lastID, changeStreamBatchSize); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(changeStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case change, ok := <-live:
+			if !ok {
+				// Dropped for falling behind or shutting down; the client resumes with Last-Event-ID
+				return
+			}
+			if change.Sequence <= lastID {
+				continue
+			}
+			if err := writeChangeEvent(c.Writer, change); err != nil {
+				return
+			}
+			lastID = change.Sequence
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// parseLastEventID reads the sequence to resume from, reporting whether one was given
+func parseLastEventID(c *gin.Context) (int64, bool, error) {
+	value := c.GetHeader("Last-Event-ID")
+	if value == "" {
+		value = c.Query("last_event_id")
+	}
+	if value == "" {
+		return 0, false, nil
+	}
+
+	sequence, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || sequence < 0 {
+		return 0, false, fmt.Errorf("last event id must be a non-negative integer")
+	}
+
+	return sequence, true, nil
+}
+
+// writeChangeEvent writes a change as a server-sent event
+func writeChangeEvent(w gin.ResponseWriter, change models.HolidayChange) error {
+	data, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", change.Sequence, change.Type, data)
+	return err
+}
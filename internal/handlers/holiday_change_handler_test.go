@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+// MockHolidayChangeService is a mock implementation of HolidayChangeService
+type MockHolidayChangeService struct {
+	mock.Mock
+}
+
+func (m *MockHolidayChangeService) Publish(changes ...models.HolidayChange) {
+	m.Called(changes)
+}
+
+func (m *MockHolidayChangeService) GetChangesSince(sequence int64, limit int) ([]models.HolidayChange, error) {
+	args := m.Called(sequence, limit)
+	return args.Get(0).([]models.HolidayChange), args.Error(1)
+}
+
+func (m *MockHolidayChangeService) Subscribe() (<-chan models.HolidayChange, func()) {
+	args := m.Called()
+	return args.Get(0).(<-chan models.HolidayChange), args.Get(1).(func())
+}
+
+// closedChangeFeed returns a live feed that delivers the given changes and then ends
+func closedChangeFeed(changes ...models.HolidayChange) <-chan models.HolidayChange {
+	ch := make(chan models.HolidayChange, len(changes))
+	for _, change := range changes {
+		ch <- change
+	}
+	close(ch)
+	return ch
+}
+
+func TestHolidayChangeHandler_StreamChanges(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		lastEventID    string
+		query          string
+		setupMock      func(*MockHolidayChangeService)
+		expectedStatus int
+		expectedIDs    []string
+	}{
+		{
+			name:        "resume replays stored changes then continues live",
+			lastEventID: "5",
+			setupMock: func(m *MockHolidayChangeService) {
+				m.On("Subscribe").Return(closedChangeFeed(
+					models.HolidayChange{Sequence: 7, Type: models.ChangeHolidayUpdated},
+					models.HolidayChange{Sequence: 8, Type: models.ChangeHolidayDeleted},
+				), func() {})
+				m.On("GetChangesSince", int64(5), changeStreamBatchSize).Return([]models.HolidayChange{
+					{Sequence: 6, Type: models.ChangeHolidayCreated},
+					{Sequence: 7, Type: models.ChangeHolidayUpdated},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []string{"6", "7", "8"},
+		},
+		{
+			name:  "resume from query parameter",
+			query: "?last_event_id=0",
+			setupMock: func(m *MockHolidayChangeService) {
+				m.On("Subscribe").Return(closedChangeFeed(), func() {})
+				m.On("GetChangesSince", int64(0), changeStreamBatchSize).Return([]models.HolidayChange{
+					{Sequence: 1, Type: models.ChangeHolidayCreated},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []string{"1"},
+		},
+		{
+			name: "live only without Last-Event-ID",
+			setupMock: func(m *MockHolidayChangeService) {
+				m.On("Subscribe").Return(closedChangeFeed(
+					models.HolidayChange{Sequence: 12, Type: models.ChangeHolidayCreated},
+				), func() {})
+			},
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []string{"12"},
+		},
+		{
+			name:           "invalid Last-Event-ID",
+			lastEventID:    "abc",
+			setupMock:      func(m *MockHolidayChangeService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockHolidayChangeService)
+			tt.setupMock(mockService)
+
+			handler := NewHolidayChangeHandler(mockService)
+
+			router := gin.New()
+			router.GET("/holidays/changes/stream", handler.StreamChanges)
+
+			req, _ := http.NewRequest("GET", "/holidays/changes/stream"+tt.query, nil)
+			if tt.lastEventID != "" {
+				req.Header.Set("Last-Event-ID", tt.lastEventID)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+
+				var ids []string
+				for _, line := range strings.Split(w.Body.String(), "\n") {
+					if id, ok := strings.CutPrefix(line, "id: "); ok {
+						ids = append(ids, id)
+					}
+				}
+				assert.Equal(t, tt.expectedIDs, ids)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
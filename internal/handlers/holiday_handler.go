@@ -1,12 +1,17 @@
 package handlers
 
 import (
+	"bytes"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 
+	"github.com/ilramdhan/holidayapi/internal/ical"
 	"github.com/ilramdhan/holidayapi/internal/models"
 	"github.com/ilramdhan/holidayapi/internal/services"
 )
@@ -33,7 +38,10 @@ func NewHolidayHandler(service services.HolidayService) *HolidayHandler {
 // @Produce json
 // @Param year query int false "Year filter"
 // @Param month query int false "Month filter (1-12)"
-// @Param type query string false "Holiday type" Enums(national, collective_leave)
+// @Param type query string false "Holiday type" Enums(national, collective_leave, regional)
+// @Param province query string false "Province code (ISO 3166-2:ID, e.g. ID-BA) to add its regional holidays"
+// @Param as_of query string false "Show the calendar as recorded at this time (RFC 3339, or YYYY-MM-DD for the end of that day)"
+// @Param status query string false "Comma-separated decree statuses (provisional, official, revised, cancelled); all but cancelled by default"
 // @Param limit query int false "Limit results (max 100)" default(50)
 // @Param offset query int false "Offset for pagination" default(0)
 // @Success 200 {object} models.HolidayResponse
@@ -41,7 +49,17 @@ func NewHolidayHandler(service services.HolidayService) *HolidayHandler {
 // @Failure 500 {object} models.ErrorResponse
 // @Router /api/v1/holidays [get]
 func (h *HolidayHandler) GetHolidays(c *gin.Context) {
-	filter := models.HolidayFilter{}
+	scope, err := parseHolidayScope(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid query parameter",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	filter := models.HolidayFilter{HolidayScope: scope}
 
 	// Parse query parameters
 	if yearStr := c.Query("year"); yearStr != "" {
@@ -58,7 +76,7 @@ func (h *HolidayHandler) GetHolidays(c *gin.Context) {
 
 	if typeStr := c.Query("type"); typeStr != "" {
 		holidayType := models.HolidayType(typeStr)
-		if holidayType == models.NationalHoliday || holidayType == models.CollectiveLeave {
+		if holidayType.IsValid() {
 			filter.Type = &holidayType
 		}
 	}
@@ -99,7 +117,10 @@ func (h *HolidayHandler) GetHolidays(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param year path int true "Year"
-// @Param type query string false "Holiday type" Enums(national, collective_leave)
+// @Param type query string false "Holiday type" Enums(national, collective_leave, regional)
+// @Param province query string false "Province code (ISO 3166-2:ID, e.g. ID-BA) to add its regional holidays"
+// @Param as_of query string false "Show the calendar as recorded at this time (RFC 3339, or YYYY-MM-DD for the end of that day)"
+// @Param status query string false "Comma-separated decree statuses (provisional, official, revised, cancelled); all but cancelled by default"
 // @Success 200 {object} models.APIResponse{data=[]models.Holiday}
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
@@ -116,7 +137,17 @@ func (h *HolidayHandler) GetHolidaysByYear(c *gin.Context) {
 		return
 	}
 
-	holidays, err := h.service.GetHolidaysByYear(year)
+	scope, err := parseHolidayScope(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid query parameter",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	holidays, err := h.service.GetHolidaysByYear(year, scope)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
@@ -129,7 +160,7 @@ func (h *HolidayHandler) GetHolidaysByYear(c *gin.Context) {
 	// Filter by type if specified
 	if typeStr := c.Query("type"); typeStr != "" {
 		holidayType := models.HolidayType(typeStr)
-		if holidayType == models.NationalHoliday || holidayType == models.CollectiveLeave {
+		if holidayType.IsValid() {
 			var filtered []models.Holiday
 			for _, holiday := range holidays {
 				if holiday.Type == holidayType {
@@ -155,7 +186,10 @@ func (h *HolidayHandler) GetHolidaysByYear(c *gin.Context) {
 // @Produce json
 // @Param year path int true "Year"
 // @Param month path int true "Month (1-12)"
-// @Param type query string false "Holiday type" Enums(national, collective_leave)
+// @Param type query string false "Holiday type" Enums(national, collective_leave, regional)
+// @Param province query string false "Province code (ISO 3166-2:ID, e.g. ID-BA) to add its regional holidays"
+// @Param as_of query string false "Show the calendar as recorded at this time (RFC 3339, or YYYY-MM-DD for the end of that day)"
+// @Param status query string false "Comma-separated decree statuses (provisional, official, revised, cancelled); all but cancelled by default"
 // @Success 200 {object} models.APIResponse{data=[]models.Holiday}
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
@@ -184,7 +218,17 @@ func (h *HolidayHandler) GetHolidaysByMonth(c *gin.Context) {
 		return
 	}
 
-	holidays, err := h.service.GetHolidaysByMonth(year, month)
+	scope, err := parseHolidayScope(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid query parameter",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	holidays, err := h.service.GetHolidaysByMonth(year, month, scope)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
@@ -197,7 +241,7 @@ func (h *HolidayHandler) GetHolidaysByMonth(c *gin.Context) {
 	// Filter by type if specified
 	if typeStr := c.Query("type"); typeStr != "" {
 		holidayType := models.HolidayType(typeStr)
-		if holidayType == models.NationalHoliday || holidayType == models.CollectiveLeave {
+		if holidayType.IsValid() {
 			var filtered []models.Holiday
 			for _, holiday := range holidays {
 				if holiday.Type == holidayType {
@@ -216,17 +260,29 @@ func (h *HolidayHandler) GetHolidaysByMonth(c *gin.Context) {
 }
 
 // GetHolidayToday godoc
-// @Summary Get today's holiday
-// @Description Get holiday for today's date if any
+// @Summary Get today's holidays
+// @Description Get every holiday on today's date, nationwide ones first. Coinciding observances are listed separately.
 // @Tags holidays
 // @Accept json
 // @Produce json
-// @Success 200 {object} models.APIResponse{data=models.Holiday}
-// @Success 204 "No holiday today"
+// @Param province query string false "Province code (ISO 3166-2:ID, e.g. ID-BA) to add its regional holidays"
+// @Param as_of query string false "Show the calendar as recorded at this time (RFC 3339, or YYYY-MM-DD for the end of that day)"
+// @Param status query string false "Comma-separated decree statuses (provisional, official, revised, cancelled); all but cancelled by default"
+// @Success 200 {object} models.APIResponse{data=[]models.Holiday}
 // @Failure 500 {object} models.ErrorResponse
 // @Router /api/v1/holidays/today [get]
 func (h *HolidayHandler) GetHolidayToday(c *gin.Context) {
-	holiday, err := h.service.GetHolidayToday()
+	scope, err := parseHolidayScope(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid query parameter",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	holidays, err := h.service.GetHolidayToday(scope)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
@@ -236,19 +292,19 @@ func (h *HolidayHandler) GetHolidayToday(c *gin.Context) {
 		return
 	}
 
-	if holiday == nil {
+	if len(holidays) == 0 {
 		c.JSON(http.StatusOK, models.APIResponse{
 			Success: true,
 			Message: "No holiday today",
-			Data:    nil,
+			Data:    []models.Holiday{},
 		})
 		return
 	}
 
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
-		Message: "Today's holiday retrieved successfully",
-		Data:    holiday,
+		Message: "Today's holidays retrieved successfully",
+		Data:    holidays,
 	})
 }
 
@@ -259,7 +315,10 @@ func (h *HolidayHandler) GetHolidayToday(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param limit query int false "Limit results" default(10)
-// @Param type query string false "Holiday type" Enums(national, collective_leave)
+// @Param type query string false "Holiday type" Enums(national, collective_leave, regional)
+// @Param province query string false "Province code (ISO 3166-2:ID, e.g. ID-BA) to add its regional holidays"
+// @Param as_of query string false "Show the calendar as recorded at this time (RFC 3339, or YYYY-MM-DD for the end of that day)"
+// @Param status query string false "Comma-separated decree statuses (provisional, official, revised, cancelled); all but cancelled by default"
 // @Success 200 {object} models.APIResponse{data=[]models.Holiday}
 // @Failure 500 {object} models.ErrorResponse
 // @Router /api/v1/holidays/upcoming [get]
@@ -271,7 +330,17 @@ func (h *HolidayHandler) GetUpcomingHolidays(c *gin.Context) {
 		}
 	}
 
-	holidays, err := h.service.GetUpcomingHolidays(limit)
+	scope, err := parseHolidayScope(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid query parameter",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	holidays, err := h.service.GetUpcomingHolidays(limit, scope)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
@@ -284,7 +353,7 @@ func (h *HolidayHandler) GetUpcomingHolidays(c *gin.Context) {
 	// Filter by type if specified
 	if typeStr := c.Query("type"); typeStr != "" {
 		holidayType := models.HolidayType(typeStr)
-		if holidayType == models.NationalHoliday || holidayType == models.CollectiveLeave {
+		if holidayType.IsValid() {
 			var filtered []models.Holiday
 			for _, holiday := range holidays {
 				if holiday.Type == holidayType {
@@ -308,12 +377,25 @@ func (h *HolidayHandler) GetUpcomingHolidays(c *gin.Context) {
 // @Tags holidays
 // @Accept json
 // @Produce json
-// @Param type query string false "Holiday type" Enums(national, collective_leave)
+// @Param type query string false "Holiday type" Enums(national, collective_leave, regional)
+// @Param province query string false "Province code (ISO 3166-2:ID, e.g. ID-BA) to add its regional holidays"
+// @Param as_of query string false "Show the calendar as recorded at this time (RFC 3339, or YYYY-MM-DD for the end of that day)"
+// @Param status query string false "Comma-separated decree statuses (provisional, official, revised, cancelled); all but cancelled by default"
 // @Success 200 {object} models.APIResponse{data=[]models.Holiday}
 // @Failure 500 {object} models.ErrorResponse
 // @Router /api/v1/holidays/this-year [get]
 func (h *HolidayHandler) GetHolidaysThisYear(c *gin.Context) {
-	holidays, err := h.service.GetHolidaysThisYear()
+	scope, err := parseHolidayScope(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid query parameter",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	holidays, err := h.service.GetHolidaysThisYear(scope)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
@@ -326,7 +408,7 @@ func (h *HolidayHandler) GetHolidaysThisYear(c *gin.Context) {
 	// Filter by type if specified
 	if typeStr := c.Query("type"); typeStr != "" {
 		holidayType := models.HolidayType(typeStr)
-		if holidayType == models.NationalHoliday || holidayType == models.CollectiveLeave {
+		if holidayType.IsValid() {
 			var filtered []models.Holiday
 			for _, holiday := range holidays {
 				if holiday.Type == holidayType {
@@ -350,12 +432,25 @@ func (h *HolidayHandler) GetHolidaysThisYear(c *gin.Context) {
 // @Tags holidays
 // @Accept json
 // @Produce json
-// @Param type query string false "Holiday type" Enums(national, collective_leave)
+// @Param type query string false "Holiday type" Enums(national, collective_leave, regional)
+// @Param province query string false "Province code (ISO 3166-2:ID, e.g. ID-BA) to add its regional holidays"
+// @Param as_of query string false "Show the calendar as recorded at this time (RFC 3339, or YYYY-MM-DD for the end of that day)"
+// @Param status query string false "Comma-separated decree statuses (provisional, official, revised, cancelled); all but cancelled by default"
 // @Success 200 {object} models.APIResponse{data=[]models.Holiday}
 // @Failure 500 {object} models.ErrorResponse
 // @Router /api/v1/holidays/this-month [get]
 func (h *HolidayHandler) GetHolidaysThisMonth(c *gin.Context) {
-	holidays, err := h.service.GetHolidaysThisMonth()
+	scope, err := parseHolidayScope(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid query parameter",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	holidays, err := h.service.GetHolidaysThisMonth(scope)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
@@ -368,7 +463,7 @@ func (h *HolidayHandler) GetHolidaysThisMonth(c *gin.Context) {
 	// Filter by type if specified
 	if typeStr := c.Query("type"); typeStr != "" {
 		holidayType := models.HolidayType(typeStr)
-		if holidayType == models.NationalHoliday || holidayType == models.CollectiveLeave {
+		if holidayType.IsValid() {
 			var filtered []models.Holiday
 			for _, holiday := range holidays {
 				if holiday.Type == holidayType {
@@ -385,3 +480,157 @@ func (h *HolidayHandler) GetHolidaysThisMonth(c *gin.Context) {
 		Data:    holidays,
 	})
 }
+
+// GetCalendarFeed godoc
+// @Summary Get holidays as an iCalendar feed
+// @Description Get holidays as an RFC 5545 (.ics) feed for Google Calendar, Outlook or Thunderbird subscriptions
+// @Tags holidays
+// @Produce text/calendar
+// @Param year query int false "Year filter"
+// @Param type query string false "Holiday type" Enums(national, collective_leave, regional)
+// @Param province query string false "Province code (ISO 3166-2:ID, e.g. ID-BA) to add its regional holidays"
+// @Param as_of query string false "Show the calendar as recorded at this time (RFC 3339, or YYYY-MM-DD for the end of that day)"
+// @Param status query string false "Comma-separated decree statuses (provisional, official, revised, cancelled); all but cancelled by default"
+// @Success 200 {string} string "iCalendar feed"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/holidays/calendar.ics [get]
+func (h *HolidayHandler) GetCalendarFeed(c *gin.Context) {
+	scope, err := parseHolidayScope(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid query parameter",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	filter := models.HolidayFilter{HolidayScope: scope}
+	calendarName := "Hari Libur Indonesia"
+	if scope.Province != nil {
+		calendarName = fmt.Sprintf("%s - %s", calendarName, models.ProvinceName(*scope.Province))
+	}
+
+	if yearStr := c.Query("year"); yearStr != "" {
+		year, err := strconv.Atoi(yearStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: "Invalid year parameter",
+				Error:   "Year must be a valid integer",
+			})
+			return
+		}
+		filter.Year = &year
+		calendarName = fmt.Sprintf("%s %d", calendarName, year)
+	}
+
+	if typeStr := c.Query("type"); typeStr != "" {
+		holidayType := models.HolidayType(typeStr)
+		if !holidayType.IsValid() {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: "Invalid type parameter",
+				Error:   "Type must be national, collective_leave or regional",
+			})
+			return
+		}
+		filter.Type = &holidayType
+	}
+
+	holidays, err := h.service.ListHolidays(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to get holidays",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	calendar := ical.Calendar{
+		Name:        calendarName,
+		Description: "Hari libur nasional dan cuti bersama Indonesia berdasarkan SKB 3 Menteri",
+		Events:      make([]ical.Event, 0, len(holidays)),
+	}
+	for _, holiday := range holidays {
+		calendar.Events = append(calendar.Events, ical.EventFromHoliday(holiday))
+	}
+
+	var buf bytes.Buffer
+	if err := ical.Write(&buf, calendar); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to render calendar",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="holidays.ics"`)
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", buf.Bytes())
+}
+
+// GetProvinces godoc
+// @Summary Get provinces
+// @Description Get the province codes accepted by the province parameter and used by regional holidays
+// @Tags holidays
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.APIResponse{data=[]models.Province}
+// @Router /api/v1/holidays/provinces [get]
+func (h *HolidayHandler) GetProvinces(c *gin.Context) {
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Provinces retrieved successfully",
+		Data:    models.Provinces,
+	})
+}
+
+// parseHolidayScope reads the optional province and as_of query parameters
+func parseHolidayScope(c *gin.Context) (models.HolidayScope, error) {
+	scope := models.HolidayScope{}
+
+	if value := c.Query("province"); value != "" {
+		code, ok := models.NormalizeProvinceCode(value)
+		if !ok {
+			return scope, fmt.Errorf("province must be an ISO 3166-2:ID code such as ID-BA, see /api/v1/holidays/provinces")
+		}
+		scope.Province = &code
+	}
+
+	if value := c.Query("as_of"); value != "" {
+		asOf, err := parseAsOf(value)
+		if err != nil {
+			return scope, err
+		}
+		scope.AsOf = &asOf
+	}
+
+	if value := c.Query("status"); value != "" {
+		for _, part := range strings.Split(value, ",") {
+			status := models.HolidayStatus(strings.TrimSpace(part))
+			if !status.IsValid() {
+				return scope, fmt.Errorf("status must be a comma-separated list of provisional, official, revised or cancelled")
+			}
+			scope.Statuses = append(scope.Statuses, status)
+		}
+	}
+
+	return scope, nil
+}
+
+// parseAsOf parses a point in time given as RFC 3339, or as YYYY-MM-DD meaning the end of that day
+func parseAsOf(value string) (time.Time, error) {
+	if asOf, err := time.Parse(time.RFC3339, value); err == nil {
+		return asOf.Local(), nil
+	}
+
+	day, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("as_of must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+	}
+	return day.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+}
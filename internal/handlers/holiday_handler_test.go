@@ -34,6 +34,11 @@ func (m *MockHolidayService) GetHolidays(filter models.HolidayFilter) (*models.H
 	return args.Get(0).(*models.HolidayResponse), args.Error(1)
 }
 
+func (m *MockHolidayService) ListHolidays(filter models.HolidayFilter) ([]models.Holiday, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]models.Holiday), args.Error(1)
+}
+
 func (m *MockHolidayService) UpdateHoliday(id int, req models.UpdateHolidayRequest) (*models.Holiday, error) {
 	args := m.Called(id, req)
 	return args.Get(0).(*models.Holiday), args.Error(1)
@@ -44,44 +49,62 @@ func (m *MockHolidayService) DeleteHoliday(id int) error {
 	return args.Error(0)
 }
 
-func (m *MockHolidayService) GetHolidaysThisYear() ([]models.Holiday, error) {
-	args := m.Called()
+func (m *MockHolidayService) GetHolidaysThisYear(scope models.HolidayScope) ([]models.Holiday, error) {
+	args := m.Called(scope)
 	return args.Get(0).([]models.Holiday), args.Error(1)
 }
 
-func (m *MockHolidayService) GetHolidaysThisMonth() ([]models.Holiday, error) {
-	args := m.Called()
+func (m *MockHolidayService) GetHolidaysThisMonth(scope models.HolidayScope) ([]models.Holiday, error) {
+	args := m.Called(scope)
 	return args.Get(0).([]models.Holiday), args.Error(1)
 }
 
-func (m *MockHolidayService) GetHolidayToday() (*models.Holiday, error) {
-	args := m.Called()
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*models.Holiday), args.Error(1)
+func (m *MockHolidayService) GetHolidayToday(scope models.HolidayScope) ([]models.Holiday, error) {
+	args := m.Called(scope)
+	return args.Get(0).([]models.Holiday), args.Error(1)
 }
 
-func (m *MockHolidayService) GetUpcomingHolidays(limit int) ([]models.Holiday, error) {
-	args := m.Called(limit)
+func (m *MockHolidayService) GetUpcomingHolidays(limit int, scope models.HolidayScope) ([]models.Holiday, error) {
+	args := m.Called(limit, scope)
 	return args.Get(0).([]models.Holiday), args.Error(1)
 }
 
-func (m *MockHolidayService) GetHolidaysByYear(year int) ([]models.Holiday, error) {
-	args := m.Called(year)
+func (m *MockHolidayService) GetHolidaysByYear(year int, scope models.HolidayScope) ([]models.Holiday, error) {
+	args := m.Called(year, scope)
 	return args.Get(0).([]models.Holiday), args.Error(1)
 }
 
-func (m *MockHolidayService) GetHolidaysByMonth(year, month int) ([]models.Holiday, error) {
-	args := m.Called(year, month)
+func (m *MockHolidayService) GetHolidaysByMonth(year, month int, scope models.HolidayScope) ([]models.Holiday, error) {
+	args := m.Called(year, month, scope)
 	return args.Get(0).([]models.Holiday), args.Error(1)
 }
 
-func (m *MockHolidayService) GetHolidaysByType(holidayType models.HolidayType) ([]models.Holiday, error) {
-	args := m.Called(holidayType)
+func (m *MockHolidayService) GetHolidaysByType(holidayType models.HolidayType, scope models.HolidayScope) ([]models.Holiday, error) {
+	args := m.Called(holidayType, scope)
 	return args.Get(0).([]models.Holiday), args.Error(1)
 }
 
+func (m *MockHolidayService) ImportHolidays(rows []models.CreateHolidayRequest, dryRun bool) (*models.HolidayImportResult, error) {
+	args := m.Called(rows, dryRun)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.HolidayImportResult), args.Error(1)
+}
+
+func (m *MockHolidayService) GetHolidayHistory(id int) ([]models.HolidayRevision, error) {
+	args := m.Called(id)
+	return args.Get(0).([]models.HolidayRevision), args.Error(1)
+}
+
+func (m *MockHolidayService) RollbackHoliday(id int, revision int) (*models.Holiday, error) {
+	args := m.Called(id, revision)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Holiday), args.Error(1)
+}
+
 func TestHolidayHandler_GetHolidayToday(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -89,27 +112,38 @@ func TestHolidayHandler_GetHolidayToday(t *testing.T) {
 		name           string
 		setupMock      func(*MockHolidayService)
 		expectedStatus int
-		expectedData   interface{}
+		expectedCount  int
 	}{
 		{
 			name: "holiday found today",
 			setupMock: func(m *MockHolidayService) {
-				holiday := &models.Holiday{
-					ID:   1,
-					Name: "Christmas",
-					Date: time.Now(),
-					Type: models.NationalHoliday,
+				holidays := []models.Holiday{
+					{ID: 1, Name: "Christmas", Date: time.Now(), Type: models.NationalHoliday},
 				}
-				m.On("GetHolidayToday").Return(holiday, nil)
+				m.On("GetHolidayToday", models.HolidayScope{}).Return(holidays, nil)
 			},
 			expectedStatus: http.StatusOK,
+			expectedCount:  1,
+		},
+		{
+			name: "coinciding holidays today",
+			setupMock: func(m *MockHolidayService) {
+				holidays := []models.Holiday{
+					{ID: 1, Name: "Hari Kemerdekaan RI", Date: time.Now(), Type: models.NationalHoliday},
+					{ID: 2, Name: "Maulid Nabi Muhammad SAW", Date: time.Now(), Type: models.NationalHoliday},
+				}
+				m.On("GetHolidayToday", models.HolidayScope{}).Return(holidays, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedCount:  2,
 		},
 		{
 			name: "no holiday today",
 			setupMock: func(m *MockHolidayService) {
-				m.On("GetHolidayToday").Return((*models.Holiday)(nil), nil)
+				m.On("GetHolidayToday", models.HolidayScope{}).Return([]models.Holiday{}, nil)
 			},
 			expectedStatus: http.StatusOK,
+			expectedCount:  0,
 		},
 	}
 
@@ -129,6 +163,13 @@ func TestHolidayHandler_GetHolidayToday(t *testing.T) {
 			router.ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response struct {
+				Data []models.Holiday `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.NotNil(t, response.Data)
+			assert.Len(t, response.Data, tt.expectedCount)
 			mockService.AssertExpectations(t)
 		})
 	}
@@ -142,7 +183,7 @@ func TestHolidayHandler_GetHolidaysByYear(t *testing.T) {
 		{ID: 1, Name: "New Year", Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
 	}
 
-	mockService.On("GetHolidaysByYear", 2024).Return(expectedHolidays, nil)
+	mockService.On("GetHolidaysByYear", 2024, models.HolidayScope{}).Return(expectedHolidays, nil)
 
 	handler := NewHolidayHandler(mockService)
 
@@ -163,3 +204,89 @@ func TestHolidayHandler_GetHolidaysByYear(t *testing.T) {
 
 	mockService.AssertExpectations(t)
 }
+
+func TestHolidayHandler_GetCalendarFeed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockHolidayService)
+	holidays := []models.Holiday{
+		{ID: 1, Name: "Tahun Baru Masehi", Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Type: models.NationalHoliday},
+	}
+
+	mockService.On("ListHolidays", mock.MatchedBy(func(filter models.HolidayFilter) bool {
+		return filter.Year != nil && *filter.Year == 2024
+	})).Return(holidays, nil)
+
+	handler := NewHolidayHandler(mockService)
+
+	router := gin.New()
+	router.GET("/holidays/calendar.ics", handler.GetCalendarFeed)
+
+	req, _ := http.NewRequest("GET", "/holidays/calendar.ics?year=2024", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/calendar")
+	assert.Contains(t, w.Body.String(), "UID:holiday-1@holidayapi")
+	mockService.AssertExpectations(t)
+}
+
+func TestHolidayHandler_GetHolidaysByYearWithProvince(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	bali := "ID-BA"
+	mockService := new(MockHolidayService)
+	mockService.On("GetHolidaysByYear", 2025, models.HolidayScope{Province: &bali}).Return([]models.Holiday{
+		{ID: 1, Name: "Hari Suci Nyepi", Date: time.Date(2025, 3, 29, 0, 0, 0, 0, time.UTC), Type: models.NationalHoliday},
+		{ID: 2, Name: "Ngembak Geni", Date: time.Date(2025, 3, 30, 0, 0, 0, 0, time.UTC), Type: models.RegionalHoliday, Province: &bali},
+	}, nil)
+
+	handler := NewHolidayHandler(mockService)
+
+	router := gin.New()
+	router.GET("/holidays/year/:year", handler.GetHolidaysByYear)
+
+	// Codes are case-insensitive and the ID- prefix is optional
+	req, _ := http.NewRequest("GET", "/holidays/year/2025?province=ba", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+
+	req, _ = http.NewRequest("GET", "/holidays/year/2025?province=XX", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestHolidayHandler_GetHolidaysByStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockHolidayService)
+	scope := models.HolidayScope{Statuses: []models.HolidayStatus{models.StatusOfficial, models.StatusRevised}}
+	mockService.On("GetHolidaysByYear", 2026, scope).Return([]models.Holiday{
+		{ID: 1, Name: "Tahun Baru Masehi", Date: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Type: models.NationalHoliday, Status: models.StatusOfficial},
+	}, nil)
+
+	handler := NewHolidayHandler(mockService)
+
+	router := gin.New()
+	router.GET("/holidays/year/:year", handler.GetHolidaysByYear)
+
+	req, _ := http.NewRequest("GET", "/holidays/year/2026?status=official,revised", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+
+	req, _ = http.NewRequest("GET", "/holidays/year/2026?status=ratified", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/services"
+)
+
+// JobHandler handles background job HTTP requests
+type JobHandler struct {
+	jobService services.JobService
+}
+
+// NewJobHandler creates a new job handler
+func NewJobHandler(jobService services.JobService) *JobHandler {
+	return &JobHandler{
+		jobService: jobService,
+	}
+}
+
+// GetJobs godoc
+// @Summary List background jobs (requires job:manage)
+// @Description List every background job with its interval, run counts, last outcome and next run
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=[]scheduler.Status}
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /api/v1/admin/jobs [get]
+func (h *JobHandler) GetJobs(c *gin.Context) {
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Jobs retrieved successfully",
+		Data:    h.jobService.ListJobs(),
+	})
+}
+
+// GetJob godoc
+// @Summary Get a background job (requires job:manage)
+// @Description Get the status of a background job by name
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Job name"
+// @Success 200 {object} models.APIResponse{data=scheduler.Status}
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/v1/admin/jobs/{name} [get]
+func (h *JobHandler) GetJob(c *gin.Context) {
+	status, err := h.jobService.GetJob(c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Message: "Job not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Job retrieved successfully",
+		Data:    status,
+	})
+}
+
+// RunJob godoc
+// @Summary Run a background job now (requires job:manage)
+// @Description Ask a background job to run without waiting for its next interval. The run happens in the background; a job that is already running finishes first.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Job name"
+// @Success 202 {object} models.APIResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/v1/admin/jobs/{name}/run [post]
+func (h *JobHandler) RunJob(c *gin.Context) {
+	triggeredBy, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.jobService.TriggerJob(c.Param("name"), triggeredBy, c.ClientIP(), c.GetHeader("User-Agent")); err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to run job",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, models.APIResponse{
+		Success: true,
+		Message: "Job run requested",
+	})
+}
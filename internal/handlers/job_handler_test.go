@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/scheduler"
+)
+
+// MockJobService is a mock implementation of JobService
+type MockJobService struct {
+	mock.Mock
+}
+
+func (m *MockJobService) AddMaintenanceJob(job scheduler.Job) {
+	m.Called(job)
+}
+
+func (m *MockJobService) ListJobs() []scheduler.Status {
+	args := m.Called()
+	return args.Get(0).([]scheduler.Status)
+}
+
+func (m *MockJobService) GetJob(name string) (*scheduler.Status, error) {
+	args := m.Called(name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*scheduler.Status), args.Error(1)
+}
+
+func (m *MockJobService) TriggerJob(name string, triggeredBy *models.User, ipAddress, userAgent string) error {
+	args := m.Called(name, triggeredBy, ipAddress, userAgent)
+	return args.Error(0)
+}
+
+func TestJobHandler_RunJob(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		job            string
+		err            error
+		expectedStatus int
+	}{
+		{name: "known job", job: "audit-retention", expectedStatus: http.StatusAccepted},
+		{name: "unknown job", job: "missing", err: fmt.Errorf("job not found"), expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockJobService)
+			mockService.On("TriggerJob", tt.job, mock.AnythingOfType("*models.User"), mock.Anything, mock.Anything).Return(tt.err)
+
+			handler := NewJobHandler(mockService)
+			router := gin.New()
+			router.POST("/admin/jobs/:name/run", withCurrentUser(1, "superadmin"), handler.RunJob)
+
+			req, _ := http.NewRequest("POST", "/admin/jobs/"+tt.job+"/run", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestJobHandler_GetJob(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockJobService)
+	mockService.On("GetJob", "token-purge").Return(&scheduler.Status{Name: "token-purge", Interval: "1h0m0s", Runs: 3}, nil)
+	mockService.On("GetJob", "missing").Return(nil, fmt.Errorf("job not found"))
+
+	handler := NewJobHandler(mockService)
+	router := gin.New()
+	router.GET("/admin/jobs/:name", handler.GetJob)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/admin/jobs/token-purge", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"runs":3`)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/admin/jobs/missing", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	mockService.AssertExpectations(t)
+}
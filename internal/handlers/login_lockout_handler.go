@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/services"
+)
+
+// LoginLockoutHandler handles failed login lock HTTP requests
+type LoginLockoutHandler struct {
+	lockoutService services.LoginLockoutService
+}
+
+// NewLoginLockoutHandler creates a new login lockout handler
+func NewLoginLockoutHandler(lockoutService services.LoginLockoutService) *LoginLockoutHandler {
+	return &LoginLockoutHandler{
+		lockoutService: lockoutService,
+	}
+}
+
+// GetLoginLocks godoc
+// @Summary List login locks (requires user:manage)
+// @Description List the usernames and client IP addresses that are locked out after too many failed logins, with when each lock ends
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=[]models.LoginLockout}
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/auth/login-locks [get]
+func (h *LoginLockoutHandler) GetLoginLocks(c *gin.Context) {
+	locks, err := h.lockoutService.ListLocks()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to get login locks",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Login locks retrieved successfully",
+		Data:    locks,
+	})
+}
+
+// ClearLoginLock godoc
+// @Summary Clear a login lock (requires user:manage)
+// @Description Lift a lock right away and forget the failed logins it counted
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Login lock ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/auth/login-locks/{id} [delete]
+func (h *LoginLockoutHandler) ClearLoginLock(c *gin.Context) {
+	id, ok := parseIDParam(c, "login lock")
+	if !ok {
+		return
+	}
+
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.lockoutService.ClearLock(id, user, c.ClientIP(), c.GetHeader("User-Agent")); err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to clear login lock",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Login lock cleared successfully",
+	})
+}
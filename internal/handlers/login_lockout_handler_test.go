@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/services"
+)
+
+// MockLoginLockoutService is a mock implementation of LoginLockoutService
+type MockLoginLockoutService struct {
+	mock.Mock
+}
+
+func (m *MockLoginLockoutService) Check(username, ipAddress string) error {
+	args := m.Called(username, ipAddress)
+	return args.Error(0)
+}
+
+func (m *MockLoginLockoutService) RecordFailure(username, ipAddress, userAgent string) {
+	m.Called(username, ipAddress, userAgent)
+}
+
+func (m *MockLoginLockoutService) RecordSuccess(username, ipAddress string) {
+	m.Called(username, ipAddress)
+}
+
+func (m *MockLoginLockoutService) ListLocks() ([]models.LoginLockout, error) {
+	args := m.Called()
+	return args.Get(0).([]models.LoginLockout), args.Error(1)
+}
+
+func (m *MockLoginLockoutService) ClearLock(id int, clearedBy *models.User, ipAddress, userAgent string) error {
+	args := m.Called(id, clearedBy, ipAddress, userAgent)
+	return args.Error(0)
+}
+
+func (m *MockLoginLockoutService) PurgeStale() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestLoginLockoutHandler_ClearLoginLock(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		id             string
+		err            error
+		expectedStatus int
+	}{
+		{name: "locked", id: "1", expectedStatus: http.StatusOK},
+		{name: "unknown lock", id: "2", err: fmt.Errorf("login lockout not found"), expectedStatus: http.StatusNotFound},
+		{name: "invalid id", id: "abc", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockLoginLockoutService)
+			mockService.On("ClearLock", mock.AnythingOfType("int"), mock.AnythingOfType("*models.User"), mock.Anything, mock.Anything).Return(tt.err)
+
+			handler := NewLoginLockoutHandler(mockService)
+			router := gin.New()
+			router.DELETE("/auth/login-locks/:id", withCurrentUser(1, "superadmin"), handler.ClearLoginLock)
+
+			req, _ := http.NewRequest("DELETE", "/auth/login-locks/"+tt.id, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestLoginFailed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name               string
+		err                error
+		expectedStatus     int
+		expectedRetryAfter string
+	}{
+		{name: "invalid credentials", err: fmt.Errorf("invalid credentials"), expectedStatus: http.StatusUnauthorized},
+		{name: "locked out", err: &services.LoginLockedError{RetryAfter: 90*time.Second + time.Millisecond}, expectedStatus: http.StatusTooManyRequests, expectedRetryAfter: "91"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			loginFailed(c, tt.err)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedRetryAfter, w.Header().Get("Retry-After"))
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/services"
+)
+
+// LongWeekendHandler handles long weekend HTTP requests
+type LongWeekendHandler struct {
+	service services.LongWeekendService
+}
+
+// NewLongWeekendHandler creates a new long weekend handler
+func NewLongWeekendHandler(service services.LongWeekendService) *LongWeekendHandler {
+	return &LongWeekendHandler{
+		service: service,
+	}
+}
+
+// GetLongWeekends godoc
+// @Summary Get long weekends and bridge days
+// @Description Get the runs of consecutive days off (weekends plus holidays) in a year that include a holiday and last at least three days,
+// @Description or that a single day of leave (hari kejepit / harpitnas) would stretch to four days or more. Each run lists those bridge days.
+// @Tags holidays
+// @Accept json
+// @Produce json
+// @Param year query int false "Year (defaults to the current year)"
+// @Param include_collective_leave query bool false "Treat collective leave (cuti bersama) as days off" default(true)
+// @Param province query string false "Province code (ISO 3166-2:ID, e.g. ID-BA) whose regional holidays are also days off"
+// @Param as_of query string false "Use the holidays as recorded at this time (RFC 3339, or YYYY-MM-DD for the end of that day)"
+// @Param status query string false "Comma-separated decree statuses (provisional, official, revised, cancelled); all but cancelled by default"
+// @Success 200 {object} models.APIResponse{data=models.LongWeekendResponse}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/holidays/long-weekends [get]
+func (h *LongWeekendHandler) GetLongWeekends(c *gin.Context) {
+	year := time.Now().Year()
+	if yearStr := c.Query("year"); yearStr != "" {
+		parsed, err := strconv.Atoi(yearStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: "Invalid year parameter",
+				Error:   "Year must be a valid integer",
+			})
+			return
+		}
+		year = parsed
+	}
+
+	opts, err := parseWorkdayOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid query parameter",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.service.FindLongWeekends(year, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to find long weekends",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Long weekends retrieved successfully",
+		Data:    response,
+	})
+}
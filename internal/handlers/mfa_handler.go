@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/services"
+)
+
+// MFAHandler handles two-factor authentication HTTP requests
+type MFAHandler struct {
+	mfaService services.MFAService
+}
+
+// NewMFAHandler creates a new MFA handler
+func NewMFAHandler(mfaService services.MFAService) *MFAHandler {
+	return &MFAHandler{
+		mfaService: mfaService,
+	}
+}
+
+// GetMFAStatus godoc
+// @Summary Get MFA status
+// @Description Get whether the current user has MFA enabled, whether their role requires it and how many recovery codes are left
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=models.MFAStatus}
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/auth/mfa [get]
+func (h *MFAHandler) GetMFAStatus(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	status, err := h.mfaService.GetStatus(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to get MFA status",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "MFA status retrieved successfully",
+		Data:    status,
+	})
+}
+
+// EnrollMFA godoc
+// @Summary Start MFA enrollment
+// @Description Generate a new authenticator secret for the current user. Add it to an authenticator app (the provisioning URI can be rendered as a QR code), then confirm it at /api/v1/auth/mfa/enable. Calling this again replaces a secret that was not confirmed yet.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=models.MFAEnrollment}
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /api/v1/auth/mfa/enroll [post]
+func (h *MFAHandler) EnrollMFA(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	enrollment, err := h.mfaService.Enroll(user.ID, c.ClientIP(), c.GetHeader("User-Agent"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "already enabled") {
+			status = http.StatusConflict
+		}
+		c.JSON(status, models.ErrorResponse{
+			Success: false,
+			Message: "MFA enrollment failed",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Scan the provisioning URI with an authenticator app, then confirm with a code",
+		Data:    enrollment,
+	})
+}
+
+// EnableMFA godoc
+// @Summary Enable MFA
+// @Description Confirm the enrolled secret with a code from the authenticator app. The response lists single-use recovery codes; they are shown only once.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code body models.MFACodeRequest true "Authenticator code"
+// @Success 200 {object} models.APIResponse{data=models.MFARecoveryCodesResponse}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /api/v1/auth/mfa/enable [post]
+func (h *MFAHandler) EnableMFA(c *gin.Context) {
+	var req models.MFACodeRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	codes, err := h.mfaService.Enable(user.ID, req, c.ClientIP(), c.GetHeader("User-Agent"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to enable MFA",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "MFA enabled successfully",
+		Data:    codes,
+	})
+}
+
+// DisableMFA godoc
+// @Summary Disable MFA
+// @Description Turn MFA off for the current user after checking their password and an authenticator or recovery code. Not allowed when the user's role requires MFA.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.MFADisableRequest true "Password and code"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /api/v1/auth/mfa/disable [post]
+func (h *MFAHandler) DisableMFA(c *gin.Context) {
+	var req models.MFADisableRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.mfaService.Disable(user.ID, req, c.ClientIP(), c.GetHeader("User-Agent")); err != nil {
+		status := http.StatusBadRequest
+		if strings.Contains(err.Error(), "required for role") {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to disable MFA",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "MFA disabled successfully",
+	})
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Regenerate MFA recovery codes
+// @Description Replace all recovery codes of the current user after checking an authenticator code. The new codes are shown only once.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code body models.MFACodeRequest true "Authenticator code"
+// @Success 200 {object} models.APIResponse{data=models.MFARecoveryCodesResponse}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /api/v1/auth/mfa/recovery-codes [post]
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req models.MFACodeRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	codes, err := h.mfaService.RegenerateRecoveryCodes(user.ID, req, c.ClientIP(), c.GetHeader("User-Agent"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to regenerate recovery codes",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Recovery codes regenerated successfully",
+		Data:    codes,
+	})
+}
+
+// ResetUserMFA godoc
+// @Summary Reset the MFA of a user (requires user:manage)
+// @Description Remove the MFA setup of a user who lost their authenticator and recovery codes. If their role requires MFA they enroll again on their next login.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/auth/users/{id}/mfa [delete]
+func (h *MFAHandler) ResetUserMFA(c *gin.Context) {
+	id, ok := parseIDParam(c, "user")
+	if !ok {
+		return
+	}
+
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.mfaService.Reset(id, user, c.ClientIP(), c.GetHeader("User-Agent")); err != nil {
+		c.JSON(userManagementStatus(err), models.ErrorResponse{
+			Success: false,
+			Message: "Failed to reset MFA",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "MFA reset successfully",
+	})
+}
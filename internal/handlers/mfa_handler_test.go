@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/repository"
+)
+
+// MockMFAService is a mock implementation of MFAService
+type MockMFAService struct {
+	mock.Mock
+}
+
+func (m *MockMFAService) GetStatus(userID int) (*models.MFAStatus, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MFAStatus), args.Error(1)
+}
+
+func (m *MockMFAService) Enroll(userID int, ipAddress, userAgent string) (*models.MFAEnrollment, error) {
+	args := m.Called(userID, ipAddress, userAgent)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MFAEnrollment), args.Error(1)
+}
+
+func (m *MockMFAService) Enable(userID int, req models.MFACodeRequest, ipAddress, userAgent string) (*models.MFARecoveryCodesResponse, error) {
+	args := m.Called(userID, req, ipAddress, userAgent)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MFARecoveryCodesResponse), args.Error(1)
+}
+
+func (m *MockMFAService) Disable(userID int, req models.MFADisableRequest, ipAddress, userAgent string) error {
+	args := m.Called(userID, req, ipAddress, userAgent)
+	return args.Error(0)
+}
+
+func (m *MockMFAService) RegenerateRecoveryCodes(userID int, req models.MFACodeRequest, ipAddress, userAgent string) (*models.MFARecoveryCodesResponse, error) {
+	args := m.Called(userID, req, ipAddress, userAgent)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MFARecoveryCodesResponse), args.Error(1)
+}
+
+func (m *MockMFAService) Reset(userID int, resetBy *models.User, ipAddress, userAgent string) error {
+	args := m.Called(userID, resetBy, ipAddress, userAgent)
+	return args.Error(0)
+}
+
+func TestMFAHandler_EnableMFA(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "valid code", body: `{"code":"123456"}`, expectedStatus: http.StatusOK},
+		{name: "code too short", body: `{"code":"12345"}`, expectedStatus: http.StatusBadRequest},
+		{name: "code not numeric", body: `{"code":"abcdef"}`, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockMFAService)
+			mockService.On("Enable", 1, models.MFACodeRequest{Code: "123456"}, mock.Anything, mock.Anything).
+				Return(&models.MFARecoveryCodesResponse{RecoveryCodes: []string{"abcde-12345"}}, nil)
+
+			handler := NewMFAHandler(mockService)
+			router := gin.New()
+			router.POST("/auth/mfa/enable", withCurrentUser(1, "admin"), handler.EnableMFA)
+
+			req, _ := http.NewRequest("POST", "/auth/mfa/enable", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Contains(t, w.Body.String(), `"recovery_codes":["abcde-12345"]`)
+			}
+		})
+	}
+}
+
+func TestMFAHandler_DisableMFARequiredByRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockMFAService)
+	mockService.On("Disable", 1, models.MFADisableRequest{Password: "secret", Code: "123456"}, mock.Anything, mock.Anything).
+		Return(fmt.Errorf("mfa is required for role super_admin"))
+
+	handler := NewMFAHandler(mockService)
+	router := gin.New()
+	router.POST("/auth/mfa/disable", withCurrentUser(1, "admin"), handler.DisableMFA)
+
+	req, _ := http.NewRequest("POST", "/auth/mfa/disable", bytes.NewBufferString(`{"password":"secret","code":"123456"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestMFAHandler_ResetUserMFA(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		id             string
+		err            error
+		expectedStatus int
+	}{
+		{name: "enrolled user", id: "2", expectedStatus: http.StatusOK},
+		{name: "not enrolled", id: "3", err: repository.ErrMFANotFound, expectedStatus: http.StatusNotFound},
+		{name: "invalid id", id: "abc", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockMFAService)
+			mockService.On("Reset", mock.AnythingOfType("int"), mock.AnythingOfType("*models.User"), mock.Anything, mock.Anything).Return(tt.err)
+
+			handler := NewMFAHandler(mockService)
+			router := gin.New()
+			router.DELETE("/auth/users/:id/mfa", withCurrentUser(1, "superadmin"), handler.ResetUserMFA)
+
+			req, _ := http.NewRequest("DELETE", "/auth/users/"+tt.id+"/mfa", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
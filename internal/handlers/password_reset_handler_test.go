@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+// MockPasswordResetService is a mock implementation of PasswordResetService
+type MockPasswordResetService struct {
+	mock.Mock
+}
+
+func (m *MockPasswordResetService) ForgotPassword(ctx context.Context, req models.ForgotPasswordRequest, ipAddress, userAgent string) error {
+	args := m.Called(req, ipAddress, userAgent)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetService) ResetPassword(req models.ResetPasswordRequest, ipAddress, userAgent string) error {
+	args := m.Called(req, ipAddress, userAgent)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetService) PurgeExpiredTokens() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestPasswordResetHandler_ForgotPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "valid email", body: `{"email":"editor@example.com"}`, expectedStatus: http.StatusOK},
+		{name: "invalid email", body: `{"email":"editor"}`, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockPasswordResetService)
+			mockService.On("ForgotPassword", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+			handler := NewPasswordResetHandler(mockService)
+			router := gin.New()
+			router.POST("/auth/forgot-password", handler.ForgotPassword)
+
+			req, _ := http.NewRequest("POST", "/auth/forgot-password", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestPasswordResetHandler_ResetPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "valid token", expectedStatus: http.StatusOK},
+		{name: "expired token", err: fmt.Errorf("invalid or expired password reset token"), expectedStatus: http.StatusBadRequest},
+		{name: "database error", err: fmt.Errorf("failed to reset password: connection lost"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockPasswordResetService)
+			mockService.On("ResetPassword", models.ResetPasswordRequest{Token: "token", NewPassword: "NewPass123!"}, mock.Anything, mock.Anything).Return(tt.err)
+
+			handler := NewPasswordResetHandler(mockService)
+			router := gin.New()
+			router.POST("/auth/reset-password", handler.ResetPassword)
+
+			req, _ := http.NewRequest("POST", "/auth/reset-password", bytes.NewBufferString(`{"token":"token","new_password":"NewPass123!"}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,401 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/services"
+)
+
+// ReminderHandler handles reminder rule and distribution list HTTP requests
+type ReminderHandler struct {
+	reminderService services.ReminderService
+}
+
+// NewReminderHandler creates a new reminder handler
+func NewReminderHandler(reminderService services.ReminderService) *ReminderHandler {
+	return &ReminderHandler{
+		reminderService: reminderService,
+	}
+}
+
+// CreateReminder godoc
+// @Summary Add a reminder rule (requires reminder:manage)
+// @Description Email recipients about upcoming holidays. days_before rules announce holidays exactly that many days ahead; next_week rules announce, on send_weekday (0 = Sunday, default Friday), the holidays of the following Monday to Sunday. Recipients are email addresses or distribution list names.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param reminder body models.CreateReminderRuleRequest true "Reminder rule"
+// @Success 201 {object} models.APIResponse{data=models.ReminderRule}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/reminders [post]
+func (h *ReminderHandler) CreateReminder(c *gin.Context) {
+	var req models.CreateReminderRuleRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	createdBy, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	rule, err := h.reminderService.CreateRule(req, createdBy, c.ClientIP(), c.GetHeader("User-Agent"))
+	if err != nil {
+		c.JSON(reminderErrorStatus(err), models.ErrorResponse{
+			Success: false,
+			Message: "Failed to create reminder",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Reminder created successfully",
+		Data:    rule,
+	})
+}
+
+// GetReminders godoc
+// @Summary List reminder rules (requires reminder:manage)
+// @Description List all reminder rules with the day each was last handled
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=[]models.ReminderRule}
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/reminders [get]
+func (h *ReminderHandler) GetReminders(c *gin.Context) {
+	rules, err := h.reminderService.ListRules()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to get reminders",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Reminders retrieved successfully",
+		Data:    rules,
+	})
+}
+
+// GetReminder godoc
+// @Summary Get a reminder rule (requires reminder:manage)
+// @Description Get a reminder rule by ID
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Reminder ID"
+// @Success 200 {object} models.APIResponse{data=models.ReminderRule}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/reminders/{id} [get]
+func (h *ReminderHandler) GetReminder(c *gin.Context) {
+	id, ok := parseIDParam(c, "reminder")
+	if !ok {
+		return
+	}
+
+	rule, err := h.reminderService.GetRule(id)
+	if err != nil {
+		c.JSON(reminderErrorStatus(err), models.ErrorResponse{
+			Success: false,
+			Message: "Failed to get reminder",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Reminder retrieved successfully",
+		Data:    rule,
+	})
+}
+
+// UpdateReminder godoc
+// @Summary Update a reminder rule (requires reminder:manage)
+// @Description Change a reminder rule, or pause it with is_active=false
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Reminder ID"
+// @Param reminder body models.UpdateReminderRuleRequest true "Fields to change"
+// @Success 200 {object} models.APIResponse{data=models.ReminderRule}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/reminders/{id} [put]
+func (h *ReminderHandler) UpdateReminder(c *gin.Context) {
+	id, ok := parseIDParam(c, "reminder")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateReminderRuleRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	updatedBy, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	rule, err := h.reminderService.UpdateRule(id, req, updatedBy, c.ClientIP(), c.GetHeader("User-Agent"))
+	if err != nil {
+		c.JSON(reminderErrorStatus(err), models.ErrorResponse{
+			Success: false,
+			Message: "Failed to update reminder",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Reminder updated successfully",
+		Data:    rule,
+	})
+}
+
+// DeleteReminder godoc
+// @Summary Delete a reminder rule (requires reminder:manage)
+// @Description Delete a reminder rule
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Reminder ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/reminders/{id} [delete]
+func (h *ReminderHandler) DeleteReminder(c *gin.Context) {
+	id, ok := parseIDParam(c, "reminder")
+	if !ok {
+		return
+	}
+
+	deletedBy, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.reminderService.DeleteRule(id, deletedBy, c.ClientIP(), c.GetHeader("User-Agent")); err != nil {
+		c.JSON(reminderErrorStatus(err), models.ErrorResponse{
+			Success: false,
+			Message: "Failed to delete reminder",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Reminder deleted successfully",
+	})
+}
+
+// CreateDistributionList godoc
+// @Summary Add a distribution list (requires reminder:manage)
+// @Description Create a named list of email addresses that reminder rules can send to
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param list body models.DistributionListRequest true "Distribution list"
+// @Success 201 {object} models.APIResponse{data=models.DistributionList}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/distribution-lists [post]
+func (h *ReminderHandler) CreateDistributionList(c *gin.Context) {
+	var req models.DistributionListRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	createdBy, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	list, err := h.reminderService.CreateList(req, createdBy, c.ClientIP(), c.GetHeader("User-Agent"))
+	if err != nil {
+		c.JSON(reminderErrorStatus(err), models.ErrorResponse{
+			Success: false,
+			Message: "Failed to create distribution list",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Distribution list created successfully",
+		Data:    list,
+	})
+}
+
+// GetDistributionLists godoc
+// @Summary List distribution lists (requires reminder:manage)
+// @Description List all distribution lists with their members
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=[]models.DistributionList}
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/distribution-lists [get]
+func (h *ReminderHandler) GetDistributionLists(c *gin.Context) {
+	lists, err := h.reminderService.ListLists()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to get distribution lists",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Distribution lists retrieved successfully",
+		Data:    lists,
+	})
+}
+
+// UpdateDistributionList godoc
+// @Summary Replace a distribution list (requires reminder:manage)
+// @Description Replace the name and members of a distribution list. A list cannot be renamed while reminder rules send to it.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Distribution list ID"
+// @Param list body models.DistributionListRequest true "Distribution list"
+// @Success 200 {object} models.APIResponse{data=models.DistributionList}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/distribution-lists/{id} [put]
+func (h *ReminderHandler) UpdateDistributionList(c *gin.Context) {
+	id, ok := parseIDParam(c, "distribution list")
+	if !ok {
+		return
+	}
+
+	var req models.DistributionListRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	updatedBy, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	list, err := h.reminderService.UpdateList(id, req, updatedBy, c.ClientIP(), c.GetHeader("User-Agent"))
+	if err != nil {
+		c.JSON(reminderErrorStatus(err), models.ErrorResponse{
+			Success: false,
+			Message: "Failed to update distribution list",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Distribution list updated successfully",
+		Data:    list,
+	})
+}
+
+// DeleteDistributionList godoc
+// @Summary Delete a distribution list (requires reminder:manage)
+// @Description Delete a distribution list that no reminder rule sends to
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Distribution list ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/distribution-lists/{id} [delete]
+func (h *ReminderHandler) DeleteDistributionList(c *gin.Context) {
+	id, ok := parseIDParam(c, "distribution list")
+	if !ok {
+		return
+	}
+
+	deletedBy, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.reminderService.DeleteList(id, deletedBy, c.ClientIP(), c.GetHeader("User-Agent")); err != nil {
+		c.JSON(reminderErrorStatus(err), models.ErrorResponse{
+			Success: false,
+			Message: "Failed to delete distribution list",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Distribution list deleted successfully",
+	})
+}
+
+// reminderErrorStatus maps a reminder service error to an HTTP status
+func reminderErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "invalid"):
+		return http.StatusBadRequest
+	case strings.Contains(err.Error(), "not found"):
+		return http.StatusNotFound
+	case strings.Contains(err.Error(), "already exists"), strings.Contains(err.Error(), "in use"):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+// MockReminderService is a mock implementation of ReminderService
+type MockReminderService struct {
+	mock.Mock
+}
+
+func (m *MockReminderService) CreateRule(req models.CreateReminderRuleRequest, createdBy *models.User, ipAddress, userAgent string) (*models.ReminderRule, error) {
+	args := m.Called(req, createdBy, ipAddress, userAgent)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReminderRule), args.Error(1)
+}
+
+func (m *MockReminderService) ListRules() ([]models.ReminderRule, error) {
+	args := m.Called()
+	return args.Get(0).([]models.ReminderRule), args.Error(1)
+}
+
+func (m *MockReminderService) GetRule(id int) (*models.ReminderRule, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReminderRule), args.Error(1)
+}
+
+func (m *MockReminderService) UpdateRule(id int, req models.UpdateReminderRuleRequest, updatedBy *models.User, ipAddress, userAgent string) (*models.ReminderRule, error) {
+	args := m.Called(id, req, updatedBy, ipAddress, userAgent)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReminderRule), args.Error(1)
+}
+
+func (m *MockReminderService) DeleteRule(id int, deletedBy *models.User, ipAddress, userAgent string) error {
+	args := m.Called(id, deletedBy, ipAddress, userAgent)
+	return args.Error(0)
+}
+
+func (m *MockReminderService) CreateList(req models.DistributionListRequest, createdBy *models.User, ipAddress, userAgent string) (*models.DistributionList, error) {
+	args := m.Called(req, createdBy, ipAddress, userAgent)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DistributionList), args.Error(1)
+}
+
+func (m *MockReminderService) ListLists() ([]models.DistributionList, error) {
+	args := m.Called()
+	return args.Get(0).([]models.DistributionList), args.Error(1)
+}
+
+func (m *MockReminderService) UpdateList(id int, req models.DistributionListRequest, updatedBy *models.User, ipAddress, userAgent string) (*models.DistributionList, error) {
+	args := m.Called(id, req, updatedBy, ipAddress, userAgent)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DistributionList), args.Error(1)
+}
+
+func (m *MockReminderService) DeleteList(id int, deletedBy *models.User, ipAddress, userAgent string) error {
+	args := m.Called(id, deletedBy, ipAddress, userAgent)
+	return args.Error(0)
+}
+
+func (m *MockReminderService) SendDue(ctx context.Context, now time.Time) (int, error) {
+	args := m.Called(ctx, now)
+	return args.Int(0), args.Error(1)
+}
+
+func TestReminderHandler_CreateReminder(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*MockReminderService)
+		expectedStatus int
+	}{
+		{
+			name: "days before rule",
+			body: `{"name":"Holiday in 7 days","kind":"days_before","days_before":7,"recipients":["all-staff"]}`,
+			setupMock: func(m *MockReminderService) {
+				m.On("CreateRule", mock.AnythingOfType("models.CreateReminderRuleRequest"), mock.AnythingOfType("*models.User"), mock.Anything, mock.Anything).
+					Return(&models.ReminderRule{ID: 1, Name: "Holiday in 7 days", Kind: models.ReminderDaysBefore, DaysBefore: 7}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "unknown kind",
+			body:           `{"name":"Monthly","kind":"monthly","recipients":["hr@example.com"]}`,
+			setupMock:      func(m *MockReminderService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "no recipients",
+			body:           `{"name":"Holiday in 7 days","kind":"days_before","days_before":7,"recipients":[]}`,
+			setupMock:      func(m *MockReminderService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "unknown distribution list",
+			body: `{"name":"Holiday in 7 days","kind":"days_before","days_before":7,"recipients":["nobody"]}`,
+			setupMock: func(m *MockReminderService) {
+				m.On("CreateRule", mock.AnythingOfType("models.CreateReminderRuleRequest"), mock.AnythingOfType("*models.User"), mock.Anything, mock.Anything).
+					Return(nil, fmt.Errorf(`invalid recipient "nobody", no distribution list has that name`))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockReminderService)
+			tt.setupMock(mockService)
+
+			handler := NewReminderHandler(mockService)
+			router := gin.New()
+			router.POST("/admin/reminders", withCurrentUser(1, "superadmin"), handler.CreateReminder)
+
+			req, _ := http.NewRequest("POST", "/admin/reminders", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReminderHandler_DeleteDistributionListInUse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockReminderService)
+	mockService.On("DeleteList", 2, mock.AnythingOfType("*models.User"), mock.Anything, mock.Anything).
+		Return(fmt.Errorf(`distribution list "all-staff" is in use by reminder "Holiday in 7 days"`))
+
+	handler := NewReminderHandler(mockService)
+	router := gin.New()
+	router.DELETE("/admin/distribution-lists/:id", withCurrentUser(1, "superadmin"), handler.DeleteDistributionList)
+
+	req, _ := http.NewRequest("DELETE", "/admin/distribution-lists/2", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockService.AssertExpectations(t)
+}
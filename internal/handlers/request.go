@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/ilramdhan/holidayapi/internal/middleware"
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+// requestValidator validates the request bodies bound by bindJSON
+var requestValidator = validator.New()
+
+// bindJSON binds the JSON request body into req and validates it, writing a 400 response when either fails
+func bindJSON(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return false
+	}
+
+	if err := requestValidator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Validation failed",
+			Error:   err.Error(),
+		})
+		return false
+	}
+
+	return true
+}
+
+// currentUser returns the authenticated user, writing a 401 response when there is none
+func currentUser(c *gin.Context) (*models.User, bool) {
+	user, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Message: "Unauthorized",
+			Error:   err.Error(),
+		})
+		return nil, false
+	}
+
+	return &models.User{
+		ID:       user.UserID,
+		Username: user.Username,
+		Role:     user.Role,
+	}, true
+}
+
+// parseIDParam reads the id path parameter, writing a 400 response naming the resource when it is invalid
+func parseIDParam(c *gin.Context, resource string) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid " + resource + " ID",
+			Error:   "ID must be a valid integer",
+		})
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/services"
+)
+
+// RoleHandler handles role and permission HTTP requests
+type RoleHandler struct {
+	roleService services.RoleService
+}
+
+// NewRoleHandler creates a new role handler
+func NewRoleHandler(roleService services.RoleService) *RoleHandler {
+	return &RoleHandler{
+		roleService: roleService,
+	}
+}
+
+// GetPermissions godoc
+// @Summary List permissions (requires role:manage)
+// @Description List every permission a role can grant
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=[]string}
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /api/v1/auth/permissions [get]
+func (h *RoleHandler) GetPermissions(c *gin.Context) {
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Permissions retrieved successfully",
+		Data:    h.roleService.ListPermissions(),
+	})
+}
+
+// GetRoles godoc
+// @Summary List roles (requires role:manage)
+// @Description List the built-in and custom roles with the permissions they grant
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=[]models.Role}
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/auth/roles [get]
+func (h *RoleHandler) GetRoles(c *gin.Context) {
+	roles, err := h.roleService.ListRoles()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to get roles",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Roles retrieved successfully",
+		Data:    roles,
+	})
+}
+
+// GetRole godoc
+// @Summary Get a role (requires role:manage)
+// @Description Get a role by ID
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Role ID"
+// @Success 200 {object} models.APIResponse{data=models.Role}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/auth/roles/{id} [get]
+func (h *RoleHandler) GetRole(c *gin.Context) {
+	id, ok := parseIDParam(c, "role")
+	if !ok {
+		return
+	}
+
+	role, err := h.roleService.GetRole(id)
+	if err != nil {
+		c.JSON(roleErrorStatus(err), models.ErrorResponse{
+			Success: false,
+			Message: "Failed to get role",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Role retrieved successfully",
+		Data:    role,
+	})
+}
+
+// CreateRole godoc
+// @Summary Create a custom role (requires role:manage)
+// @Description Define a role that grants the given permissions. Only permissions the caller holds can be granted.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param role body models.CreateRoleRequest true "Role data"
+// @Success 201 {object} models.APIResponse{data=models.Role}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/auth/roles [post]
+func (h *RoleHandler) CreateRole(c *gin.Context) {
+	var req models.CreateRoleRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	createdBy, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	role, err := h.roleService.CreateRole(req, createdBy, c.ClientIP(), c.GetHeader("User-Agent"))
+	if err != nil {
+		c.JSON(roleErrorStatus(err), models.ErrorResponse{
+			Success: false,
+			Message: "Failed to create role",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Role created successfully",
+		Data:    role,
+	})
+}
+
+// UpdateRole godoc
+// @Summary Update a role (requires role:manage)
+// @Description Change the description or permissions of a role. Users of the role get the new permissions when they next log in or refresh their token.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Role ID"
+// @Param role body models.UpdateRoleRequest true "Fields to change"
+// @Success 200 {object} models.APIResponse{data=models.Role}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/auth/roles/{id} [patch]
+func (h *RoleHandler) UpdateRole(c *gin.Context) {
+	id, ok := parseIDParam(c, "role")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateRoleRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	updatedBy, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	role, err := h.roleService.UpdateRole(id, req, updatedBy, c.ClientIP(), c.GetHeader("User-Agent"))
+	if err != nil {
+		c.JSON(roleErrorStatus(err), models.ErrorResponse{
+			Success: false,
+			Message: "Failed to update role",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Role updated successfully",
+		Data:    role,
+	})
+}
+
+// DeleteRole godoc
+// @Summary Delete a custom role (requires role:manage)
+// @Description Delete a custom role. Built-in roles and roles still assigned to users cannot be deleted.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Role ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/auth/roles/{id} [delete]
+func (h *RoleHandler) DeleteRole(c *gin.Context) {
+	id, ok := parseIDParam(c, "role")
+	if !ok {
+		return
+	}
+
+	deletedBy, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.roleService.DeleteRole(id, deletedBy, c.ClientIP(), c.GetHeader("User-Agent")); err != nil {
+		c.JSON(roleErrorStatus(err), models.ErrorResponse{
+			Success: false,
+			Message: "Failed to delete role",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Role deleted successfully",
+	})
+}
+
+// roleErrorStatus maps a role service error to an HTTP status
+func roleErrorStatus(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(message, "which you do not have"):
+		return http.StatusForbidden
+	case strings.Contains(message, "already exists"), strings.Contains(message, "still assigned"):
+		return http.StatusConflict
+	case strings.HasPrefix(message, "failed to"):
+		return http.StatusInternalServerError
+	default:
+		return http.StatusBadRequest
+	}
+}
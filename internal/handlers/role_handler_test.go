@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ilramdhan/holidayapi/internal/middleware"
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+// MockRoleService is a mock implementation of RoleService
+type MockRoleService struct {
+	mock.Mock
+}
+
+func (m *MockRoleService) ListPermissions() []models.Permission {
+	args := m.Called()
+	return args.Get(0).([]models.Permission)
+}
+
+func (m *MockRoleService) ListRoles() ([]models.Role, error) {
+	args := m.Called()
+	return args.Get(0).([]models.Role), args.Error(1)
+}
+
+func (m *MockRoleService) GetRole(id int) (*models.Role, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Role), args.Error(1)
+}
+
+func (m *MockRoleService) CreateRole(req models.CreateRoleRequest, createdBy *models.User, ipAddress, userAgent string) (*models.Role, error) {
+	args := m.Called(req, createdBy, ipAddress, userAgent)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Role), args.Error(1)
+}
+
+func (m *MockRoleService) UpdateRole(id int, req models.UpdateRoleRequest, updatedBy *models.User, ipAddress, userAgent string) (*models.Role, error) {
+	args := m.Called(id, req, updatedBy, ipAddress, userAgent)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Role), args.Error(1)
+}
+
+func (m *MockRoleService) DeleteRole(id int, deletedBy *models.User, ipAddress, userAgent string) error {
+	args := m.Called(id, deletedBy, ipAddress, userAgent)
+	return args.Error(0)
+}
+
+func TestRoleHandler_CreateRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "created", body: `{"name":"auditor","permissions":["audit:read"]}`, expectedStatus: http.StatusCreated},
+		{name: "no permissions", body: `{"name":"auditor","permissions":[]}`, expectedStatus: http.StatusBadRequest},
+		{name: "existing name", body: `{"name":"admin","permissions":["audit:read"]}`, err: fmt.Errorf("role already exists"), expectedStatus: http.StatusConflict},
+		{name: "beyond own permissions", body: `{"name":"manager","permissions":["user:manage"]}`, err: fmt.Errorf("cannot grant the user:manage permission, which you do not have"), expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockRoleService)
+			var role *models.Role
+			if tt.err == nil {
+				role = &models.Role{ID: 3, Name: "auditor", Permissions: []models.Permission{models.PermissionAuditRead}}
+			}
+			mockService.On("CreateRole", mock.AnythingOfType("models.CreateRoleRequest"), mock.AnythingOfType("*models.User"), mock.Anything, mock.Anything).Return(role, tt.err)
+
+			handler := NewRoleHandler(mockService)
+			router := gin.New()
+			router.POST("/auth/roles", withCurrentUser(1, "superadmin"), handler.CreateRole)
+
+			req, _ := http.NewRequest("POST", "/auth/roles", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestRoleHandler_DeleteRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "deleted", expectedStatus: http.StatusOK},
+		{name: "unknown role", err: fmt.Errorf("role not found"), expectedStatus: http.StatusNotFound},
+		{name: "built-in role", err: fmt.Errorf("built-in roles cannot be deleted"), expectedStatus: http.StatusBadRequest},
+		{name: "role in use", err: fmt.Errorf("role is still assigned to 2 users"), expectedStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockRoleService)
+			mockService.On("DeleteRole", 3, mock.AnythingOfType("*models.User"), mock.Anything, mock.Anything).Return(tt.err)
+
+			handler := NewRoleHandler(mockService)
+			router := gin.New()
+			router.DELETE("/auth/roles/:id", withCurrentUser(1, "superadmin"), handler.DeleteRole)
+
+			req, _ := http.NewRequest("DELETE", "/auth/roles/3", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		permissions    []models.Permission
+		expectedStatus int
+	}{
+		{name: "granted", permissions: []models.Permission{models.PermissionHolidayRead, models.PermissionHolidayDelete}, expectedStatus: http.StatusOK},
+		{name: "missing", permissions: []models.Permission{models.PermissionHolidayRead}, expectedStatus: http.StatusForbidden},
+		{name: "token without permissions", permissions: nil, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.DELETE("/admin/holidays/:id",
+				func(c *gin.Context) { c.Set("user_permissions", tt.permissions) },
+				middleware.RequirePermission(models.PermissionHolidayDelete),
+				func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req, _ := http.NewRequest("DELETE", "/admin/holidays/1", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
@@ -6,18 +6,21 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 
 	"github.com/ilramdhan/holidayapi/internal/config"
+	"github.com/ilramdhan/holidayapi/internal/metrics"
 	"github.com/ilramdhan/holidayapi/internal/middleware"
+	"github.com/ilramdhan/holidayapi/internal/models"
 	"github.com/ilramdhan/holidayapi/internal/services"
 )
 
 // SetupRouter sets up the HTTP router with all routes and middleware
-func SetupRouter(cfg *config.Config, holidayService services.HolidayService, authService services.AuthService, jwtService services.JWTService, auditService services.AuditService) *gin.Engine {
+func SetupRouter(cfg *config.Config, holidayService services.HolidayService, authService services.AuthService, jwtService services.JWTService, auditService services.AuditService, workdayService services.WorkdayService, apiKeyService services.APIKeyService, longWeekendService services.LongWeekendService, forecastService services.ForecastService, holidayChangeService services.HolidayChangeService, webhookService services.WebhookService, reminderService services.ReminderService, jobService services.JobService, mfaService services.MFAService, lockoutService services.LoginLockoutService, passwordResetService services.PasswordResetService, roleService services.RoleService, rateLimiter *middleware.RateLimiter) *gin.Engine {
 	// Set Gin mode
 	gin.SetMode(gin.ReleaseMode)
 
 	router := gin.New()
 
 	// Global middleware
+	router.Use(middleware.MetricsMiddleware())
 	router.Use(middleware.SecurityHeadersMiddleware())
 	router.Use(middleware.LoggingMiddleware())
 	router.Use(middleware.RequestIDMiddleware())
@@ -28,14 +31,25 @@ func SetupRouter(cfg *config.Config, holidayService services.HolidayService, aut
 	router.Use(gin.Recovery())
 
 	// Rate limiting
-	rateLimiter := middleware.NewRateLimiter(cfg.RateLimit.RequestsPerMinute, cfg.RateLimit.BurstSize)
 	router.Use(rateLimiter.RateLimitMiddleware())
 
 	// Initialize handlers
 	holidayHandler := NewHolidayHandler(holidayService)
-	adminHandler := NewAdminHandler(holidayService)
+	adminHandler := NewAdminHandler(holidayService, auditService)
 	authHandler := NewAuthHandler(authService)
 	auditHandler := NewAuditHandler(auditService)
+	workdayHandler := NewWorkdayHandler(workdayService)
+	apiKeyHandler := NewAPIKeyHandler(apiKeyService)
+	longWeekendHandler := NewLongWeekendHandler(longWeekendService)
+	forecastHandler := NewForecastHandler(forecastService)
+	holidayChangeHandler := NewHolidayChangeHandler(holidayChangeService)
+	webhookHandler := NewWebhookHandler(webhookService)
+	reminderHandler := NewReminderHandler(reminderService)
+	jobHandler := NewJobHandler(jobService)
+	mfaHandler := NewMFAHandler(mfaService)
+	lockoutHandler := NewLoginLockoutHandler(lockoutService)
+	passwordResetHandler := NewPasswordResetHandler(passwordResetService)
+	roleHandler := NewRoleHandler(roleService)
 
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
@@ -46,6 +60,9 @@ func SetupRouter(cfg *config.Config, holidayService services.HolidayService, aut
 		})
 	})
 
+	// Prometheus metrics
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+
 	// API v1 routes
 	v1 := router.Group("/api/v1")
 	{
@@ -53,20 +70,56 @@ func SetupRouter(cfg *config.Config, holidayService services.HolidayService, aut
 		auth := v1.Group("/auth")
 		{
 			auth.POST("/login", authHandler.Login)
+			auth.POST("/login/mfa", authHandler.VerifyMFALogin)
+			auth.POST("/login/mfa/enroll", authHandler.EnrollMFALogin)
 			auth.POST("/refresh", authHandler.RefreshToken)
+			auth.POST("/forgot-password", passwordResetHandler.ForgotPassword)
+			auth.POST("/reset-password", passwordResetHandler.ResetPassword)
 
 			// Protected auth endpoints
 			authProtected := auth.Group("")
 			authProtected.Use(middleware.JWTAuthMiddleware(jwtService))
 			{
 				authProtected.GET("/profile", authHandler.GetProfile)
+				authProtected.POST("/logout", authHandler.Logout)
 				authProtected.POST("/change-password", authHandler.ChangePassword)
 				authProtected.GET("/audit-logs", auditHandler.GetMyAuditLogs)
 
-				// Super admin only endpoints
-				authProtected.POST("/register", middleware.RequireSuperAdmin(), authHandler.Register)
-				authProtected.GET("/users", middleware.RequireAdminOrSuperAdmin(), authHandler.GetAllUsers)
-				authProtected.DELETE("/users/:id", middleware.RequireSuperAdmin(), authHandler.DeleteUser)
+				// Two-factor authentication
+				authProtected.GET("/mfa", mfaHandler.GetMFAStatus)
+				authProtected.POST("/mfa/enroll", mfaHandler.EnrollMFA)
+				authProtected.POST("/mfa/enable", mfaHandler.EnableMFA)
+				authProtected.POST("/mfa/disable", mfaHandler.DisableMFA)
+				authProtected.POST("/mfa/recovery-codes", mfaHandler.RegenerateRecoveryCodes)
+
+				// User management
+				manageUsers := middleware.RequirePermission(models.PermissionUserManage)
+				authProtected.POST("/register", manageUsers, authHandler.Register)
+				authProtected.GET("/users", middleware.RequirePermission(models.PermissionUserRead), authHandler.GetAllUsers)
+				authProtected.GET("/users/:id", manageUsers, authHandler.GetUser)
+				authProtected.PATCH("/users/:id", manageUsers, authHandler.UpdateUser)
+				authProtected.DELETE("/users/:id", manageUsers, authHandler.DeleteUser)
+				authProtected.POST("/users/:id/reactivate", manageUsers, authHandler.ReactivateUser)
+				authProtected.POST("/users/:id/reset-password", manageUsers, authHandler.ResetUserPassword)
+				authProtected.POST("/users/:id/revoke-sessions", manageUsers, authHandler.RevokeUserSessions)
+				authProtected.DELETE("/users/:id/mfa", manageUsers, mfaHandler.ResetUserMFA)
+				authProtected.GET("/login-locks", manageUsers, lockoutHandler.GetLoginLocks)
+				authProtected.DELETE("/login-locks/:id", manageUsers, lockoutHandler.ClearLoginLock)
+
+				// Roles and permissions
+				manageRoles := middleware.RequirePermission(models.PermissionRoleManage)
+				authProtected.GET("/permissions", manageRoles, roleHandler.GetPermissions)
+				authProtected.GET("/roles", manageRoles, roleHandler.GetRoles)
+				authProtected.POST("/roles", manageRoles, roleHandler.CreateRole)
+				authProtected.GET("/roles/:id", manageRoles, roleHandler.GetRole)
+				authProtected.PATCH("/roles/:id", manageRoles, roleHandler.UpdateRole)
+				authProtected.DELETE("/roles/:id", manageRoles, roleHandler.DeleteRole)
+
+				// Client API keys
+				manageAPIKeys := middleware.RequirePermission(models.PermissionAPIKeyManage)
+				authProtected.GET("/api-keys", manageAPIKeys, apiKeyHandler.GetAPIKeys)
+				authProtected.POST("/api-keys", manageAPIKeys, apiKeyHandler.CreateAPIKey)
+				authProtected.DELETE("/api-keys/:id", manageAPIKeys, apiKeyHandler.RevokeAPIKey)
 			}
 		}
 
@@ -80,22 +133,77 @@ func SetupRouter(cfg *config.Config, holidayService services.HolidayService, aut
 			holidays.GET("/upcoming", holidayHandler.GetUpcomingHolidays)
 			holidays.GET("/this-year", holidayHandler.GetHolidaysThisYear)
 			holidays.GET("/this-month", holidayHandler.GetHolidaysThisMonth)
+			holidays.GET("/long-weekends", longWeekendHandler.GetLongWeekends)
+			holidays.GET("/forecast", forecastHandler.GetForecast)
+			holidays.GET("/calendar.ics", holidayHandler.GetCalendarFeed)
+			holidays.GET("/provinces", holidayHandler.GetProvinces)
+			holidays.GET("/changes/stream", holidayChangeHandler.StreamChanges)
 		}
 
-		// Admin endpoints (JWT protected)
+		// Public working-day endpoints
+		workdays := v1.Group("/workdays")
+		{
+			workdays.GET("/count", workdayHandler.CountWorkdays)
+			workdays.GET("/add", workdayHandler.AddWorkdays)
+			workdays.GET("/next", workdayHandler.NextWorkday)
+		}
+
+		// Admin endpoints (JWT or API key protected)
 		admin := v1.Group("/admin")
-		admin.Use(middleware.JWTAuthMiddleware(jwtService))
-		admin.Use(middleware.RequireAdminOrSuperAdmin())
+		admin.Use(middleware.AuthMiddleware(jwtService, apiKeyService))
 		{
+			readHolidays := middleware.RequireScope(models.ScopeReadHolidays)
+			writeHolidays := middleware.RequireScope(models.ScopeWriteHolidays)
+			readAudit := middleware.RequireScope(models.ScopeReadAudit)
+			webhookScope := middleware.RequireScope(models.ScopeManageWebhooks)
+			reminderScope := middleware.RequireScope(models.ScopeManageReminders)
+			jobScope := middleware.RequireScope(models.ScopeManageJobs)
+
 			// Holiday management
-			admin.POST("/holidays", adminHandler.CreateHoliday)
-			admin.GET("/holidays/:id", adminHandler.GetHoliday)
-			admin.PUT("/holidays/:id", adminHandler.UpdateHoliday)
-			admin.DELETE("/holidays/:id", adminHandler.DeleteHoliday)
+			createHoliday := middleware.RequirePermission(models.PermissionHolidayCreate)
+			readHoliday := middleware.RequirePermission(models.PermissionHolidayRead)
+			updateHoliday := middleware.RequirePermission(models.PermissionHolidayUpdate)
+			admin.POST("/holidays", createHoliday, writeHolidays, adminHandler.CreateHoliday)
+			admin.POST("/holidays/import", createHoliday, writeHolidays, adminHandler.ImportHolidays)
+			admin.GET("/holidays/:id", readHoliday, readHolidays, adminHandler.GetHoliday)
+			admin.PUT("/holidays/:id", updateHoliday, writeHolidays, adminHandler.UpdateHoliday)
+			admin.DELETE("/holidays/:id", middleware.RequirePermission(models.PermissionHolidayDelete), writeHolidays, adminHandler.DeleteHoliday)
+			admin.GET("/holidays/:id/history", readHoliday, readHolidays, adminHandler.GetHolidayHistory)
+			admin.POST("/holidays/:id/rollback", updateHoliday, writeHolidays, adminHandler.RollbackHoliday)
 
 			// Audit logs
-			admin.GET("/audit-logs", auditHandler.GetAuditLogs)
-			admin.GET("/audit-logs/user/:id", auditHandler.GetUserAuditLogs)
+			auditLogs := middleware.RequirePermission(models.PermissionAuditRead)
+			admin.GET("/audit-logs", auditLogs, readAudit, auditHandler.GetAuditLogs)
+			admin.GET("/audit-logs/export", auditLogs, readAudit, auditHandler.ExportAuditLogs)
+			admin.GET("/audit-logs/user/:id", auditLogs, readAudit, auditHandler.GetUserAuditLogs)
+			admin.GET("/audit-logs/verify", middleware.RequirePermission(models.PermissionAuditVerify), readAudit, auditHandler.VerifyAuditChain)
+
+			// Webhook subscriptions
+			manageWebhooks := middleware.RequirePermission(models.PermissionWebhookManage)
+			admin.GET("/webhooks", manageWebhooks, webhookScope, webhookHandler.GetWebhooks)
+			admin.POST("/webhooks", manageWebhooks, webhookScope, webhookHandler.CreateWebhook)
+			admin.GET("/webhooks/:id", manageWebhooks, webhookScope, webhookHandler.GetWebhook)
+			admin.PUT("/webhooks/:id", manageWebhooks, webhookScope, webhookHandler.UpdateWebhook)
+			admin.DELETE("/webhooks/:id", manageWebhooks, webhookScope, webhookHandler.DeleteWebhook)
+			admin.GET("/webhooks/:id/deliveries", manageWebhooks, webhookScope, webhookHandler.GetWebhookDeliveries)
+
+			// Holiday reminder emails
+			manageReminders := middleware.RequirePermission(models.PermissionReminderManage)
+			admin.GET("/reminders", manageReminders, reminderScope, reminderHandler.GetReminders)
+			admin.POST("/reminders", manageReminders, reminderScope, reminderHandler.CreateReminder)
+			admin.GET("/reminders/:id", manageReminders, reminderScope, reminderHandler.GetReminder)
+			admin.PUT("/reminders/:id", manageReminders, reminderScope, reminderHandler.UpdateReminder)
+			admin.DELETE("/reminders/:id", manageReminders, reminderScope, reminderHandler.DeleteReminder)
+			admin.GET("/distribution-lists", manageReminders, reminderScope, reminderHandler.GetDistributionLists)
+			admin.POST("/distribution-lists", manageReminders, reminderScope, reminderHandler.CreateDistributionList)
+			admin.PUT("/distribution-lists/:id", manageReminders, reminderScope, reminderHandler.UpdateDistributionList)
+			admin.DELETE("/distribution-lists/:id", manageReminders, reminderScope, reminderHandler.DeleteDistributionList)
+
+			// Background jobs
+			manageJobs := middleware.RequirePermission(models.PermissionJobManage)
+			admin.GET("/jobs", manageJobs, jobScope, jobHandler.GetJobs)
+			admin.GET("/jobs/:name", manageJobs, jobScope, jobHandler.GetJob)
+			admin.POST("/jobs/:name/run", manageJobs, jobScope, jobHandler.RunJob)
 		}
 	}
 
@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ilramdhan/holidayapi/internal/config"
+	"github.com/ilramdhan/holidayapi/internal/middleware"
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+// MockAPIKeyService is a mock implementation of APIKeyService
+type MockAPIKeyService struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyService) CreateAPIKey(req models.CreateAPIKeyRequest, createdBy *models.User, ipAddress, userAgent string) (*models.CreateAPIKeyResponse, error) {
+	args := m.Called(req, createdBy, ipAddress, userAgent)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CreateAPIKeyResponse), args.Error(1)
+}
+
+func (m *MockAPIKeyService) ListAPIKeys() ([]models.APIKey, error) {
+	args := m.Called()
+	return args.Get(0).([]models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyService) RevokeAPIKey(id int, revokedBy *models.User, ipAddress, userAgent string) error {
+	args := m.Called(id, revokedBy, ipAddress, userAgent)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyService) Authenticate(rawKey, requestPath, ipAddress, userAgent string) (*models.APIKey, *models.User, error) {
+	args := m.Called(rawKey, requestPath, ipAddress, userAgent)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*models.APIKey), args.Get(1).(*models.User), args.Error(2)
+}
+
+func TestRouter_VerifyAuditChainRequiresReadAuditScope(t *testing.T) {
+	tests := []struct {
+		name           string
+		scopes         []models.APIKeyScope
+		expectedStatus int
+	}{
+		{name: "key with read:audit", scopes: []models.APIKeyScope{models.ScopeReadAudit}, expectedStatus: http.StatusOK},
+		{name: "key without read:audit", scopes: []models.APIKeyScope{models.ScopeReadHolidays}, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPIKeys := new(MockAPIKeyService)
+			mockAudit := new(MockAuditService)
+
+			// The key's owner may verify the chain; only the key's scopes differ
+			owner := &models.User{ID: 1, Username: "admin", Role: models.SuperAdminRole, Permissions: []models.Permission{models.PermissionAuditVerify}}
+			mockAPIKeys.On("Authenticate", "hk_test", "GET /api/v1/admin/audit-logs/verify", mock.Anything, mock.Anything).
+				Return(&models.APIKey{ID: 3, Scopes: tt.scopes}, owner, nil)
+			mockAudit.On("VerifyChain").Return(&models.AuditChainVerification{Valid: true}, nil)
+
+			router := SetupRouter(&config.Config{}, nil, nil, nil, mockAudit, nil, mockAPIKeys, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				middleware.NewRateLimiter(600, 100))
+
+			req, _ := http.NewRequest("GET", "/api/v1/admin/audit-logs/verify", nil)
+			req.Header.Set(middleware.APIKeyHeader, "hk_test")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusForbidden {
+				assert.Contains(t, w.Body.String(), "API key is missing the read:audit scope")
+				mockAudit.AssertNotCalled(t, "VerifyChain")
+			}
+		})
+	}
+}
+
+func TestRouter_WebhookRoutesRequireManageWebhooksScope(t *testing.T) {
+	tests := []struct {
+		name           string
+		scopes         []models.APIKeyScope
+		expectedStatus int
+	}{
+		{name: "key with manage:webhooks", scopes: []models.APIKeyScope{models.ScopeManageWebhooks}, expectedStatus: http.StatusOK},
+		{name: "key without manage:webhooks", scopes: []models.APIKeyScope{models.ScopeReadHolidays}, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPIKeys := new(MockAPIKeyService)
+			mockWebhooks := new(MockWebhookService)
+
+			// The key's owner may manage webhooks; only the key's scopes differ
+			owner := &models.User{ID: 1, Username: "admin", Role: models.SuperAdminRole, Permissions: []models.Permission{models.PermissionWebhookManage}}
+			mockAPIKeys.On("Authenticate", "hk_test", "DELETE /api/v1/admin/webhooks/7", mock.Anything, mock.Anything).
+				Return(&models.APIKey{ID: 3, Scopes: tt.scopes}, owner, nil)
+			mockWebhooks.On("DeleteWebhook", 7, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+			router := SetupRouter(&config.Config{}, nil, nil, nil, nil, nil, mockAPIKeys, nil, nil, nil, mockWebhooks, nil, nil, nil, nil, nil, nil,
+				middleware.NewRateLimiter(600, 100))
+
+			req, _ := http.NewRequest("DELETE", "/api/v1/admin/webhooks/7", nil)
+			req.Header.Set(middleware.APIKeyHeader, "hk_test")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusForbidden {
+				assert.Contains(t, w.Body.String(), "API key is missing the manage:webhooks scope")
+				mockWebhooks.AssertNotCalled(t, "DeleteWebhook", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestRouter_ReminderRoutesRequireManageRemindersScope(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		method         string
+		scopes         []models.APIKeyScope
+		expectedStatus int
+	}{
+		{name: "reminder with manage:reminders", path: "/api/v1/admin/reminders/5", method: "DeleteRule", scopes: []models.APIKeyScope{models.ScopeManageReminders}, expectedStatus: http.StatusOK},
+		{name: "reminder without manage:reminders", path: "/api/v1/admin/reminders/5", method: "DeleteRule", scopes: []models.APIKeyScope{models.ScopeReadHolidays}, expectedStatus: http.StatusForbidden},
+		{name: "distribution list with manage:reminders", path: "/api/v1/admin/distribution-lists/5", method: "DeleteList", scopes: []models.APIKeyScope{models.ScopeManageReminders}, expectedStatus: http.StatusOK},
+		{name: "distribution list without manage:reminders", path: "/api/v1/admin/distribution-lists/5", method: "DeleteList", scopes: []models.APIKeyScope{models.ScopeManageWebhooks}, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPIKeys := new(MockAPIKeyService)
+			mockReminders := new(MockReminderService)
+
+			// The key's owner may manage reminders; only the key's scopes differ
+			owner := &models.User{ID: 1, Username: "admin", Role: models.SuperAdminRole, Permissions: []models.Permission{models.PermissionReminderManage}}
+			mockAPIKeys.On("Authenticate", "hk_test", "DELETE "+tt.path, mock.Anything, mock.Anything).
+				Return(&models.APIKey{ID: 3, Scopes: tt.scopes}, owner, nil)
+			mockReminders.On(tt.method, 5, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+			router := SetupRouter(&config.Config{}, nil, nil, nil, nil, nil, mockAPIKeys, nil, nil, nil, nil, mockReminders, nil, nil, nil, nil, nil,
+				middleware.NewRateLimiter(600, 100))
+
+			req, _ := http.NewRequest("DELETE", tt.path, nil)
+			req.Header.Set(middleware.APIKeyHeader, "hk_test")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusForbidden {
+				assert.Contains(t, w.Body.String(), "API key is missing the manage:reminders scope")
+				mockReminders.AssertNotCalled(t, tt.method, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestRouter_RunJobRequiresManageJobsScope(t *testing.T) {
+	tests := []struct {
+		name           string
+		scopes         []models.APIKeyScope
+		expectedStatus int
+	}{
+		{name: "key with manage:jobs", scopes: []models.APIKeyScope{models.ScopeManageJobs}, expectedStatus: http.StatusAccepted},
+		{name: "key without manage:jobs", scopes: []models.APIKeyScope{models.ScopeReadHolidays, models.ScopeReadAudit}, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPIKeys := new(MockAPIKeyService)
+			mockJobs := new(MockJobService)
+
+			// The key's owner may run jobs; only the key's scopes differ
+			owner := &models.User{ID: 1, Username: "admin", Role: models.SuperAdminRole, Permissions: []models.Permission{models.PermissionJobManage}}
+			mockAPIKeys.On("Authenticate", "hk_test", "POST /api/v1/admin/jobs/audit-retention/run", mock.Anything, mock.Anything).
+				Return(&models.APIKey{ID: 3, Scopes: tt.scopes}, owner, nil)
+			mockJobs.On("TriggerJob", "audit-retention", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+			router := SetupRouter(&config.Config{}, nil, nil, nil, nil, nil, mockAPIKeys, nil, nil, nil, nil, nil, mockJobs, nil, nil, nil, nil,
+				middleware.NewRateLimiter(600, 100))
+
+			req, _ := http.NewRequest("POST", "/api/v1/admin/jobs/audit-retention/run", nil)
+			req.Header.Set(middleware.APIKeyHeader, "hk_test")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusForbidden {
+				assert.Contains(t, w.Body.String(), "API key is missing the manage:jobs scope")
+				mockJobs.AssertNotCalled(t, "TriggerJob", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
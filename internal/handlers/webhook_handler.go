@@ -0,0 +1,311 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/services"
+)
+
+// WebhookHandler handles webhook subscription HTTP requests
+type WebhookHandler struct {
+	webhookService services.WebhookService
+	validator      *validator.Validate
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(webhookService services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		validator:      validator.New(),
+	}
+}
+
+// CreateWebhook godoc
+// @Summary Add a webhook subscription (requires webhook:manage)
+// @Description Subscribe a URL to holiday change events. Every delivery is signed with the secret, which is generated when omitted and only returned once.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param webhook body models.CreateWebhookRequest true "Webhook data"
+// @Success 201 {object} models.APIResponse{data=models.CreateWebhookResponse}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req models.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Validation failed",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	createdBy, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	created, err := h.webhookService.CreateWebhook(req, createdBy, c.ClientIP(), c.GetHeader("User-Agent"))
+	if err != nil {
+		c.JSON(webhookErrorStatus(err), models.ErrorResponse{
+			Success: false,
+			Message: "Failed to create webhook",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Webhook created successfully. Store the secret now, it will not be shown again",
+		Data:    created,
+	})
+}
+
+// GetWebhooks godoc
+// @Summary List webhook subscriptions (requires webhook:manage)
+// @Description List all webhook subscriptions. Secrets are never returned.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=[]models.WebhookSubscription}
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/webhooks [get]
+func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
+	subscriptions, err := h.webhookService.ListWebhooks()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Message: "Failed to get webhooks",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Webhooks retrieved successfully",
+		Data:    subscriptions,
+	})
+}
+
+// GetWebhook godoc
+// @Summary Get a webhook subscription (requires webhook:manage)
+// @Description Get a webhook subscription by ID
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} models.APIResponse{data=models.WebhookSubscription}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	id, ok := parseIDParam(c, "webhook")
+	if !ok {
+		return
+	}
+
+	subscription, err := h.webhookService.GetWebhook(id)
+	if err != nil {
+		c.JSON(webhookErrorStatus(err), models.ErrorResponse{
+			Success: false,
+			Message: "Failed to get webhook",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Webhook retrieved successfully",
+		Data:    subscription,
+	})
+}
+
+// UpdateWebhook godoc
+// @Summary Update a webhook subscription (requires webhook:manage)
+// @Description Change the URL, events or secret of a subscription, or pause it with is_active=false. Deliveries queued while paused are sent once it is reactivated.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Param webhook body models.UpdateWebhookRequest true "Fields to change"
+// @Success 200 {object} models.APIResponse{data=models.WebhookSubscription}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	id, ok := parseIDParam(c, "webhook")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Validation failed",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	updatedBy, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	subscription, err := h.webhookService.UpdateWebhook(id, req, updatedBy, c.ClientIP(), c.GetHeader("User-Agent"))
+	if err != nil {
+		c.JSON(webhookErrorStatus(err), models.ErrorResponse{
+			Success: false,
+			Message: "Failed to update webhook",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Webhook updated successfully",
+		Data:    subscription,
+	})
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook subscription (requires webhook:manage)
+// @Description Delete a subscription together with its queued deliveries and delivery history
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, ok := parseIDParam(c, "webhook")
+	if !ok {
+		return
+	}
+
+	deletedBy, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.DeleteWebhook(id, deletedBy, c.ClientIP(), c.GetHeader("User-Agent")); err != nil {
+		c.JSON(webhookErrorStatus(err), models.ErrorResponse{
+			Success: false,
+			Message: "Failed to delete webhook",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Webhook deleted successfully",
+	})
+}
+
+// GetWebhookDeliveries godoc
+// @Summary Get webhook delivery history (requires webhook:manage)
+// @Description List the deliveries of a subscription, newest first, with their status, attempts, last response and next retry
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Param limit query int false "Limit results (max 100)" default(50)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Success 200 {object} models.APIResponse{data=models.WebhookDeliveryResponse}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) GetWebhookDeliveries(c *gin.Context) {
+	id, ok := parseIDParam(c, "webhook")
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	offset, _ := strconv.Atoi(c.Query("offset"))
+
+	response, err := h.webhookService.GetDeliveries(id, limit, offset)
+	if err != nil {
+		c.JSON(webhookErrorStatus(err), models.ErrorResponse{
+			Success: false,
+			Message: "Failed to get webhook deliveries",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Webhook deliveries retrieved successfully",
+		Data:    response,
+	})
+}
+
+// webhookErrorStatus maps a webhook service error to an HTTP status
+func webhookErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return http.StatusNotFound
+	case strings.Contains(err.Error(), "invalid webhook url"):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+// MockWebhookService is a mock implementation of WebhookService
+type MockWebhookService struct {
+	mock.Mock
+}
+
+func (m *MockWebhookService) CreateWebhook(req models.CreateWebhookRequest, createdBy *models.User, ipAddress, userAgent string) (*models.CreateWebhookResponse, error) {
+	args := m.Called(req, createdBy, ipAddress, userAgent)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CreateWebhookResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) ListWebhooks() ([]models.WebhookSubscription, error) {
+	args := m.Called()
+	return args.Get(0).([]models.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookService) GetWebhook(id int) (*models.WebhookSubscription, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookService) UpdateWebhook(id int, req models.UpdateWebhookRequest, updatedBy *models.User, ipAddress, userAgent string) (*models.WebhookSubscription, error) {
+	args := m.Called(id, req, updatedBy, ipAddress, userAgent)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookService) DeleteWebhook(id int, deletedBy *models.User, ipAddress, userAgent string) error {
+	args := m.Called(id, deletedBy, ipAddress, userAgent)
+	return args.Error(0)
+}
+
+func (m *MockWebhookService) GetDeliveries(id, limit, offset int) (*models.WebhookDeliveryResponse, error) {
+	args := m.Called(id, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookDeliveryResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) DeliverDue(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func TestWebhookHandler_CreateWebhook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*MockWebhookService)
+		expectedStatus int
+	}{
+		{
+			name: "secret is returned once",
+			body: `{"url":"https://payroll.example.com/hooks","events":["holiday.created","holiday.deleted"]}`,
+			setupMock: func(m *MockWebhookService) {
+				subscription := &models.WebhookSubscription{ID: 1, URL: "https://payroll.example.com/hooks", Secret: "whsec_abc", IsActive: true}
+				m.On("CreateWebhook", mock.AnythingOfType("models.CreateWebhookRequest"), mock.AnythingOfType("*models.User"), mock.Anything, mock.Anything).
+					Return(&models.CreateWebhookResponse{WebhookSubscription: subscription, Secret: "whsec_abc"}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "unknown event",
+			body:           `{"url":"https://payroll.example.com/hooks","events":["holiday.viewed"]}`,
+			setupMock:      func(m *MockWebhookService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "unsupported url scheme",
+			body: `{"url":"ftp://payroll.example.com/hooks","events":["holiday.created"]}`,
+			setupMock: func(m *MockWebhookService) {
+				m.On("CreateWebhook", mock.AnythingOfType("models.CreateWebhookRequest"), mock.AnythingOfType("*models.User"), mock.Anything, mock.Anything).
+					Return(nil, fmt.Errorf(`invalid webhook url "ftp://payroll.example.com/hooks", use an absolute http or https URL`))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockWebhookService)
+			tt.setupMock(mockService)
+
+			handler := NewWebhookHandler(mockService)
+			router := gin.New()
+			router.POST("/admin/webhooks", withCurrentUser(1, "superadmin"), handler.CreateWebhook)
+
+			req, _ := http.NewRequest("POST", "/admin/webhooks", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusCreated {
+				var response struct {
+					Data map[string]interface{} `json:"data"`
+				}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, "whsec_abc", response.Data["secret"])
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookHandler_GetWebhookDeliveries(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockWebhookService)
+	mockService.On("GetDeliveries", 3, 10, 20).Return(&models.WebhookDeliveryResponse{
+		Data:  []models.WebhookDelivery{{ID: 9, SubscriptionID: 3, Status: models.DeliveryFailed, Attempts: 8}},
+		Total: 21, Limit: 10, Offset: 20,
+	}, nil)
+	mockService.On("GetDeliveries", 4, 0, 0).Return(nil, fmt.Errorf("webhook not found"))
+
+	handler := NewWebhookHandler(mockService)
+	router := gin.New()
+	router.GET("/admin/webhooks/:id/deliveries", handler.GetWebhookDeliveries)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/admin/webhooks/3/deliveries?limit=10&offset=20", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"total":21`)
+	assert.Contains(t, w.Body.String(), `"status":"failed"`)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/admin/webhooks/4/deliveries", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
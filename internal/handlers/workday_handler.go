@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/services"
+)
+
+// WorkdayHandler handles working-day calculation HTTP requests
+type WorkdayHandler struct {
+	service services.WorkdayService
+}
+
+// NewWorkdayHandler creates a new workday handler
+func NewWorkdayHandler(service services.WorkdayService) *WorkdayHandler {
+	return &WorkdayHandler{
+		service: service,
+	}
+}
+
+// CountWorkdays godoc
+// @Summary Count working days
+// @Description Count working days between two dates (inclusive), excluding weekends and holidays
+// @Tags workdays
+// @Accept json
+// @Produce json
+// @Param start_date query string true "Start date (YYYY-MM-DD)"
+// @Param end_date query string true "End date (YYYY-MM-DD)"
+// @Param include_collective_leave query bool false "Treat collective leave (cuti bersama) as days off" default(true)
+// @Param province query string false "Province code (ISO 3166-2:ID, e.g. ID-BA) whose regional holidays are also days off"
+// @Param as_of query string false "Count the holidays as recorded at this time (RFC 3339, or YYYY-MM-DD for the end of that day)"
+// @Param status query string false "Comma-separated decree statuses (provisional, official, revised, cancelled); all but cancelled by default"
+// @Success 200 {object} models.APIResponse{data=models.WorkdayCountResponse}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/workdays/count [get]
+func (h *WorkdayHandler) CountWorkdays(c *gin.Context) {
+	startDate, err := parseRequiredDate(c, "start_date")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid start_date parameter",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	endDate, err := parseRequiredDate(c, "end_date")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid end_date parameter",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	opts, err := parseWorkdayOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid working-day options",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.service.CountWorkdays(startDate, endDate, opts)
+	if err != nil {
+		c.JSON(workdayStatus(err), models.ErrorResponse{
+			Success: false,
+			Message: "Failed to count working days",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Working days counted successfully",
+		Data:    response,
+	})
+}
+
+// AddWorkdays godoc
+// @Summary Add working days to a date
+// @Description Get the date that is N working days after (or before, for negative N) the given date
+// @Tags workdays
+// @Accept json
+// @Produce json
+// @Param date query string true "Start date (YYYY-MM-DD)"
+// @Param days query int true "Number of working days to add (negative to subtract)"
+// @Param include_collective_leave query bool false "Treat collective leave (cuti bersama) as days off" default(true)
+// @Param province query string false "Province code (ISO 3166-2:ID, e.g. ID-BA) whose regional holidays are also days off"
+// @Param as_of query string false "Count the holidays as recorded at this time (RFC 3339, or YYYY-MM-DD for the end of that day)"
+// @Param status query string false "Comma-separated decree statuses (provisional, official, revised, cancelled); all but cancelled by default"
+// @Success 200 {object} models.APIResponse{data=models.WorkdayShiftResponse}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/workdays/add [get]
+func (h *WorkdayHandler) AddWorkdays(c *gin.Context) {
+	date, err := parseRequiredDate(c, "date")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid date parameter",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	days, err := strconv.Atoi(c.Query("days"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid days parameter",
+			Error:   "Days must be a valid integer",
+		})
+		return
+	}
+
+	opts, err := parseWorkdayOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid working-day options",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.service.AddWorkdays(date, days, opts)
+	if err != nil {
+		c.JSON(workdayStatus(err), models.ErrorResponse{
+			Success: false,
+			Message: "Failed to add working days",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Working days added successfully",
+		Data:    response,
+	})
+}
+
+// NextWorkday godoc
+// @Summary Get next working day
+// @Description Get the first working day after the given date (defaults to today)
+// @Tags workdays
+// @Accept json
+// @Produce json
+// @Param date query string false "Date (YYYY-MM-DD), defaults to today"
+// @Param include_collective_leave query bool false "Treat collective leave (cuti bersama) as days off" default(true)
+// @Param province query string false "Province code (ISO 3166-2:ID, e.g. ID-BA) whose regional holidays are also days off"
+// @Param as_of query string false "Count the holidays as recorded at this time (RFC 3339, or YYYY-MM-DD for the end of that day)"
+// @Param status query string false "Comma-separated decree statuses (provisional, official, revised, cancelled); all but cancelled by default"
+// @Success 200 {object} models.APIResponse{data=models.WorkdayShiftResponse}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/workdays/next [get]
+func (h *WorkdayHandler) NextWorkday(c *gin.Context) {
+	date := time.Now()
+	if dateStr := c.Query("date"); dateStr != "" {
+		parsed, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Message: "Invalid date parameter",
+				Error:   "Date must use the YYYY-MM-DD format",
+			})
+			return
+		}
+		date = parsed
+	}
+
+	opts, err := parseWorkdayOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Message: "Invalid working-day options",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.service.NextWorkday(date, opts)
+	if err != nil {
+		c.JSON(workdayStatus(err), models.ErrorResponse{
+			Success: false,
+			Message: "Failed to get next working day",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Next working day retrieved successfully",
+		Data:    response,
+	})
+}
+
+// workdayStatus maps a working-day service error to a response status: input the service refused
+// is a bad request, anything else, such as a failed holiday lookup, is an internal error
+func workdayStatus(err error) int {
+	var invalid *services.WorkdayRequestError
+	if errors.As(err, &invalid) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// parseRequiredDate parses a mandatory YYYY-MM-DD query parameter
+func parseRequiredDate(c *gin.Context, name string) (time.Time, error) {
+	value := c.Query(name)
+	if value == "" {
+		return time.Time{}, fmt.Errorf("%s is required", name)
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must use the YYYY-MM-DD format", name)
+	}
+
+	return date, nil
+}
+
+// parseWorkdayOptions parses working-day options, counting cuti bersama as a day off by default
+func parseWorkdayOptions(c *gin.Context) (models.WorkdayOptions, error) {
+	opts := models.WorkdayOptions{IncludeCollectiveLeave: true}
+
+	if value := c.Query("include_collective_leave"); value != "" {
+		include, err := strconv.ParseBool(value)
+		if err != nil {
+			return opts, fmt.Errorf("include_collective_leave must be true or false")
+		}
+		opts.IncludeCollectiveLeave = include
+	}
+
+	scope, err := parseHolidayScope(c)
+	if err != nil {
+		return opts, err
+	}
+	opts.Province = scope.Province
+	opts.AsOf = scope.AsOf
+	opts.Statuses = scope.Statuses
+
+	return opts, nil
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/services"
+)
+
+// MockWorkdayService is a mock implementation of WorkdayService
+type MockWorkdayService struct {
+	mock.Mock
+}
+
+func (m *MockWorkdayService) CountWorkdays(startDate, endDate time.Time, opts models.WorkdayOptions) (*models.WorkdayCountResponse, error) {
+	args := m.Called(startDate, endDate, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WorkdayCountResponse), args.Error(1)
+}
+
+func (m *MockWorkdayService) AddWorkdays(date time.Time, days int, opts models.WorkdayOptions) (*models.WorkdayShiftResponse, error) {
+	args := m.Called(date, days, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WorkdayShiftResponse), args.Error(1)
+}
+
+func (m *MockWorkdayService) NextWorkday(date time.Time, opts models.WorkdayOptions) (*models.WorkdayShiftResponse, error) {
+	args := m.Called(date, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WorkdayShiftResponse), args.Error(1)
+}
+
+func TestWorkdayHandler_ErrorStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "calculated", expectedStatus: http.StatusOK},
+		{name: "invalid range", err: &services.WorkdayRequestError{Reason: "end date must not be before start date"}, expectedStatus: http.StatusBadRequest},
+		{name: "holiday lookup failed", err: fmt.Errorf("failed to get holidays: %w", fmt.Errorf("database is locked")), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockWorkdayService)
+			if tt.err != nil {
+				mockService.On("CountWorkdays", mock.Anything, mock.Anything, mock.Anything).Return(nil, tt.err)
+				mockService.On("AddWorkdays", mock.Anything, 5, mock.Anything).Return(nil, tt.err)
+			} else {
+				mockService.On("CountWorkdays", mock.Anything, mock.Anything, mock.Anything).Return(&models.WorkdayCountResponse{}, nil)
+				mockService.On("AddWorkdays", mock.Anything, 5, mock.Anything).Return(&models.WorkdayShiftResponse{}, nil)
+			}
+
+			handler := NewWorkdayHandler(mockService)
+			router := gin.New()
+			router.GET("/workdays/count", handler.CountWorkdays)
+			router.GET("/workdays/add", handler.AddWorkdays)
+
+			for _, url := range []string{"/workdays/count?start_date=2024-04-30&end_date=2024-04-01", "/workdays/add?date=2024-04-01&days=5"} {
+				req, _ := http.NewRequest("GET", url, nil)
+				w := httptest.NewRecorder()
+
+				router.ServeHTTP(w, req)
+
+				assert.Equal(t, tt.expectedStatus, w.Code, url)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,178 @@
+// Package ical renders holidays as an RFC 5545 iCalendar feed
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+const (
+	// productID identifies this API as the calendar producer
+	productID = "-//Holiday API Indonesia//Holiday Calendar//ID"
+	// uidDomain makes holiday UIDs globally unique
+	uidDomain = "holidayapi"
+	// maxLineOctets is the RFC 5545 content line limit, excluding CRLF
+	maxLineOctets = 75
+
+	dateFormat     = "20060102"
+	dateTimeFormat = "20060102T150405Z"
+)
+
+// Calendar represents an iCalendar document with holiday events
+type Calendar struct {
+	Name        string
+	Description string
+	Events      []Event
+}
+
+// Event represents an all-day VEVENT
+type Event struct {
+	UID          string
+	Sequence     int
+	Summary      string
+	Description  string
+	Date         time.Time
+	Categories   []string
+	Created      time.Time
+	LastModified time.Time
+}
+
+// EventFromHoliday converts a holiday into an all-day event.
+// The UID is derived from the holiday ID so subscribers can track edits,
+// and SEQUENCE grows whenever the holiday's UpdatedAt moves forward.
+func EventFromHoliday(holiday models.Holiday) Event {
+	sequence := 0
+	if holiday.UpdatedAt.After(holiday.CreatedAt) {
+		sequence = int(holiday.UpdatedAt.Sub(holiday.CreatedAt) / time.Second)
+	}
+
+	return Event{
+		UID:          fmt.Sprintf("holiday-%d@%s", holiday.ID, uidDomain),
+		Sequence:     sequence,
+		Summary:      holiday.Name,
+		Description:  holiday.Description,
+		Date:         holiday.Date,
+		Categories:   []string{string(holiday.Type)},
+		Created:      holiday.CreatedAt,
+		LastModified: holiday.UpdatedAt,
+	}
+}
+
+// Write renders the calendar to w using CRLF line endings and line folding
+func Write(w io.Writer, cal Calendar) error {
+	bw := bufio.NewWriter(w)
+	cw := &contentWriter{w: bw}
+
+	cw.line("BEGIN:VCALENDAR")
+	cw.line("VERSION:2.0")
+	cw.line("PRODID:" + productID)
+	cw.line("CALSCALE:GREGORIAN")
+	cw.line("METHOD:PUBLISH")
+	if cal.Name != "" {
+		cw.line("X-WR-CALNAME:" + escapeText(cal.Name))
+	}
+	if cal.Description != "" {
+		cw.line("X-WR-CALDESC:" + escapeText(cal.Description))
+	}
+	cw.line("X-WR-TIMEZONE:Asia/Jakarta")
+
+	for _, event := range cal.Events {
+		writeEvent(cw, event)
+	}
+
+	cw.line("END:VCALENDAR")
+
+	if cw.err != nil {
+		return cw.err
+	}
+	return bw.Flush()
+}
+
+// writeEvent renders a single all-day VEVENT
+func writeEvent(cw *contentWriter, event Event) {
+	stamp := event.LastModified
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+
+	date := time.Date(event.Date.Year(), event.Date.Month(), event.Date.Day(), 0, 0, 0, 0, time.UTC)
+
+	cw.line("BEGIN:VEVENT")
+	cw.line("UID:" + event.UID)
+	cw.line(fmt.Sprintf("SEQUENCE:%d", event.Sequence))
+	cw.line("DTSTAMP:" + stamp.UTC().Format(dateTimeFormat))
+	cw.line("DTSTART;VALUE=DATE:" + date.Format(dateFormat))
+	cw.line("DTEND;VALUE=DATE:" + date.AddDate(0, 0, 1).Format(dateFormat))
+	cw.line("SUMMARY:" + escapeText(event.Summary))
+	if event.Description != "" {
+		cw.line("DESCRIPTION:" + escapeText(event.Description))
+	}
+	if len(event.Categories) > 0 {
+		categories := make([]string, len(event.Categories))
+		for i, category := range event.Categories {
+			categories[i] = escapeText(category)
+		}
+		cw.line("CATEGORIES:" + strings.Join(categories, ","))
+	}
+	if !event.Created.IsZero() {
+		cw.line("CREATED:" + event.Created.UTC().Format(dateTimeFormat))
+	}
+	if !event.LastModified.IsZero() {
+		cw.line("LAST-MODIFIED:" + event.LastModified.UTC().Format(dateTimeFormat))
+	}
+	cw.line("TRANSP:TRANSPARENT")
+	cw.line("END:VEVENT")
+}
+
+// escapeText escapes a TEXT property value (RFC 5545 section 3.3.11)
+func escapeText(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	)
+	return replacer.Replace(value)
+}
+
+// contentWriter writes folded content lines and remembers the first error
+type contentWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+// line writes a content line, folding it at 75 octets without splitting UTF-8 characters
+func (cw *contentWriter) line(content string) {
+	if cw.err != nil {
+		return
+	}
+
+	limit := maxLineOctets
+	for len(content) > limit {
+		cut := limit
+		// Step back to the start of a UTF-8 sequence
+		for cut > 0 && !isRuneStart(content[cut]) {
+			cut--
+		}
+		if _, cw.err = cw.w.WriteString(content[:cut] + "\r\n "); cw.err != nil {
+			return
+		}
+		content = content[cut:]
+		// Continuation lines start with a space, which counts towards the limit
+		limit = maxLineOctets - 1
+	}
+
+	_, cw.err = cw.w.WriteString(content + "\r\n")
+}
+
+// isRuneStart reports whether b begins a UTF-8 encoded character
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
@@ -0,0 +1,79 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+func TestEventFromHoliday(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	holiday := models.Holiday{
+		ID:        42,
+		Name:      "Hari Raya Natal",
+		Date:      time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC),
+		Type:      models.NationalHoliday,
+		CreatedAt: created,
+		UpdatedAt: created,
+	}
+
+	event := EventFromHoliday(holiday)
+	assert.Equal(t, "holiday-42@holidayapi", event.UID)
+	assert.Equal(t, 0, event.Sequence)
+
+	holiday.UpdatedAt = created.Add(90 * time.Second)
+	edited := EventFromHoliday(holiday)
+	assert.Equal(t, event.UID, edited.UID)
+	assert.Greater(t, edited.Sequence, event.Sequence)
+}
+
+func TestWrite(t *testing.T) {
+	holiday := models.Holiday{
+		ID:          1,
+		Name:        "Hari Raya Idul Fitri",
+		Date:        time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC),
+		Type:        models.NationalHoliday,
+		Description: "Idul Fitri 1445 H; hari pertama, libur nasional",
+		CreatedAt:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	var buf bytes.Buffer
+	err := Write(&buf, Calendar{Name: "Libur Indonesia", Events: []Event{EventFromHoliday(holiday)}})
+	assert.NoError(t, err)
+
+	output := buf.String()
+	assert.True(t, strings.HasPrefix(output, "BEGIN:VCALENDAR\r\n"))
+	assert.True(t, strings.HasSuffix(output, "END:VCALENDAR\r\n"))
+	assert.Contains(t, output, "UID:holiday-1@holidayapi\r\n")
+	assert.Contains(t, output, "DTSTART;VALUE=DATE:20240410\r\n")
+	assert.Contains(t, output, "DTEND;VALUE=DATE:20240411\r\n")
+	assert.Contains(t, output, `DESCRIPTION:Idul Fitri 1445 H\; hari pertama\, libur nasional`)
+	assert.Contains(t, output, "CATEGORIES:national\r\n")
+}
+
+func TestWriteFoldsLongLines(t *testing.T) {
+	holiday := models.Holiday{
+		ID:          7,
+		Name:        "Cuti Bersama",
+		Date:        time.Date(2024, 4, 8, 0, 0, 0, 0, time.UTC),
+		Type:        models.CollectiveLeave,
+		Description: strings.Repeat("Cuti bersama Idul Fitri — ", 10),
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, Calendar{Events: []Event{EventFromHoliday(holiday)}}))
+
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), maxLineOctets)
+	}
+
+	// Unfolding must restore the original value
+	unfolded := strings.ReplaceAll(buf.String(), "\r\n ", "")
+	assert.Contains(t, unfolded, "DESCRIPTION:"+escapeText(holiday.Description)+"\r\n")
+}
@@ -0,0 +1,114 @@
+// Package metrics exposes Prometheus metrics for the HTTP, database and auth layers
+package metrics
+
+import (
+	"net/http"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "holidayapi"
+
+// Build information, set at link time:
+//
+//	go build -ldflags "-X github.com/ilramdhan/holidayapi/internal/metrics.Version=v2.1.0"
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildDate = "unknown"
+)
+
+var (
+	registry = prometheus.NewRegistry()
+
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route template and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	rateLimitRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limit_rejections_total",
+		Help:      "Requests rejected by the rate limiter, by route template.",
+	}, []string{"route"})
+
+	logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Login attempts by result (success or failure).",
+	}, []string{"result"})
+
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Database query duration by repository and operation.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"repository", "operation"})
+
+	buildInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "build_info",
+		Help:      "Build information; the value is always 1.",
+	}, []string{"version", "commit", "build_date", "go_version"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		rateLimitRejections,
+		logins,
+		dbQueryDuration,
+		buildInfo,
+	)
+}
+
+// Handler returns the HTTP handler serving metrics in the Prometheus text format
+func Handler() http.Handler {
+	buildInfo.WithLabelValues(Version, Commit, BuildDate, runtime.Version()).Set(1)
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// ObserveHTTPRequest records a handled HTTP request
+func ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	statusLabel := strconv.Itoa(status)
+	httpRequests.WithLabelValues(method, route, statusLabel).Inc()
+	httpDuration.WithLabelValues(method, route, statusLabel).Observe(duration.Seconds())
+}
+
+// IncRateLimitRejection records a request rejected by the rate limiter
+func IncRateLimitRejection(route string) {
+	rateLimitRejections.WithLabelValues(route).Inc()
+}
+
+// IncLogin records a login attempt
+func IncLogin(success bool) {
+	result := "failure"
+	if success {
+		result = "success"
+	}
+	logins.WithLabelValues(result).Inc()
+}
+
+// ObserveDBQuery records the duration of a repository operation started at start.
+// It is meant to be deferred at the top of a repository method:
+//
+//	defer metrics.ObserveDBQuery("holiday", "GetByID", time.Now())
+func ObserveDBQuery(repository, operation string, start time.Time) {
+	dbQueryDuration.WithLabelValues(repository, operation).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerExposesMetrics(t *testing.T) {
+	ObserveHTTPRequest(http.MethodGet, "/api/v1/holidays/year/:year", http.StatusOK, 20*time.Millisecond)
+	IncRateLimitRejection("/api/v1/holidays")
+	IncLogin(true)
+	IncLogin(false)
+	ObserveDBQuery("holiday", "GetAll", time.Now().Add(-time.Millisecond))
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body := rec.Body.String()
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, body, `holidayapi_http_requests_total{method="GET",route="/api/v1/holidays/year/:year",status="200"} 1`)
+	assert.Contains(t, body, `holidayapi_http_request_duration_seconds_bucket{method="GET",route="/api/v1/holidays/year/:year",status="200",le="0.025"} 1`)
+	assert.Contains(t, body, `holidayapi_rate_limit_rejections_total{route="/api/v1/holidays"} 1`)
+	assert.Contains(t, body, `holidayapi_logins_total{result="failure"} 1`)
+	assert.Contains(t, body, `holidayapi_logins_total{result="success"} 1`)
+	assert.Contains(t, body, `holidayapi_db_query_duration_seconds_count{operation="GetAll",repository="holiday"} 1`)
+	assert.Contains(t, body, `holidayapi_build_info{build_date="unknown",commit="unknown",go_version=`)
+}
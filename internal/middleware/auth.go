@@ -1,33 +1,63 @@
 package middleware
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/services"
 )
 
-// AdminAuthMiddleware validates admin API key
-func AdminAuthMiddleware(apiKey string) gin.HandlerFunc {
+// APIKeyHeader is the request header carrying a client API key
+const APIKeyHeader = "X-API-Key"
+
+// AuthMiddleware accepts either a client API key in X-API-Key or a JWT bearer token.
+// A key authenticates as its owner, limited further by the key's scopes (see RequireScope).
+func AuthMiddleware(jwtService services.JWTService, apiKeyService services.APIKeyService) gin.HandlerFunc {
+	jwtAuth := JWTAuthMiddleware(jwtService)
+
 	return func(c *gin.Context) {
-		providedKey := c.GetHeader("X-API-Key")
-		
-		if providedKey == "" {
+		rawKey := c.GetHeader(APIKeyHeader)
+		if rawKey == "" {
+			jwtAuth(c)
+			return
+		}
+
+		requestPath := fmt.Sprintf("%s %s", c.Request.Method, c.Request.URL.Path)
+		apiKey, owner, err := apiKeyService.Authenticate(rawKey, requestPath, c.ClientIP(), c.GetHeader("User-Agent"))
+		if err != nil {
 			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
 				Success: false,
 				Message: "Unauthorized",
-				Error:   "API key is required",
+				Error:   "Invalid, expired or revoked API key",
 			})
 			c.Abort()
 			return
 		}
 
-		if providedKey != apiKey {
-			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+		// Set owner info in context, as the JWT middleware does
+		c.Set("user_id", owner.ID)
+		c.Set("username", owner.Username)
+		c.Set("user_role", owner.Role)
+		c.Set("user_permissions", owner.Permissions)
+		c.Set("api_key", apiKey)
+
+		c.Next()
+	}
+}
+
+// RequireScope middleware checks that a request authenticated with an API key was granted the scope.
+// Requests authenticated with a JWT are only subject to permission checks.
+func RequireScope(scope models.APIKeyScope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKey, ok := GetAPIKey(c)
+		if ok && !apiKey.HasScope(scope) {
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
 				Success: false,
-				Message: "Unauthorized",
-				Error:   "Invalid API key",
+				Message: "Forbidden",
+				Error:   fmt.Sprintf("API key is missing the %s scope", scope),
 			})
 			c.Abort()
 			return
@@ -36,3 +66,13 @@ func AdminAuthMiddleware(apiKey string) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// GetAPIKey returns the API key the request was authenticated with, if any
+func GetAPIKey(c *gin.Context) (*models.APIKey, bool) {
+	value, exists := c.Get("api_key")
+	if !exists {
+		return nil, false
+	}
+	apiKey, ok := value.(*models.APIKey)
+	return apiKey, ok
+}
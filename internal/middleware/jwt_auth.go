@@ -11,6 +11,13 @@ import (
 	"github.com/ilramdhan/holidayapi/internal/services"
 )
 
+// passwordChangeRoutes are the only routes open to a user who must replace a temporary password
+var passwordChangeRoutes = map[string]bool{
+	"/api/v1/auth/profile":         true,
+	"/api/v1/auth/change-password": true,
+	"/api/v1/auth/logout":          true,
+}
+
 // JWTAuthMiddleware validates JWT tokens
 func JWTAuthMiddleware(jwtService services.JWTService) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -60,73 +67,67 @@ func JWTAuthMiddleware(jwtService services.JWTService) gin.HandlerFunc {
 			return
 		}
 
+		if claims.PasswordChangeRequired && !passwordChangeRoutes[c.FullPath()] {
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Success: false,
+				Message: "Password change required",
+				Error:   "Change your temporary password, then refresh your token",
+			})
+			c.Abort()
+			return
+		}
+
 		// Set user info in context
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("user_role", claims.Role)
+		c.Set("user_permissions", claims.Permissions)
 
 		c.Next()
 	}
 }
 
-// RequireRole middleware checks if user has required role
-func RequireRole(requiredRoles ...models.UserRole) gin.HandlerFunc {
+// RequirePermission middleware checks that the user's role grants every one of the permissions
+func RequirePermission(permissions ...models.Permission) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		userRole, exists := c.Get("user_role")
+		value, exists := c.Get("user_permissions")
 		if !exists {
 			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
 				Success: false,
 				Message: "Unauthorized",
-				Error:   "User role not found in context",
+				Error:   "User permissions not found in context",
 			})
 			c.Abort()
 			return
 		}
 
-		role, ok := userRole.(models.UserRole)
+		granted, ok := value.([]models.Permission)
 		if !ok {
 			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
 				Success: false,
 				Message: "Unauthorized",
-				Error:   "Invalid user role format",
+				Error:   "Invalid user permissions format",
 			})
 			c.Abort()
 			return
 		}
 
-		// Check if user has any of the required roles
-		hasPermission := false
-		for _, requiredRole := range requiredRoles {
-			if role == requiredRole {
-				hasPermission = true
-				break
+		for _, permission := range permissions {
+			if !models.HasPermission(granted, permission) {
+				c.JSON(http.StatusForbidden, models.ErrorResponse{
+					Success: false,
+					Message: "Forbidden",
+					Error:   fmt.Sprintf("Missing the %s permission", permission),
+				})
+				c.Abort()
+				return
 			}
 		}
 
-		if !hasPermission {
-			c.JSON(http.StatusForbidden, models.ErrorResponse{
-				Success: false,
-				Message: "Forbidden",
-				Error:   "Insufficient permissions",
-			})
-			c.Abort()
-			return
-		}
-
 		c.Next()
 	}
 }
 
-// RequireSuperAdmin middleware checks if user is super admin
-func RequireSuperAdmin() gin.HandlerFunc {
-	return RequireRole(models.SuperAdminRole)
-}
-
-// RequireAdminOrSuperAdmin middleware checks if user is admin or super admin
-func RequireAdminOrSuperAdmin() gin.HandlerFunc {
-	return RequireRole(models.AdminRole, models.SuperAdminRole)
-}
-
 // GetCurrentUser helper function to get current user from context
 func GetCurrentUser(c *gin.Context) (*models.JWTClaims, error) {
 	userID, exists := c.Get("user_id")
@@ -144,9 +145,14 @@ func GetCurrentUser(c *gin.Context) (*models.JWTClaims, error) {
 		return nil, fmt.Errorf("user role not found in context")
 	}
 
+	// Permissions are optional here, RequirePermission is what enforces them
+	permissions, _ := c.Get("user_permissions")
+	granted, _ := permissions.([]models.Permission)
+
 	return &models.JWTClaims{
-		UserID:   userID.(int),
-		Username: username.(string),
-		Role:     userRole.(models.UserRole),
+		UserID:      userID.(int),
+		Username:    username.(string),
+		Role:        userRole.(models.UserRole),
+		Permissions: granted,
 	}, nil
 }
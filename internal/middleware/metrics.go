@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ilramdhan/holidayapi/internal/metrics"
+)
+
+// unmatchedRoute labels requests that did not match any route, keeping label cardinality bounded
+const unmatchedRoute = "unmatched"
+
+// MetricsMiddleware records request counts and latency per route template and status
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		metrics.ObserveHTTPRequest(c.Request.Method, routeLabel(c), c.Writer.Status(), time.Since(start))
+	}
+}
+
+// routeLabel returns the route template (e.g. /api/v1/holidays/year/:year) rather than the raw path
+func routeLabel(c *gin.Context) string {
+	if route := c.FullPath(); route != "" {
+		return route
+	}
+	return unmatchedRoute
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"golang.org/x/time/rate"
 
+	"github.com/ilramdhan/holidayapi/internal/metrics"
 	"github.com/ilramdhan/holidayapi/internal/models"
 )
 
@@ -22,16 +23,11 @@ type RateLimiter struct {
 
 // NewRateLimiter creates a new rate limiter
 func NewRateLimiter(requestsPerMinute, burstSize int) *RateLimiter {
-	rl := &RateLimiter{
+	return &RateLimiter{
 		visitors: make(map[string]*rate.Limiter),
 		rate:     rate.Limit(float64(requestsPerMinute) / 60.0), // Convert to requests per second
 		burst:    burstSize,
 	}
-
-	// Clean up old visitors every 5 minutes
-	go rl.cleanupVisitors()
-
-	return rl
 }
 
 // getVisitor gets or creates a rate limiter for an IP
@@ -48,21 +44,22 @@ func (rl *RateLimiter) getVisitor(ip string) *rate.Limiter {
 	return limiter
 }
 
-// cleanupVisitors removes old visitors to prevent memory leaks
-func (rl *RateLimiter) cleanupVisitors() {
-	ticker := time.NewTicker(5 * time.Minute)
-	defer ticker.Stop()
+// CleanupStale forgets visitors whose bucket has refilled, since a new limiter would start
+// in the same state, and returns how many were removed. It is run as a background job.
+func (rl *RateLimiter) CleanupStale() int {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
 
-	for range ticker.C {
-		rl.mu.Lock()
-		for ip, limiter := range rl.visitors {
-			// Remove visitors that haven't been used recently
-			if limiter.TokensAt(time.Now()) == float64(rl.burst) {
-				delete(rl.visitors, ip)
-			}
+	now := time.Now()
+	removed := 0
+	for ip, limiter := range rl.visitors {
+		if limiter.TokensAt(now) >= float64(rl.burst) {
+			delete(rl.visitors, ip)
+			removed++
 		}
-		rl.mu.Unlock()
 	}
+
+	return removed
 }
 
 // RateLimitMiddleware returns a rate limiting middleware
@@ -77,6 +74,7 @@ func (rl *RateLimiter) RateLimitMiddleware() gin.HandlerFunc {
 		limiter := rl.getVisitor(identifier)
 
 		if !limiter.Allow() {
+			metrics.IncRateLimitRejection(routeLabel(c))
 			c.JSON(http.StatusTooManyRequests, models.ErrorResponse{
 				Success: false,
 				Message: "Rate limit exceeded",
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// APIKeyScope represents a permission granted to an API key
+type APIKeyScope string
+
+const (
+	// ScopeReadHolidays allows reading holidays through the admin API
+	ScopeReadHolidays APIKeyScope = "read:holidays"
+	// ScopeWriteHolidays allows creating, updating, deleting and importing holidays
+	ScopeWriteHolidays APIKeyScope = "write:holidays"
+	// ScopeReadAudit allows reading audit logs
+	ScopeReadAudit APIKeyScope = "read:audit"
+	// ScopeManageWebhooks allows listing, creating, updating and deleting webhook subscriptions
+	ScopeManageWebhooks APIKeyScope = "manage:webhooks"
+	// ScopeManageReminders allows managing holiday reminder rules and distribution lists
+	ScopeManageReminders APIKeyScope = "manage:reminders"
+	// ScopeManageJobs allows inspecting background jobs and running them on demand
+	ScopeManageJobs APIKeyScope = "manage:jobs"
+)
+
+// IsValid reports whether the scope is one of the known API key scopes
+func (s APIKeyScope) IsValid() bool {
+	switch s {
+	case ScopeReadHolidays, ScopeWriteHolidays, ScopeReadAudit, ScopeManageWebhooks, ScopeManageReminders, ScopeManageJobs:
+		return true
+	}
+	return false
+}
+
+// APIKey represents a client API key. The key itself is only shown once, when it is issued.
+type APIKey struct {
+	ID         int           `json:"id" db:"id"`
+	Name       string        `json:"name" db:"name"`
+	Prefix     string        `json:"prefix" db:"key_prefix"`
+	KeyHash    string        `json:"-" db:"key_hash"` // SHA-256 of the key, never the key itself
+	UserID     int           `json:"user_id" db:"user_id"`
+	Username   string        `json:"username,omitempty"`
+	Scopes     []APIKeyScope `json:"scopes" db:"scopes"`
+	ExpiresAt  *time.Time    `json:"expires_at,omitempty" db:"expires_at"`
+	LastUsedAt *time.Time    `json:"last_used_at,omitempty" db:"last_used_at"`
+	RevokedAt  *time.Time    `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedBy  *int          `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt  time.Time     `json:"created_at" db:"created_at"`
+}
+
+// HasScope reports whether the key was granted the given scope
+func (k *APIKey) HasScope(scope APIKeyScope) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsUsable reports whether the key is neither revoked nor expired at the given time
+func (k *APIKey) IsUsable(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+// CreateAPIKeyRequest represents a request to issue an API key (requires apikey:manage)
+type CreateAPIKeyRequest struct {
+	Name      string        `json:"name" validate:"required,min=3,max=100"`
+	UserID    *int          `json:"user_id,omitempty"` // owner, defaults to the issuing user
+	Scopes    []APIKeyScope `json:"scopes" validate:"required,min=1,dive,oneof=read:holidays write:holidays read:audit manage:webhooks manage:reminders manage:jobs"`
+	ExpiresAt *time.Time    `json:"expires_at,omitempty"`
+}
+
+// CreateAPIKeyResponse represents a newly issued API key including its secret value
+type CreateAPIKeyResponse struct {
+	*APIKey
+	Key string `json:"key"`
+}
+
+// JoinAPIKeyScopes encodes scopes for storage
+func JoinAPIKeyScopes(scopes []APIKeyScope) string {
+	parts := make([]string, len(scopes))
+	for i, scope := range scopes {
+		parts[i] = string(scope)
+	}
+	return strings.Join(parts, ",")
+}
+
+// SplitAPIKeyScopes decodes scopes from storage
+func SplitAPIKeyScopes(value string) []APIKeyScope {
+	scopes := []APIKeyScope{}
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			scopes = append(scopes, APIKeyScope(part))
+		}
+	}
+	return scopes
+}
@@ -1,6 +1,10 @@
 package models
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
 	"time"
 )
 
@@ -13,32 +17,78 @@ const (
 	ActionLogout       AuditAction = "LOGOUT"
 	ActionLoginFailed  AuditAction = "LOGIN_FAILED"
 	ActionTokenRefresh AuditAction = "TOKEN_REFRESH"
+	ActionLoginLocked  AuditAction = "LOGIN_LOCKED"
+	ActionLoginUnlock  AuditAction = "LOGIN_UNLOCK"
+
+	// Two-factor authentication actions
+	ActionMFAChallenge               AuditAction = "MFA_CHALLENGE"
+	ActionMFAVerify                  AuditAction = "MFA_VERIFY"
+	ActionMFARecoveryCodeUsed        AuditAction = "MFA_RECOVERY_CODE_USED"
+	ActionMFAEnroll                  AuditAction = "MFA_ENROLL"
+	ActionMFAEnable                  AuditAction = "MFA_ENABLE"
+	ActionMFADisable                 AuditAction = "MFA_DISABLE"
+	ActionMFARecoveryCodesRegenerate AuditAction = "MFA_RECOVERY_CODES_REGENERATE"
+	ActionMFAReset                   AuditAction = "MFA_RESET"
 
 	// User management actions
-	ActionUserCreate     AuditAction = "USER_CREATE"
-	ActionUserUpdate     AuditAction = "USER_UPDATE"
-	ActionUserDelete     AuditAction = "USER_DELETE"
-	ActionPasswordChange AuditAction = "PASSWORD_CHANGE"
+	ActionUserCreate           AuditAction = "USER_CREATE"
+	ActionUserUpdate           AuditAction = "USER_UPDATE"
+	ActionUserDelete           AuditAction = "USER_DELETE"
+	ActionPasswordChange       AuditAction = "PASSWORD_CHANGE"
+	ActionPasswordResetRequest AuditAction = "PASSWORD_RESET_REQUEST"
+	ActionPasswordReset        AuditAction = "PASSWORD_RESET"
+
+	// Role actions
+	ActionRoleCreate AuditAction = "ROLE_CREATE"
+	ActionRoleUpdate AuditAction = "ROLE_UPDATE"
+	ActionRoleDelete AuditAction = "ROLE_DELETE"
 
 	// Holiday management actions
-	ActionHolidayCreate AuditAction = "HOLIDAY_CREATE"
-	ActionHolidayUpdate AuditAction = "HOLIDAY_UPDATE"
-	ActionHolidayDelete AuditAction = "HOLIDAY_DELETE"
-	ActionHolidayView   AuditAction = "HOLIDAY_VIEW"
+	ActionHolidayCreate   AuditAction = "HOLIDAY_CREATE"
+	ActionHolidayUpdate   AuditAction = "HOLIDAY_UPDATE"
+	ActionHolidayDelete   AuditAction = "HOLIDAY_DELETE"
+	ActionHolidayRollback AuditAction = "HOLIDAY_ROLLBACK"
+	ActionHolidayView     AuditAction = "HOLIDAY_VIEW"
+
+	// API key actions
+	ActionAPIKeyCreate AuditAction = "API_KEY_CREATE"
+	ActionAPIKeyRevoke AuditAction = "API_KEY_REVOKE"
+	ActionAPIKeyUse    AuditAction = "API_KEY_USE"
+
+	// Webhook actions
+	ActionWebhookCreate AuditAction = "WEBHOOK_CREATE"
+	ActionWebhookUpdate AuditAction = "WEBHOOK_UPDATE"
+	ActionWebhookDelete AuditAction = "WEBHOOK_DELETE"
+
+	// Reminder actions
+	ActionReminderCreate         AuditAction = "REMINDER_CREATE"
+	ActionReminderUpdate         AuditAction = "REMINDER_UPDATE"
+	ActionReminderDelete         AuditAction = "REMINDER_DELETE"
+	ActionDistributionListCreate AuditAction = "DISTRIBUTION_LIST_CREATE"
+	ActionDistributionListUpdate AuditAction = "DISTRIBUTION_LIST_UPDATE"
+	ActionDistributionListDelete AuditAction = "DISTRIBUTION_LIST_DELETE"
 
 	// System actions
 	ActionSystemAccess AuditAction = "SYSTEM_ACCESS"
 	ActionConfigChange AuditAction = "CONFIG_CHANGE"
+	ActionJobRun       AuditAction = "JOB_RUN"
+	ActionJobTrigger   AuditAction = "JOB_TRIGGER"
+	ActionAuditExport  AuditAction = "AUDIT_EXPORT"
 )
 
 // AuditResource represents audit resource types
 type AuditResource string
 
 const (
-	ResourceAuth    AuditResource = "auth"
-	ResourceUser    AuditResource = "user"
-	ResourceHoliday AuditResource = "holiday"
-	ResourceSystem  AuditResource = "system"
+	ResourceAuth             AuditResource = "auth"
+	ResourceUser             AuditResource = "user"
+	ResourceRole             AuditResource = "role"
+	ResourceHoliday          AuditResource = "holiday"
+	ResourceAPIKey           AuditResource = "api_key"
+	ResourceWebhook          AuditResource = "webhook"
+	ResourceReminder         AuditResource = "reminder"
+	ResourceDistributionList AuditResource = "distribution_list"
+	ResourceSystem           AuditResource = "system"
 )
 
 // AuditLog represents audit log entry
@@ -54,6 +104,95 @@ type AuditLog struct {
 	UserAgent  string        `json:"user_agent" db:"user_agent"`
 	Success    bool          `json:"success" db:"success"`
 	CreatedAt  time.Time     `json:"created_at" db:"created_at"`
+	PrevHash   string        `json:"prev_hash,omitempty" db:"prev_hash"`
+	Hash       string        `json:"hash,omitempty" db:"hash"`
+}
+
+// AuditChainGenesis is the predecessor hash of the first entry of the audit chain
+var AuditChainGenesis = strings.Repeat("0", sha256.Size*2)
+
+// auditChainFields are the canonical fields of an audit log entry covered by its hash
+type auditChainFields struct {
+	PrevHash   string `json:"prev_hash"`
+	UserID     *int   `json:"user_id"`
+	Username   string `json:"username"`
+	Action     string `json:"action"`
+	Resource   string `json:"resource"`
+	ResourceID *int   `json:"resource_id"`
+	Details    string `json:"details"`
+	IPAddress  string `json:"ip_address"`
+	UserAgent  string `json:"user_agent"`
+	Success    bool   `json:"success"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// ComputeHash returns the hex SHA-256 hash of the entry's PrevHash and content. The creation
+// time is hashed in UTC at microsecond precision, which every supported database keeps.
+func (l *AuditLog) ComputeHash() string {
+	canonical, _ := json.Marshal(auditChainFields{
+		PrevHash:   l.PrevHash,
+		UserID:     l.UserID,
+		Username:   l.Username,
+		Action:     string(l.Action),
+		Resource:   string(l.Resource),
+		ResourceID: l.ResourceID,
+		Details:    l.Details,
+		IPAddress:  l.IPAddress,
+		UserAgent:  l.UserAgent,
+		Success:    l.Success,
+		CreatedAt:  l.CreatedAt.UTC().Format("2006-01-02T15:04:05.000000Z"),
+	})
+
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:])
+}
+
+// AuditChainVerification reports the outcome of walking the audit chain from its oldest entry
+type AuditChainVerification struct {
+	Valid      bool                  `json:"valid"`
+	Checked    int                   `json:"checked"`              // Chained entries whose hash matched
+	Unchained  int                   `json:"unchained"`            // Entries written before the chain was introduced
+	FirstID    *int                  `json:"first_id,omitempty"`   // Oldest chained entry; it must link to the checkpoint, or to the genesis without one
+	LastHash   string                `json:"last_hash,omitempty"`  // Hash of the newest verified entry
+	Checkpoint *AuditChainCheckpoint `json:"checkpoint,omitempty"` // Latest retention checkpoint, if retention removed chained entries
+	BrokenAtID *int                  `json:"broken_at_id,omitempty"`
+	Reason     string                `json:"reason,omitempty"`
+	VerifiedAt time.Time             `json:"verified_at"`
+}
+
+// AuditChainCheckpoint records the last chained entry removed by audit retention. The oldest
+// remaining entry must link to its hash, so a prefix deleted by other means is detected. The
+// checkpoint is appended to the chain: it links to the chain head at the time of the run and the
+// next entry links to its hash, so it cannot be forged without breaking the chain.
+type AuditChainCheckpoint struct {
+	ID              int       `json:"-" db:"id"`
+	LastDeletedID   int       `json:"last_deleted_id" db:"last_deleted_id"`
+	LastDeletedHash string    `json:"last_deleted_hash" db:"last_deleted_hash"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	PrevHash        string    `json:"prev_hash" db:"prev_hash"`
+	Hash            string    `json:"hash" db:"hash"`
+}
+
+// auditCheckpointFields are the canonical fields of a retention checkpoint covered by its hash
+type auditCheckpointFields struct {
+	PrevHash        string `json:"prev_hash"`
+	LastDeletedID   int    `json:"last_deleted_id"`
+	LastDeletedHash string `json:"last_deleted_hash"`
+	CreatedAt       string `json:"created_at"`
+}
+
+// ComputeHash returns the hex SHA-256 hash of the checkpoint's PrevHash and content, with the
+// creation time hashed as for audit log entries
+func (c *AuditChainCheckpoint) ComputeHash() string {
+	canonical, _ := json.Marshal(auditCheckpointFields{
+		PrevHash:        c.PrevHash,
+		LastDeletedID:   c.LastDeletedID,
+		LastDeletedHash: c.LastDeletedHash,
+		CreatedAt:       c.CreatedAt.UTC().Format("2006-01-02T15:04:05.000000Z"),
+	})
+
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:])
 }
 
 // AuditLogFilter represents filters for audit log queries
@@ -76,3 +215,67 @@ type AuditLogResponse struct {
 	PerPage    int        `json:"per_page"`
 	TotalPages int        `json:"total_pages"`
 }
+
+// HolidayAuditDetails represents the structured details of a holiday audit entry.
+// Before is nil for creations and After is nil for deletions.
+type HolidayAuditDetails struct {
+	Source  string                 `json:"source,omitempty"`
+	Before  *Holiday               `json:"before"`
+	After   *Holiday               `json:"after"`
+	Changes map[string]FieldChange `json:"changes,omitempty"`
+	Error   string                 `json:"error,omitempty"`
+}
+
+// NewHolidayAuditDetails builds audit details with the field-level diff between two holiday states
+func NewHolidayAuditDetails(before, after *Holiday) HolidayAuditDetails {
+	details := HolidayAuditDetails{
+		Before:  before,
+		After:   after,
+		Changes: make(map[string]FieldChange),
+	}
+
+	beforeFields := holidayAuditFields(before)
+	afterFields := holidayAuditFields(after)
+	for field, to := range afterFields {
+		if from := beforeFields[field]; from != to {
+			details.Changes[field] = FieldChange{From: from, To: to}
+		}
+	}
+	for field, from := range beforeFields {
+		if _, ok := afterFields[field]; !ok {
+			details.Changes[field] = FieldChange{From: from, To: nil}
+		}
+	}
+
+	return details
+}
+
+// holidayAuditFields returns the user-editable fields of a holiday keyed by JSON name
+func holidayAuditFields(holiday *Holiday) map[string]interface{} {
+	if holiday == nil {
+		return map[string]interface{}{}
+	}
+
+	var province, decreeNumber, decreeDate interface{}
+	if holiday.Province != nil {
+		province = *holiday.Province
+	}
+	if holiday.DecreeNumber != nil {
+		decreeNumber = *holiday.DecreeNumber
+	}
+	if holiday.DecreeDate != nil {
+		decreeDate = holiday.DecreeDate.Format("2006-01-02")
+	}
+
+	return map[string]interface{}{
+		"name":          holiday.Name,
+		"date":          holiday.Date.Format("2006-01-02"),
+		"type":          string(holiday.Type),
+		"province":      province,
+		"description":   holiday.Description,
+		"is_active":     holiday.IsActive,
+		"status":        string(holiday.Status),
+		"decree_number": decreeNumber,
+		"decree_date":   decreeDate,
+	}
+}
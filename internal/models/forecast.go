@@ -0,0 +1,20 @@
+package models
+
+// ForecastHoliday is a holiday in a multi-year forecast. Recorded holidays come from the
+// database; computed ones are projected from calendar rules and stay provisional until an
+// admin records the date from the official decree.
+type ForecastHoliday struct {
+	Holiday
+	Computed bool   `json:"computed"`           // Projected, not yet confirmed by a recorded holiday
+	Calendar string `json:"calendar,omitempty"` // Calendar a computed date is derived from, e.g. hijri
+}
+
+// HolidayForecastResponse lists recorded and computed holidays for a range of years
+type HolidayForecastResponse struct {
+	FromYear  int               `json:"from_year"`
+	ToYear    int               `json:"to_year"`
+	Province  *string           `json:"province,omitempty"`
+	Confirmed int               `json:"confirmed"` // Number of recorded holidays
+	Computed  int               `json:"computed"`  // Number of provisional computed holidays
+	Holidays  []ForecastHoliday `json:"holidays"`
+}
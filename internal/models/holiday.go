@@ -12,39 +12,128 @@ const (
 	NationalHoliday HolidayType = "national"
 	// CollectiveLeave represents "Cuti Bersama"
 	CollectiveLeave HolidayType = "collective_leave"
+	// RegionalHoliday represents a day off observed only in one province
+	RegionalHoliday HolidayType = "regional"
 )
 
+// IsValid reports whether t is a known holiday type
+func (t HolidayType) IsValid() bool {
+	return t == NationalHoliday || t == CollectiveLeave || t == RegionalHoliday
+}
+
+// HolidayStatus represents how far a holiday has come through the decree process
+type HolidayStatus string
+
+const (
+	// StatusProvisional is an announced holiday whose decree is not ratified yet
+	StatusProvisional HolidayStatus = "provisional"
+	// StatusOfficial is a holiday set by a ratified decree (SKB)
+	StatusOfficial HolidayStatus = "official"
+	// StatusRevised is an official holiday changed or moved by a revised decree
+	StatusRevised HolidayStatus = "revised"
+	// StatusCancelled is a holiday withdrawn by decree
+	StatusCancelled HolidayStatus = "cancelled"
+)
+
+// AllHolidayStatuses lists every holiday status in lifecycle order
+var AllHolidayStatuses = []HolidayStatus{StatusProvisional, StatusOfficial, StatusRevised, StatusCancelled}
+
+// holidayStatusTransitions lists the statuses each status may change to
+var holidayStatusTransitions = map[HolidayStatus][]HolidayStatus{
+	StatusProvisional: {StatusOfficial, StatusCancelled},
+	StatusOfficial:    {StatusRevised, StatusCancelled},
+	StatusRevised:     {StatusRevised, StatusCancelled},
+	StatusCancelled:   {},
+}
+
+// IsValid reports whether s is a known holiday status
+func (s HolidayStatus) IsValid() bool {
+	_, ok := holidayStatusTransitions[s]
+	return ok
+}
+
+// CanTransitionTo reports whether a holiday with status s may change to next.
+// A revised holiday may be revised again by a later decree.
+func (s HolidayStatus) CanTransitionTo(next HolidayStatus) bool {
+	for _, allowed := range holidayStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
 // Holiday represents a holiday record
 type Holiday struct {
-	ID          int         `json:"id" db:"id"`
-	Name        string      `json:"name" db:"name" validate:"required,min=3,max=255"`
-	Date        time.Time   `json:"date" db:"date" validate:"required"`
-	Type        HolidayType `json:"type" db:"type" validate:"required,oneof=national collective_leave"`
-	Description string      `json:"description" db:"description" validate:"max=1000"`
-	IsActive    bool        `json:"is_active" db:"is_active"`
-	CreatedAt   time.Time   `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time   `json:"updated_at" db:"updated_at"`
+	ID           int           `json:"id" db:"id"`
+	Name         string        `json:"name" db:"name" validate:"required,min=3,max=255"`
+	Date         time.Time     `json:"date" db:"date" validate:"required"`
+	Type         HolidayType   `json:"type" db:"type" validate:"required,oneof=national collective_leave regional"`
+	Province     *string       `json:"province,omitempty" db:"province"` // ISO 3166-2:ID code, nil for nationwide holidays
+	Description  string        `json:"description" db:"description" validate:"max=1000"`
+	IsActive     bool          `json:"is_active" db:"is_active"`
+	Status       HolidayStatus `json:"status" db:"status"`
+	DecreeNumber *string       `json:"decree_number,omitempty" db:"decree_number"` // Legal basis of the current status, e.g. an SKB number
+	DecreeDate   *time.Time    `json:"decree_date,omitempty" db:"decree_date"`
+	CreatedAt    time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at" db:"updated_at"`
 }
 
 // CreateHolidayRequest represents request to create a holiday
 type CreateHolidayRequest struct {
-	Name        string      `json:"name" validate:"required,min=3,max=255"`
-	Date        string      `json:"date" validate:"required"` // Format: YYYY-MM-DD
-	Type        HolidayType `json:"type" validate:"required,oneof=national collective_leave"`
-	Description string      `json:"description" validate:"max=1000"`
+	Name         string        `json:"name" validate:"required,min=3,max=255"`
+	Date         string        `json:"date" validate:"required"` // Format: YYYY-MM-DD
+	Type         HolidayType   `json:"type" validate:"required,oneof=national collective_leave regional"`
+	Province     *string       `json:"province,omitempty"` // Required for regional holidays
+	Description  string        `json:"description" validate:"max=1000"`
+	Status       HolidayStatus `json:"status,omitempty" validate:"omitempty,oneof=provisional official"` // Defaults to official
+	DecreeNumber *string       `json:"decree_number,omitempty" validate:"omitempty,max=100"`
+	DecreeDate   *string       `json:"decree_date,omitempty"` // Format: YYYY-MM-DD
 }
 
 // UpdateHolidayRequest represents request to update a holiday
 type UpdateHolidayRequest struct {
 	Name        *string      `json:"name,omitempty" validate:"omitempty,min=3,max=255"`
 	Date        *string      `json:"date,omitempty"` // Format: YYYY-MM-DD
-	Type        *HolidayType `json:"type,omitempty" validate:"omitempty,oneof=national collective_leave"`
+	Type        *HolidayType `json:"type,omitempty" validate:"omitempty,oneof=national collective_leave regional"`
+	Province    *string      `json:"province,omitempty"` // Empty string makes the holiday nationwide
 	Description *string      `json:"description,omitempty" validate:"omitempty,max=1000"`
 	IsActive    *bool        `json:"is_active,omitempty"`
+	// Status changes must follow the allowed transitions and name the decree behind them
+	Status       *HolidayStatus `json:"status,omitempty" validate:"omitempty,oneof=provisional official revised cancelled"`
+	DecreeNumber *string        `json:"decree_number,omitempty" validate:"omitempty,max=100"` // Empty string clears it
+	DecreeDate   *string        `json:"decree_date,omitempty"`                                // Format: YYYY-MM-DD, empty string clears it
+}
+
+// HolidayScope narrows which holidays are effective for a query
+type HolidayScope struct {
+	// Province adds the holidays of one province to the nationwide ones.
+	// When nil, only nationwide holidays are returned.
+	Province *string `json:"province,omitempty"`
+	// AsOf shows the holidays as they were recorded at that point in time,
+	// replaying the revision history. When nil, the current data is used.
+	AsOf *time.Time `json:"as_of,omitempty"`
+	// Statuses selects holidays by decree status.
+	// When empty, every status except cancelled is returned.
+	Statuses []HolidayStatus `json:"status,omitempty"`
+}
+
+// MatchesStatus reports whether the scope selects holidays with the given status
+func (s HolidayScope) MatchesStatus(status HolidayStatus) bool {
+	if len(s.Statuses) == 0 {
+		return status != StatusCancelled
+	}
+	for _, selected := range s.Statuses {
+		if selected == status {
+			return true
+		}
+	}
+	return false
 }
 
 // HolidayFilter represents filters for querying holidays
 type HolidayFilter struct {
+	HolidayScope
 	Year      *int         `json:"year,omitempty"`
 	Month     *int         `json:"month,omitempty"`
 	Day       *int         `json:"day,omitempty"`
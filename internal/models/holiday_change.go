@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// HolidayChangeType names an event published on the holiday change feed
+type HolidayChangeType string
+
+const (
+	// ChangeHolidayCreated is published when a holiday is created, including by a bulk import
+	ChangeHolidayCreated HolidayChangeType = "holiday.created"
+	// ChangeHolidayUpdated is published when a holiday is updated or rolled back
+	ChangeHolidayUpdated HolidayChangeType = "holiday.updated"
+	// ChangeHolidayDeleted is published when a holiday is deleted
+	ChangeHolidayDeleted HolidayChangeType = "holiday.deleted"
+)
+
+// HolidayChange is one entry of the persisted holiday change feed.
+// Sequence increases with every change and is used as the SSE event ID.
+type HolidayChange struct {
+	Sequence  int64             `json:"sequence" db:"id"`
+	Type      HolidayChangeType `json:"type" db:"event_type"`
+	HolidayID int               `json:"holiday_id" db:"holiday_id"`
+	Holiday   Holiday           `json:"holiday" db:"payload"` // State of the holiday after the change
+	CreatedAt time.Time         `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,46 @@
+package models
+
+// ImportAction represents what an import row does to the stored holidays
+type ImportAction string
+
+const (
+	// ImportCreate means the row adds a new holiday
+	ImportCreate ImportAction = "create"
+	// ImportUpdate means the row changes an existing holiday
+	ImportUpdate ImportAction = "update"
+	// ImportUnchanged means the row already matches an existing holiday
+	ImportUnchanged ImportAction = "unchanged"
+	// ImportConflict means the row repeats a holiday listed earlier in the file
+	ImportConflict ImportAction = "conflict"
+	// ImportInvalid means the row fails validation
+	ImportInvalid ImportAction = "invalid"
+)
+
+// FieldChange represents the old and new value of a changed field
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// HolidayImportRow represents the planned outcome of a single import row
+type HolidayImportRow struct {
+	Row       int                    `json:"row"`
+	Action    ImportAction           `json:"action"`
+	Holiday   CreateHolidayRequest   `json:"holiday"`
+	HolidayID *int                   `json:"holiday_id,omitempty"`
+	Changes   map[string]FieldChange `json:"changes,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+}
+
+// HolidayImportResult represents the diff and outcome of a bulk import
+type HolidayImportResult struct {
+	DryRun    bool               `json:"dry_run"`
+	Committed bool               `json:"committed"`
+	Total     int                `json:"total"`
+	Created   int                `json:"created"`
+	Updated   int                `json:"updated"`
+	Unchanged int                `json:"unchanged"`
+	Conflicts int                `json:"conflicts"`
+	Invalid   int                `json:"invalid"`
+	Rows      []HolidayImportRow `json:"rows"`
+}
@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// RevisionOperation names the change recorded by a holiday revision
+type RevisionOperation string
+
+const (
+	// RevisionCreate records a newly created holiday
+	RevisionCreate RevisionOperation = "create"
+	// RevisionUpdate records an update, including bulk import updates
+	RevisionUpdate RevisionOperation = "update"
+	// RevisionDelete records a soft delete
+	RevisionDelete RevisionOperation = "delete"
+	// RevisionRollback records a holiday restored to an earlier revision
+	RevisionRollback RevisionOperation = "rollback"
+)
+
+// HolidayRevision is an immutable snapshot of a holiday taken after each change
+type HolidayRevision struct {
+	ID             int               `json:"id" db:"id"`
+	HolidayID      int               `json:"holiday_id" db:"holiday_id"`
+	Revision       int               `json:"revision" db:"revision"`
+	Operation      RevisionOperation `json:"operation" db:"operation"`
+	SourceRevision *int              `json:"source_revision,omitempty" db:"source_revision"` // Set on rollbacks
+	Name           string            `json:"name" db:"name"`
+	Date           time.Time         `json:"date" db:"date"`
+	Type           HolidayType       `json:"type" db:"type"`
+	Province       *string           `json:"province,omitempty" db:"province"`
+	Description    string            `json:"description" db:"description"`
+	IsActive       bool              `json:"is_active" db:"is_active"`
+	Status         HolidayStatus     `json:"status" db:"status"`
+	DecreeNumber   *string           `json:"decree_number,omitempty" db:"decree_number"`
+	DecreeDate     *time.Time        `json:"decree_date,omitempty" db:"decree_date"`
+	CreatedAt      time.Time         `json:"created_at" db:"created_at"`
+}
+
+// Holiday returns the holiday as it was at this revision
+func (r HolidayRevision) Holiday() Holiday {
+	return Holiday{
+		ID:           r.HolidayID,
+		Name:         r.Name,
+		Date:         r.Date,
+		Type:         r.Type,
+		Province:     r.Province,
+		Description:  r.Description,
+		IsActive:     r.IsActive,
+		Status:       r.Status,
+		DecreeNumber: r.DecreeNumber,
+		DecreeDate:   r.DecreeDate,
+		UpdatedAt:    r.CreatedAt,
+	}
+}
+
+// RollbackHolidayRequest represents request to restore a holiday to an earlier revision
+type RollbackHolidayRequest struct {
+	Revision int `json:"revision" validate:"required,min=1"`
+}
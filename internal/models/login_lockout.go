@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+)
+
+// LockoutScope is what failed logins are counted against
+type LockoutScope string
+
+const (
+	// LockoutScopeUsername counts failed logins per username, whether or not the user exists
+	LockoutScopeUsername LockoutScope = "username"
+	// LockoutScopeIP counts failed logins per client IP address
+	LockoutScopeIP LockoutScope = "ip"
+)
+
+// LoginLockout tracks recent failed logins of a username or IP address
+type LoginLockout struct {
+	ID             int          `json:"id" db:"id"`
+	Scope          LockoutScope `json:"scope" db:"scope"`
+	Key            string       `json:"key" db:"lock_key"`                        // Lowercased username or IP address
+	FailedAttempts int          `json:"failed_attempts" db:"failed_attempts"`     // Failures since the last lock
+	Lockouts       int          `json:"lockouts" db:"lockouts"`                   // Locks so far; each doubles the next lock
+	LockedUntil    *time.Time   `json:"locked_until,omitempty" db:"locked_until"` // Logins are refused until then
+	LastFailedAt   time.Time    `json:"last_failed_at" db:"last_failed_at"`
+	CreatedAt      time.Time    `json:"created_at" db:"created_at"`
+}
+
+// IsLocked reports whether the lock is in effect at a point in time
+func (l *LoginLockout) IsLocked(now time.Time) bool {
+	return l.LockedUntil != nil && l.LockedUntil.After(now)
+}
@@ -0,0 +1,28 @@
+package models
+
+// LongWeekend is a run of three or more consecutive days off that contains at least one holiday,
+// or a shorter run that a single day of leave (a bridge day) would extend to four days or more
+type LongWeekend struct {
+	StartDate  string      `json:"start_date"`
+	EndDate    string      `json:"end_date"`
+	Days       int         `json:"days"` // Consecutive days off without taking leave
+	Holidays   []Holiday   `json:"holidays"`
+	BridgeDays []BridgeDay `json:"bridge_days"`
+}
+
+// BridgeDay is a single working day squeezed between days off (hari kejepit, harpitnas).
+// Taking it as leave joins the days off on both sides into one longer break.
+type BridgeDay struct {
+	Date      string `json:"date"`
+	StartDate string `json:"start_date"` // First day of the break when the bridge day is taken
+	EndDate   string `json:"end_date"`   // Last day of the break when the bridge day is taken
+	Days      int    `json:"days"`       // Length of that break, including the bridge day
+}
+
+// LongWeekendResponse lists the long weekends overlapping a year
+type LongWeekendResponse struct {
+	Year                   int           `json:"year"`
+	IncludeCollectiveLeave bool          `json:"include_collective_leave"`
+	Province               *string       `json:"province,omitempty"`
+	LongWeekends           []LongWeekend `json:"long_weekends"`
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+)
+
+// UserMFA represents a user's TOTP enrollment
+type UserMFA struct {
+	UserID       int        `json:"user_id" db:"user_id"`
+	Secret       string     `json:"-" db:"secret"`
+	Enabled      bool       `json:"enabled" db:"enabled"`  // False until the user confirms a first code
+	LastUsedStep int64      `json:"-" db:"last_used_step"` // Newest accepted time step; older codes are refused
+	EnabledAt    *time.Time `json:"enabled_at,omitempty" db:"enabled_at"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+}
+
+// MFAChallenge is returned by login instead of tokens when a second factor is needed
+type MFAChallenge struct {
+	MFARequired        bool   `json:"mfa_required"`
+	MFAToken           string `json:"mfa_token"`
+	EnrollmentRequired bool   `json:"enrollment_required"` // The user's role requires MFA but none is set up yet
+	ExpiresIn          int64  `json:"expires_in"`          // seconds
+}
+
+// MFALoginRequest completes a login with an authenticator code or a recovery code
+type MFALoginRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+// MFATokenRequest identifies a login waiting for its second factor
+type MFATokenRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+}
+
+// MFAEnrollment holds a new secret for the user to add to an authenticator app
+type MFAEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"` // otpauth:// URI to render as a QR code
+}
+
+// MFACodeRequest represents a request confirmed with an authenticator code
+type MFACodeRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// MFADisableRequest represents a request to turn MFA off
+type MFADisableRequest struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required"` // Authenticator code or recovery code
+}
+
+// MFARecoveryCodesResponse holds newly generated recovery codes, shown only once
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// MFAStatus describes a user's MFA setup
+type MFAStatus struct {
+	Enabled                bool       `json:"enabled"`
+	Pending                bool       `json:"pending"`  // Enrolled but not confirmed yet
+	Required               bool       `json:"required"` // The user's role requires MFA
+	EnabledAt              *time.Time `json:"enabled_at,omitempty"`
+	RecoveryCodesRemaining int        `json:"recovery_codes_remaining"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"strings"
+)
+
+// Province represents an Indonesian province identified by its ISO 3166-2:ID code
+type Province struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// Provinces lists all Indonesian provinces
+var Provinces = []Province{
+	{Code: "ID-AC", Name: "Aceh"},
+	{Code: "ID-SU", Name: "Sumatera Utara"},
+	{Code: "ID-SB", Name: "Sumatera Barat"},
+	{Code: "ID-RI", Name: "Riau"},
+	{Code: "ID-KR", Name: "Kepulauan Riau"},
+	{Code: "ID-JA", Name: "Jambi"},
+	{Code: "ID-SS", Name: "Sumatera Selatan"},
+	{Code: "ID-BB", Name: "Kepulauan Bangka Belitung"},
+	{Code: "ID-BE", Name: "Bengkulu"},
+	{Code: "ID-LA", Name: "Lampung"},
+	{Code: "ID-JK", Name: "DKI Jakarta"},
+	{Code: "ID-JB", Name: "Jawa Barat"},
+	{Code: "ID-BT", Name: "Banten"},
+	{Code: "ID-JT", Name: "Jawa Tengah"},
+	{Code: "ID-YO", Name: "DI Yogyakarta"},
+	{Code: "ID-JI", Name: "Jawa Timur"},
+	{Code: "ID-BA", Name: "Bali"},
+	{Code: "ID-NB", Name: "Nusa Tenggara Barat"},
+	{Code: "ID-NT", Name: "Nusa Tenggara Timur"},
+	{Code: "ID-KB", Name: "Kalimantan Barat"},
+	{Code: "ID-KT", Name: "Kalimantan Tengah"},
+	{Code: "ID-KS", Name: "Kalimantan Selatan"},
+	{Code: "ID-KI", Name: "Kalimantan Timur"},
+	{Code: "ID-KU", Name: "Kalimantan Utara"},
+	{Code: "ID-SA", Name: "Sulawesi Utara"},
+	{Code: "ID-ST", Name: "Sulawesi Tengah"},
+	{Code: "ID-SN", Name: "Sulawesi Selatan"},
+	{Code: "ID-SG", Name: "Sulawesi Tenggara"},
+	{Code: "ID-GO", Name: "Gorontalo"},
+	{Code: "ID-SR", Name: "Sulawesi Barat"},
+	{Code: "ID-MA", Name: "Maluku"},
+	{Code: "ID-MU", Name: "Maluku Utara"},
+	{Code: "ID-PA", Name: "Papua"},
+	{Code: "ID-PB", Name: "Papua Barat"},
+	{Code: "ID-PD", Name: "Papua Barat Daya"},
+	{Code: "ID-PT", Name: "Papua Tengah"},
+	{Code: "ID-PE", Name: "Papua Pegunungan"},
+	{Code: "ID-PS", Name: "Papua Selatan"},
+}
+
+// NormalizeProvinceCode returns the canonical ISO 3166-2:ID code for a province.
+// Codes are case-insensitive and the "ID-" prefix is optional, so "ba" and "ID-BA" both mean Bali.
+func NormalizeProvinceCode(code string) (string, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !strings.HasPrefix(code, "ID-") {
+		code = "ID-" + code
+	}
+
+	for _, province := range Provinces {
+		if province.Code == code {
+			return code, true
+		}
+	}
+	return "", false
+}
+
+// ProvinceName returns the name of a province by its canonical code
+func ProvinceName(code string) string {
+	for _, province := range Provinces {
+		if province.Code == code {
+			return province.Name
+		}
+	}
+	return code
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+// RefreshToken represents a server-side record of an issued refresh token
+type RefreshToken struct {
+	ID         int        `json:"id" db:"id"`
+	UserID     int        `json:"user_id" db:"user_id"`
+	TokenHash  string     `json:"-" db:"token_hash"` // SHA-256 of the token, never the token itself
+	FamilyID   string     `json:"family_id" db:"family_id"`
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	ReplacedBy *int       `json:"replaced_by,omitempty" db:"replaced_by"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// LogoutRequest represents logout request
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// RevokeSessionsResponse represents the result of revoking a user's sessions
+type RevokeSessionsResponse struct {
+	UserID        int   `json:"user_id"`
+	RevokedTokens int64 `json:"revoked_tokens"`
+}
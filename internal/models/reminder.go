@@ -0,0 +1,118 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// ReminderKind selects which holidays a reminder rule announces and when
+type ReminderKind string
+
+const (
+	// ReminderDaysBefore announces, every day, the holidays exactly DaysBefore days ahead
+	ReminderDaysBefore ReminderKind = "days_before"
+	// ReminderNextWeek announces, on SendWeekday, the holidays of the following Monday to Sunday
+	ReminderNextWeek ReminderKind = "next_week"
+)
+
+// ReminderRule emails its recipients about upcoming holidays
+type ReminderRule struct {
+	ID           int           `json:"id" db:"id"`
+	Name         string        `json:"name" db:"name"`
+	Kind         ReminderKind  `json:"kind" db:"kind"`
+	DaysBefore   int           `json:"days_before,omitempty" db:"days_before"`
+	SendWeekday  time.Weekday  `json:"send_weekday" db:"send_weekday"`   // 0 = Sunday, used by next_week rules
+	HolidayTypes []HolidayType `json:"holiday_types" db:"holiday_types"` // Empty means every type
+	Province     *string       `json:"province,omitempty" db:"province"` // Adds the province's regional holidays
+	Recipients   []string      `json:"recipients" db:"recipients"`       // Email addresses or distribution list names
+	IsActive     bool          `json:"is_active" db:"is_active"`
+	LastRunOn    *time.Time    `json:"last_run_on,omitempty" db:"last_run_on"`
+	CreatedBy    *int          `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt    time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+// MatchesType reports whether the rule announces holidays of the given type
+func (r *ReminderRule) MatchesType(holidayType HolidayType) bool {
+	if len(r.HolidayTypes) == 0 {
+		return true
+	}
+	for _, t := range r.HolidayTypes {
+		if t == holidayType {
+			return true
+		}
+	}
+	return false
+}
+
+// DistributionList is a named group of email addresses that reminder rules can send to
+type DistributionList struct {
+	ID        int       `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	Members   []string  `json:"members" db:"members"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CreateReminderRuleRequest represents a request to add a reminder rule
+type CreateReminderRuleRequest struct {
+	Name         string        `json:"name" validate:"required,min=1,max=150"`
+	Kind         ReminderKind  `json:"kind" validate:"required,oneof=days_before next_week"`
+	DaysBefore   int           `json:"days_before" validate:"min=0,max=365"`
+	SendWeekday  *int          `json:"send_weekday,omitempty" validate:"omitempty,min=0,max=6"` // Defaults to 5 (Friday)
+	HolidayTypes []HolidayType `json:"holiday_types,omitempty" validate:"omitempty,dive,oneof=national collective_leave regional"`
+	Province     *string       `json:"province,omitempty"`
+	Recipients   []string      `json:"recipients" validate:"required,min=1,dive,required,max=254"`
+}
+
+// UpdateReminderRuleRequest represents a request to change a reminder rule
+type UpdateReminderRuleRequest struct {
+	Name         *string       `json:"name,omitempty" validate:"omitempty,min=1,max=150"`
+	Kind         *ReminderKind `json:"kind,omitempty" validate:"omitempty,oneof=days_before next_week"`
+	DaysBefore   *int          `json:"days_before,omitempty" validate:"omitempty,min=0,max=365"`
+	SendWeekday  *int          `json:"send_weekday,omitempty" validate:"omitempty,min=0,max=6"`
+	HolidayTypes []HolidayType `json:"holiday_types,omitempty" validate:"omitempty,dive,oneof=national collective_leave regional"`
+	Province     *string       `json:"province,omitempty"` // An empty string removes the province
+	Recipients   []string      `json:"recipients,omitempty" validate:"omitempty,min=1,dive,required,max=254"`
+	IsActive     *bool         `json:"is_active,omitempty"`
+}
+
+// DistributionListRequest represents a request to create or replace a distribution list
+type DistributionListRequest struct {
+	Name    string   `json:"name" validate:"required,min=1,max=100,excludesall=@0x2C"`
+	Members []string `json:"members" validate:"required,min=1,dive,required,email,max=254"`
+}
+
+// JoinList encodes a list of strings for storage
+func JoinList(values []string) string {
+	return strings.Join(values, ",")
+}
+
+// SplitList decodes a list of strings from storage
+func SplitList(value string) []string {
+	values := []string{}
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
+
+// JoinHolidayTypes encodes holiday types for storage
+func JoinHolidayTypes(types []HolidayType) string {
+	parts := make([]string, len(types))
+	for i, t := range types {
+		parts[i] = string(t)
+	}
+	return strings.Join(parts, ",")
+}
+
+// SplitHolidayTypes decodes holiday types from storage
+func SplitHolidayTypes(value string) []HolidayType {
+	types := []HolidayType{}
+	for _, part := range SplitList(value) {
+		types = append(types, HolidayType(part))
+	}
+	return types
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Permission is a single action a role may allow, named "<resource>:<action>"
+type Permission string
+
+const (
+	// PermissionHolidayRead allows reading holidays through the admin API, including their history
+	PermissionHolidayRead Permission = "holiday:read"
+	// PermissionHolidayCreate allows creating and importing holidays
+	PermissionHolidayCreate Permission = "holiday:create"
+	// PermissionHolidayUpdate allows updating holidays and rolling them back
+	PermissionHolidayUpdate Permission = "holiday:update"
+	// PermissionHolidayDelete allows deleting holidays
+	PermissionHolidayDelete Permission = "holiday:delete"
+	// PermissionAuditRead allows reading and exporting the audit logs of every user
+	PermissionAuditRead Permission = "audit:read"
+	// PermissionAuditVerify allows verifying the audit log hash chain
+	PermissionAuditVerify Permission = "audit:verify"
+	// PermissionUserRead allows listing users
+	PermissionUserRead Permission = "user:read"
+	// PermissionUserManage allows creating, updating and deactivating users and their sessions, MFA and login locks
+	PermissionUserManage Permission = "user:manage"
+	// PermissionRoleManage allows managing roles
+	PermissionRoleManage Permission = "role:manage"
+	// PermissionAPIKeyManage allows issuing and revoking client API keys
+	PermissionAPIKeyManage Permission = "apikey:manage"
+	// PermissionWebhookManage allows managing webhook subscriptions
+	PermissionWebhookManage Permission = "webhook:manage"
+	// PermissionReminderManage allows managing holiday reminders and distribution lists
+	PermissionReminderManage Permission = "reminder:manage"
+	// PermissionJobManage allows inspecting and running background jobs
+	PermissionJobManage Permission = "job:manage"
+)
+
+// AllPermissions lists every permission, in the order they are documented
+var AllPermissions = []Permission{
+	PermissionHolidayRead, PermissionHolidayCreate, PermissionHolidayUpdate, PermissionHolidayDelete,
+	PermissionAuditRead, PermissionAuditVerify,
+	PermissionUserRead, PermissionUserManage,
+	PermissionRoleManage, PermissionAPIKeyManage, PermissionWebhookManage, PermissionReminderManage, PermissionJobManage,
+}
+
+// IsValid reports whether the permission is one of the known permissions
+func (p Permission) IsValid() bool {
+	for _, permission := range AllPermissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Role is a named set of permissions that users are assigned to
+type Role struct {
+	ID          int          `json:"id" db:"id"`
+	Name        UserRole     `json:"name" db:"name"`
+	Description string       `json:"description" db:"description"`
+	Permissions []Permission `json:"permissions" db:"permissions"`
+	IsSystem    bool         `json:"is_system" db:"is_system"` // Built-in roles cannot be deleted
+	CreatedAt   time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// HasPermission reports whether the role grants the given permission
+func (r *Role) HasPermission(permission Permission) bool {
+	return HasPermission(r.Permissions, permission)
+}
+
+// CreateRoleRequest represents a request to define a custom role
+type CreateRoleRequest struct {
+	Name        UserRole     `json:"name" validate:"required,min=3,max=50"`
+	Description string       `json:"description,omitempty" validate:"max=255"`
+	Permissions []Permission `json:"permissions" validate:"required,min=1"`
+}
+
+// UpdateRoleRequest represents a request to change the description or permissions of a role
+type UpdateRoleRequest struct {
+	Description *string      `json:"description,omitempty" validate:"omitempty,max=255"`
+	Permissions []Permission `json:"permissions,omitempty" validate:"omitempty,min=1"`
+}
+
+// HasPermission reports whether the permissions include the given one
+func HasPermission(permissions []Permission, permission Permission) bool {
+	for _, p := range permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// JoinPermissions encodes permissions for storage
+func JoinPermissions(permissions []Permission) string {
+	parts := make([]string, len(permissions))
+	for i, permission := range permissions {
+		parts[i] = string(permission)
+	}
+	return strings.Join(parts, ",")
+}
+
+// SplitPermissions decodes permissions from storage
+func SplitPermissions(value string) []Permission {
+	permissions := []Permission{}
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			permissions = append(permissions, Permission(part))
+		}
+	}
+	return permissions
+}
@@ -4,27 +4,29 @@ import (
 	"time"
 )
 
-// UserRole represents user roles
+// UserRole is the name of the role a user is assigned to
 type UserRole string
 
 const (
-	// SuperAdminRole represents super admin role
+	// SuperAdminRole is the built-in role holding every permission
 	SuperAdminRole UserRole = "super_admin"
-	// AdminRole represents admin role
+	// AdminRole is the built-in role for holiday editors
 	AdminRole UserRole = "admin"
 )
 
 // User represents a user in the system
 type User struct {
-	ID        int        `json:"id" db:"id"`
-	Username  string     `json:"username" db:"username" validate:"required,min=3,max=50"`
-	Email     string     `json:"email" db:"email" validate:"required,email"`
-	Password  string     `json:"-" db:"password"` // Never expose password in JSON
-	Role      UserRole   `json:"role" db:"role" validate:"required,oneof=super_admin admin"`
-	IsActive  bool       `json:"is_active" db:"is_active"`
-	CreatedAt time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
-	LastLogin *time.Time `json:"last_login,omitempty" db:"last_login"`
+	ID                 int          `json:"id" db:"id"`
+	Username           string       `json:"username" db:"username" validate:"required,min=3,max=50"`
+	Email              string       `json:"email" db:"email" validate:"required,email"`
+	Password           string       `json:"-" db:"password"` // Never expose password in JSON
+	Role               UserRole     `json:"role" db:"role" validate:"required,max=50"`
+	Permissions        []Permission `json:"permissions,omitempty" db:"-"` // Granted by the role, loaded when tokens are issued
+	IsActive           bool         `json:"is_active" db:"is_active"`
+	MustChangePassword bool         `json:"must_change_password" db:"must_change_password"`
+	CreatedAt          time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time    `json:"updated_at" db:"updated_at"`
+	LastLogin          *time.Time   `json:"last_login,omitempty" db:"last_login"`
 }
 
 // LoginRequest represents login request
@@ -33,12 +35,12 @@ type LoginRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
-// RegisterRequest represents registration request (only for super admin)
+// RegisterRequest represents registration request (requires user:manage)
 type RegisterRequest struct {
 	Username string   `json:"username" validate:"required,min=3,max=50"`
 	Email    string   `json:"email" validate:"required,email"`
 	Password string   `json:"password" validate:"required,min=8"`
-	Role     UserRole `json:"role" validate:"required,oneof=super_admin admin"`
+	Role     UserRole `json:"role" validate:"required,max=50"`
 }
 
 // ChangePasswordRequest represents change password request
@@ -50,28 +52,37 @@ type ChangePasswordRequest struct {
 // UpdateUserRequest represents update user request
 type UpdateUserRequest struct {
 	Email    *string   `json:"email,omitempty" validate:"omitempty,email"`
-	Role     *UserRole `json:"role,omitempty" validate:"omitempty,oneof=super_admin admin"`
+	Role     *UserRole `json:"role,omitempty" validate:"omitempty,max=50"`
 	IsActive *bool     `json:"is_active,omitempty"`
 }
 
+// AdminResetPasswordRequest represents an administrator setting a temporary password for a user
+type AdminResetPasswordRequest struct {
+	TemporaryPassword string `json:"temporary_password" validate:"required,min=8"`
+}
+
 // AuthResponse represents authentication response
 type AuthResponse struct {
-	User         *UserResponse `json:"user"`
-	AccessToken  string        `json:"access_token"`
-	RefreshToken string        `json:"refresh_token"`
-	ExpiresIn    int64         `json:"expires_in"` // seconds
-	TokenType    string        `json:"token_type"`
+	User             *UserResponse `json:"user"`
+	AccessToken      string        `json:"access_token"`
+	RefreshToken     string        `json:"refresh_token"`
+	ExpiresIn        int64         `json:"expires_in"`         // seconds
+	RefreshExpiresIn int64         `json:"refresh_expires_in"` // seconds
+	TokenType        string        `json:"token_type"`
+	RecoveryCodes    []string      `json:"recovery_codes,omitempty"` // Only set when the login completed MFA enrollment
 }
 
 // UserResponse represents user data in responses (without sensitive info)
 type UserResponse struct {
-	ID        int        `json:"id"`
-	Username  string     `json:"username"`
-	Email     string     `json:"email"`
-	Role      UserRole   `json:"role"`
-	IsActive  bool       `json:"is_active"`
-	CreatedAt time.Time  `json:"created_at"`
-	LastLogin *time.Time `json:"last_login,omitempty"`
+	ID                 int          `json:"id"`
+	Username           string       `json:"username"`
+	Email              string       `json:"email"`
+	Role               UserRole     `json:"role"`
+	Permissions        []Permission `json:"permissions,omitempty"`
+	IsActive           bool         `json:"is_active"`
+	MustChangePassword bool         `json:"must_change_password"`
+	CreatedAt          time.Time    `json:"created_at"`
+	LastLogin          *time.Time   `json:"last_login,omitempty"`
 }
 
 // RefreshTokenRequest represents refresh token request
@@ -81,21 +92,25 @@ type RefreshTokenRequest struct {
 
 // JWTClaims represents JWT claims
 type JWTClaims struct {
-	UserID   int      `json:"user_id"`
-	Username string   `json:"username"`
-	Role     UserRole `json:"role"`
-	Type     string   `json:"type"` // "access" or "refresh"
+	UserID                 int          `json:"user_id"`
+	Username               string       `json:"username"`
+	Role                   UserRole     `json:"role"`
+	Permissions            []Permission `json:"permissions"`              // Granted by the role when the token was issued
+	Type                   string       `json:"type"`                     // "access", "refresh" or "mfa"
+	PasswordChangeRequired bool         `json:"password_change_required"` // Set until the user replaces a temporary password
 }
 
 // ToUserResponse converts User to UserResponse
 func (u *User) ToUserResponse() *UserResponse {
 	return &UserResponse{
-		ID:        u.ID,
-		Username:  u.Username,
-		Email:     u.Email,
-		Role:      u.Role,
-		IsActive:  u.IsActive,
-		CreatedAt: u.CreatedAt,
-		LastLogin: u.LastLogin,
+		ID:                 u.ID,
+		Username:           u.Username,
+		Email:              u.Email,
+		Role:               u.Role,
+		Permissions:        u.Permissions,
+		IsActive:           u.IsActive,
+		MustChangePassword: u.MustChangePassword,
+		CreatedAt:          u.CreatedAt,
+		LastLogin:          u.LastLogin,
 	}
 }
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// WebhookDeliveryStatus is the state of a queued webhook delivery
+type WebhookDeliveryStatus string
+
+const (
+	// DeliveryPending means the delivery is waiting for its next attempt
+	DeliveryPending WebhookDeliveryStatus = "pending"
+	// DeliveryDelivered means the receiver answered with a 2xx status
+	DeliveryDelivered WebhookDeliveryStatus = "delivered"
+	// DeliveryFailed means every attempt failed and the delivery was given up
+	DeliveryFailed WebhookDeliveryStatus = "failed"
+)
+
+// WebhookSubscription is a receiver of holiday change events
+type WebhookSubscription struct {
+	ID        int                 `json:"id" db:"id"`
+	URL       string              `json:"url" db:"url"`
+	Events    []HolidayChangeType `json:"events" db:"events"`
+	Secret    string              `json:"-" db:"secret"` // Shared HMAC key, only returned when the subscription is created
+	IsActive  bool                `json:"is_active" db:"is_active"`
+	CreatedBy *int                `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt time.Time           `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at" db:"updated_at"`
+}
+
+// Subscribes reports whether the subscription wants events of the given type
+func (s *WebhookSubscription) Subscribes(changeType HolidayChangeType) bool {
+	for _, event := range s.Events {
+		if event == changeType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is one event queued for a subscription, with the outcome of its latest attempt
+type WebhookDelivery struct {
+	ID             int                   `json:"id" db:"id"`
+	SubscriptionID int                   `json:"subscription_id" db:"subscription_id"`
+	ChangeSequence int64                 `json:"change_sequence" db:"change_sequence"`
+	EventType      HolidayChangeType     `json:"event_type" db:"event_type"`
+	Payload        json.RawMessage       `json:"payload" db:"payload"` // Request body, a HolidayChange
+	Status         WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts       int                   `json:"attempts" db:"attempts"`
+	NextAttemptAt  *time.Time            `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	LastAttemptAt  *time.Time            `json:"last_attempt_at,omitempty" db:"last_attempt_at"`
+	ResponseStatus *int                  `json:"response_status,omitempty" db:"response_status"`
+	LastError      *string               `json:"last_error,omitempty" db:"last_error"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty" db:"delivered_at"`
+	CreatedAt      time.Time             `json:"created_at" db:"created_at"`
+}
+
+// CreateWebhookRequest represents a request to add a webhook subscription (requires webhook:manage)
+type CreateWebhookRequest struct {
+	URL    string              `json:"url" validate:"required,url,max=2048"`
+	Events []HolidayChangeType `json:"events" validate:"required,min=1,dive,oneof=holiday.created holiday.updated holiday.deleted"`
+	Secret string              `json:"secret,omitempty" validate:"omitempty,min=16,max=128"` // Generated when omitted
+}
+
+// UpdateWebhookRequest represents a request to change a webhook subscription
+type UpdateWebhookRequest struct {
+	URL      *string             `json:"url,omitempty" validate:"omitempty,url,max=2048"`
+	Events   []HolidayChangeType `json:"events,omitempty" validate:"omitempty,min=1,dive,oneof=holiday.created holiday.updated holiday.deleted"`
+	Secret   *string             `json:"secret,omitempty" validate:"omitempty,min=16,max=128"`
+	IsActive *bool               `json:"is_active,omitempty"`
+}
+
+// CreateWebhookResponse represents a new webhook subscription including its secret
+type CreateWebhookResponse struct {
+	*WebhookSubscription
+	Secret string `json:"secret"`
+}
+
+// WebhookDeliveryResponse represents a page of a subscription's delivery history
+type WebhookDeliveryResponse struct {
+	Data   []WebhookDelivery `json:"data"`
+	Total  int               `json:"total"`
+	Limit  int               `json:"limit"`
+	Offset int               `json:"offset"`
+}
+
+// JoinWebhookEvents encodes event types for storage
+func JoinWebhookEvents(events []HolidayChangeType) string {
+	parts := make([]string, len(events))
+	for i, event := range events {
+		parts[i] = string(event)
+	}
+	return strings.Join(parts, ",")
+}
+
+// SplitWebhookEvents decodes event types from storage
+func SplitWebhookEvents(value string) []HolidayChangeType {
+	events := []HolidayChangeType{}
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			events = append(events, HolidayChangeType(part))
+		}
+	}
+	return events
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// WorkdayOptions controls which days are treated as days off
+type WorkdayOptions struct {
+	// IncludeCollectiveLeave treats collective leave (cuti bersama) as days off.
+	// Civil servants follow cuti bersama, while many private employers do not.
+	IncludeCollectiveLeave bool `json:"include_collective_leave"`
+	// Province also treats that province's regional holidays as days off
+	Province *string `json:"province,omitempty"`
+	// AsOf counts the holidays as they were recorded at that point in time
+	AsOf *time.Time `json:"as_of,omitempty"`
+	// Statuses counts only holidays with these decree statuses, all but cancelled when empty
+	Statuses []HolidayStatus `json:"status,omitempty"`
+}
+
+// WorkdayCountResponse represents the number of working days in a date range
+type WorkdayCountResponse struct {
+	StartDate              string    `json:"start_date"`
+	EndDate                string    `json:"end_date"`
+	TotalDays              int       `json:"total_days"`
+	WorkingDays            int       `json:"working_days"`
+	WeekendDays            int       `json:"weekend_days"`
+	HolidayDays            int       `json:"holiday_days"`
+	IncludeCollectiveLeave bool      `json:"include_collective_leave"`
+	Province               *string   `json:"province,omitempty"`
+	Holidays               []Holiday `json:"holidays"`
+}
+
+// WorkdayShiftResponse represents the result of moving a date by working days
+type WorkdayShiftResponse struct {
+	Date                   string    `json:"date"`
+	Days                   int       `json:"days"`
+	ResultDate             string    `json:"result_date"`
+	IncludeCollectiveLeave bool      `json:"include_collective_leave"`
+	Province               *string   `json:"province,omitempty"`
+	SkippedHolidays        []Holiday `json:"skipped_holidays"`
+}
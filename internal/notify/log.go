@@ -0,0 +1,33 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// LogNotifier writes messages to a log instead of sending them, for development
+type LogNotifier struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// NewLogNotifier creates a notifier that writes messages to out
+func NewLogNotifier(out io.Writer) *LogNotifier {
+	return &LogNotifier{out: out}
+}
+
+// Send writes the message with its recipients and subject
+func (n *LogNotifier) Send(ctx context.Context, msg Message) error {
+	if len(msg.To) == 0 {
+		return fmt.Errorf("message has no recipients")
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	_, err := fmt.Fprintf(n.out, "Email to %s\nSubject: %s\n\n%s\n", strings.Join(msg.To, ", "), msg.Subject, msg.Body)
+	return err
+}
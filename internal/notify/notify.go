@@ -0,0 +1,51 @@
+// Package notify delivers plain-text email notifications
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ilramdhan/holidayapi/internal/config"
+)
+
+// Message is a plain-text email
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Notifier delivers messages to their recipients
+type Notifier interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New creates the notifier selected by NOTIFIER_DRIVER
+func New(cfg config.NotifierConfig) (Notifier, error) {
+	switch cfg.Driver {
+	case "log":
+		return NewLogNotifier(os.Stdout), nil
+	case "file":
+		return NewFileNotifier(cfg.File)
+	case "smtp":
+		return NewSMTPNotifier(cfg.SMTP)
+	}
+	return nil, fmt.Errorf("unsupported notifier driver %q, use log, file or smtp", cfg.Driver)
+}
+
+// NewFileNotifier creates a notifier that appends messages to a file instead of sending
+// them, for development. The file stays open for the life of the process.
+func NewFileNotifier(path string) (*LogNotifier, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create directory for NOTIFIER_FILE %q: %w", path, err)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open NOTIFIER_FILE %q: %w", path, err)
+	}
+
+	return NewLogNotifier(f), nil
+}
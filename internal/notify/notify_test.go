@@ -0,0 +1,34 @@
+package notify
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilramdhan/holidayapi/internal/config"
+)
+
+func TestNew_FileDriver(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mail", "emails.log")
+
+	notifier, err := New(config.NotifierConfig{Driver: "file", File: path})
+	require.NoError(t, err)
+
+	msg := Message{To: []string{"admin@example.com"}, Subject: "Hello", Body: "First"}
+	require.NoError(t, notifier.Send(context.Background(), msg))
+	msg.Body = "Second"
+	require.NoError(t, notifier.Send(context.Background(), msg))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Email to admin@example.com\nSubject: Hello\n\nFirst\nEmail to admin@example.com\nSubject: Hello\n\nSecond\n", string(data))
+}
+
+func TestNew_UnknownDriver(t *testing.T) {
+	_, err := New(config.NotifierConfig{Driver: "carrier-pigeon"})
+	assert.EqualError(t, err, `unsupported notifier driver "carrier-pigeon", use log, file or smtp`)
+}
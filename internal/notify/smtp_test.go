@@ -0,0 +1,148 @@
+package notify
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilramdhan/holidayapi/internal/config"
+)
+
+// smtpTransaction is what the stub server received in one session
+type smtpTransaction struct {
+	from string
+	to   []string
+	data string
+}
+
+// startSMTPStub accepts one SMTP session on a local port and reports what it received
+func startSMTPStub(t *testing.T) (string, <-chan smtpTransaction) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan smtpTransaction, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+		var tx smtpTransaction
+		reply("220 stub ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			command := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250 stub")
+			case strings.HasPrefix(command, "MAIL FROM:"):
+				tx.from = strings.Trim(strings.TrimSpace(line)[len("MAIL FROM:"):], "<>")
+				reply("250 OK")
+			case strings.HasPrefix(command, "RCPT TO:"):
+				tx.to = append(tx.to, strings.Trim(strings.TrimSpace(line)[len("RCPT TO:"):], "<>"))
+				reply("250 OK")
+			case command == "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					dataLine, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if dataLine == ".\r\n" {
+						break
+					}
+					data.WriteString(dataLine)
+				}
+				tx.data = data.String()
+				reply("250 OK")
+			case command == "QUIT":
+				reply("221 Bye")
+				received <- tx
+				return
+			default:
+				reply("502 Command not implemented")
+			}
+		}
+	}()
+
+	return listener.Addr().String(), received
+}
+
+func TestSMTPNotifier_Send(t *testing.T) {
+	addr, received := startSMTPStub(t)
+	host, port, _ := net.SplitHostPort(addr)
+
+	notifier, err := NewSMTPNotifier(config.SMTPConfig{
+		Host:    host,
+		Port:    port,
+		From:    "Holiday API <noreply@example.com>",
+		Timeout: 5 * time.Second,
+	})
+	require.NoError(t, err)
+
+	err = notifier.Send(context.Background(), Message{
+		To:      []string{"hr@example.com", "finance@example.com"},
+		Subject: "Cuti bersama minggu depan – 2 hari",
+		Body:    "Senin, 31 Maret 2025: Hari Raya Idul Fitri\nSelasa, 1 April 2025: Cuti Bersama Idul Fitri",
+	})
+	require.NoError(t, err)
+
+	var tx smtpTransaction
+	select {
+	case tx = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("smtp stub received no message")
+	}
+
+	assert.Equal(t, "noreply@example.com", tx.from)
+	assert.Equal(t, []string{"hr@example.com", "finance@example.com"}, tx.to)
+
+	msg, err := mail.ReadMessage(strings.NewReader(tx.data))
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Cuti bersama minggu depan – 2 hari", subject)
+	assert.Equal(t, "hr@example.com, finance@example.com", msg.Header.Get("To"))
+
+	body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	require.NoError(t, err)
+	assert.Equal(t, "Senin, 31 Maret 2025: Hari Raya Idul Fitri\r\nSelasa, 1 April 2025: Cuti Bersama Idul Fitri", string(bytes.TrimSpace(body)))
+}
+
+func TestSMTPNotifier_InvalidFrom(t *testing.T) {
+	_, err := NewSMTPNotifier(config.SMTPConfig{From: "not an address"})
+	assert.Error(t, err)
+}
+
+func TestLogNotifier_Send(t *testing.T) {
+	var out bytes.Buffer
+	notifier := NewLogNotifier(&out)
+
+	err := notifier.Send(context.Background(), Message{To: []string{"hr@example.com"}, Subject: "Libur 7 hari lagi", Body: "Hari Raya Nyepi"})
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "Email to hr@example.com")
+	assert.Contains(t, out.String(), "Subject: Libur 7 hari lagi")
+
+	assert.Error(t, notifier.Send(context.Background(), Message{Subject: "nobody"}))
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ilramdhan/holidayapi/internal/database"
+	"github.com/ilramdhan/holidayapi/internal/metrics"
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+// APIKeyRepository interface defines API key data access methods
+type APIKeyRepository interface {
+	Create(key *models.APIKey) error
+	GetByID(id int) (*models.APIKey, error)
+	GetByHash(keyHash string) (*models.APIKey, error)
+	GetAll() ([]models.APIKey, error)
+	Revoke(id int) error
+	UpdateLastUsed(id int, usedAt time.Time) error
+}
+
+// apiKeyRepository implements APIKeyRepository
+type apiKeyRepository struct {
+	db *database.DB
+}
+
+// NewAPIKeyRepository creates a new API key repository
+func NewAPIKeyRepository(db *database.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+// apiKeyColumns lists the columns read by scanAPIKey, joined with the owner's username
+const apiKeyColumns = `k.id, k.name, k.key_prefix, k.key_hash, k.user_id, COALESCE(u.username, ''), k.scopes,
+	k.expires_at, k.last_used_at, k.revoked_at, k.created_by, k.created_at`
+
+// Create stores a newly issued API key
+func (r *apiKeyRepository) Create(key *models.APIKey) error {
+	defer metrics.ObserveDBQuery("api_key", "Create", time.Now())
+
+	query := `
+		INSERT INTO api_keys (name, key_prefix, key_hash, user_id, scopes, expires_at, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING id
+	`
+
+	key.CreatedAt = time.Now()
+
+	err := r.db.QueryRow(query, key.Name, key.Prefix, key.KeyHash, key.UserID,
+		models.JoinAPIKeyScopes(key.Scopes), key.ExpiresAt, key.CreatedBy, key.CreatedAt).Scan(&key.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an API key by ID, including revoked keys
+func (r *apiKeyRepository) GetByID(id int) (*models.APIKey, error) {
+	defer metrics.ObserveDBQuery("api_key", "GetByID", time.Now())
+
+	query := `SELECT ` + apiKeyColumns + `
+		FROM api_keys k
+		LEFT JOIN users u ON u.id = k.user_id
+		WHERE k.id = ?
+	`
+
+	key, err := scanAPIKey(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("api key not found")
+		}
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	return key, nil
+}
+
+// GetByHash retrieves an API key by the hash of its value, including revoked keys
+func (r *apiKeyRepository) GetByHash(keyHash string) (*models.APIKey, error) {
+	defer metrics.ObserveDBQuery("api_key", "GetByHash", time.Now())
+
+	query := `SELECT ` + apiKeyColumns + `
+		FROM api_keys k
+		LEFT JOIN users u ON u.id = k.user_id
+		WHERE k.key_hash = ?
+	`
+
+	key, err := scanAPIKey(r.db.QueryRow(query, keyHash))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("api key not found")
+		}
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	return key, nil
+}
+
+// GetAll retrieves all API keys, newest first
+func (r *apiKeyRepository) GetAll() ([]models.APIKey, error) {
+	defer metrics.ObserveDBQuery("api_key", "GetAll", time.Now())
+
+	query := `SELECT ` + apiKeyColumns + `
+		FROM api_keys k
+		LEFT JOIN users u ON u.id = k.user_id
+		ORDER BY k.created_at DESC, k.id DESC
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api key: %w", err)
+		}
+		keys = append(keys, *key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate api keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// Revoke revokes an API key. Revoking an already revoked key is reported as not found.
+func (r *apiKeyRepository) Revoke(id int) error {
+	defer metrics.ObserveDBQuery("api_key", "Revoke", time.Now())
+
+	query := `UPDATE api_keys SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`
+
+	result, err := r.db.Exec(query, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("api key not found")
+	}
+
+	return nil
+}
+
+// UpdateLastUsed records when an API key was last used
+func (r *apiKeyRepository) UpdateLastUsed(id int, usedAt time.Time) error {
+	defer metrics.ObserveDBQuery("api_key", "UpdateLastUsed", time.Now())
+
+	query := `UPDATE api_keys SET last_used_at = ? WHERE id = ?`
+
+	if _, err := r.db.Exec(query, usedAt, id); err != nil {
+		return fmt.Errorf("failed to update api key last used: %w", err)
+	}
+
+	return nil
+}
+
+// scanAPIKey scans a single API key row selected with apiKeyColumns
+func scanAPIKey(row rowScanner) (*models.APIKey, error) {
+	key := &models.APIKey{}
+	var scopes string
+	var expiresAt, lastUsedAt, revokedAt sql.NullTime
+	var createdBy sql.NullInt64
+
+	err := row.Scan(
+		&key.ID, &key.Name, &key.Prefix, &key.KeyHash, &key.UserID, &key.Username, &scopes,
+		&expiresAt, &lastUsedAt, &revokedAt, &createdBy, &key.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	key.Scopes = models.SplitAPIKeyScopes(scopes)
+	if expiresAt.Valid {
+		key.ExpiresAt = &expiresAt.Time
+	}
+	if lastUsedAt.Valid {
+		key.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+	if createdBy.Valid {
+		id := int(createdBy.Int64)
+		key.CreatedBy = &id
+	}
+
+	return key, nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilramdhan/holidayapi/internal/database"
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+func TestAPIKeyRepository_Lifecycle(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		repo := NewAPIKeyRepository(db)
+		createdBy := migratedAdminID
+
+		key := &models.APIKey{
+			Name:      "ci",
+			Prefix:    "hk_abcdefgh",
+			KeyHash:   "key-hash",
+			UserID:    migratedAdminID,
+			Scopes:    []models.APIKeyScope{models.ScopeReadHolidays, models.ScopeReadAudit},
+			CreatedBy: &createdBy,
+		}
+		require.NoError(t, repo.Create(key))
+
+		got, err := repo.GetByHash("key-hash")
+		require.NoError(t, err)
+		assert.Equal(t, key.ID, got.ID)
+		assert.Equal(t, "admin", got.Username)
+		assert.Equal(t, key.Scopes, got.Scopes)
+		assert.Nil(t, got.ExpiresAt)
+
+		require.NoError(t, repo.UpdateLastUsed(key.ID, time.Now()))
+		require.NoError(t, repo.Revoke(key.ID))
+		assert.EqualError(t, repo.Revoke(key.ID), "api key not found")
+
+		keys, err := repo.GetAll()
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		assert.NotNil(t, keys[0].LastUsedAt)
+		assert.NotNil(t, keys[0].RevokedAt)
+	})
+}
@@ -4,8 +4,11 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/ilramdhan/holidayapi/internal/database"
+	"github.com/ilramdhan/holidayapi/internal/metrics"
 	"github.com/ilramdhan/holidayapi/internal/models"
 )
 
@@ -14,83 +17,85 @@ type AuditRepository interface {
 	Create(log *models.AuditLog) error
 	GetAll(filter models.AuditLogFilter) ([]models.AuditLog, int, error)
 	GetByUserID(userID int, limit, offset int) ([]models.AuditLog, error)
-	DeleteOldLogs(olderThan time.Time) error
+	DeleteOldLogs(olderThan time.Time) (int64, error)
+	Walk(filter models.AuditLogFilter, fn func(log *models.AuditLog) error) error
+	GetChainCheckpoints() ([]models.AuditChainCheckpoint, error)
 }
 
+// auditChainMu serializes appends to the audit chain and retention within this process, so two
+// entries never link to the same predecessor. PostgreSQL also takes auditChainLockKey as an advisory
+// lock, which extends that to several servers sharing one database.
+var auditChainMu sync.Mutex
+
+// auditChainLockKey is the PostgreSQL advisory lock key of the audit chain
+const auditChainLockKey = 0x61756469 // "audi"
+
+// auditLogColumns are the selected columns scanned by scanAuditLog
+const auditLogColumns = `id, user_id, username, action, resource, resource_id, details, ip_address, user_agent, success, created_at, prev_hash, hash`
+
 // auditRepository implements AuditRepository
 type auditRepository struct {
-	db *sql.DB
+	db *database.DB
 }
 
 // NewAuditRepository creates a new audit repository
-func NewAuditRepository(db *sql.DB) AuditRepository {
+func NewAuditRepository(db *database.DB) AuditRepository {
 	return &auditRepository{db: db}
 }
 
-// Create creates a new audit log entry
+// Create appends an audit log entry to the chain: under a lock it reads the hash of the chain
+// head, hashes the new entry over it and inserts both hashes in one transaction.
 func (r *auditRepository) Create(log *models.AuditLog) error {
-	query := `
-		INSERT INTO audit_logs (user_id, username, action, resource, resource_id, details, ip_address, user_agent, success, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`
+	defer metrics.ObserveDBQuery("audit", "Create", time.Now())
 
-	log.CreatedAt = time.Now()
+	auditChainMu.Lock()
+	defer auditChainMu.Unlock()
 
-	result, err := r.db.Exec(query, log.UserID, log.Username, log.Action, log.Resource,
-		log.ResourceID, log.Details, log.IPAddress, log.UserAgent, log.Success, log.CreatedAt)
+	tx, err := r.db.Begin()
 	if err != nil {
-		return fmt.Errorf("failed to create audit log: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("failed to get last insert id: %w", err)
+	if err := r.lockChain(tx); err != nil {
+		return err
 	}
 
-	log.ID = int(id)
-	return nil
-}
-
-// GetAll retrieves audit logs with filters
-func (r *auditRepository) GetAll(filter models.AuditLogFilter) ([]models.AuditLog, int, error) {
-	// Build WHERE clause
-	whereConditions := []string{}
-	args := []interface{}{}
-
-	if filter.UserID != nil {
-		whereConditions = append(whereConditions, "user_id = ?")
-		args = append(args, *filter.UserID)
+	prevHash, err := r.chainHead(tx)
+	if err != nil {
+		return err
 	}
 
-	if filter.Action != nil {
-		whereConditions = append(whereConditions, "action = ?")
-		args = append(args, string(*filter.Action))
-	}
+	// Hashes cover the time at the precision the database stores it
+	log.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	log.PrevHash = prevHash
+	log.Hash = log.ComputeHash()
 
-	if filter.Resource != nil {
-		whereConditions = append(whereConditions, "resource = ?")
-		args = append(args, string(*filter.Resource))
-	}
+	query := `
+		INSERT INTO audit_logs (user_id, username, action, resource, resource_id, details, ip_address, user_agent, success, created_at, prev_hash, hash)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING id
+	`
 
-	if filter.Success != nil {
-		whereConditions = append(whereConditions, "success = ?")
-		args = append(args, *filter.Success)
+	err = tx.QueryRow(query, log.UserID, log.Username, log.Action, log.Resource,
+		log.ResourceID, log.Details, log.IPAddress, log.UserAgent, log.Success, log.CreatedAt,
+		log.PrevHash, log.Hash).Scan(&log.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create audit log: %w", err)
 	}
 
-	if filter.StartDate != nil {
-		whereConditions = append(whereConditions, "created_at >= ?")
-		args = append(args, filter.StartDate.Format("2006-01-02 15:04:05"))
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	if filter.EndDate != nil {
-		whereConditions = append(whereConditions, "created_at <= ?")
-		args = append(args, filter.EndDate.Format("2006-01-02 15:04:05"))
-	}
+	return nil
+}
 
-	whereClause := ""
-	if len(whereConditions) > 0 {
-		whereClause = "WHERE " + strings.Join(whereConditions, " AND ")
-	}
+// GetAll retrieves audit logs with filters
+func (r *auditRepository) GetAll(filter models.AuditLogFilter) ([]models.AuditLog, int, error) {
+	defer metrics.ObserveDBQuery("audit", "GetAll", time.Now())
+
+	whereClause, args := auditFilterClause(filter)
 
 	// Count total records
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM audit_logs %s", whereClause)
@@ -102,11 +107,11 @@ func (r *auditRepository) GetAll(filter models.AuditLogFilter) ([]models.AuditLo
 
 	// Build main query
 	query := fmt.Sprintf(`
-		SELECT id, user_id, username, action, resource, resource_id, details, ip_address, user_agent, success, created_at
+		SELECT %s
 		FROM audit_logs
 		%s
 		ORDER BY created_at DESC
-	`, whereClause)
+	`, auditLogColumns, whereClause)
 
 	// Add pagination
 	if filter.Limit > 0 {
@@ -127,44 +132,11 @@ func (r *auditRepository) GetAll(filter models.AuditLogFilter) ([]models.AuditLo
 
 	var logs []models.AuditLog
 	for rows.Next() {
-		var log models.AuditLog
-		var userID sql.NullInt64
-		var resourceID sql.NullInt64
-		var ipAddress sql.NullString
-		var userAgent sql.NullString
-		var details sql.NullString
-
-		err := rows.Scan(
-			&log.ID, &userID, &log.Username, &log.Action, &log.Resource,
-			&resourceID, &details, &ipAddress, &userAgent, &log.Success, &log.CreatedAt,
-		)
+		log, err := scanAuditLog(rows)
 		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan audit log: %w", err)
+			return nil, 0, err
 		}
-
-		if userID.Valid {
-			uid := int(userID.Int64)
-			log.UserID = &uid
-		}
-
-		if resourceID.Valid {
-			rid := int(resourceID.Int64)
-			log.ResourceID = &rid
-		}
-
-		if details.Valid {
-			log.Details = details.String
-		}
-
-		if ipAddress.Valid {
-			log.IPAddress = ipAddress.String
-		}
-
-		if userAgent.Valid {
-			log.UserAgent = userAgent.String
-		}
-
-		logs = append(logs, log)
+		logs = append(logs, *log)
 	}
 
 	return logs, total, nil
@@ -172,8 +144,10 @@ func (r *auditRepository) GetAll(filter models.AuditLogFilter) ([]models.AuditLo
 
 // GetByUserID retrieves audit logs for a specific user
 func (r *auditRepository) GetByUserID(userID int, limit, offset int) ([]models.AuditLog, error) {
+	defer metrics.ObserveDBQuery("audit", "GetByUserID", time.Now())
+
 	query := `
-		SELECT id, user_id, username, action, resource, resource_id, details, ip_address, user_agent, success, created_at
+		SELECT ` + auditLogColumns + `
 		FROM audit_logs
 		WHERE user_id = ?
 		ORDER BY created_at DESC
@@ -188,63 +162,286 @@ func (r *auditRepository) GetByUserID(userID int, limit, offset int) ([]models.A
 
 	var logs []models.AuditLog
 	for rows.Next() {
-		var log models.AuditLog
-		var userIDNull sql.NullInt64
-		var resourceID sql.NullInt64
-		var ipAddress sql.NullString
-		var userAgent sql.NullString
-		var details sql.NullString
-
-		err := rows.Scan(
-			&log.ID, &userIDNull, &log.Username, &log.Action, &log.Resource,
-			&resourceID, &details, &ipAddress, &userAgent, &log.Success, &log.CreatedAt,
-		)
+		log, err := scanAuditLog(rows)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan audit log: %w", err)
+			return nil, err
 		}
+		logs = append(logs, *log)
+	}
+
+	return logs, nil
+}
+
+// DeleteOldLogs deletes audit logs older than specified time and returns how many were deleted.
+// Only a prefix of the chain is deleted, up to the newest old entry, and when that entry is
+// chained a checkpoint recording it is appended to the chain in the same transaction.
+func (r *auditRepository) DeleteOldLogs(olderThan time.Time) (int64, error) {
+	defer metrics.ObserveDBQuery("audit", "DeleteOldLogs", time.Now())
+
+	auditChainMu.Lock()
+	defer auditChainMu.Unlock()
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := r.lockChain(tx); err != nil {
+		return 0, err
+	}
 
-		if userIDNull.Valid {
-			uid := int(userIDNull.Int64)
-			log.UserID = &uid
+	// Entries are stored in UTC, and SQLite compares times as text
+	var lastID int
+	var lastHash sql.NullString
+	err = tx.QueryRow(`SELECT id, hash FROM audit_logs WHERE created_at < ? ORDER BY id DESC LIMIT 1`,
+		olderThan.UTC()).Scan(&lastID, &lastHash)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to find old audit logs: %w", err)
+	}
+
+	// The head is read before the delete, which may remove the entry it points to
+	head, err := r.chainHead(tx)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := tx.Exec(`DELETE FROM audit_logs WHERE id <= ?`, lastID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old audit logs: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if lastHash.Valid {
+		checkpoint := models.AuditChainCheckpoint{
+			LastDeletedID:   lastID,
+			LastDeletedHash: lastHash.String,
+			CreatedAt:       time.Now().UTC().Truncate(time.Microsecond),
+			PrevHash:        head,
 		}
+		checkpoint.Hash = checkpoint.ComputeHash()
+
+		query := `
+			INSERT INTO audit_chain_checkpoints (last_deleted_id, last_deleted_hash, created_at, prev_hash, hash)
+			VALUES (?, ?, ?, ?, ?)
+		`
+		_, err := tx.Exec(query, checkpoint.LastDeletedID, checkpoint.LastDeletedHash, checkpoint.CreatedAt,
+			checkpoint.PrevHash, checkpoint.Hash)
+		if err != nil {
+			return 0, fmt.Errorf("failed to record audit chain checkpoint: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// GetChainCheckpoints retrieves every retention checkpoint in the order retention recorded them
+func (r *auditRepository) GetChainCheckpoints() ([]models.AuditChainCheckpoint, error) {
+	defer metrics.ObserveDBQuery("audit", "GetChainCheckpoints", time.Now())
 
-		if resourceID.Valid {
-			rid := int(resourceID.Int64)
-			log.ResourceID = &rid
+	query := `
+		SELECT id, last_deleted_id, last_deleted_hash, created_at, prev_hash, hash
+		FROM audit_chain_checkpoints
+		ORDER BY id ASC
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit chain checkpoints: %w", err)
+	}
+	defer rows.Close()
+
+	var checkpoints []models.AuditChainCheckpoint
+	for rows.Next() {
+		var checkpoint models.AuditChainCheckpoint
+		err := rows.Scan(&checkpoint.ID, &checkpoint.LastDeletedID, &checkpoint.LastDeletedHash,
+			&checkpoint.CreatedAt, &checkpoint.PrevHash, &checkpoint.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit chain checkpoint: %w", err)
 		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate audit chain checkpoints: %w", err)
+	}
 
-		if details.Valid {
-			log.Details = details.String
+	return checkpoints, nil
+}
+
+// chainHead returns the hash the next link of the chain must point to: the latest chained entry,
+// followed by any checkpoints appended after it. Without chained entries the chain continues
+// from the latest checkpoint, or starts at the genesis hash.
+func (r *auditRepository) chainHead(tx *database.Tx) (string, error) {
+	var head string
+	err := tx.QueryRow(`SELECT hash FROM audit_logs WHERE hash IS NOT NULL ORDER BY id DESC LIMIT 1`).Scan(&head)
+	if err == sql.ErrNoRows {
+		err = tx.QueryRow(`SELECT hash FROM audit_chain_checkpoints ORDER BY id DESC LIMIT 1`).Scan(&head)
+	}
+	switch {
+	case err == sql.ErrNoRows:
+		return models.AuditChainGenesis, nil
+	case err != nil:
+		return "", fmt.Errorf("failed to get previous audit log hash: %w", err)
+	}
+
+	seen := map[string]bool{head: true}
+	for {
+		var next string
+		err := tx.QueryRow(`SELECT hash FROM audit_chain_checkpoints WHERE prev_hash = ? ORDER BY id DESC LIMIT 1`, head).Scan(&next)
+		if err == sql.ErrNoRows {
+			return head, nil
 		}
+		if err != nil {
+			return "", fmt.Errorf("failed to get audit chain checkpoint: %w", err)
+		}
+		if seen[next] {
+			return head, nil
+		}
+		seen[next] = true
+		head = next
+	}
+}
 
-		if ipAddress.Valid {
-			log.IPAddress = ipAddress.String
+// lockChain takes the PostgreSQL advisory lock of the audit chain for the rest of the transaction.
+// Callers also hold auditChainMu.
+func (r *auditRepository) lockChain(tx *database.Tx) error {
+	if r.db.Driver != database.Postgres {
+		return nil
+	}
+
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(?)`, auditChainLockKey); err != nil {
+		return fmt.Errorf("failed to lock audit chain: %w", err)
+	}
+
+	return nil
+}
+
+// Walk calls fn for every audit log entry matching the filter in insertion order, stopping at
+// the first error. Rows are read from an open cursor, so any number of entries can be walked.
+func (r *auditRepository) Walk(filter models.AuditLogFilter, fn func(log *models.AuditLog) error) error {
+	defer metrics.ObserveDBQuery("audit", "Walk", time.Now())
+
+	whereClause, args := auditFilterClause(filter)
+	query := fmt.Sprintf(`SELECT %s FROM audit_logs %s ORDER BY id ASC`, auditLogColumns, whereClause)
+
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+
+		if filter.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, filter.Offset)
 		}
+	}
 
-		if userAgent.Valid {
-			log.UserAgent = userAgent.String
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query audit logs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		log, err := scanAuditLog(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(log); err != nil {
+			return err
 		}
+	}
 
-		logs = append(logs, log)
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate audit logs: %w", err)
 	}
 
-	return logs, nil
+	return nil
 }
 
-// DeleteOldLogs deletes audit logs older than specified time
-func (r *auditRepository) DeleteOldLogs(olderThan time.Time) error {
-	query := `DELETE FROM audit_logs WHERE created_at < ?`
+// auditFilterClause builds the WHERE clause and its arguments for the conditions of a filter
+func auditFilterClause(filter models.AuditLogFilter) (string, []interface{}) {
+	whereConditions := []string{}
+	args := []interface{}{}
 
-	result, err := r.db.Exec(query, olderThan.Format("2006-01-02 15:04:05"))
-	if err != nil {
-		return fmt.Errorf("failed to delete old audit logs: %w", err)
+	if filter.UserID != nil {
+		whereConditions = append(whereConditions, "user_id = ?")
+		args = append(args, *filter.UserID)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	if filter.Action != nil {
+		whereConditions = append(whereConditions, "action = ?")
+		args = append(args, string(*filter.Action))
+	}
+
+	if filter.Resource != nil {
+		whereConditions = append(whereConditions, "resource = ?")
+		args = append(args, string(*filter.Resource))
+	}
+
+	if filter.Success != nil {
+		whereConditions = append(whereConditions, "success = ?")
+		args = append(args, *filter.Success)
+	}
+
+	if filter.StartDate != nil {
+		whereConditions = append(whereConditions, "created_at >= ?")
+		args = append(args, filter.StartDate.UTC())
+	}
+
+	if filter.EndDate != nil {
+		whereConditions = append(whereConditions, "created_at <= ?")
+		args = append(args, filter.EndDate.UTC())
+	}
+
+	if len(whereConditions) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(whereConditions, " AND "), args
+}
+
+// scanAuditLog scans a row of auditLogColumns, reading NULL columns as zero values
+func scanAuditLog(rows *sql.Rows) (*models.AuditLog, error) {
+	var log models.AuditLog
+	var userID sql.NullInt64
+	var resourceID sql.NullInt64
+	var details, ipAddress, userAgent sql.NullString
+	var prevHash, hash sql.NullString
+
+	err := rows.Scan(
+		&log.ID, &userID, &log.Username, &log.Action, &log.Resource,
+		&resourceID, &details, &ipAddress, &userAgent, &log.Success, &log.CreatedAt,
+		&prevHash, &hash,
+	)
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return nil, fmt.Errorf("failed to scan audit log: %w", err)
 	}
 
-	fmt.Printf("Deleted %d old audit log entries\n", rowsAffected)
-	return nil
+	if userID.Valid {
+		uid := int(userID.Int64)
+		log.UserID = &uid
+	}
+
+	if resourceID.Valid {
+		rid := int(resourceID.Int64)
+		log.ResourceID = &rid
+	}
+
+	log.Details = details.String
+	log.IPAddress = ipAddress.String
+	log.UserAgent = userAgent.String
+	log.PrevHash = prevHash.String
+	log.Hash = hash.String
+
+	return &log, nil
 }
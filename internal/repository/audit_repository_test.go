@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilramdhan/holidayapi/internal/database"
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+func TestAuditRepository_GetAllFilters(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		repo := NewAuditRepository(db)
+		userID := migratedAdminID
+
+		for _, success := range []bool{true, false, false} {
+			require.NoError(t, repo.Create(&models.AuditLog{
+				UserID: &userID, Username: "admin", Action: models.ActionLogin, Resource: models.ResourceUser, Success: success,
+			}))
+		}
+
+		failed := false
+		action := models.ActionLogin
+		logs, total, err := repo.GetAll(models.AuditLogFilter{Action: &action, Success: &failed, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		assert.Len(t, logs, 2)
+
+		// The users migration logs the initial setup, which also falls within the last minute
+		since := time.Now().Add(-time.Minute)
+		_, total, err = repo.GetAll(models.AuditLogFilter{UserID: &userID, StartDate: &since, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, 4, total)
+	})
+}
+
+func TestAuditRepository_HashChain(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		repo := NewAuditRepository(db)
+		userID := migratedAdminID
+		resourceID := 7
+
+		first := &models.AuditLog{UserID: &userID, Username: "admin", Action: models.ActionLogin, Resource: models.ResourceAuth, Success: true}
+		require.NoError(t, repo.Create(first))
+		second := &models.AuditLog{Username: "system", Action: models.ActionJobRun, Resource: models.ResourceSystem, ResourceID: &resourceID, Details: "line one\nline two", Success: true}
+		require.NoError(t, repo.Create(second))
+
+		assert.Equal(t, models.AuditChainGenesis, first.PrevHash)
+		assert.Equal(t, first.Hash, second.PrevHash)
+
+		var walked []models.AuditLog
+		require.NoError(t, repo.Walk(models.AuditLogFilter{}, func(log *models.AuditLog) error {
+			walked = append(walked, *log)
+			return nil
+		}))
+
+		// The entry written by the users migration predates the chain
+		require.Len(t, walked, 3)
+		assert.Empty(t, walked[0].Hash)
+		for i, created := range []*models.AuditLog{first, second} {
+			stored := walked[i+1]
+			assert.Equal(t, created.ID, stored.ID)
+			assert.Equal(t, created.Hash, stored.Hash)
+			assert.Equal(t, stored.Hash, stored.ComputeHash(), "hash must survive the round trip through the database")
+		}
+
+		// Walks apply the filter conditions
+		action := models.ActionJobRun
+		var filtered []int
+		require.NoError(t, repo.Walk(models.AuditLogFilter{Action: &action}, func(log *models.AuditLog) error {
+			filtered = append(filtered, log.ID)
+			return nil
+		}))
+		assert.Equal(t, []int{second.ID}, filtered)
+	})
+}
+
+func TestAuditRepository_RetentionCheckpoint(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		repo := NewAuditRepository(db)
+
+		checkpoints, err := repo.GetChainCheckpoints()
+		require.NoError(t, err)
+		assert.Empty(t, checkpoints)
+
+		first := &models.AuditLog{Username: "system", Action: models.ActionJobRun, Resource: models.ResourceSystem, Success: true}
+		require.NoError(t, repo.Create(first))
+		second := &models.AuditLog{Username: "system", Action: models.ActionJobRun, Resource: models.ResourceSystem, Success: true}
+		require.NoError(t, repo.Create(second))
+
+		// A cutoff in another zone is the same instant, so nothing written within the hour is old
+		wib := time.FixedZone("WIB", 7*60*60)
+		deleted, err := repo.DeleteOldLogs(time.Now().Add(-time.Hour).In(wib))
+		require.NoError(t, err)
+		assert.Zero(t, deleted)
+
+		// Retention removes every entry, including the one written by the users migration
+		deleted, err = repo.DeleteOldLogs(time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), deleted)
+
+		checkpoints, err = repo.GetChainCheckpoints()
+		require.NoError(t, err)
+		require.Len(t, checkpoints, 1)
+		checkpoint := checkpoints[0]
+		assert.Equal(t, second.ID, checkpoint.LastDeletedID)
+		assert.Equal(t, second.Hash, checkpoint.LastDeletedHash)
+		assert.Equal(t, second.Hash, checkpoint.PrevHash, "the checkpoint is appended to the chain head")
+		assert.Equal(t, checkpoint.Hash, checkpoint.ComputeHash(), "hash must survive the round trip through the database")
+
+		// The next entry links to the checkpoint
+		third := &models.AuditLog{Username: "system", Action: models.ActionJobRun, Resource: models.ResourceSystem, Success: true}
+		require.NoError(t, repo.Create(third))
+		assert.Equal(t, checkpoint.Hash, third.PrevHash)
+
+		// A run that deletes nothing leaves the checkpoints alone
+		deleted, err = repo.DeleteOldLogs(time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Zero(t, deleted)
+
+		checkpoints, err = repo.GetChainCheckpoints()
+		require.NoError(t, err)
+		assert.Len(t, checkpoints, 1)
+
+		// A later run is appended after the newest entry, and the chain continues from it
+		deleted, err = repo.DeleteOldLogs(time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		checkpoints, err = repo.GetChainCheckpoints()
+		require.NoError(t, err)
+		require.Len(t, checkpoints, 2)
+		assert.Equal(t, third.Hash, checkpoints[1].PrevHash)
+		assert.Equal(t, third.Hash, checkpoints[1].LastDeletedHash)
+
+		fourth := &models.AuditLog{Username: "system", Action: models.ActionJobRun, Resource: models.ResourceSystem, Success: true}
+		require.NoError(t, repo.Create(fourth))
+		assert.Equal(t, checkpoints[1].Hash, fourth.PrevHash)
+	})
+}
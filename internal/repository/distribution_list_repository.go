@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ilramdhan/holidayapi/internal/database"
+	"github.com/ilramdhan/holidayapi/internal/metrics"
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+// DistributionListRepository interface defines distribution list data access methods
+type DistributionListRepository interface {
+	Create(list *models.DistributionList) error
+	GetByID(id int) (*models.DistributionList, error)
+	GetByName(name string) (*models.DistributionList, error)
+	GetAll() ([]models.DistributionList, error)
+	Update(list *models.DistributionList) error
+	Delete(id int) error
+}
+
+// distributionListRepository implements DistributionListRepository
+type distributionListRepository struct {
+	db *database.DB
+}
+
+// NewDistributionListRepository creates a new distribution list repository
+func NewDistributionListRepository(db *database.DB) DistributionListRepository {
+	return &distributionListRepository{db: db}
+}
+
+// distributionListColumns lists the columns read by scanDistributionList
+const distributionListColumns = `id, name, members, created_at, updated_at`
+
+// Create stores a new distribution list
+func (r *distributionListRepository) Create(list *models.DistributionList) error {
+	defer metrics.ObserveDBQuery("distribution_list", "Create", time.Now())
+
+	query := `
+		INSERT INTO distribution_lists (name, members, created_at, updated_at)
+		VALUES (?, ?, ?, ?)
+		RETURNING id
+	`
+
+	now := time.Now()
+	list.CreatedAt = now
+	list.UpdatedAt = now
+
+	err := r.db.QueryRow(query, list.Name, models.JoinList(list.Members), list.CreatedAt, list.UpdatedAt).Scan(&list.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create distribution list: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a distribution list by ID
+func (r *distributionListRepository) GetByID(id int) (*models.DistributionList, error) {
+	defer metrics.ObserveDBQuery("distribution_list", "GetByID", time.Now())
+
+	query := `SELECT ` + distributionListColumns + ` FROM distribution_lists WHERE id = ?`
+
+	list, err := scanDistributionList(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("distribution list not found")
+		}
+		return nil, fmt.Errorf("failed to get distribution list: %w", err)
+	}
+
+	return list, nil
+}
+
+// GetByName retrieves a distribution list by name
+func (r *distributionListRepository) GetByName(name string) (*models.DistributionList, error) {
+	defer metrics.ObserveDBQuery("distribution_list", "GetByName", time.Now())
+
+	query := `SELECT ` + distributionListColumns + ` FROM distribution_lists WHERE name = ?`
+
+	list, err := scanDistributionList(r.db.QueryRow(query, name))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("distribution list %q not found", name)
+		}
+		return nil, fmt.Errorf("failed to get distribution list: %w", err)
+	}
+
+	return list, nil
+}
+
+// GetAll retrieves all distribution lists ordered by name
+func (r *distributionListRepository) GetAll() ([]models.DistributionList, error) {
+	defer metrics.ObserveDBQuery("distribution_list", "GetAll", time.Now())
+
+	query := `SELECT ` + distributionListColumns + ` FROM distribution_lists ORDER BY name ASC`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get distribution lists: %w", err)
+	}
+	defer rows.Close()
+
+	lists := []models.DistributionList{}
+	for rows.Next() {
+		list, err := scanDistributionList(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan distribution list: %w", err)
+		}
+		lists = append(lists, *list)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate distribution lists: %w", err)
+	}
+
+	return lists, nil
+}
+
+// Update saves the name and members of a distribution list
+func (r *distributionListRepository) Update(list *models.DistributionList) error {
+	defer metrics.ObserveDBQuery("distribution_list", "Update", time.Now())
+
+	query := `UPDATE distribution_lists SET name = ?, members = ?, updated_at = ? WHERE id = ?`
+
+	list.UpdatedAt = time.Now()
+
+	result, err := r.db.Exec(query, list.Name, models.JoinList(list.Members), list.UpdatedAt, list.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update distribution list: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("distribution list not found")
+	}
+
+	return nil
+}
+
+// Delete removes a distribution list
+func (r *distributionListRepository) Delete(id int) error {
+	defer metrics.ObserveDBQuery("distribution_list", "Delete", time.Now())
+
+	result, err := r.db.Exec(`DELETE FROM distribution_lists WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete distribution list: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("distribution list not found")
+	}
+
+	return nil
+}
+
+// scanDistributionList scans a single row selected with distributionListColumns
+func scanDistributionList(row rowScanner) (*models.DistributionList, error) {
+	list := &models.DistributionList{}
+	var members string
+
+	if err := row.Scan(&list.ID, &list.Name, &members, &list.CreatedAt, &list.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	list.Members = models.SplitList(members)
+	return list, nil
+}
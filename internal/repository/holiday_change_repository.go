@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ilramdhan/holidayapi/internal/database"
+	"github.com/ilramdhan/holidayapi/internal/metrics"
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+// HolidayChangeRepository interface defines holiday change feed data access methods.
+// Changes are written by HolidayRepository, in the transaction of the mutation they describe.
+type HolidayChangeRepository interface {
+	GetSince(sequence int64, limit int) ([]models.HolidayChange, error)
+}
+
+// holidayChangeRepository implements HolidayChangeRepository
+type holidayChangeRepository struct {
+	db *database.DB
+}
+
+// NewHolidayChangeRepository creates a new holiday change repository
+func NewHolidayChangeRepository(db *database.DB) HolidayChangeRepository {
+	return &holidayChangeRepository{db: db}
+}
+
+// GetSince retrieves up to limit changes with a sequence greater than the given one, oldest first
+func (r *holidayChangeRepository) GetSince(sequence int64, limit int) ([]models.HolidayChange, error) {
+	defer metrics.ObserveDBQuery("holiday_change", "GetSince", time.Now())
+
+	query := `
+		SELECT id, event_type, holiday_id, payload, created_at
+		FROM holiday_changes
+		WHERE id > ?
+		ORDER BY id ASC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, sequence, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holiday changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []models.HolidayChange{}
+	for rows.Next() {
+		var change models.HolidayChange
+		var payload string
+
+		if err := rows.Scan(&change.Sequence, &change.Type, &change.HolidayID, &payload, &change.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan holiday change: %w", err)
+		}
+		if err := json.Unmarshal([]byte(payload), &change.Holiday); err != nil {
+			return nil, fmt.Errorf("failed to decode holiday change %d: %w", change.Sequence, err)
+		}
+
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate holiday changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// recordHolidayChange appends a change to the feed using the given executor and queues it in the
+// webhook outbox for every active subscription that wants its type
+func recordHolidayChange(exec sqlExecutor, changeType models.HolidayChangeType, holiday *models.Holiday) (*models.HolidayChange, error) {
+	change := &models.HolidayChange{
+		Type:      changeType,
+		HolidayID: holiday.ID,
+		Holiday:   *holiday,
+		CreatedAt: time.Now(),
+	}
+
+	data, err := json.Marshal(change.Holiday)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode holiday change: %w", err)
+	}
+
+	query := `
+		INSERT INTO holiday_changes (event_type, holiday_id, payload, created_at)
+		VALUES (?, ?, ?, ?)
+		RETURNING id
+	`
+
+	err = exec.QueryRow(query, change.Type, change.HolidayID, string(data), change.CreatedAt).Scan(&change.Sequence)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record holiday change: %w", err)
+	}
+
+	payload, err := json.Marshal(change)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode holiday change: %w", err)
+	}
+	if err := enqueueWebhookDeliveries(exec, change, payload); err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilramdhan/holidayapi/internal/database"
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+func TestHolidayChangeRepository_GetSince(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		holidays := NewHolidayRepository(db)
+		repo := NewHolidayChangeRepository(db)
+
+		holiday, err := holidays.GetByID(1)
+		require.NoError(t, err)
+
+		holiday.Description = "Diperbarui"
+		updated, err := holidays.Update(holiday.ID, holiday)
+		require.NoError(t, err)
+		assert.Equal(t, models.ChangeHolidayUpdated, updated.Type)
+
+		deleted, err := holidays.Delete(holiday.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ChangeHolidayDeleted, deleted.Type)
+		assert.Greater(t, deleted.Sequence, updated.Sequence)
+
+		changes, err := repo.GetSince(0, 10)
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+		assert.Equal(t, updated.Sequence, changes[0].Sequence)
+		assert.Equal(t, holiday.Name, changes[0].Holiday.Name)
+		assert.Equal(t, "Diperbarui", changes[0].Holiday.Description)
+
+		changes, err = repo.GetSince(updated.Sequence, 10)
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		assert.Equal(t, models.ChangeHolidayDeleted, changes[0].Type)
+		assert.False(t, changes[0].Holiday.IsActive)
+
+		changes, err = repo.GetSince(0, 1)
+		require.NoError(t, err)
+		assert.Len(t, changes, 1)
+
+		changes, err = repo.GetSince(deleted.Sequence, 10)
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+}
+
+func TestHolidayChangeRepository_FailedMutationRecordsNothing(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		holidays := NewHolidayRepository(db)
+		repo := NewHolidayChangeRepository(db)
+
+		holiday, err := holidays.GetByID(1)
+		require.NoError(t, err)
+
+		// The second update fails, so the whole import is rolled back along with its changes
+		missing := *holiday
+		missing.ID = 999999
+		_, err = holidays.BulkSave(nil, []*models.Holiday{holiday, &missing})
+		require.Error(t, err)
+
+		changes, err := repo.GetSince(0, 10)
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+
+		_, err = holidays.Delete(999999)
+		require.EqualError(t, err, "holiday not found")
+
+		changes, err = repo.GetSince(0, 10)
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+}
@@ -3,74 +3,129 @@ package repository
 import (
 	"database/sql"
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 
+	"github.com/ilramdhan/holidayapi/internal/database"
+	"github.com/ilramdhan/holidayapi/internal/metrics"
 	"github.com/ilramdhan/holidayapi/internal/models"
 )
 
-// HolidayRepository interface defines holiday data access methods
+// HolidayRepository interface defines holiday data access methods.
+// Every mutation appends to the holiday change feed in its own transaction and returns what it recorded.
 type HolidayRepository interface {
-	Create(holiday *models.Holiday) error
+	Create(holiday *models.Holiday) (*models.HolidayChange, error)
 	GetByID(id int) (*models.Holiday, error)
 	GetAll(filter models.HolidayFilter) ([]models.Holiday, int, error)
-	Update(id int, holiday *models.Holiday) error
-	Delete(id int) error
-	GetByDate(date time.Time) (*models.Holiday, error)
-	GetByDateRange(startDate, endDate time.Time, holidayType *models.HolidayType) ([]models.Holiday, error)
+	Update(id int, holiday *models.Holiday) (*models.HolidayChange, error)
+	Delete(id int) (*models.HolidayChange, error)
+	GetByDate(date time.Time, scope models.HolidayScope) ([]models.Holiday, error)
+	GetByDateRange(startDate, endDate time.Time, holidayType *models.HolidayType, scope models.HolidayScope) ([]models.Holiday, error)
+	BulkSave(creates []*models.Holiday, updates []*models.Holiday) ([]models.HolidayChange, error)
+	GetRevisions(holidayID int) ([]models.HolidayRevision, error)
+	GetRevision(holidayID, revision int) (*models.HolidayRevision, error)
+	Rollback(holiday *models.Holiday, revision int) (*models.HolidayChange, error)
 }
 
+// sqlExecutor is implemented by both *database.DB and *database.Tx
+type sqlExecutor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// holidayColumns lists the columns read by scanHoliday, in order
+const holidayColumns = "id, name, date, type, province, description, is_active, status, decree_number, decree_date, created_at, updated_at"
+
+// revisionColumns lists the columns read by scanRevision, in order
+const revisionColumns = `id, holiday_id, revision, operation, source_revision, name, date, type, province, description, is_active,
+	status, decree_number, decree_date, created_at`
+
+// holidaysAsOf replays the revision history: it selects the latest revision of every
+// holiday recorded at or before a point in time, shaped like the holidays table
+const holidaysAsOf = `(
+		SELECT r.holiday_id AS id, r.name, r.date, r.type, r.province, r.description, r.is_active,
+			r.status, r.decree_number, r.decree_date, h.created_at, r.created_at AS updated_at
+		FROM holiday_revisions r
+		JOIN holidays h ON h.id = r.holiday_id
+		WHERE r.id IN (SELECT MAX(id) FROM holiday_revisions WHERE created_at <= ? GROUP BY holiday_id)
+	) holidays`
+
 // holidayRepository implements HolidayRepository
 type holidayRepository struct {
-	db *sql.DB
+	db *database.DB
 }
 
 // NewHolidayRepository creates a new holiday repository
-func NewHolidayRepository(db *sql.DB) HolidayRepository {
+func NewHolidayRepository(db *database.DB) HolidayRepository {
 	return &holidayRepository{db: db}
 }
 
-// Create creates a new holiday
-func (r *holidayRepository) Create(holiday *models.Holiday) error {
+// Create creates a new holiday and records its first revision and change
+func (r *holidayRepository) Create(holiday *models.Holiday) (*models.HolidayChange, error) {
+	defer metrics.ObserveDBQuery("holiday", "Create", time.Now())
+
+	var change *models.HolidayChange
+	err := r.inTx(func(tx *database.Tx) error {
+		var err error
+		change, err = r.create(tx, holiday)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
+
+// create inserts a holiday with its first revision and change using the given executor
+func (r *holidayRepository) create(exec sqlExecutor, holiday *models.Holiday) (*models.HolidayChange, error) {
 	query := `
-		INSERT INTO holidays (name, date, type, description, is_active, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO holidays (name, date, type, province, description, is_active, status, decree_number, decree_date, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING id
 	`
-	
+
 	now := time.Now()
 	holiday.CreatedAt = now
 	holiday.UpdatedAt = now
 	holiday.IsActive = true
+	if holiday.Status == "" {
+		holiday.Status = models.StatusOfficial
+	}
 
-	result, err := r.db.Exec(query, holiday.Name, holiday.Date, holiday.Type, 
-		holiday.Description, holiday.IsActive, holiday.CreatedAt, holiday.UpdatedAt)
+	// Dates are stored as YYYY-MM-DD so date comparisons work the same on every driver
+	err := exec.QueryRow(query, holiday.Name, holiday.Date.Format("2006-01-02"), holiday.Type, holiday.Province,
+		holiday.Description, holiday.IsActive, holiday.Status, holiday.DecreeNumber, formatOptionalDate(holiday.DecreeDate),
+		holiday.CreatedAt, holiday.UpdatedAt).Scan(&holiday.ID)
 	if err != nil {
-		return fmt.Errorf("failed to create holiday: %w", err)
+		return nil, fmt.Errorf("failed to create holiday: %w", err)
 	}
 
-	id, err := result.LastInsertId()
+	snapshot, err := recordRevision(exec, holiday.ID, models.RevisionCreate, nil)
 	if err != nil {
-		return fmt.Errorf("failed to get last insert id: %w", err)
+		return nil, err
 	}
 
-	holiday.ID = int(id)
-	return nil
+	return recordHolidayChange(exec, models.ChangeHolidayCreated, snapshot)
 }
 
 // GetByID retrieves a holiday by ID
 func (r *holidayRepository) GetByID(id int) (*models.Holiday, error) {
+	defer metrics.ObserveDBQuery("holiday", "GetByID", time.Now())
+
 	query := `
-		SELECT id, name, date, type, description, is_active, created_at, updated_at
+		SELECT ` + holidayColumns + `
 		FROM holidays
 		WHERE id = ? AND is_active = TRUE
 	`
 
-	holiday := &models.Holiday{}
-	err := r.db.QueryRow(query, id).Scan(
-		&holiday.ID, &holiday.Name, &holiday.Date, &holiday.Type,
-		&holiday.Description, &holiday.IsActive, &holiday.CreatedAt, &holiday.UpdatedAt,
-	)
+	holiday, err := scanHoliday(r.db.QueryRow(query, id))
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -84,23 +139,25 @@ func (r *holidayRepository) GetByID(id int) (*models.Holiday, error) {
 
 // GetAll retrieves holidays with filters
 func (r *holidayRepository) GetAll(filter models.HolidayFilter) ([]models.Holiday, int, error) {
+	defer metrics.ObserveDBQuery("holiday", "GetAll", time.Now())
+
 	// Build WHERE clause
 	whereConditions := []string{"is_active = TRUE"}
 	args := []interface{}{}
 
 	if filter.Year != nil {
-		whereConditions = append(whereConditions, "strftime('%Y', date) = ?")
-		args = append(args, strconv.Itoa(*filter.Year))
+		whereConditions = append(whereConditions, r.db.DatePart(database.Year, "date")+" = ?")
+		args = append(args, *filter.Year)
 	}
 
 	if filter.Month != nil {
-		whereConditions = append(whereConditions, "strftime('%m', date) = ?")
-		args = append(args, strconv.Itoa(*filter.Month))
+		whereConditions = append(whereConditions, r.db.DatePart(database.Month, "date")+" = ?")
+		args = append(args, *filter.Month)
 	}
 
 	if filter.Day != nil {
-		whereConditions = append(whereConditions, "strftime('%d', date) = ?")
-		args = append(args, strconv.Itoa(*filter.Day))
+		whereConditions = append(whereConditions, r.db.DatePart(database.Day, "date")+" = ?")
+		args = append(args, *filter.Day)
 	}
 
 	if filter.Type != nil {
@@ -108,6 +165,8 @@ func (r *holidayRepository) GetAll(filter models.HolidayFilter) ([]models.Holida
 		args = append(args, string(*filter.Type))
 	}
 
+	whereConditions, args = appendScopeConditions(whereConditions, args, filter.HolidayScope)
+
 	if filter.StartDate != nil {
 		whereConditions = append(whereConditions, "date >= ?")
 		args = append(args, filter.StartDate.Format("2006-01-02"))
@@ -119,9 +178,10 @@ func (r *holidayRepository) GetAll(filter models.HolidayFilter) ([]models.Holida
 	}
 
 	whereClause := strings.Join(whereConditions, " AND ")
+	source, args := holidaySource(filter.HolidayScope, args)
 
 	// Count total records
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM holidays WHERE %s", whereClause)
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", source, whereClause)
 	var total int
 	err := r.db.QueryRow(countQuery, args...).Scan(&total)
 	if err != nil {
@@ -130,11 +190,11 @@ func (r *holidayRepository) GetAll(filter models.HolidayFilter) ([]models.Holida
 
 	// Build main query
 	query := fmt.Sprintf(`
-		SELECT id, name, date, type, description, is_active, created_at, updated_at
-		FROM holidays
+		SELECT %s
+		FROM %s
 		WHERE %s
 		ORDER BY date ASC
-	`, whereClause)
+	`, holidayColumns, source, whereClause)
 
 	// Add pagination
 	if filter.Limit > 0 {
@@ -155,95 +215,155 @@ func (r *holidayRepository) GetAll(filter models.HolidayFilter) ([]models.Holida
 
 	var holidays []models.Holiday
 	for rows.Next() {
-		var holiday models.Holiday
-		err := rows.Scan(
-			&holiday.ID, &holiday.Name, &holiday.Date, &holiday.Type,
-			&holiday.Description, &holiday.IsActive, &holiday.CreatedAt, &holiday.UpdatedAt,
-		)
+		holiday, err := scanHoliday(rows)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan holiday: %w", err)
 		}
-		holidays = append(holidays, holiday)
+		holidays = append(holidays, *holiday)
 	}
 
 	return holidays, total, nil
 }
 
-// Update updates a holiday
-func (r *holidayRepository) Update(id int, holiday *models.Holiday) error {
+// Update updates a holiday and records the new revision and change
+func (r *holidayRepository) Update(id int, holiday *models.Holiday) (*models.HolidayChange, error) {
+	defer metrics.ObserveDBQuery("holiday", "Update", time.Now())
+
+	var change *models.HolidayChange
+	err := r.inTx(func(tx *database.Tx) error {
+		var err error
+		change, err = r.update(tx, id, holiday, models.RevisionUpdate, nil)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
+
+// update updates a holiday and records the revision and change using the given executor.
+// An update that leaves the holiday inactive is recorded as a deletion.
+func (r *holidayRepository) update(exec sqlExecutor, id int, holiday *models.Holiday, operation models.RevisionOperation, sourceRevision *int) (*models.HolidayChange, error) {
 	query := `
 		UPDATE holidays 
-		SET name = ?, date = ?, type = ?, description = ?, is_active = ?, updated_at = ?
+		SET name = ?, date = ?, type = ?, province = ?, description = ?, is_active = ?,
+			status = ?, decree_number = ?, decree_date = ?, updated_at = ?
 		WHERE id = ?
 	`
 
 	holiday.UpdatedAt = time.Now()
 
-	result, err := r.db.Exec(query, holiday.Name, holiday.Date, holiday.Type,
-		holiday.Description, holiday.IsActive, holiday.UpdatedAt, id)
+	result, err := exec.Exec(query, holiday.Name, holiday.Date.Format("2006-01-02"), holiday.Type, holiday.Province,
+		holiday.Description, holiday.IsActive, holiday.Status, holiday.DecreeNumber, formatOptionalDate(holiday.DecreeDate),
+		holiday.UpdatedAt, id)
 	if err != nil {
-		return fmt.Errorf("failed to update holiday: %w", err)
+		return nil, fmt.Errorf("failed to update holiday: %w", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("holiday not found")
+		return nil, fmt.Errorf("holiday not found")
 	}
 
-	return nil
-}
-
-// Delete soft deletes a holiday
-func (r *holidayRepository) Delete(id int) error {
-	query := `UPDATE holidays SET is_active = FALSE, updated_at = ? WHERE id = ?`
-
-	result, err := r.db.Exec(query, time.Now(), id)
+	snapshot, err := recordRevision(exec, id, operation, sourceRevision)
 	if err != nil {
-		return fmt.Errorf("failed to delete holiday: %w", err)
+		return nil, err
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+	changeType := models.ChangeHolidayUpdated
+	if !snapshot.IsActive {
+		changeType = models.ChangeHolidayDeleted
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("holiday not found")
+	return recordHolidayChange(exec, changeType, snapshot)
+}
+
+// Delete soft deletes a holiday and records the deletion as a revision and change
+func (r *holidayRepository) Delete(id int) (*models.HolidayChange, error) {
+	defer metrics.ObserveDBQuery("holiday", "Delete", time.Now())
+
+	var change *models.HolidayChange
+	err := r.inTx(func(tx *database.Tx) error {
+		query := `UPDATE holidays SET is_active = FALSE, updated_at = ? WHERE id = ? AND is_active = TRUE`
+
+		result, err := tx.Exec(query, time.Now(), id)
+		if err != nil {
+			return fmt.Errorf("failed to delete holiday: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return fmt.Errorf("holiday not found")
+		}
+
+		snapshot, err := recordRevision(tx, id, models.RevisionDelete, nil)
+		if err != nil {
+			return err
+		}
+
+		change, err = recordHolidayChange(tx, models.ChangeHolidayDeleted, snapshot)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return change, nil
 }
 
-// GetByDate retrieves holiday by specific date
-func (r *holidayRepository) GetByDate(date time.Time) (*models.Holiday, error) {
-	query := `
-		SELECT id, name, date, type, description, is_active, created_at, updated_at
-		FROM holidays
-		WHERE date = ? AND is_active = TRUE
-	`
+// GetByDate retrieves every holiday on a specific date, nationwide holidays first
+func (r *holidayRepository) GetByDate(date time.Time, scope models.HolidayScope) ([]models.Holiday, error) {
+	defer metrics.ObserveDBQuery("holiday", "GetByDate", time.Now())
 
-	holiday := &models.Holiday{}
-	err := r.db.QueryRow(query, date.Format("2006-01-02")).Scan(
-		&holiday.ID, &holiday.Name, &holiday.Date, &holiday.Type,
-		&holiday.Description, &holiday.IsActive, &holiday.CreatedAt, &holiday.UpdatedAt,
+	whereConditions, args := appendScopeConditions(
+		[]string{"date = ?", "is_active = TRUE"},
+		[]interface{}{date.Format("2006-01-02")},
+		scope,
 	)
+	source, args := holidaySource(scope, args)
 
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE %s
+		ORDER BY CASE WHEN province IS NULL THEN 0 ELSE 1 END, id ASC
+	`, holidayColumns, source, strings.Join(whereConditions, " AND "))
+
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil // No holiday found, not an error
+		return nil, fmt.Errorf("failed to query holidays by date: %w", err)
+	}
+	defer rows.Close()
+
+	holidays := []models.Holiday{}
+	for rows.Next() {
+		holiday, err := scanHoliday(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan holiday: %w", err)
 		}
-		return nil, fmt.Errorf("failed to get holiday by date: %w", err)
+		holidays = append(holidays, *holiday)
 	}
 
-	return holiday, nil
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate holidays: %w", err)
+	}
+
+	return holidays, nil
 }
 
 // GetByDateRange retrieves holidays within date range
-func (r *holidayRepository) GetByDateRange(startDate, endDate time.Time, holidayType *models.HolidayType) ([]models.Holiday, error) {
+func (r *holidayRepository) GetByDateRange(startDate, endDate time.Time, holidayType *models.HolidayType, scope models.HolidayScope) ([]models.Holiday, error) {
+	defer metrics.ObserveDBQuery("holiday", "GetByDateRange", time.Now())
+
 	whereConditions := []string{"is_active = TRUE", "date >= ?", "date <= ?"}
 	args := []interface{}{startDate.Format("2006-01-02"), endDate.Format("2006-01-02")}
 
@@ -252,12 +372,15 @@ func (r *holidayRepository) GetByDateRange(startDate, endDate time.Time, holiday
 		args = append(args, string(*holidayType))
 	}
 
+	whereConditions, args = appendScopeConditions(whereConditions, args, scope)
+	source, args := holidaySource(scope, args)
+
 	query := fmt.Sprintf(`
-		SELECT id, name, date, type, description, is_active, created_at, updated_at
-		FROM holidays
+		SELECT %s
+		FROM %s
 		WHERE %s
 		ORDER BY date ASC
-	`, strings.Join(whereConditions, " AND "))
+	`, holidayColumns, source, strings.Join(whereConditions, " AND "))
 
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
@@ -267,16 +390,262 @@ func (r *holidayRepository) GetByDateRange(startDate, endDate time.Time, holiday
 
 	var holidays []models.Holiday
 	for rows.Next() {
-		var holiday models.Holiday
-		err := rows.Scan(
-			&holiday.ID, &holiday.Name, &holiday.Date, &holiday.Type,
-			&holiday.Description, &holiday.IsActive, &holiday.CreatedAt, &holiday.UpdatedAt,
-		)
+		holiday, err := scanHoliday(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan holiday: %w", err)
 		}
-		holidays = append(holidays, holiday)
+		holidays = append(holidays, *holiday)
 	}
 
 	return holidays, nil
 }
+
+// BulkSave creates and updates holidays in a single transaction and returns the recorded changes in order
+func (r *holidayRepository) BulkSave(creates []*models.Holiday, updates []*models.Holiday) ([]models.HolidayChange, error) {
+	defer metrics.ObserveDBQuery("holiday", "BulkSave", time.Now())
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	changes := make([]models.HolidayChange, 0, len(creates)+len(updates))
+
+	for _, holiday := range creates {
+		change, err := r.create(tx, holiday)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, *change)
+	}
+
+	for _, holiday := range updates {
+		change, err := r.update(tx, holiday.ID, holiday, models.RevisionUpdate, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update holiday %d: %w", holiday.ID, err)
+		}
+		changes = append(changes, *change)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return changes, nil
+}
+
+// GetRevisions retrieves the full revision history of a holiday, oldest first
+func (r *holidayRepository) GetRevisions(holidayID int) ([]models.HolidayRevision, error) {
+	defer metrics.ObserveDBQuery("holiday", "GetRevisions", time.Now())
+
+	query := `SELECT ` + revisionColumns + ` FROM holiday_revisions WHERE holiday_id = ? ORDER BY revision ASC`
+
+	rows, err := r.db.Query(query, holidayID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query holiday revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []models.HolidayRevision{}
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan holiday revision: %w", err)
+		}
+		revisions = append(revisions, *revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate holiday revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// GetRevision retrieves one revision of a holiday
+func (r *holidayRepository) GetRevision(holidayID, revision int) (*models.HolidayRevision, error) {
+	defer metrics.ObserveDBQuery("holiday", "GetRevision", time.Now())
+
+	query := `SELECT ` + revisionColumns + ` FROM holiday_revisions WHERE holiday_id = ? AND revision = ?`
+
+	rev, err := scanRevision(r.db.QueryRow(query, holidayID, revision))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("holiday revision not found")
+		}
+		return nil, fmt.Errorf("failed to get holiday revision: %w", err)
+	}
+
+	return rev, nil
+}
+
+// Rollback overwrites a holiday, deleted or not, with the state of an earlier revision
+// and records the restore as a new revision and change
+func (r *holidayRepository) Rollback(holiday *models.Holiday, revision int) (*models.HolidayChange, error) {
+	defer metrics.ObserveDBQuery("holiday", "Rollback", time.Now())
+
+	var change *models.HolidayChange
+	err := r.inTx(func(tx *database.Tx) error {
+		var err error
+		change, err = r.update(tx, holiday.ID, holiday, models.RevisionRollback, &revision)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
+
+// inTx runs fn in a transaction, committing only when it succeeds
+func (r *holidayRepository) inTx(fn func(tx *database.Tx) error) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// recordRevision appends a snapshot of the holiday's current row to its history and returns the snapshot
+func recordRevision(exec sqlExecutor, holidayID int, operation models.RevisionOperation, sourceRevision *int) (*models.Holiday, error) {
+	holiday, err := scanHoliday(exec.QueryRow(`SELECT `+holidayColumns+` FROM holidays WHERE id = ?`, holidayID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read holiday for revision: %w", err)
+	}
+
+	var revision int
+	err = exec.QueryRow(`SELECT COALESCE(MAX(revision), 0) + 1 FROM holiday_revisions WHERE holiday_id = ?`, holidayID).Scan(&revision)
+	if err != nil {
+		return nil, fmt.Errorf("failed to number holiday revision: %w", err)
+	}
+
+	query := `
+		INSERT INTO holiday_revisions (holiday_id, revision, operation, source_revision, name, date, type, province, description,
+			is_active, status, decree_number, decree_date, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err = exec.Exec(query, holidayID, revision, operation, sourceRevision, holiday.Name, holiday.Date.Format("2006-01-02"),
+		holiday.Type, holiday.Province, holiday.Description, holiday.IsActive, holiday.Status, holiday.DecreeNumber,
+		formatOptionalDate(holiday.DecreeDate), time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to record holiday revision: %w", err)
+	}
+
+	return holiday, nil
+}
+
+// holidaySource returns the table to read holidays from for the scope, prepending its
+// arguments: the live table, or the replayed history when the scope has a point in time
+func holidaySource(scope models.HolidayScope, args []interface{}) (string, []interface{}) {
+	if scope.AsOf == nil {
+		return "holidays", args
+	}
+
+	// Revision times are stored in UTC, and SQLite compares them as text
+	return holidaysAsOf, append([]interface{}{scope.AsOf.UTC()}, args...)
+}
+
+// appendScopeConditions restricts a query to the holidays effective in the scope:
+// nationwide holidays, plus the regional holidays of the scope's province if any,
+// with the selected statuses (anything but cancelled by default)
+func appendScopeConditions(whereConditions []string, args []interface{}, scope models.HolidayScope) ([]string, []interface{}) {
+	if scope.Province == nil {
+		whereConditions = append(whereConditions, "province IS NULL")
+	} else {
+		whereConditions = append(whereConditions, "(province IS NULL OR province = ?)")
+		args = append(args, *scope.Province)
+	}
+
+	if len(scope.Statuses) == 0 {
+		return append(whereConditions, "status <> ?"), append(args, models.StatusCancelled)
+	}
+
+	placeholders := make([]string, len(scope.Statuses))
+	for i, status := range scope.Statuses {
+		placeholders[i] = "?"
+		args = append(args, status)
+	}
+	return append(whereConditions, "status IN ("+strings.Join(placeholders, ", ")+")"), args
+}
+
+// formatOptionalDate formats an optional date as YYYY-MM-DD for storage
+func formatOptionalDate(date *time.Time) interface{} {
+	if date == nil {
+		return nil
+	}
+	return date.Format("2006-01-02")
+}
+
+// scanHoliday scans a row selected with holidayColumns
+func scanHoliday(row rowScanner) (*models.Holiday, error) {
+	holiday := &models.Holiday{}
+	var province, decreeNumber sql.NullString
+	var decreeDate sql.NullTime
+
+	err := row.Scan(
+		&holiday.ID, &holiday.Name, &holiday.Date, &holiday.Type, &province,
+		&holiday.Description, &holiday.IsActive, &holiday.Status, &decreeNumber, &decreeDate,
+		&holiday.CreatedAt, &holiday.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if province.Valid {
+		holiday.Province = &province.String
+	}
+	if decreeNumber.Valid {
+		holiday.DecreeNumber = &decreeNumber.String
+	}
+	if decreeDate.Valid {
+		holiday.DecreeDate = &decreeDate.Time
+	}
+
+	return holiday, nil
+}
+
+// scanRevision scans a row selected with revisionColumns
+func scanRevision(row rowScanner) (*models.HolidayRevision, error) {
+	revision := &models.HolidayRevision{}
+	var sourceRevision sql.NullInt64
+	var province, description, decreeNumber sql.NullString
+	var decreeDate sql.NullTime
+
+	err := row.Scan(
+		&revision.ID, &revision.HolidayID, &revision.Revision, &revision.Operation, &sourceRevision,
+		&revision.Name, &revision.Date, &revision.Type, &province, &description, &revision.IsActive,
+		&revision.Status, &decreeNumber, &decreeDate, &revision.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if sourceRevision.Valid {
+		id := int(sourceRevision.Int64)
+		revision.SourceRevision = &id
+	}
+	if province.Valid {
+		revision.Province = &province.String
+	}
+	revision.Description = description.String
+	if decreeNumber.Valid {
+		revision.DecreeNumber = &decreeNumber.String
+	}
+	if decreeDate.Valid {
+		revision.DecreeDate = &decreeDate.Time
+	}
+
+	return revision, nil
+}
@@ -0,0 +1,352 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilramdhan/holidayapi/internal/database"
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestHolidayRepository_CRUD(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		repo := NewHolidayRepository(db)
+
+		holiday := &models.Holiday{
+			Name:        "Test Holiday",
+			Date:        time.Date(2030, 3, 14, 0, 0, 0, 0, time.UTC),
+			Type:        models.NationalHoliday,
+			Description: "Created by a test",
+		}
+		change, err := repo.Create(holiday)
+		require.NoError(t, err)
+		assert.NotZero(t, holiday.ID)
+		assert.Equal(t, models.ChangeHolidayCreated, change.Type)
+		assert.Equal(t, holiday.ID, change.HolidayID)
+
+		got, err := repo.GetByID(holiday.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Test Holiday", got.Name)
+		assert.Equal(t, "2030-03-14", got.Date.Format("2006-01-02"))
+		assert.Nil(t, got.Province)
+
+		got.Name = "Renamed Holiday"
+		change, err = repo.Update(got.ID, got)
+		require.NoError(t, err)
+		assert.Equal(t, models.ChangeHolidayUpdated, change.Type)
+		assert.Equal(t, "Renamed Holiday", change.Holiday.Name)
+
+		got, err = repo.GetByID(holiday.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Renamed Holiday", got.Name)
+
+		change, err = repo.Delete(holiday.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ChangeHolidayDeleted, change.Type)
+		assert.False(t, change.Holiday.IsActive)
+		_, err = repo.GetByID(holiday.ID)
+		assert.EqualError(t, err, "holiday not found")
+
+		_, err = repo.Delete(999999)
+		assert.EqualError(t, err, "holiday not found")
+	})
+}
+
+func TestHolidayRepository_GetAllDateFilters(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		repo := NewHolidayRepository(db)
+
+		// Sample data from the migrations: April 2024 has two Idul Fitri days and four collective leave days
+		holidays, total, err := repo.GetAll(models.HolidayFilter{Year: intPtr(2024), Month: intPtr(4)})
+		require.NoError(t, err)
+		assert.Equal(t, 6, total)
+		require.Len(t, holidays, 6)
+		for _, h := range holidays {
+			assert.Equal(t, 2024, h.Date.Year())
+			assert.Equal(t, time.April, h.Date.Month())
+		}
+
+		holidayType := models.NationalHoliday
+		holidays, total, err = repo.GetAll(models.HolidayFilter{Year: intPtr(2024), Month: intPtr(4), Day: intPtr(10), Type: &holidayType})
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		require.Len(t, holidays, 1)
+		assert.Equal(t, "Hari Raya Idul Fitri", holidays[0].Name)
+
+		holidays, total, err = repo.GetAll(models.HolidayFilter{Year: intPtr(2024), Limit: 5, Offset: 5})
+		require.NoError(t, err)
+		assert.Equal(t, 26, total)
+		assert.Len(t, holidays, 5)
+	})
+}
+
+func TestHolidayRepository_ProvinceScope(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		repo := NewHolidayRepository(db)
+
+		bali := "ID-BA"
+		date := time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)
+		_, err := repo.Create(&models.Holiday{
+			Name: "Regional Nyepi", Date: date, Type: models.RegionalHoliday, Province: &bali,
+		})
+		require.NoError(t, err)
+
+		national, err := repo.GetByDate(date, models.HolidayScope{})
+		require.NoError(t, err)
+		require.Len(t, national, 1)
+		assert.Nil(t, national[0].Province)
+
+		// The province's regional holiday is listed after the nationwide one on the same date
+		withRegional, err := repo.GetByDate(date, models.HolidayScope{Province: &bali})
+		require.NoError(t, err)
+		require.Len(t, withRegional, 2)
+		assert.Nil(t, withRegional[0].Province)
+		assert.Equal(t, "Regional Nyepi", withRegional[1].Name)
+
+		_, total, err := repo.GetAll(models.HolidayFilter{HolidayScope: models.HolidayScope{Province: &bali}, Year: intPtr(2024), Month: intPtr(3)})
+		require.NoError(t, err)
+		assert.Equal(t, 4, total)
+
+		none, err := repo.GetByDate(time.Date(2024, 3, 13, 0, 0, 0, 0, time.UTC), models.HolidayScope{})
+		require.NoError(t, err)
+		assert.Empty(t, none)
+	})
+}
+
+func TestHolidayRepository_Status(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		repo := NewHolidayRepository(db)
+
+		date := time.Date(2030, 2, 3, 0, 0, 0, 0, time.UTC)
+		holiday := &models.Holiday{Name: "Tahun Baru Imlek", Date: date, Type: models.NationalHoliday, Status: models.StatusProvisional}
+		_, err := repo.Create(holiday)
+		require.NoError(t, err)
+
+		// Migrated holidays default to official
+		existing, err := repo.GetByID(1)
+		require.NoError(t, err)
+		assert.Equal(t, models.StatusOfficial, existing.Status)
+		assert.Nil(t, existing.DecreeNumber)
+
+		decree := "SKB 3 Menteri No. 2/2029"
+		decreeDate := time.Date(2029, 9, 1, 0, 0, 0, 0, time.UTC)
+		holiday.Status = models.StatusCancelled
+		holiday.DecreeNumber = &decree
+		holiday.DecreeDate = &decreeDate
+		_, err = repo.Update(holiday.ID, holiday)
+		require.NoError(t, err)
+
+		got, err := repo.GetByID(holiday.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.StatusCancelled, got.Status)
+		assert.Equal(t, decree, *got.DecreeNumber)
+		assert.Equal(t, "2029-09-01", got.DecreeDate.Format("2006-01-02"))
+
+		// Cancelled holidays are hidden unless selected
+		none, err := repo.GetByDate(date, models.HolidayScope{})
+		require.NoError(t, err)
+		assert.Empty(t, none)
+
+		cancelled := models.HolidayScope{Statuses: []models.HolidayStatus{models.StatusCancelled}}
+		found, err := repo.GetByDate(date, cancelled)
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, holiday.ID, found[0].ID)
+
+		_, total, err := repo.GetAll(models.HolidayFilter{
+			HolidayScope: models.HolidayScope{Statuses: []models.HolidayStatus{models.StatusOfficial, models.StatusRevised}},
+			Year:         intPtr(2024),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 26, total)
+
+		revisions, err := repo.GetRevisions(holiday.ID)
+		require.NoError(t, err)
+		require.Len(t, revisions, 2)
+		assert.Equal(t, models.StatusProvisional, revisions[0].Status)
+		assert.Equal(t, models.StatusCancelled, revisions[1].Status)
+		assert.Equal(t, decree, *revisions[1].DecreeNumber)
+	})
+}
+
+func TestHolidayRepository_SameDate(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		repo := NewHolidayRepository(db)
+
+		// A religious holiday falling on Independence Day
+		date := time.Date(2024, 8, 17, 0, 0, 0, 0, time.UTC)
+		maulid := &models.Holiday{Name: "Maulid Nabi Muhammad SAW", Date: date, Type: models.NationalHoliday}
+		_, err := repo.Create(maulid)
+		require.NoError(t, err)
+
+		holidays, err := repo.GetByDate(date, models.HolidayScope{})
+		require.NoError(t, err)
+		require.Len(t, holidays, 2)
+		assert.Equal(t, "Hari Kemerdekaan Republik Indonesia", holidays[0].Name)
+		assert.Equal(t, maulid.ID, holidays[1].ID)
+
+		// The same name, date, type and region may only be stored once
+		duplicate := &models.Holiday{Name: "Maulid Nabi Muhammad SAW", Date: date, Type: models.NationalHoliday}
+		_, err = repo.Create(duplicate)
+		assert.Error(t, err)
+
+		// Deleted holidays do not count
+		_, err = repo.Delete(maulid.ID)
+		require.NoError(t, err)
+		_, err = repo.Create(duplicate)
+		assert.NoError(t, err)
+	})
+}
+
+func TestHolidayRepository_BulkSave(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		repo := NewHolidayRepository(db)
+
+		existing := &models.Holiday{Name: "Existing", Date: time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC), Type: models.NationalHoliday}
+		_, err := repo.Create(existing)
+		require.NoError(t, err)
+
+		existing.Description = "Updated in bulk"
+		creates := []*models.Holiday{
+			{Name: "Bulk One", Date: time.Date(2031, 2, 1, 0, 0, 0, 0, time.UTC), Type: models.NationalHoliday},
+			{Name: "Bulk Two", Date: time.Date(2031, 2, 2, 0, 0, 0, 0, time.UTC), Type: models.CollectiveLeave},
+		}
+		changes, err := repo.BulkSave(creates, []*models.Holiday{existing})
+		require.NoError(t, err)
+		require.Len(t, changes, 3)
+		assert.Equal(t, models.ChangeHolidayCreated, changes[0].Type)
+		assert.Equal(t, creates[1].ID, changes[1].HolidayID)
+		assert.Equal(t, models.ChangeHolidayUpdated, changes[2].Type)
+		assert.Less(t, changes[0].Sequence, changes[2].Sequence)
+
+		holidays, err := repo.GetByDateRange(
+			time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2031, 12, 31, 0, 0, 0, 0, time.UTC), nil, models.HolidayScope{},
+		)
+		require.NoError(t, err)
+		require.Len(t, holidays, 3)
+		assert.Equal(t, "Updated in bulk", holidays[0].Description)
+
+		// A failing update rolls back the creates of the same batch
+		missing := &models.Holiday{ID: 999999, Name: "Missing", Date: time.Date(2031, 6, 1, 0, 0, 0, 0, time.UTC), Type: models.NationalHoliday}
+		_, err = repo.BulkSave([]*models.Holiday{
+			{Name: "Rolled Back", Date: time.Date(2031, 5, 1, 0, 0, 0, 0, time.UTC), Type: models.NationalHoliday},
+		}, []*models.Holiday{missing})
+		assert.Error(t, err)
+
+		_, total, err := repo.GetAll(models.HolidayFilter{Year: intPtr(2031)})
+		require.NoError(t, err)
+		assert.Equal(t, 3, total)
+	})
+}
+
+func TestHolidayRepository_AsOfIgnoresTimeZone(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		repo := NewHolidayRepository(db)
+
+		holiday := &models.Holiday{Name: "Backfilled", Date: time.Date(2033, 8, 17, 0, 0, 0, 0, time.UTC), Type: models.NationalHoliday}
+		_, err := repo.Create(holiday)
+		require.NoError(t, err)
+
+		// Revisions backfilled by the migration hold UTC CURRENT_TIMESTAMP values
+		recordedAt := time.Now().UTC().Add(-time.Hour)
+		_, err = db.Exec("UPDATE holiday_revisions SET created_at = ? WHERE holiday_id = ?", recordedAt.Format("2006-01-02 15:04:05"), holiday.ID)
+		require.NoError(t, err)
+
+		// A point in time given in another zone means the same instant
+		wib := time.FixedZone("WIB", 7*60*60)
+		for _, tc := range []struct {
+			asOf     time.Time
+			expected int
+		}{
+			{asOf: recordedAt.Add(time.Minute).In(wib), expected: 1},
+			{asOf: recordedAt.Add(-time.Minute).In(wib), expected: 0},
+		} {
+			asOf := tc.asOf
+			found, err := repo.GetByDate(holiday.Date, models.HolidayScope{AsOf: &asOf})
+			require.NoError(t, err)
+			assert.Len(t, found, tc.expected, "as of %s", asOf)
+		}
+	})
+}
+
+func TestHolidayRepository_Revisions(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		repo := NewHolidayRepository(db)
+
+		holiday := &models.Holiday{Name: "Original", Date: time.Date(2032, 5, 1, 0, 0, 0, 0, time.UTC), Type: models.NationalHoliday}
+		_, err := repo.Create(holiday)
+		require.NoError(t, err)
+		afterCreate := time.Now()
+
+		holiday.Date = time.Date(2032, 5, 2, 0, 0, 0, 0, time.UTC)
+		_, err = repo.Update(holiday.ID, holiday)
+		require.NoError(t, err)
+		afterUpdate := time.Now()
+
+		_, err = repo.Delete(holiday.ID)
+		require.NoError(t, err)
+		_, err = repo.Delete(holiday.ID)
+		assert.EqualError(t, err, "holiday not found")
+
+		revisions, err := repo.GetRevisions(holiday.ID)
+		require.NoError(t, err)
+		require.Len(t, revisions, 3)
+		assert.Equal(t, []models.RevisionOperation{models.RevisionCreate, models.RevisionUpdate, models.RevisionDelete},
+			[]models.RevisionOperation{revisions[0].Operation, revisions[1].Operation, revisions[2].Operation})
+		assert.Equal(t, "2032-05-01", revisions[0].Date.Format("2006-01-02"))
+		assert.Equal(t, "2032-05-02", revisions[1].Date.Format("2006-01-02"))
+		assert.False(t, revisions[2].IsActive)
+
+		// Point-in-time queries replay the history
+		year := intPtr(2032)
+		for _, tc := range []struct {
+			asOf     time.Time
+			expected []string
+		}{
+			{asOf: afterCreate, expected: []string{"2032-05-01"}},
+			{asOf: afterUpdate, expected: []string{"2032-05-02"}},
+			{asOf: time.Now(), expected: nil},
+		} {
+			asOf := tc.asOf
+			holidays, _, err := repo.GetAll(models.HolidayFilter{HolidayScope: models.HolidayScope{AsOf: &asOf}, Year: year})
+			require.NoError(t, err)
+			var dates []string
+			for _, h := range holidays {
+				dates = append(dates, h.Date.Format("2006-01-02"))
+			}
+			assert.Equal(t, tc.expected, dates)
+		}
+
+		asOf := afterCreate
+		found, err := repo.GetByDate(time.Date(2032, 5, 1, 0, 0, 0, 0, time.UTC), models.HolidayScope{AsOf: &asOf})
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, holiday.ID, found[0].ID)
+
+		// Rolling back to the first revision restores the deleted holiday
+		first, err := repo.GetRevision(holiday.ID, 1)
+		require.NoError(t, err)
+		restored := first.Holiday()
+		change, err := repo.Rollback(&restored, 1)
+		require.NoError(t, err)
+		assert.Equal(t, models.ChangeHolidayUpdated, change.Type, "restoring a deleted holiday is an update")
+
+		current, err := repo.GetByID(holiday.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "2032-05-01", current.Date.Format("2006-01-02"))
+
+		last, err := repo.GetRevision(holiday.ID, 4)
+		require.NoError(t, err)
+		assert.Equal(t, models.RevisionRollback, last.Operation)
+		require.NotNil(t, last.SourceRevision)
+		assert.Equal(t, 1, *last.SourceRevision)
+
+		_, err = repo.GetRevision(holiday.ID, 5)
+		assert.EqualError(t, err, "holiday revision not found")
+	})
+}
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ilramdhan/holidayapi/internal/database"
+	"github.com/ilramdhan/holidayapi/internal/metrics"
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+// ErrLoginLockoutNotFound is returned when a username or IP address has no failed login record
+var ErrLoginLockoutNotFound = errors.New("login lockout not found")
+
+// LoginLockoutRepository interface defines failed login tracking data access methods
+type LoginLockoutRepository interface {
+	Get(scope models.LockoutScope, key string) (*models.LoginLockout, error)
+	GetByID(id int) (*models.LoginLockout, error)
+	GetLocked(now time.Time) ([]models.LoginLockout, error)
+	RecordFailure(scope models.LockoutScope, key string, at, resetBefore time.Time) (*models.LoginLockout, error)
+	Lock(id, lockouts int, until time.Time) error
+	ForgiveFailure(scope models.LockoutScope, key string) error
+	Delete(scope models.LockoutScope, key string) error
+	DeleteByID(id int) error
+	DeleteStale(before, now time.Time) (int64, error)
+}
+
+// loginLockoutRepository implements LoginLockoutRepository
+type loginLockoutRepository struct {
+	db *database.DB
+}
+
+// NewLoginLockoutRepository creates a new login lockout repository
+func NewLoginLockoutRepository(db *database.DB) LoginLockoutRepository {
+	return &loginLockoutRepository{db: db}
+}
+
+// loginLockoutColumns lists the columns read by scanLoginLockout
+const loginLockoutColumns = `id, scope, lock_key, failed_attempts, lockouts, locked_until, last_failed_at, created_at`
+
+// Get retrieves the failed login record of a username or IP address
+func (r *loginLockoutRepository) Get(scope models.LockoutScope, key string) (*models.LoginLockout, error) {
+	defer metrics.ObserveDBQuery("login_lockout", "Get", time.Now())
+
+	query := `SELECT ` + loginLockoutColumns + ` FROM login_lockouts WHERE scope = ? AND lock_key = ?`
+
+	lockout, err := scanLoginLockout(r.db.QueryRow(query, scope, key))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrLoginLockoutNotFound
+		}
+		return nil, fmt.Errorf("failed to get login lockout: %w", err)
+	}
+
+	return lockout, nil
+}
+
+// GetByID retrieves a failed login record by ID
+func (r *loginLockoutRepository) GetByID(id int) (*models.LoginLockout, error) {
+	defer metrics.ObserveDBQuery("login_lockout", "GetByID", time.Now())
+
+	query := `SELECT ` + loginLockoutColumns + ` FROM login_lockouts WHERE id = ?`
+
+	lockout, err := scanLoginLockout(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrLoginLockoutNotFound
+		}
+		return nil, fmt.Errorf("failed to get login lockout: %w", err)
+	}
+
+	return lockout, nil
+}
+
+// GetLocked retrieves the records that are locked at a point in time, longest lock first
+func (r *loginLockoutRepository) GetLocked(now time.Time) ([]models.LoginLockout, error) {
+	defer metrics.ObserveDBQuery("login_lockout", "GetLocked", time.Now())
+
+	query := `SELECT ` + loginLockoutColumns + `
+		FROM login_lockouts
+		WHERE locked_until > ?
+		ORDER BY locked_until DESC, id DESC
+	`
+
+	rows, err := r.db.Query(query, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get login lockouts: %w", err)
+	}
+	defer rows.Close()
+
+	lockouts := []models.LoginLockout{}
+	for rows.Next() {
+		lockout, err := scanLoginLockout(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan login lockout: %w", err)
+		}
+		lockouts = append(lockouts, *lockout)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate login lockouts: %w", err)
+	}
+
+	return lockouts, nil
+}
+
+// RecordFailure counts a failed login and returns the updated record. Counts of a record
+// whose last failure was before resetBefore start over, so old failures are forgiven.
+func (r *loginLockoutRepository) RecordFailure(scope models.LockoutScope, key string, at, resetBefore time.Time) (*models.LoginLockout, error) {
+	defer metrics.ObserveDBQuery("login_lockout", "RecordFailure", time.Now())
+
+	query := `
+		INSERT INTO login_lockouts (scope, lock_key, failed_attempts, lockouts, last_failed_at, created_at)
+		VALUES (?, ?, 1, 0, ?, ?)
+		ON CONFLICT (scope, lock_key) DO UPDATE
+		SET failed_attempts = CASE WHEN login_lockouts.last_failed_at < ? THEN 1 ELSE login_lockouts.failed_attempts + 1 END,
+			lockouts = CASE WHEN login_lockouts.last_failed_at < ? THEN 0 ELSE login_lockouts.lockouts END,
+			last_failed_at = excluded.last_failed_at
+		RETURNING ` + loginLockoutColumns
+
+	lockout, err := scanLoginLockout(r.db.QueryRow(query, scope, key, at, at, resetBefore, resetBefore))
+	if err != nil {
+		return nil, fmt.Errorf("failed to record failed login: %w", err)
+	}
+
+	return lockout, nil
+}
+
+// Lock locks a record until a point in time and starts counting failures again
+func (r *loginLockoutRepository) Lock(id, lockouts int, until time.Time) error {
+	defer metrics.ObserveDBQuery("login_lockout", "Lock", time.Now())
+
+	query := `UPDATE login_lockouts SET failed_attempts = 0, lockouts = ?, locked_until = ? WHERE id = ?`
+
+	if _, err := r.db.Exec(query, lockouts, until, id); err != nil {
+		return fmt.Errorf("failed to lock login: %w", err)
+	}
+
+	return nil
+}
+
+// ForgiveFailure takes one failed login off the count of a username or IP address. A lock in
+// force and the number of earlier locks are kept.
+func (r *loginLockoutRepository) ForgiveFailure(scope models.LockoutScope, key string) error {
+	defer metrics.ObserveDBQuery("login_lockout", "ForgiveFailure", time.Now())
+
+	query := `UPDATE login_lockouts SET failed_attempts = failed_attempts - 1 WHERE scope = ? AND lock_key = ? AND failed_attempts > 0`
+
+	if _, err := r.db.Exec(query, scope, key); err != nil {
+		return fmt.Errorf("failed to forgive failed login: %w", err)
+	}
+
+	return nil
+}
+
+// Delete forgets the failed logins of a username or IP address
+func (r *loginLockoutRepository) Delete(scope models.LockoutScope, key string) error {
+	defer metrics.ObserveDBQuery("login_lockout", "Delete", time.Now())
+
+	if _, err := r.db.Exec(`DELETE FROM login_lockouts WHERE scope = ? AND lock_key = ?`, scope, key); err != nil {
+		return fmt.Errorf("failed to delete login lockout: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteByID deletes a failed login record, lifting its lock
+func (r *loginLockoutRepository) DeleteByID(id int) error {
+	defer metrics.ObserveDBQuery("login_lockout", "DeleteByID", time.Now())
+
+	result, err := r.db.Exec(`DELETE FROM login_lockouts WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete login lockout: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrLoginLockoutNotFound
+	}
+
+	return nil
+}
+
+// DeleteStale deletes records that are not locked at now and whose last failure was before
+// a point in time, and returns how many were deleted
+func (r *loginLockoutRepository) DeleteStale(before, now time.Time) (int64, error) {
+	defer metrics.ObserveDBQuery("login_lockout", "DeleteStale", time.Now())
+
+	query := `DELETE FROM login_lockouts WHERE last_failed_at < ? AND (locked_until IS NULL OR locked_until <= ?)`
+
+	result, err := r.db.Exec(query, before, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete stale login lockouts: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// scanLoginLockout scans a failed login record from a row
+func scanLoginLockout(row rowScanner) (*models.LoginLockout, error) {
+	lockout := &models.LoginLockout{}
+	var lockedUntil sql.NullTime
+
+	err := row.Scan(
+		&lockout.ID, &lockout.Scope, &lockout.Key, &lockout.FailedAttempts, &lockout.Lockouts,
+		&lockedUntil, &lockout.LastFailedAt, &lockout.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if lockedUntil.Valid {
+		lockout.LockedUntil = &lockedUntil.Time
+	}
+
+	return lockout, nil
+}
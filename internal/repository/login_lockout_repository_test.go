@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilramdhan/holidayapi/internal/database"
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+func TestLoginLockoutRepository_RecordFailure(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		repo := NewLoginLockoutRepository(db)
+		now := time.Now()
+		resetBefore := now.Add(-time.Hour)
+
+		first, err := repo.RecordFailure(models.LockoutScopeUsername, "admin", now.Add(-2*time.Minute), resetBefore)
+		require.NoError(t, err)
+		assert.Equal(t, 1, first.FailedAttempts)
+		assert.Nil(t, first.LockedUntil)
+
+		second, err := repo.RecordFailure(models.LockoutScopeUsername, "admin", now.Add(-time.Minute), resetBefore)
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, second.ID)
+		assert.Equal(t, 2, second.FailedAttempts)
+
+		// The same key in another scope is counted separately
+		ip, err := repo.RecordFailure(models.LockoutScopeIP, "admin", now, resetBefore)
+		require.NoError(t, err)
+		assert.NotEqual(t, first.ID, ip.ID)
+		assert.Equal(t, 1, ip.FailedAttempts)
+
+		until := now.Add(time.Minute)
+		require.NoError(t, repo.Lock(second.ID, 1, until))
+
+		locked, err := repo.GetLocked(now)
+		require.NoError(t, err)
+		require.Len(t, locked, 1)
+		assert.Equal(t, "admin", locked[0].Key)
+		assert.Equal(t, 0, locked[0].FailedAttempts)
+		assert.Equal(t, 1, locked[0].Lockouts)
+		assert.True(t, locked[0].IsLocked(now))
+		assert.False(t, locked[0].IsLocked(until.Add(time.Second)))
+
+		// A failure after a quiet period starts the counts over
+		later := now.Add(2 * time.Hour)
+		reset, err := repo.RecordFailure(models.LockoutScopeUsername, "admin", later, later.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 1, reset.FailedAttempts)
+		assert.Equal(t, 0, reset.Lockouts)
+	})
+}
+
+func TestLoginLockoutRepository_Delete(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		repo := NewLoginLockoutRepository(db)
+		now := time.Now()
+
+		stale, err := repo.RecordFailure(models.LockoutScopeIP, "10.0.0.1", now.Add(-2*time.Hour), now.Add(-24*time.Hour))
+		require.NoError(t, err)
+		locked, err := repo.RecordFailure(models.LockoutScopeIP, "10.0.0.2", now.Add(-2*time.Hour), now.Add(-24*time.Hour))
+		require.NoError(t, err)
+		require.NoError(t, repo.Lock(locked.ID, 1, now.Add(time.Hour)))
+		_, err = repo.RecordFailure(models.LockoutScopeUsername, "admin", now, now.Add(-24*time.Hour))
+		require.NoError(t, err)
+
+		// Records still locked are kept even when their last failure is old
+		deleted, err := repo.DeleteStale(now.Add(-time.Hour), now)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+		_, err = repo.GetByID(stale.ID)
+		assert.ErrorIs(t, err, ErrLoginLockoutNotFound)
+
+		require.NoError(t, repo.DeleteByID(locked.ID))
+		assert.ErrorIs(t, repo.DeleteByID(locked.ID), ErrLoginLockoutNotFound)
+
+		require.NoError(t, repo.Delete(models.LockoutScopeUsername, "admin"))
+		_, err = repo.Get(models.LockoutScopeUsername, "admin")
+		assert.ErrorIs(t, err, ErrLoginLockoutNotFound)
+	})
+}
+
+func TestLoginLockoutRepository_ForgiveFailure(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		repo := NewLoginLockoutRepository(db)
+		now := time.Now()
+
+		_, err := repo.RecordFailure(models.LockoutScopeIP, "10.0.0.1", now, now.Add(-time.Hour))
+		require.NoError(t, err)
+		record, err := repo.RecordFailure(models.LockoutScopeIP, "10.0.0.1", now, now.Add(-time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, 2, record.FailedAttempts)
+
+		require.NoError(t, repo.ForgiveFailure(models.LockoutScopeIP, "10.0.0.1"))
+		record, err = repo.Get(models.LockoutScopeIP, "10.0.0.1")
+		require.NoError(t, err)
+		assert.Equal(t, 1, record.FailedAttempts)
+
+		// A lock in force is kept and the count never goes below zero
+		require.NoError(t, repo.Lock(record.ID, 1, now.Add(time.Hour)))
+		require.NoError(t, repo.ForgiveFailure(models.LockoutScopeIP, "10.0.0.1"))
+		record, err = repo.Get(models.LockoutScopeIP, "10.0.0.1")
+		require.NoError(t, err)
+		assert.Equal(t, 0, record.FailedAttempts)
+		assert.Equal(t, 1, record.Lockouts)
+		assert.True(t, record.IsLocked(now))
+
+		// Addresses without failures have nothing to forgive
+		require.NoError(t, repo.ForgiveFailure(models.LockoutScopeIP, "10.0.0.9"))
+		_, err = repo.Get(models.LockoutScopeIP, "10.0.0.9")
+		assert.ErrorIs(t, err, ErrLoginLockoutNotFound)
+	})
+}
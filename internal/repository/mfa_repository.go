@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ilramdhan/holidayapi/internal/database"
+	"github.com/ilramdhan/holidayapi/internal/metrics"
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+// ErrMFANotFound is returned when a user has not started enrolling in two-factor authentication
+var ErrMFANotFound = errors.New("mfa not found")
+
+// MFARepository interface defines two-factor authentication data access methods
+type MFARepository interface {
+	Get(userID int) (*models.UserMFA, error)
+	SavePending(mfa *models.UserMFA) error
+	Enable(userID int, step int64, recoveryCodeHashes []string) (bool, error)
+	UseStep(userID int, step int64) (bool, error)
+	UseRecoveryCode(userID int, codeHash string) (bool, error)
+	ReplaceRecoveryCodes(userID int, codeHashes []string) error
+	CountRecoveryCodes(userID int) (int, error)
+	Delete(userID int) error
+}
+
+// mfaRepository implements MFARepository
+type mfaRepository struct {
+	db *database.DB
+}
+
+// NewMFARepository creates a new MFA repository
+func NewMFARepository(db *database.DB) MFARepository {
+	return &mfaRepository{db: db}
+}
+
+// Get retrieves the MFA enrollment of a user
+func (r *mfaRepository) Get(userID int) (*models.UserMFA, error) {
+	defer metrics.ObserveDBQuery("mfa", "Get", time.Now())
+
+	query := `
+		SELECT user_id, secret, enabled, last_used_step, enabled_at, created_at
+		FROM user_mfa
+		WHERE user_id = ?
+	`
+
+	mfa := &models.UserMFA{}
+	var enabledAt sql.NullTime
+
+	err := r.db.QueryRow(query, userID).Scan(
+		&mfa.UserID, &mfa.Secret, &mfa.Enabled, &mfa.LastUsedStep, &enabledAt, &mfa.CreatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrMFANotFound
+		}
+		return nil, fmt.Errorf("failed to get mfa: %w", err)
+	}
+
+	if enabledAt.Valid {
+		mfa.EnabledAt = &enabledAt.Time
+	}
+
+	return mfa, nil
+}
+
+// SavePending stores a new, not yet confirmed secret, replacing an earlier unconfirmed one.
+// An enabled enrollment is never replaced; turn MFA off first.
+func (r *mfaRepository) SavePending(mfa *models.UserMFA) error {
+	defer metrics.ObserveDBQuery("mfa", "SavePending", time.Now())
+
+	query := `
+		INSERT INTO user_mfa (user_id, secret, enabled, last_used_step, created_at)
+		VALUES (?, ?, FALSE, 0, ?)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = excluded.secret, last_used_step = 0, created_at = excluded.created_at
+		WHERE user_mfa.enabled = FALSE
+	`
+
+	mfa.Enabled = false
+	mfa.LastUsedStep = 0
+	mfa.EnabledAt = nil
+	mfa.CreatedAt = time.Now()
+
+	result, err := r.db.Exec(query, mfa.UserID, mfa.Secret, mfa.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save mfa: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("mfa is already enabled")
+	}
+
+	return nil
+}
+
+// Enable confirms a pending enrollment with the time step of its first code and stores
+// its recovery codes in a single transaction. It returns false without changing anything
+// when the enrollment is already enabled or the step was already used.
+func (r *mfaRepository) Enable(userID int, step int64, recoveryCodeHashes []string) (bool, error) {
+	defer metrics.ObserveDBQuery("mfa", "Enable", time.Now())
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE user_mfa
+		SET enabled = TRUE, enabled_at = ?, last_used_step = ?
+		WHERE user_id = ? AND enabled = FALSE AND last_used_step < ?
+	`, time.Now(), step, userID, step)
+	if err != nil {
+		return false, fmt.Errorf("failed to enable mfa: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	if err := r.replaceRecoveryCodes(tx, userID, recoveryCodeHashes); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// UseStep records that the code of a time step was accepted. It returns false when that
+// step or a later one was already used, which means the code is being replayed.
+func (r *mfaRepository) UseStep(userID int, step int64) (bool, error) {
+	defer metrics.ObserveDBQuery("mfa", "UseStep", time.Now())
+
+	query := `UPDATE user_mfa SET last_used_step = ? WHERE user_id = ? AND enabled = TRUE AND last_used_step < ?`
+
+	result, err := r.db.Exec(query, step, userID, step)
+	if err != nil {
+		return false, fmt.Errorf("failed to use mfa code: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// UseRecoveryCode marks an unused recovery code as used. It returns false when the
+// user has no unused code with that hash.
+func (r *mfaRepository) UseRecoveryCode(userID int, codeHash string) (bool, error) {
+	defer metrics.ObserveDBQuery("mfa", "UseRecoveryCode", time.Now())
+
+	query := `UPDATE mfa_recovery_codes SET used_at = ? WHERE user_id = ? AND code_hash = ? AND used_at IS NULL`
+
+	result, err := r.db.Exec(query, time.Now(), userID, codeHash)
+	if err != nil {
+		return false, fmt.Errorf("failed to use recovery code: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// ReplaceRecoveryCodes discards every recovery code of a user and stores new ones
+func (r *mfaRepository) ReplaceRecoveryCodes(userID int, codeHashes []string) error {
+	defer metrics.ObserveDBQuery("mfa", "ReplaceRecoveryCodes", time.Now())
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := r.replaceRecoveryCodes(tx, userID, codeHashes); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// replaceRecoveryCodes replaces the recovery codes of a user using the given executor
+func (r *mfaRepository) replaceRecoveryCodes(exec sqlExecutor, userID int, codeHashes []string) error {
+	if _, err := exec.Exec(`DELETE FROM mfa_recovery_codes WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	now := time.Now()
+	for _, codeHash := range codeHashes {
+		if _, err := exec.Exec(`INSERT INTO mfa_recovery_codes (user_id, code_hash, created_at) VALUES (?, ?, ?)`, userID, codeHash, now); err != nil {
+			return fmt.Errorf("failed to create recovery code: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// CountRecoveryCodes returns how many unused recovery codes a user has left
+func (r *mfaRepository) CountRecoveryCodes(userID int) (int, error) {
+	defer metrics.ObserveDBQuery("mfa", "CountRecoveryCodes", time.Now())
+
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM mfa_recovery_codes WHERE user_id = ? AND used_at IS NULL`, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count recovery codes: %w", err)
+	}
+
+	return count, nil
+}
+
+// Delete removes the MFA enrollment and recovery codes of a user
+func (r *mfaRepository) Delete(userID int) error {
+	defer metrics.ObserveDBQuery("mfa", "Delete", time.Now())
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM mfa_recovery_codes WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM user_mfa WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to delete mfa: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
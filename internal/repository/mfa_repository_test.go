@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilramdhan/holidayapi/internal/database"
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+func TestMFARepository_Lifecycle(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		repo := NewMFARepository(db)
+
+		_, err := repo.Get(migratedAdminID)
+		assert.ErrorIs(t, err, ErrMFANotFound)
+
+		require.NoError(t, repo.SavePending(&models.UserMFA{UserID: migratedAdminID, Secret: "FIRST"}))
+		// An unconfirmed secret can be replaced
+		require.NoError(t, repo.SavePending(&models.UserMFA{UserID: migratedAdminID, Secret: "SECOND"}))
+
+		pending, err := repo.Get(migratedAdminID)
+		require.NoError(t, err)
+		assert.Equal(t, "SECOND", pending.Secret)
+		assert.False(t, pending.Enabled)
+		assert.Nil(t, pending.EnabledAt)
+
+		enabled, err := repo.Enable(migratedAdminID, 100, []string{"code-1", "code-2"})
+		require.NoError(t, err)
+		assert.True(t, enabled)
+
+		enabled, err = repo.Enable(migratedAdminID, 101, nil)
+		require.NoError(t, err)
+		assert.False(t, enabled)
+
+		stored, err := repo.Get(migratedAdminID)
+		require.NoError(t, err)
+		assert.True(t, stored.Enabled)
+		assert.NotNil(t, stored.EnabledAt)
+		assert.Equal(t, int64(100), stored.LastUsedStep)
+
+		// An enabled secret is never replaced
+		assert.EqualError(t, repo.SavePending(&models.UserMFA{UserID: migratedAdminID, Secret: "THIRD"}), "mfa is already enabled")
+
+		count, err := repo.CountRecoveryCodes(migratedAdminID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		require.NoError(t, repo.Delete(migratedAdminID))
+		_, err = repo.Get(migratedAdminID)
+		assert.ErrorIs(t, err, ErrMFANotFound)
+		count, err = repo.CountRecoveryCodes(migratedAdminID)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+}
+
+func TestMFARepository_SingleUse(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		repo := NewMFARepository(db)
+
+		require.NoError(t, repo.SavePending(&models.UserMFA{UserID: migratedAdminID, Secret: "SECRET"}))
+		_, err := repo.Enable(migratedAdminID, 100, []string{"code-1"})
+		require.NoError(t, err)
+
+		// A time step is accepted once and earlier steps never again
+		used, err := repo.UseStep(migratedAdminID, 100)
+		require.NoError(t, err)
+		assert.False(t, used)
+		used, err = repo.UseStep(migratedAdminID, 101)
+		require.NoError(t, err)
+		assert.True(t, used)
+		used, err = repo.UseStep(migratedAdminID, 101)
+		require.NoError(t, err)
+		assert.False(t, used)
+
+		used, err = repo.UseRecoveryCode(migratedAdminID, "code-1")
+		require.NoError(t, err)
+		assert.True(t, used)
+		used, err = repo.UseRecoveryCode(migratedAdminID, "code-1")
+		require.NoError(t, err)
+		assert.False(t, used)
+
+		require.NoError(t, repo.ReplaceRecoveryCodes(migratedAdminID, []string{"code-2", "code-3"}))
+		count, err := repo.CountRecoveryCodes(migratedAdminID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+}
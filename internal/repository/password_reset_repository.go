@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ilramdhan/holidayapi/internal/database"
+	"github.com/ilramdhan/holidayapi/internal/metrics"
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+// ErrPasswordResetTokenNotFound is returned when a reset token does not exist, was used or has expired
+var ErrPasswordResetTokenNotFound = errors.New("password reset token not found")
+
+// PasswordResetRepository interface defines password reset token data access methods
+type PasswordResetRepository interface {
+	Create(token *models.PasswordResetToken) error
+	Use(tokenHash string, now time.Time) (*models.PasswordResetToken, error)
+	InvalidateForUser(userID int, now time.Time) (int64, error)
+	DeleteExpired(before time.Time) (int64, error)
+}
+
+// passwordResetRepository implements PasswordResetRepository
+type passwordResetRepository struct {
+	db *database.DB
+}
+
+// NewPasswordResetRepository creates a new password reset repository
+func NewPasswordResetRepository(db *database.DB) PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+// Create stores a newly issued reset token
+func (r *passwordResetRepository) Create(token *models.PasswordResetToken) error {
+	defer metrics.ObserveDBQuery("password_reset", "Create", time.Now())
+
+	query := `
+		INSERT INTO password_reset_tokens (user_id, token_hash, expires_at, created_at)
+		VALUES (?, ?, ?, ?)
+		RETURNING id
+	`
+
+	token.CreatedAt = time.Now()
+
+	err := r.db.QueryRow(query, token.UserID, token.TokenHash, token.ExpiresAt, token.CreatedAt).Scan(&token.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create password reset token: %w", err)
+	}
+
+	return nil
+}
+
+// Use marks an unused, unexpired token as used and returns it. Concurrent requests with
+// the same token cannot both succeed, since only one of them can set used_at.
+func (r *passwordResetRepository) Use(tokenHash string, now time.Time) (*models.PasswordResetToken, error) {
+	defer metrics.ObserveDBQuery("password_reset", "Use", time.Now())
+
+	query := `
+		UPDATE password_reset_tokens
+		SET used_at = ?
+		WHERE token_hash = ? AND used_at IS NULL AND expires_at > ?
+		RETURNING id, user_id, token_hash, expires_at, used_at, created_at
+	`
+
+	token := &models.PasswordResetToken{}
+	var usedAt sql.NullTime
+
+	err := r.db.QueryRow(query, now, tokenHash, now).Scan(
+		&token.ID, &token.UserID, &token.TokenHash, &token.ExpiresAt, &usedAt, &token.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrPasswordResetTokenNotFound
+		}
+		return nil, fmt.Errorf("failed to use password reset token: %w", err)
+	}
+
+	if usedAt.Valid {
+		token.UsedAt = &usedAt.Time
+	}
+
+	return token, nil
+}
+
+// InvalidateForUser marks every unused token of a user as used and returns how many there were
+func (r *passwordResetRepository) InvalidateForUser(userID int, now time.Time) (int64, error) {
+	defer metrics.ObserveDBQuery("password_reset", "InvalidateForUser", time.Now())
+
+	query := `UPDATE password_reset_tokens SET used_at = ? WHERE user_id = ? AND used_at IS NULL`
+
+	result, err := r.db.Exec(query, now, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to invalidate password reset tokens: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// DeleteExpired deletes tokens that expired before a point in time, used or not, and
+// returns how many were deleted
+func (r *passwordResetRepository) DeleteExpired(before time.Time) (int64, error) {
+	defer metrics.ObserveDBQuery("password_reset", "DeleteExpired", time.Now())
+
+	result, err := r.db.Exec(`DELETE FROM password_reset_tokens WHERE expires_at < ?`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired password reset tokens: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilramdhan/holidayapi/internal/database"
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+func TestPasswordResetRepository_Use(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		repo := NewPasswordResetRepository(db)
+		now := time.Now()
+
+		valid := &models.PasswordResetToken{UserID: migratedAdminID, TokenHash: "hash-valid", ExpiresAt: now.Add(time.Hour)}
+		require.NoError(t, repo.Create(valid))
+		assert.NotZero(t, valid.ID)
+		expired := &models.PasswordResetToken{UserID: migratedAdminID, TokenHash: "hash-expired", ExpiresAt: now.Add(-time.Minute)}
+		require.NoError(t, repo.Create(expired))
+
+		used, err := repo.Use("hash-valid", now)
+		require.NoError(t, err)
+		assert.Equal(t, valid.ID, used.ID)
+		assert.Equal(t, migratedAdminID, used.UserID)
+		require.NotNil(t, used.UsedAt)
+
+		// A token works only once, and never after it expired
+		_, err = repo.Use("hash-valid", now)
+		assert.ErrorIs(t, err, ErrPasswordResetTokenNotFound)
+		_, err = repo.Use("hash-expired", now)
+		assert.ErrorIs(t, err, ErrPasswordResetTokenNotFound)
+
+		deleted, err := repo.DeleteExpired(now)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+	})
+}
+
+func TestPasswordResetRepository_InvalidateForUser(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		repo := NewPasswordResetRepository(db)
+		now := time.Now()
+
+		for _, hash := range []string{"hash-1", "hash-2"} {
+			require.NoError(t, repo.Create(&models.PasswordResetToken{UserID: migratedAdminID, TokenHash: hash, ExpiresAt: now.Add(time.Hour)}))
+		}
+
+		invalidated, err := repo.InvalidateForUser(migratedAdminID, now)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), invalidated)
+
+		_, err = repo.Use("hash-2", now)
+		assert.ErrorIs(t, err, ErrPasswordResetTokenNotFound)
+	})
+}
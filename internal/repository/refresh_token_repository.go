@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ilramdhan/holidayapi/internal/database"
+	"github.com/ilramdhan/holidayapi/internal/metrics"
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+// RefreshTokenRepository interface defines refresh token data access methods
+type RefreshTokenRepository interface {
+	Create(token *models.RefreshToken) error
+	GetByHash(tokenHash string) (*models.RefreshToken, error)
+	Rotate(oldID int, next *models.RefreshToken) (bool, error)
+	RevokeFamily(familyID string) error
+	RevokeAllForUser(userID int) (int64, error)
+	DeleteExpired(before time.Time) (int64, error)
+}
+
+// refreshTokenRepository implements RefreshTokenRepository
+type refreshTokenRepository struct {
+	db *database.DB
+}
+
+// NewRefreshTokenRepository creates a new refresh token repository
+func NewRefreshTokenRepository(db *database.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{db: db}
+}
+
+// Create stores a newly issued refresh token
+func (r *refreshTokenRepository) Create(token *models.RefreshToken) error {
+	defer metrics.ObserveDBQuery("refresh_token", "Create", time.Now())
+
+	return r.create(r.db, token)
+}
+
+// create inserts a refresh token using the given executor
+func (r *refreshTokenRepository) create(exec sqlExecutor, token *models.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (user_id, token_hash, family_id, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?)
+		RETURNING id
+	`
+
+	token.CreatedAt = time.Now()
+
+	err := exec.QueryRow(query, token.UserID, token.TokenHash, token.FamilyID, token.ExpiresAt, token.CreatedAt).Scan(&token.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	return nil
+}
+
+// GetByHash retrieves a refresh token by the hash of its value, including revoked tokens
+func (r *refreshTokenRepository) GetByHash(tokenHash string) (*models.RefreshToken, error) {
+	defer metrics.ObserveDBQuery("refresh_token", "GetByHash", time.Now())
+
+	query := `
+		SELECT id, user_id, token_hash, family_id, expires_at, revoked_at, replaced_by, created_at
+		FROM refresh_tokens
+		WHERE token_hash = ?
+	`
+
+	token := &models.RefreshToken{}
+	var revokedAt sql.NullTime
+	var replacedBy sql.NullInt64
+
+	err := r.db.QueryRow(query, tokenHash).Scan(
+		&token.ID, &token.UserID, &token.TokenHash, &token.FamilyID,
+		&token.ExpiresAt, &revokedAt, &replacedBy, &token.CreatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("refresh token not found")
+		}
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+	if replacedBy.Valid {
+		id := int(replacedBy.Int64)
+		token.ReplacedBy = &id
+	}
+
+	return token, nil
+}
+
+// Rotate revokes the old token and stores its replacement in a single transaction.
+// It returns false without storing anything when the old token was already revoked,
+// which means the same refresh token was presented twice.
+func (r *refreshTokenRepository) Rotate(oldID int, next *models.RefreshToken) (bool, error) {
+	defer metrics.ObserveDBQuery("refresh_token", "Rotate", time.Now())
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := r.create(tx, next); err != nil {
+		return false, err
+	}
+
+	result, err := tx.Exec(`
+		UPDATE refresh_tokens
+		SET revoked_at = ?, replaced_by = ?
+		WHERE id = ? AND revoked_at IS NULL
+	`, time.Now(), next.ID, oldID)
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// RevokeFamily revokes every token descended from the same login
+func (r *refreshTokenRepository) RevokeFamily(familyID string) error {
+	defer metrics.ObserveDBQuery("refresh_token", "RevokeFamily", time.Now())
+
+	query := `UPDATE refresh_tokens SET revoked_at = ? WHERE family_id = ? AND revoked_at IS NULL`
+
+	if _, err := r.db.Exec(query, time.Now(), familyID); err != nil {
+		return fmt.Errorf("failed to revoke token family: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeAllForUser revokes every active token of a user and returns how many were revoked
+func (r *refreshTokenRepository) RevokeAllForUser(userID int) (int64, error) {
+	defer metrics.ObserveDBQuery("refresh_token", "RevokeAllForUser", time.Now())
+
+	query := `UPDATE refresh_tokens SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL`
+
+	result, err := r.db.Exec(query, time.Now(), userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke user tokens: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// DeleteExpired deletes tokens that expired before a point in time and returns how many
+// were deleted. Revoked tokens are kept until they expire so reuse can still be detected.
+func (r *refreshTokenRepository) DeleteExpired(before time.Time) (int64, error) {
+	defer metrics.ObserveDBQuery("refresh_token", "DeleteExpired", time.Now())
+
+	result, err := r.db.Exec(`DELETE FROM refresh_tokens WHERE expires_at < ?`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired refresh tokens: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
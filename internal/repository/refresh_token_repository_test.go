@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilramdhan/holidayapi/internal/database"
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+// migratedAdminID is the super admin inserted by the users migration
+const migratedAdminID = 1
+
+func TestRefreshTokenRepository_Rotate(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		repo := NewRefreshTokenRepository(db)
+		expiresAt := time.Now().Add(time.Hour)
+
+		first := &models.RefreshToken{UserID: migratedAdminID, TokenHash: "hash-1", FamilyID: "family", ExpiresAt: expiresAt}
+		require.NoError(t, repo.Create(first))
+		assert.NotZero(t, first.ID)
+
+		second := &models.RefreshToken{UserID: migratedAdminID, TokenHash: "hash-2", FamilyID: "family", ExpiresAt: expiresAt}
+		rotated, err := repo.Rotate(first.ID, second)
+		require.NoError(t, err)
+		assert.True(t, rotated)
+
+		stored, err := repo.GetByHash("hash-1")
+		require.NoError(t, err)
+		require.NotNil(t, stored.RevokedAt)
+		require.NotNil(t, stored.ReplacedBy)
+		assert.Equal(t, second.ID, *stored.ReplacedBy)
+
+		// Rotating the same token twice is refused and stores nothing
+		rotated, err = repo.Rotate(first.ID, &models.RefreshToken{UserID: migratedAdminID, TokenHash: "hash-3", FamilyID: "family", ExpiresAt: expiresAt})
+		require.NoError(t, err)
+		assert.False(t, rotated)
+		_, err = repo.GetByHash("hash-3")
+		assert.EqualError(t, err, "refresh token not found")
+
+		revoked, err := repo.RevokeAllForUser(migratedAdminID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), revoked)
+	})
+}
+
+func TestRefreshTokenRepository_DeleteExpired(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		repo := NewRefreshTokenRepository(db)
+
+		expired := &models.RefreshToken{UserID: migratedAdminID, TokenHash: "hash-expired", FamilyID: "family", ExpiresAt: time.Now().Add(-time.Hour)}
+		require.NoError(t, repo.Create(expired))
+		valid := &models.RefreshToken{UserID: migratedAdminID, TokenHash: "hash-valid", FamilyID: "family", ExpiresAt: time.Now().Add(time.Hour)}
+		require.NoError(t, repo.Create(valid))
+
+		deleted, err := repo.DeleteExpired(time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		_, err = repo.GetByHash("hash-expired")
+		assert.EqualError(t, err, "refresh token not found")
+		_, err = repo.GetByHash("hash-valid")
+		assert.NoError(t, err)
+	})
+}
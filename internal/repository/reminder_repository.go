@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ilramdhan/holidayapi/internal/database"
+	"github.com/ilramdhan/holidayapi/internal/metrics"
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+// ReminderRepository interface defines reminder rule data access methods
+type ReminderRepository interface {
+	Create(rule *models.ReminderRule) error
+	GetByID(id int) (*models.ReminderRule, error)
+	GetAll() ([]models.ReminderRule, error)
+	Update(rule *models.ReminderRule) error
+	Delete(id int) error
+	MarkRun(id int, day time.Time) error
+}
+
+// reminderRepository implements ReminderRepository
+type reminderRepository struct {
+	db *database.DB
+}
+
+// NewReminderRepository creates a new reminder rule repository
+func NewReminderRepository(db *database.DB) ReminderRepository {
+	return &reminderRepository{db: db}
+}
+
+// reminderColumns lists the columns read by scanReminderRule
+const reminderColumns = `id, name, kind, days_before, send_weekday, holiday_types, province, recipients,
+	is_active, last_run_on, created_by, created_at, updated_at`
+
+// Create stores a new reminder rule
+func (r *reminderRepository) Create(rule *models.ReminderRule) error {
+	defer metrics.ObserveDBQuery("reminder", "Create", time.Now())
+
+	query := `
+		INSERT INTO reminder_rules (name, kind, days_before, send_weekday, holiday_types, province, recipients,
+			is_active, created_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING id
+	`
+
+	now := time.Now()
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+
+	err := r.db.QueryRow(query, rule.Name, rule.Kind, rule.DaysBefore, int(rule.SendWeekday),
+		models.JoinHolidayTypes(rule.HolidayTypes), rule.Province, models.JoinList(rule.Recipients),
+		rule.IsActive, rule.CreatedBy, rule.CreatedAt, rule.UpdatedAt).Scan(&rule.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create reminder: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a reminder rule by ID
+func (r *reminderRepository) GetByID(id int) (*models.ReminderRule, error) {
+	defer metrics.ObserveDBQuery("reminder", "GetByID", time.Now())
+
+	query := `SELECT ` + reminderColumns + ` FROM reminder_rules WHERE id = ?`
+
+	rule, err := scanReminderRule(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("reminder not found")
+		}
+		return nil, fmt.Errorf("failed to get reminder: %w", err)
+	}
+
+	return rule, nil
+}
+
+// GetAll retrieves all reminder rules, oldest first
+func (r *reminderRepository) GetAll() ([]models.ReminderRule, error) {
+	defer metrics.ObserveDBQuery("reminder", "GetAll", time.Now())
+
+	query := `SELECT ` + reminderColumns + ` FROM reminder_rules ORDER BY id ASC`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reminders: %w", err)
+	}
+	defer rows.Close()
+
+	rules := []models.ReminderRule{}
+	for rows.Next() {
+		rule, err := scanReminderRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reminder: %w", err)
+		}
+		rules = append(rules, *rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate reminders: %w", err)
+	}
+
+	return rules, nil
+}
+
+// Update saves every editable field of a reminder rule
+func (r *reminderRepository) Update(rule *models.ReminderRule) error {
+	defer metrics.ObserveDBQuery("reminder", "Update", time.Now())
+
+	query := `
+		UPDATE reminder_rules
+		SET name = ?, kind = ?, days_before = ?, send_weekday = ?, holiday_types = ?, province = ?,
+			recipients = ?, is_active = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	rule.UpdatedAt = time.Now()
+
+	result, err := r.db.Exec(query, rule.Name, rule.Kind, rule.DaysBefore, int(rule.SendWeekday),
+		models.JoinHolidayTypes(rule.HolidayTypes), rule.Province, models.JoinList(rule.Recipients),
+		rule.IsActive, rule.UpdatedAt, rule.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update reminder: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("reminder not found")
+	}
+
+	return nil
+}
+
+// Delete removes a reminder rule
+func (r *reminderRepository) Delete(id int) error {
+	defer metrics.ObserveDBQuery("reminder", "Delete", time.Now())
+
+	result, err := r.db.Exec(`DELETE FROM reminder_rules WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete reminder: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("reminder not found")
+	}
+
+	return nil
+}
+
+// MarkRun records the day a reminder rule was last handled
+func (r *reminderRepository) MarkRun(id int, day time.Time) error {
+	defer metrics.ObserveDBQuery("reminder", "MarkRun", time.Now())
+
+	if _, err := r.db.Exec(`UPDATE reminder_rules SET last_run_on = ? WHERE id = ?`, day, id); err != nil {
+		return fmt.Errorf("failed to mark reminder run: %w", err)
+	}
+
+	return nil
+}
+
+// scanReminderRule scans a single reminder rule row selected with reminderColumns
+func scanReminderRule(row rowScanner) (*models.ReminderRule, error) {
+	rule := &models.ReminderRule{}
+	var sendWeekday int
+	var holidayTypes, recipients string
+	var province sql.NullString
+	var lastRunOn sql.NullTime
+	var createdBy sql.NullInt64
+
+	err := row.Scan(
+		&rule.ID, &rule.Name, &rule.Kind, &rule.DaysBefore, &sendWeekday, &holidayTypes, &province, &recipients,
+		&rule.IsActive, &lastRunOn, &createdBy, &rule.CreatedAt, &rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.SendWeekday = time.Weekday(sendWeekday)
+	rule.HolidayTypes = models.SplitHolidayTypes(holidayTypes)
+	rule.Recipients = models.SplitList(recipients)
+	if province.Valid {
+		rule.Province = &province.String
+	}
+	if lastRunOn.Valid {
+		rule.LastRunOn = &lastRunOn.Time
+	}
+	if createdBy.Valid {
+		id := int(createdBy.Int64)
+		rule.CreatedBy = &id
+	}
+
+	return rule, nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilramdhan/holidayapi/internal/database"
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+func TestReminderRepository_RulesAndLists(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		rules := NewReminderRepository(db)
+		lists := NewDistributionListRepository(db)
+		createdBy := migratedAdminID
+		bali := "ID-BA"
+
+		staff := &models.DistributionList{Name: "all-staff", Members: []string{"hr@example.com", "ops@example.com"}}
+		require.NoError(t, lists.Create(staff))
+		assert.Error(t, lists.Create(&models.DistributionList{Name: "all-staff", Members: []string{"x@example.com"}}), "names are unique")
+
+		got, err := lists.GetByName("all-staff")
+		require.NoError(t, err)
+		assert.Equal(t, staff.Members, got.Members)
+		_, err = lists.GetByName("nobody")
+		assert.EqualError(t, err, `distribution list "nobody" not found`)
+
+		rule := &models.ReminderRule{
+			Name:         "Cuti bersama next week",
+			Kind:         models.ReminderNextWeek,
+			SendWeekday:  time.Friday,
+			HolidayTypes: []models.HolidayType{models.CollectiveLeave},
+			Province:     &bali,
+			Recipients:   []string{"all-staff", "ceo@example.com"},
+			IsActive:     true,
+			CreatedBy:    &createdBy,
+		}
+		require.NoError(t, rules.Create(rule))
+
+		stored, err := rules.GetByID(rule.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ReminderNextWeek, stored.Kind)
+		assert.Equal(t, time.Friday, stored.SendWeekday)
+		assert.Equal(t, rule.HolidayTypes, stored.HolidayTypes)
+		assert.Equal(t, rule.Recipients, stored.Recipients)
+		assert.Equal(t, "ID-BA", *stored.Province)
+		assert.Nil(t, stored.LastRunOn)
+
+		day := time.Date(2025, 3, 28, 0, 0, 0, 0, time.UTC)
+		require.NoError(t, rules.MarkRun(rule.ID, day))
+
+		stored.Kind = models.ReminderDaysBefore
+		stored.DaysBefore = 7
+		stored.HolidayTypes = nil
+		stored.Province = nil
+		require.NoError(t, rules.Update(stored))
+
+		all, err := rules.GetAll()
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+		assert.Equal(t, 7, all[0].DaysBefore)
+		assert.Empty(t, all[0].HolidayTypes)
+		assert.Nil(t, all[0].Province)
+		require.NotNil(t, all[0].LastRunOn)
+		assert.Equal(t, "2025-03-28", all[0].LastRunOn.Format("2006-01-02"))
+
+		require.NoError(t, rules.Delete(rule.ID))
+		assert.EqualError(t, rules.Delete(rule.ID), "reminder not found")
+		require.NoError(t, lists.Delete(staff.ID))
+
+		remaining, err := lists.GetAll()
+		require.NoError(t, err)
+		assert.Empty(t, remaining)
+	})
+}
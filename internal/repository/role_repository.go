@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ilramdhan/holidayapi/internal/database"
+	"github.com/ilramdhan/holidayapi/internal/metrics"
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+// RoleRepository interface defines role data access methods
+type RoleRepository interface {
+	Create(role *models.Role) error
+	GetByID(id int) (*models.Role, error)
+	GetByName(name models.UserRole) (*models.Role, error)
+	GetAll() ([]models.Role, error)
+	Update(role *models.Role) error
+	Delete(id int) error
+	CountUsers(name models.UserRole) (int, error)
+}
+
+// roleRepository implements RoleRepository
+type roleRepository struct {
+	db *database.DB
+}
+
+// NewRoleRepository creates a new role repository
+func NewRoleRepository(db *database.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+// roleColumns lists the columns read by scanRole
+const roleColumns = `id, name, description, permissions, is_system, created_at, updated_at`
+
+// Create stores a new role
+func (r *roleRepository) Create(role *models.Role) error {
+	defer metrics.ObserveDBQuery("role", "Create", time.Now())
+
+	query := `
+		INSERT INTO roles (name, description, permissions, is_system, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		RETURNING id
+	`
+
+	now := time.Now()
+	role.CreatedAt = now
+	role.UpdatedAt = now
+
+	err := r.db.QueryRow(query, role.Name, role.Description, models.JoinPermissions(role.Permissions),
+		role.IsSystem, role.CreatedAt, role.UpdatedAt).Scan(&role.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create role: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a role by ID
+func (r *roleRepository) GetByID(id int) (*models.Role, error) {
+	defer metrics.ObserveDBQuery("role", "GetByID", time.Now())
+
+	query := `SELECT ` + roleColumns + ` FROM roles WHERE id = ?`
+
+	role, err := scanRole(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("role not found")
+		}
+		return nil, fmt.Errorf("failed to get role: %w", err)
+	}
+
+	return role, nil
+}
+
+// GetByName retrieves a role by name
+func (r *roleRepository) GetByName(name models.UserRole) (*models.Role, error) {
+	defer metrics.ObserveDBQuery("role", "GetByName", time.Now())
+
+	query := `SELECT ` + roleColumns + ` FROM roles WHERE name = ?`
+
+	role, err := scanRole(r.db.QueryRow(query, name))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("role not found")
+		}
+		return nil, fmt.Errorf("failed to get role: %w", err)
+	}
+
+	return role, nil
+}
+
+// GetAll retrieves all roles, built-in roles first
+func (r *roleRepository) GetAll() ([]models.Role, error) {
+	defer metrics.ObserveDBQuery("role", "GetAll", time.Now())
+
+	query := `SELECT ` + roleColumns + ` FROM roles ORDER BY id ASC`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get roles: %w", err)
+	}
+	defer rows.Close()
+
+	roles := []models.Role{}
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan role: %w", err)
+		}
+		roles = append(roles, *role)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate roles: %w", err)
+	}
+
+	return roles, nil
+}
+
+// Update saves the description and permissions of a role
+func (r *roleRepository) Update(role *models.Role) error {
+	defer metrics.ObserveDBQuery("role", "Update", time.Now())
+
+	query := `UPDATE roles SET description = ?, permissions = ?, updated_at = ? WHERE id = ?`
+
+	role.UpdatedAt = time.Now()
+
+	result, err := r.db.Exec(query, role.Description, models.JoinPermissions(role.Permissions), role.UpdatedAt, role.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update role: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("role not found")
+	}
+
+	return nil
+}
+
+// Delete removes a role
+func (r *roleRepository) Delete(id int) error {
+	defer metrics.ObserveDBQuery("role", "Delete", time.Now())
+
+	result, err := r.db.Exec(`DELETE FROM roles WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("role not found")
+	}
+
+	return nil
+}
+
+// CountUsers counts the users assigned to a role, active or not
+func (r *roleRepository) CountUsers(name models.UserRole) (int, error) {
+	defer metrics.ObserveDBQuery("role", "CountUsers", time.Now())
+
+	var count int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM users WHERE role = ?`, name).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count role users: %w", err)
+	}
+
+	return count, nil
+}
+
+// scanRole reads one role row selected with roleColumns
+func scanRole(row rowScanner) (*models.Role, error) {
+	role := &models.Role{}
+	var description sql.NullString
+	var permissions string
+
+	err := row.Scan(&role.ID, &role.Name, &description, &permissions, &role.IsSystem, &role.CreatedAt, &role.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	role.Description = description.String
+	role.Permissions = models.SplitPermissions(permissions)
+
+	return role, nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilramdhan/holidayapi/internal/database"
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+func TestRoleRepository_BuiltInRoles(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		repo := NewRoleRepository(db)
+
+		superAdmin, err := repo.GetByName(models.SuperAdminRole)
+		require.NoError(t, err)
+		assert.True(t, superAdmin.IsSystem)
+		assert.ElementsMatch(t, models.AllPermissions, superAdmin.Permissions)
+
+		admin, err := repo.GetByName(models.AdminRole)
+		require.NoError(t, err)
+		assert.True(t, admin.HasPermission(models.PermissionHolidayDelete))
+		assert.False(t, admin.HasPermission(models.PermissionUserManage))
+
+		count, err := repo.CountUsers(models.SuperAdminRole)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+}
+
+func TestRoleRepository_CustomRole(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		repo := NewRoleRepository(db)
+		users := NewUserRepository(db)
+
+		role := &models.Role{Name: "auditor", Permissions: []models.Permission{models.PermissionAuditRead}}
+		require.NoError(t, repo.Create(role))
+		assert.NotZero(t, role.ID)
+
+		// Users are no longer limited to the built-in roles
+		require.NoError(t, users.Create(&models.User{Username: "auditor", Email: "auditor@example.com", Password: "Auditor123!", Role: "auditor"}))
+		count, err := repo.CountUsers("auditor")
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		role.Description = "Reads audit logs and holidays"
+		role.Permissions = []models.Permission{models.PermissionAuditRead, models.PermissionHolidayRead}
+		require.NoError(t, repo.Update(role))
+
+		found, err := repo.GetByID(role.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Reads audit logs and holidays", found.Description)
+		assert.Equal(t, role.Permissions, found.Permissions)
+
+		require.NoError(t, repo.Delete(role.ID))
+		_, err = repo.GetByName("auditor")
+		assert.EqualError(t, err, "role not found")
+	})
+}
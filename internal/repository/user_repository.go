@@ -7,6 +7,8 @@ import (
 
 	"golang.org/x/crypto/bcrypt"
 
+	"github.com/ilramdhan/holidayapi/internal/database"
+	"github.com/ilramdhan/holidayapi/internal/metrics"
 	"github.com/ilramdhan/holidayapi/internal/models"
 )
 
@@ -14,29 +16,38 @@ import (
 type UserRepository interface {
 	Create(user *models.User) error
 	GetByID(id int) (*models.User, error)
+	GetByIDIncludingInactive(id int) (*models.User, error)
 	GetByUsername(username string) (*models.User, error)
 	GetByEmail(email string) (*models.User, error)
 	Update(id int, user *models.User) error
 	Delete(id int) error
 	UpdateLastLogin(id int) error
 	GetAll() ([]models.User, error)
+	GetAllIncludingInactive() ([]models.User, error)
+	CountActiveByRole(role models.UserRole) (int, error)
 	HashPassword(password string) (string, error)
 	CheckPassword(hashedPassword, password string) error
 	ChangePassword(userID int, newPassword string) error
+	SetTemporaryPassword(userID int, password string) error
 }
 
+// userColumns lists the columns read by scanUser, in order
+const userColumns = "id, username, email, password, role, is_active, must_change_password, created_at, updated_at, last_login"
+
 // userRepository implements UserRepository
 type userRepository struct {
-	db *sql.DB
+	db *database.DB
 }
 
 // NewUserRepository creates a new user repository
-func NewUserRepository(db *sql.DB) UserRepository {
+func NewUserRepository(db *database.DB) UserRepository {
 	return &userRepository{db: db}
 }
 
 // Create creates a new user
 func (r *userRepository) Create(user *models.User) error {
+	defer metrics.ObserveDBQuery("user", "Create", time.Now())
+
 	// Hash password
 	hashedPassword, err := r.HashPassword(user.Password)
 	if err != nil {
@@ -46,6 +57,7 @@ func (r *userRepository) Create(user *models.User) error {
 	query := `
 		INSERT INTO users (username, email, password, role, is_active, created_at, updated_at)
 		VALUES (?, ?, ?, ?, ?, ?, ?)
+		RETURNING id
 	`
 
 	now := time.Now()
@@ -53,37 +65,22 @@ func (r *userRepository) Create(user *models.User) error {
 	user.UpdatedAt = now
 	user.IsActive = true
 
-	result, err := r.db.Exec(query, user.Username, user.Email, hashedPassword,
-		user.Role, user.IsActive, user.CreatedAt, user.UpdatedAt)
+	err = r.db.QueryRow(query, user.Username, user.Email, hashedPassword,
+		user.Role, user.IsActive, user.CreatedAt, user.UpdatedAt).Scan(&user.ID)
 	if err != nil {
 		return fmt.Errorf("failed to create user: %w", err)
 	}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("failed to get last insert id: %w", err)
-	}
-
-	user.ID = int(id)
 	return nil
 }
 
-// GetByID retrieves a user by ID
+// GetByID retrieves an active user by ID
 func (r *userRepository) GetByID(id int) (*models.User, error) {
-	query := `
-		SELECT id, username, email, password, role, is_active, created_at, updated_at, last_login
-		FROM users
-		WHERE id = ? AND is_active = TRUE
-	`
-
-	user := &models.User{}
-	var lastLogin sql.NullTime
+	defer metrics.ObserveDBQuery("user", "GetByID", time.Now())
 
-	err := r.db.QueryRow(query, id).Scan(
-		&user.ID, &user.Username, &user.Email, &user.Password,
-		&user.Role, &user.IsActive, &user.CreatedAt, &user.UpdatedAt, &lastLogin,
-	)
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = ? AND is_active = TRUE`
 
+	user, err := scanUser(r.db.QueryRow(query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("user not found")
@@ -91,29 +88,33 @@ func (r *userRepository) GetByID(id int) (*models.User, error) {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	if lastLogin.Valid {
-		user.LastLogin = &lastLogin.Time
+	return user, nil
+}
+
+// GetByIDIncludingInactive retrieves a user by ID, whether or not the account is active
+func (r *userRepository) GetByIDIncludingInactive(id int) (*models.User, error) {
+	defer metrics.ObserveDBQuery("user", "GetByIDIncludingInactive", time.Now())
+
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = ?`
+
+	user, err := scanUser(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
 	return user, nil
 }
 
-// GetByUsername retrieves a user by username
+// GetByUsername retrieves an active user by username
 func (r *userRepository) GetByUsername(username string) (*models.User, error) {
-	query := `
-		SELECT id, username, email, password, role, is_active, created_at, updated_at, last_login
-		FROM users
-		WHERE username = ? AND is_active = TRUE
-	`
+	defer metrics.ObserveDBQuery("user", "GetByUsername", time.Now())
 
-	user := &models.User{}
-	var lastLogin sql.NullTime
-
-	err := r.db.QueryRow(query, username).Scan(
-		&user.ID, &user.Username, &user.Email, &user.Password,
-		&user.Role, &user.IsActive, &user.CreatedAt, &user.UpdatedAt, &lastLogin,
-	)
+	query := `SELECT ` + userColumns + ` FROM users WHERE username = ? AND is_active = TRUE`
 
+	user, err := scanUser(r.db.QueryRow(query, username))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("user not found")
@@ -121,29 +122,16 @@ func (r *userRepository) GetByUsername(username string) (*models.User, error) {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	if lastLogin.Valid {
-		user.LastLogin = &lastLogin.Time
-	}
-
 	return user, nil
 }
 
-// GetByEmail retrieves a user by email
+// GetByEmail retrieves an active user by email
 func (r *userRepository) GetByEmail(email string) (*models.User, error) {
-	query := `
-		SELECT id, username, email, password, role, is_active, created_at, updated_at, last_login
-		FROM users
-		WHERE email = ? AND is_active = TRUE
-	`
+	defer metrics.ObserveDBQuery("user", "GetByEmail", time.Now())
 
-	user := &models.User{}
-	var lastLogin sql.NullTime
-
-	err := r.db.QueryRow(query, email).Scan(
-		&user.ID, &user.Username, &user.Email, &user.Password,
-		&user.Role, &user.IsActive, &user.CreatedAt, &user.UpdatedAt, &lastLogin,
-	)
+	query := `SELECT ` + userColumns + ` FROM users WHERE email = ? AND is_active = TRUE`
 
+	user, err := scanUser(r.db.QueryRow(query, email))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("user not found")
@@ -151,15 +139,13 @@ func (r *userRepository) GetByEmail(email string) (*models.User, error) {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	if lastLogin.Valid {
-		user.LastLogin = &lastLogin.Time
-	}
-
 	return user, nil
 }
 
 // Update updates a user
 func (r *userRepository) Update(id int, user *models.User) error {
+	defer metrics.ObserveDBQuery("user", "Update", time.Now())
+
 	query := `
 		UPDATE users 
 		SET email = ?, role = ?, is_active = ?, updated_at = ?
@@ -187,6 +173,8 @@ func (r *userRepository) Update(id int, user *models.User) error {
 
 // Delete soft deletes a user
 func (r *userRepository) Delete(id int) error {
+	defer metrics.ObserveDBQuery("user", "Delete", time.Now())
+
 	query := `UPDATE users SET is_active = FALSE, updated_at = ? WHERE id = ?`
 
 	result, err := r.db.Exec(query, time.Now(), id)
@@ -208,6 +196,8 @@ func (r *userRepository) Delete(id int) error {
 
 // UpdateLastLogin updates user's last login time
 func (r *userRepository) UpdateLastLogin(id int) error {
+	defer metrics.ObserveDBQuery("user", "UpdateLastLogin", time.Now())
+
 	query := `UPDATE users SET last_login = ? WHERE id = ?`
 
 	_, err := r.db.Exec(query, time.Now(), id)
@@ -218,15 +208,22 @@ func (r *userRepository) UpdateLastLogin(id int) error {
 	return nil
 }
 
-// GetAll retrieves all users
+// GetAll retrieves all active users
 func (r *userRepository) GetAll() ([]models.User, error) {
-	query := `
-		SELECT id, username, email, role, is_active, created_at, updated_at, last_login
-		FROM users
-		WHERE is_active = TRUE
-		ORDER BY created_at DESC
-	`
+	defer metrics.ObserveDBQuery("user", "GetAll", time.Now())
+
+	return r.queryUsers(`SELECT ` + userColumns + ` FROM users WHERE is_active = TRUE ORDER BY created_at DESC`)
+}
 
+// GetAllIncludingInactive retrieves all users, including deactivated ones
+func (r *userRepository) GetAllIncludingInactive() ([]models.User, error) {
+	defer metrics.ObserveDBQuery("user", "GetAllIncludingInactive", time.Now())
+
+	return r.queryUsers(`SELECT ` + userColumns + ` FROM users ORDER BY created_at DESC`)
+}
+
+// queryUsers runs a query selecting userColumns and scans every row
+func (r *userRepository) queryUsers(query string) ([]models.User, error) {
 	rows, err := r.db.Query(query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query users: %w", err)
@@ -235,25 +232,26 @@ func (r *userRepository) GetAll() ([]models.User, error) {
 
 	var users []models.User
 	for rows.Next() {
-		var user models.User
-		var lastLogin sql.NullTime
-
-		err := rows.Scan(
-			&user.ID, &user.Username, &user.Email, &user.Role,
-			&user.IsActive, &user.CreatedAt, &user.UpdatedAt, &lastLogin,
-		)
+		user, err := scanUser(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan user: %w", err)
 		}
+		users = append(users, *user)
+	}
 
-		if lastLogin.Valid {
-			user.LastLogin = &lastLogin.Time
-		}
+	return users, nil
+}
 
-		users = append(users, user)
+// CountActiveByRole counts the active users with a role
+func (r *userRepository) CountActiveByRole(role models.UserRole) (int, error) {
+	defer metrics.ObserveDBQuery("user", "CountActiveByRole", time.Now())
+
+	var count int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM users WHERE role = ? AND is_active = TRUE`, role).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", err)
 	}
 
-	return users, nil
+	return count, nil
 }
 
 // HashPassword hashes a password using bcrypt
@@ -270,16 +268,30 @@ func (r *userRepository) CheckPassword(hashedPassword, password string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 }
 
-// ChangePassword changes user's password
+// ChangePassword changes user's password, which also ends a required password change
 func (r *userRepository) ChangePassword(userID int, newPassword string) error {
-	hashedPassword, err := r.HashPassword(newPassword)
+	defer metrics.ObserveDBQuery("user", "ChangePassword", time.Now())
+
+	return r.setPassword(userID, newPassword, false)
+}
+
+// SetTemporaryPassword sets a password the user must replace the next time they log in
+func (r *userRepository) SetTemporaryPassword(userID int, password string) error {
+	defer metrics.ObserveDBQuery("user", "SetTemporaryPassword", time.Now())
+
+	return r.setPassword(userID, password, true)
+}
+
+// setPassword hashes and stores a password along with whether it must be changed
+func (r *userRepository) setPassword(userID int, password string, mustChange bool) error {
+	hashedPassword, err := r.HashPassword(password)
 	if err != nil {
 		return fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	query := `UPDATE users SET password = ?, updated_at = ? WHERE id = ?`
+	query := `UPDATE users SET password = ?, must_change_password = ?, updated_at = ? WHERE id = ?`
 
-	result, err := r.db.Exec(query, hashedPassword, time.Now(), userID)
+	result, err := r.db.Exec(query, hashedPassword, mustChange, time.Now(), userID)
 	if err != nil {
 		return fmt.Errorf("failed to change password: %w", err)
 	}
@@ -295,3 +307,23 @@ func (r *userRepository) ChangePassword(userID int, newPassword string) error {
 
 	return nil
 }
+
+// scanUser reads one user row selected with userColumns
+func scanUser(row rowScanner) (*models.User, error) {
+	user := &models.User{}
+	var lastLogin sql.NullTime
+
+	err := row.Scan(
+		&user.ID, &user.Username, &user.Email, &user.Password, &user.Role,
+		&user.IsActive, &user.MustChangePassword, &user.CreatedAt, &user.UpdatedAt, &lastLogin,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastLogin.Valid {
+		user.LastLogin = &lastLogin.Time
+	}
+
+	return user, nil
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilramdhan/holidayapi/internal/database"
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+func TestUserRepository_DeactivatedUsers(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		repo := NewUserRepository(db)
+
+		user := &models.User{Username: "editor", Email: "editor@example.com", Password: "Editor123!", Role: models.SuperAdminRole}
+		require.NoError(t, repo.Create(user))
+
+		count, err := repo.CountActiveByRole(models.SuperAdminRole)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		require.NoError(t, repo.Delete(user.ID))
+
+		// A deactivated user is only found when asked for explicitly
+		_, err = repo.GetByID(user.ID)
+		assert.EqualError(t, err, "user not found")
+		found, err := repo.GetByIDIncludingInactive(user.ID)
+		require.NoError(t, err)
+		assert.False(t, found.IsActive)
+
+		active, err := repo.GetAll()
+		require.NoError(t, err)
+		assert.Len(t, active, 1)
+		all, err := repo.GetAllIncludingInactive()
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+		for _, listed := range all {
+			assert.Equal(t, listed.ID != user.ID, listed.IsActive)
+		}
+
+		count, err = repo.CountActiveByRole(models.SuperAdminRole)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+}
+
+func TestUserRepository_SetTemporaryPassword(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		repo := NewUserRepository(db)
+
+		require.NoError(t, repo.SetTemporaryPassword(migratedAdminID, "TempPass123!"))
+		user, err := repo.GetByID(migratedAdminID)
+		require.NoError(t, err)
+		assert.True(t, user.MustChangePassword)
+		assert.NoError(t, repo.CheckPassword(user.Password, "TempPass123!"))
+
+		// Choosing a new password ends the required change
+		require.NoError(t, repo.ChangePassword(migratedAdminID, "NewPass123!"))
+		user, err = repo.GetByID(migratedAdminID)
+		require.NoError(t, err)
+		assert.False(t, user.MustChangePassword)
+
+		assert.EqualError(t, repo.SetTemporaryPassword(9999, "TempPass123!"), "user not found")
+	})
+}
@@ -0,0 +1,353 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ilramdhan/holidayapi/internal/database"
+	"github.com/ilramdhan/holidayapi/internal/metrics"
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+// WebhookRepository interface defines webhook subscription and delivery data access methods
+type WebhookRepository interface {
+	Create(subscription *models.WebhookSubscription) error
+	GetByID(id int) (*models.WebhookSubscription, error)
+	GetAll() ([]models.WebhookSubscription, error)
+	Update(subscription *models.WebhookSubscription) error
+	Delete(id int) error
+	GetDeliveries(subscriptionID, limit, offset int) ([]models.WebhookDelivery, int, error)
+	GetDueDeliveries(now time.Time, limit int) ([]models.WebhookDelivery, error)
+	UpdateDelivery(delivery *models.WebhookDelivery) error
+}
+
+// webhookRepository implements WebhookRepository
+type webhookRepository struct {
+	db *database.DB
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(db *database.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+// webhookColumns lists the columns read by scanWebhook
+const webhookColumns = `id, url, events, secret, is_active, created_by, created_at, updated_at`
+
+// webhookDeliveryColumns lists the columns read by scanWebhookDelivery
+const webhookDeliveryColumns = `id, subscription_id, change_sequence, event_type, payload, status, attempts,
+	next_attempt_at, last_attempt_at, response_status, last_error, delivered_at, created_at`
+
+// Create stores a new webhook subscription
+func (r *webhookRepository) Create(subscription *models.WebhookSubscription) error {
+	defer metrics.ObserveDBQuery("webhook", "Create", time.Now())
+
+	query := `
+		INSERT INTO webhook_subscriptions (url, events, secret, is_active, created_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		RETURNING id
+	`
+
+	now := time.Now()
+	subscription.CreatedAt = now
+	subscription.UpdatedAt = now
+
+	err := r.db.QueryRow(query, subscription.URL, models.JoinWebhookEvents(subscription.Events), subscription.Secret,
+		subscription.IsActive, subscription.CreatedBy, subscription.CreatedAt, subscription.UpdatedAt).Scan(&subscription.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a webhook subscription by ID
+func (r *webhookRepository) GetByID(id int) (*models.WebhookSubscription, error) {
+	defer metrics.ObserveDBQuery("webhook", "GetByID", time.Now())
+
+	query := `SELECT ` + webhookColumns + ` FROM webhook_subscriptions WHERE id = ?`
+
+	subscription, err := scanWebhook(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("webhook not found")
+		}
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return subscription, nil
+}
+
+// GetAll retrieves all webhook subscriptions, oldest first
+func (r *webhookRepository) GetAll() ([]models.WebhookSubscription, error) {
+	defer metrics.ObserveDBQuery("webhook", "GetAll", time.Now())
+
+	query := `SELECT ` + webhookColumns + ` FROM webhook_subscriptions ORDER BY id ASC`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := []models.WebhookSubscription{}
+	for rows.Next() {
+		subscription, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		subscriptions = append(subscriptions, *subscription)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate webhooks: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// Update saves the URL, events, secret and active flag of a webhook subscription
+func (r *webhookRepository) Update(subscription *models.WebhookSubscription) error {
+	defer metrics.ObserveDBQuery("webhook", "Update", time.Now())
+
+	query := `
+		UPDATE webhook_subscriptions
+		SET url = ?, events = ?, secret = ?, is_active = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	subscription.UpdatedAt = time.Now()
+
+	result, err := r.db.Exec(query, subscription.URL, models.JoinWebhookEvents(subscription.Events), subscription.Secret,
+		subscription.IsActive, subscription.UpdatedAt, subscription.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("webhook not found")
+	}
+
+	return nil
+}
+
+// Delete removes a webhook subscription; its deliveries are removed with it
+func (r *webhookRepository) Delete(id int) error {
+	defer metrics.ObserveDBQuery("webhook", "Delete", time.Now())
+
+	result, err := r.db.Exec(`DELETE FROM webhook_subscriptions WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("webhook not found")
+	}
+
+	return nil
+}
+
+// GetDeliveries retrieves the deliveries of a subscription, newest first, with the total count
+func (r *webhookRepository) GetDeliveries(subscriptionID, limit, offset int) ([]models.WebhookDelivery, int, error) {
+	defer metrics.ObserveDBQuery("webhook", "GetDeliveries", time.Now())
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM webhook_deliveries WHERE subscription_id = ?`, subscriptionID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	query := `SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries
+		WHERE subscription_id = ?
+		ORDER BY id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	deliveries, err := r.queryDeliveries(query, subscriptionID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
+
+// GetDueDeliveries retrieves pending deliveries of active subscriptions whose next attempt is due, oldest first
+func (r *webhookRepository) GetDueDeliveries(now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	defer metrics.ObserveDBQuery("webhook", "GetDueDeliveries", time.Now())
+
+	query := `SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries
+		WHERE status = ? AND next_attempt_at <= ?
+		  AND subscription_id IN (SELECT id FROM webhook_subscriptions WHERE is_active = TRUE)
+		ORDER BY id ASC
+		LIMIT ?
+	`
+
+	return r.queryDeliveries(query, models.DeliveryPending, now, limit)
+}
+
+// UpdateDelivery records the outcome of a delivery attempt
+func (r *webhookRepository) UpdateDelivery(delivery *models.WebhookDelivery) error {
+	defer metrics.ObserveDBQuery("webhook", "UpdateDelivery", time.Now())
+
+	query := `
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = ?, next_attempt_at = ?, last_attempt_at = ?,
+			response_status = ?, last_error = ?, delivered_at = ?
+		WHERE id = ?
+	`
+
+	result, err := r.db.Exec(query, delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastAttemptAt,
+		delivery.ResponseStatus, delivery.LastError, delivery.DeliveredAt, delivery.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("webhook delivery not found")
+	}
+
+	return nil
+}
+
+// queryDeliveries runs a query selecting webhookDeliveryColumns
+func (r *webhookRepository) queryDeliveries(query string, args ...interface{}) ([]models.WebhookDelivery, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// enqueueWebhookDeliveries queues a recorded change for every active subscription that wants its type
+func enqueueWebhookDeliveries(exec sqlExecutor, change *models.HolidayChange, payload []byte) error {
+	rows, err := exec.Query(`SELECT id, events FROM webhook_subscriptions WHERE is_active = TRUE`)
+	if err != nil {
+		return fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	var subscriptionIDs []int
+	for rows.Next() {
+		var subscription models.WebhookSubscription
+		var events string
+		if err := rows.Scan(&subscription.ID, &events); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		subscription.Events = models.SplitWebhookEvents(events)
+		if subscription.Subscribes(change.Type) {
+			subscriptionIDs = append(subscriptionIDs, subscription.ID)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate webhooks: %w", err)
+	}
+
+	query := `
+		INSERT INTO webhook_deliveries (subscription_id, change_sequence, event_type, payload, status, attempts, next_attempt_at, created_at)
+		VALUES (?, ?, ?, ?, ?, 0, ?, ?)
+	`
+
+	for _, id := range subscriptionIDs {
+		_, err := exec.Exec(query, id, change.Sequence, change.Type, string(payload), models.DeliveryPending,
+			change.CreatedAt, change.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to queue webhook delivery: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// scanWebhook scans a single webhook subscription row selected with webhookColumns
+func scanWebhook(row rowScanner) (*models.WebhookSubscription, error) {
+	subscription := &models.WebhookSubscription{}
+	var events string
+	var createdBy sql.NullInt64
+
+	err := row.Scan(
+		&subscription.ID, &subscription.URL, &events, &subscription.Secret, &subscription.IsActive,
+		&createdBy, &subscription.CreatedAt, &subscription.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.Events = models.SplitWebhookEvents(events)
+	if createdBy.Valid {
+		id := int(createdBy.Int64)
+		subscription.CreatedBy = &id
+	}
+
+	return subscription, nil
+}
+
+// scanWebhookDelivery scans a single webhook delivery row selected with webhookDeliveryColumns
+func scanWebhookDelivery(row rowScanner) (*models.WebhookDelivery, error) {
+	delivery := &models.WebhookDelivery{}
+	var payload string
+	var nextAttemptAt, lastAttemptAt, deliveredAt sql.NullTime
+	var responseStatus sql.NullInt64
+	var lastError sql.NullString
+
+	err := row.Scan(
+		&delivery.ID, &delivery.SubscriptionID, &delivery.ChangeSequence, &delivery.EventType, &payload,
+		&delivery.Status, &delivery.Attempts, &nextAttemptAt, &lastAttemptAt, &responseStatus, &lastError,
+		&deliveredAt, &delivery.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery.Payload = []byte(payload)
+	if nextAttemptAt.Valid {
+		delivery.NextAttemptAt = &nextAttemptAt.Time
+	}
+	if lastAttemptAt.Valid {
+		delivery.LastAttemptAt = &lastAttemptAt.Time
+	}
+	if responseStatus.Valid {
+		status := int(responseStatus.Int64)
+		delivery.ResponseStatus = &status
+	}
+	if lastError.Valid {
+		delivery.LastError = &lastError.String
+	}
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+
+	return delivery, nil
+}
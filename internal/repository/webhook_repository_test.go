@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilramdhan/holidayapi/internal/database"
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+func TestWebhookRepository_Outbox(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, db *database.DB) {
+		repo := NewWebhookRepository(db)
+		holidays := NewHolidayRepository(db)
+		createdBy := migratedAdminID
+
+		all := &models.WebhookSubscription{
+			URL:       "https://payroll.example.com/hooks/holidays",
+			Events:    []models.HolidayChangeType{models.ChangeHolidayCreated, models.ChangeHolidayUpdated, models.ChangeHolidayDeleted},
+			Secret:    "payroll-shared-secret",
+			IsActive:  true,
+			CreatedBy: &createdBy,
+		}
+		require.NoError(t, repo.Create(all))
+		deletesOnly := &models.WebhookSubscription{
+			URL:      "https://scheduling.example.com/hooks",
+			Events:   []models.HolidayChangeType{models.ChangeHolidayDeleted},
+			Secret:   "scheduling-shared-secret",
+			IsActive: true,
+		}
+		require.NoError(t, repo.Create(deletesOnly))
+
+		got, err := repo.GetByID(all.ID)
+		require.NoError(t, err)
+		assert.Equal(t, all.Events, got.Events)
+		assert.Equal(t, "payroll-shared-secret", got.Secret)
+
+		// A holiday mutation queues its change for matching subscriptions only
+		holiday, err := holidays.GetByID(1)
+		require.NoError(t, err)
+		holiday.Description = "Diperbarui"
+		change, err := holidays.Update(holiday.ID, holiday)
+		require.NoError(t, err)
+
+		due, err := repo.GetDueDeliveries(time.Now().Add(time.Second), 10)
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		assert.Equal(t, all.ID, due[0].SubscriptionID)
+		assert.Equal(t, change.Sequence, due[0].ChangeSequence)
+		assert.Equal(t, models.DeliveryPending, due[0].Status)
+		assert.Contains(t, string(due[0].Payload), `"name":"Tahun Baru Masehi"`)
+
+		// A failed attempt is rescheduled and no longer due
+		delivery := due[0]
+		now := time.Now()
+		next := now.Add(time.Hour)
+		status := 503
+		lastError := "receiver responded with 503"
+		delivery.Attempts = 1
+		delivery.LastAttemptAt = &now
+		delivery.NextAttemptAt = &next
+		delivery.ResponseStatus = &status
+		delivery.LastError = &lastError
+		require.NoError(t, repo.UpdateDelivery(&delivery))
+
+		due, err = repo.GetDueDeliveries(time.Now().Add(time.Second), 10)
+		require.NoError(t, err)
+		assert.Empty(t, due)
+
+		history, total, err := repo.GetDeliveries(all.ID, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		require.Len(t, history, 1)
+		assert.Equal(t, 1, history[0].Attempts)
+		assert.Equal(t, 503, *history[0].ResponseStatus)
+
+		// Inactive subscriptions get no new deliveries
+		deletesOnly.IsActive = false
+		require.NoError(t, repo.Update(deletesOnly))
+		_, err = holidays.Delete(holiday.ID)
+		require.NoError(t, err)
+
+		_, total, err = repo.GetDeliveries(deletesOnly.ID, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 0, total)
+
+		// Deleting a subscription removes its history
+		require.NoError(t, repo.Delete(all.ID))
+		_, total, err = repo.GetDeliveries(all.ID, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 0, total)
+		assert.EqualError(t, repo.Delete(all.ID), "webhook not found")
+
+		subscriptions, err := repo.GetAll()
+		require.NoError(t, err)
+		require.Len(t, subscriptions, 1)
+		assert.False(t, subscriptions[0].IsActive)
+	})
+}
@@ -0,0 +1,192 @@
+// Package scheduler runs background jobs at fixed intervals inside the server process
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Job is a unit of background work run every Interval
+type Job struct {
+	Name     string
+	Interval time.Duration
+	// Run does one pass of the work and returns a short summary of what it did
+	Run func(ctx context.Context) (string, error)
+}
+
+// Status reports what a job has done since the server started
+type Status struct {
+	Name           string     `json:"name"`
+	Interval       string     `json:"interval"`
+	Running        bool       `json:"running"`
+	Runs           int        `json:"runs"`
+	Failures       int        `json:"failures"`
+	LastStartedAt  *time.Time `json:"last_started_at,omitempty"`
+	LastFinishedAt *time.Time `json:"last_finished_at,omitempty"`
+	LastDuration   string     `json:"last_duration,omitempty"`
+	LastResult     string     `json:"last_result,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	NextRunAt      *time.Time `json:"next_run_at,omitempty"`
+}
+
+// entry is a registered job with its run state
+type entry struct {
+	job     Job
+	trigger chan struct{}
+	status  Status
+}
+
+// Scheduler runs each added job in its own goroutine: once at start, then every interval
+type Scheduler struct {
+	mu      sync.Mutex
+	entries []*entry
+	wg      sync.WaitGroup
+}
+
+// New creates an empty scheduler
+func New() *Scheduler {
+	return &Scheduler{}
+}
+
+// Add registers a job. Jobs added after Start are not run, and a job without a
+// positive interval is disabled and not registered at all.
+func (s *Scheduler) Add(job Job) {
+	if job.Interval <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = append(s.entries, &entry{
+		job:     job,
+		trigger: make(chan struct{}, 1),
+		status:  Status{Name: job.Name, Interval: job.Interval.String()},
+	})
+}
+
+// Start runs every job until the context is cancelled
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.entries {
+		s.wg.Add(1)
+		go func(e *entry) {
+			defer s.wg.Done()
+			s.loop(ctx, e)
+		}(e)
+	}
+}
+
+// Wait blocks until every job has returned after the context was cancelled
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+// Statuses returns the status of every job in the order they were added
+func (s *Scheduler) Statuses() []Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]Status, len(s.entries))
+	for i, e := range s.entries {
+		statuses[i] = e.status
+	}
+	return statuses
+}
+
+// Status returns the status of a job by name
+func (s *Scheduler) Status(name string) (Status, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e := s.find(name); e != nil {
+		return e.status, true
+	}
+	return Status{}, false
+}
+
+// Trigger asks a job to run now instead of waiting for its next tick. A job that is
+// running finishes first; triggers made while one is already pending are merged.
+func (s *Scheduler) Trigger(name string) error {
+	s.mu.Lock()
+	e := s.find(name)
+	s.mu.Unlock()
+
+	if e == nil {
+		return fmt.Errorf("job not found")
+	}
+
+	select {
+	case e.trigger <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// find returns the entry of a job by name; the caller holds s.mu
+func (s *Scheduler) find(name string) *entry {
+	for _, e := range s.entries {
+		if e.job.Name == name {
+			return e
+		}
+	}
+	return nil
+}
+
+// loop runs a job immediately and then one interval after each run ends, or when
+// triggered; a run is never overlapped by the next one
+func (s *Scheduler) loop(ctx context.Context, e *entry) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		case <-e.trigger:
+		}
+
+		s.run(ctx, e)
+
+		// Timers of Go 1.23+ drop any pending tick on Reset, so a triggered run restarts the interval
+		timer.Reset(e.job.Interval)
+	}
+}
+
+// run runs a job once and records the outcome in its status
+func (s *Scheduler) run(ctx context.Context, e *entry) {
+	started := time.Now()
+	s.mu.Lock()
+	e.status.Running = true
+	e.status.LastStartedAt = &started
+	e.status.NextRunAt = nil
+	s.mu.Unlock()
+
+	result, err := e.job.Run(ctx)
+	if err != nil && ctx.Err() == nil {
+		fmt.Printf("Job %s failed: %v\n", e.job.Name, err)
+	}
+
+	finished := time.Now()
+	next := finished.Add(e.job.Interval)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e.status.Running = false
+	e.status.Runs++
+	e.status.LastFinishedAt = &finished
+	e.status.LastDuration = finished.Sub(started).String()
+	e.status.LastResult = result
+	e.status.LastError = ""
+	if err != nil {
+		e.status.Failures++
+		e.status.LastError = err.Error()
+	}
+	e.status.NextRunAt = &next
+}
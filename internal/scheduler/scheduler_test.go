@@ -0,0 +1,87 @@
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_RunsJobsUntilCancelled(t *testing.T) {
+	var fast, slow atomic.Int32
+
+	s := New()
+	s.Add(Job{Name: "fast", Interval: 10 * time.Millisecond, Run: func(ctx context.Context) (string, error) {
+		fast.Add(1)
+		return "", nil
+	}})
+	s.Add(Job{Name: "slow", Interval: time.Hour, Run: func(ctx context.Context) (string, error) {
+		slow.Add(1)
+		return "", assert.AnError
+	}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Start(ctx)
+
+	assert.Eventually(t, func() bool { return fast.Load() >= 3 }, time.Second, 5*time.Millisecond)
+	cancel()
+	s.Wait()
+
+	// The slow job ran once at start, and a failure does not stop the scheduler
+	assert.Equal(t, int32(1), slow.Load())
+
+	runs := fast.Load()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, runs, fast.Load(), "jobs must not run after Wait returns")
+}
+
+func TestScheduler_TriggerAndStatus(t *testing.T) {
+	var runs atomic.Int32
+
+	s := New()
+	s.Add(Job{Name: "cleanup", Interval: time.Hour, Run: func(ctx context.Context) (string, error) {
+		if runs.Add(1) == 2 {
+			return "", assert.AnError
+		}
+		return "removed 3 entries", nil
+	}})
+
+	assert.EqualError(t, s.Trigger("missing"), "job not found")
+	_, found := s.Status("missing")
+	assert.False(t, found)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		s.Wait()
+	}()
+	s.Start(ctx)
+
+	assert.Eventually(t, func() bool {
+		status, _ := s.Status("cleanup")
+		return status.Runs == 1
+	}, time.Second, 5*time.Millisecond)
+
+	status, found := s.Status("cleanup")
+	require.True(t, found)
+	assert.Equal(t, "1h0m0s", status.Interval)
+	assert.Equal(t, "removed 3 entries", status.LastResult)
+	require.NotNil(t, status.NextRunAt)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), *status.NextRunAt, time.Minute)
+
+	// A triggered run does not wait for the hour to pass
+	require.NoError(t, s.Trigger("cleanup"))
+	assert.Eventually(t, func() bool {
+		status, _ := s.Status("cleanup")
+		return status.Runs == 2
+	}, time.Second, 5*time.Millisecond)
+
+	statuses := s.Statuses()
+	require.Len(t, statuses, 1)
+	assert.Equal(t, 1, statuses[0].Failures)
+	assert.Equal(t, assert.AnError.Error(), statuses[0].LastError)
+	assert.False(t, statuses[0].Running)
+}
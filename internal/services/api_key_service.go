@@ -0,0 +1,200 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/repository"
+)
+
+const (
+	// apiKeyPrefix marks values issued by this API so they are easy to spot in logs and secret scanners
+	apiKeyPrefix = "hk_"
+	// apiKeyDisplayLength is how many leading characters of a key are stored for display
+	apiKeyDisplayLength = len(apiKeyPrefix) + 8
+)
+
+// APIKeyService handles client API keys
+type APIKeyService interface {
+	CreateAPIKey(req models.CreateAPIKeyRequest, createdBy *models.User, ipAddress, userAgent string) (*models.CreateAPIKeyResponse, error)
+	ListAPIKeys() ([]models.APIKey, error)
+	RevokeAPIKey(id int, revokedBy *models.User, ipAddress, userAgent string) error
+	Authenticate(rawKey, requestPath, ipAddress, userAgent string) (*models.APIKey, *models.User, error)
+}
+
+// apiKeyService implements APIKeyService
+type apiKeyService struct {
+	apiKeyRepo repository.APIKeyRepository
+	userRepo   UserRepository
+	roleRepo   repository.RoleRepository
+	auditRepo  repository.AuditRepository
+}
+
+// NewAPIKeyService creates a new API key service
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository, userRepo UserRepository, roleRepo repository.RoleRepository, auditRepo repository.AuditRepository) APIKeyService {
+	return &apiKeyService{
+		apiKeyRepo: apiKeyRepo,
+		userRepo:   userRepo,
+		roleRepo:   roleRepo,
+		auditRepo:  auditRepo,
+	}
+}
+
+// CreateAPIKey issues a new API key. The returned key value is not stored and cannot be retrieved again.
+func (s *apiKeyService) CreateAPIKey(req models.CreateAPIKeyRequest, createdBy *models.User, ipAddress, userAgent string) (*models.CreateAPIKeyResponse, error) {
+	ownerID := createdBy.ID
+	if req.UserID != nil {
+		ownerID = *req.UserID
+	}
+
+	owner, err := s.userRepo.GetByID(ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	// A key acts with its owner's permissions, so it may only be issued for a user the issuer could manage
+	if err := ensureManageable(s.roleRepo, createdBy, owner); err != nil {
+		s.logAudit(&createdBy.ID, createdBy.Username, models.ActionAPIKeyCreate, nil,
+			fmt.Sprintf("Creation of API key %q for user %s refused: %v", strings.TrimSpace(req.Name), owner.Username, err), ipAddress, userAgent, false)
+		return nil, err
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("expires_at must be in the future")
+	}
+
+	secret, err := generateRandomID(32)
+	if err != nil {
+		return nil, err
+	}
+	rawKey := apiKeyPrefix + secret
+
+	key := &models.APIKey{
+		Name:      strings.TrimSpace(req.Name),
+		Prefix:    rawKey[:apiKeyDisplayLength],
+		KeyHash:   hashToken(rawKey),
+		UserID:    owner.ID,
+		Username:  owner.Username,
+		Scopes:    uniqueScopes(req.Scopes),
+		ExpiresAt: req.ExpiresAt,
+		CreatedBy: &createdBy.ID,
+	}
+
+	if err := s.apiKeyRepo.Create(key); err != nil {
+		s.logAudit(&createdBy.ID, createdBy.Username, models.ActionAPIKeyCreate, nil,
+			fmt.Sprintf("Failed to create API key %q for user: %s", key.Name, owner.Username), ipAddress, userAgent, false)
+		return nil, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	s.logAudit(&createdBy.ID, createdBy.Username, models.ActionAPIKeyCreate, &key.ID,
+		fmt.Sprintf("API key %q (%s) created for user: %s with scopes: %s", key.Name, key.Prefix, owner.Username,
+			models.JoinAPIKeyScopes(key.Scopes)), ipAddress, userAgent, true)
+
+	return &models.CreateAPIKeyResponse{APIKey: key, Key: rawKey}, nil
+}
+
+// ListAPIKeys retrieves all API keys without their secret values
+func (s *apiKeyService) ListAPIKeys() ([]models.APIKey, error) {
+	keys, err := s.apiKeyRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api keys: %w", err)
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey revokes an API key so it can no longer authenticate
+func (s *apiKeyService) RevokeAPIKey(id int, revokedBy *models.User, ipAddress, userAgent string) error {
+	key, err := s.apiKeyRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	if key.RevokedAt != nil {
+		return fmt.Errorf("api key already revoked")
+	}
+
+	if err := s.apiKeyRepo.Revoke(id); err != nil {
+		s.logAudit(&revokedBy.ID, revokedBy.Username, models.ActionAPIKeyRevoke, &id,
+			fmt.Sprintf("Failed to revoke API key %q (%s)", key.Name, key.Prefix), ipAddress, userAgent, false)
+		return err
+	}
+
+	s.logAudit(&revokedBy.ID, revokedBy.Username, models.ActionAPIKeyRevoke, &id,
+		fmt.Sprintf("API key %q (%s) of user %s revoked", key.Name, key.Prefix, key.Username), ipAddress, userAgent, true)
+
+	return nil
+}
+
+// Authenticate resolves a raw API key to the key and its owner and records the usage
+func (s *apiKeyService) Authenticate(rawKey, requestPath, ipAddress, userAgent string) (*models.APIKey, *models.User, error) {
+	key, err := s.apiKeyRepo.GetByHash(hashToken(rawKey))
+	if err != nil {
+		s.logAudit(nil, "anonymous", models.ActionAPIKeyUse, nil,
+			fmt.Sprintf("Unknown API key used for %s", requestPath), ipAddress, userAgent, false)
+		return nil, nil, fmt.Errorf("invalid api key")
+	}
+
+	now := time.Now()
+	if !key.IsUsable(now) {
+		s.logAudit(&key.UserID, key.Username, models.ActionAPIKeyUse, &key.ID,
+			fmt.Sprintf("Revoked or expired API key %s used for %s", key.Prefix, requestPath), ipAddress, userAgent, false)
+		return nil, nil, fmt.Errorf("api key is revoked or expired")
+	}
+
+	owner, err := s.userRepo.GetByID(key.UserID)
+	if err != nil {
+		s.logAudit(&key.UserID, key.Username, models.ActionAPIKeyUse, &key.ID,
+			fmt.Sprintf("API key %s of inactive user used for %s", key.Prefix, requestPath), ipAddress, userAgent, false)
+		return nil, nil, fmt.Errorf("api key owner is inactive")
+	}
+
+	// A key acts with its owner's current permissions, narrowed further by its scopes
+	owner.Permissions, err = rolePermissions(s.roleRepo, owner.Role)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.apiKeyRepo.UpdateLastUsed(key.ID, now); err != nil {
+		fmt.Printf("Failed to update api key last used: %v\n", err)
+	}
+	key.LastUsedAt = &now
+
+	s.logAudit(&owner.ID, owner.Username, models.ActionAPIKeyUse, &key.ID,
+		fmt.Sprintf("API key %s used for %s", key.Prefix, requestPath), ipAddress, userAgent, true)
+
+	return key, owner, nil
+}
+
+// logAudit logs an audit entry for an API key
+func (s *apiKeyService) logAudit(userID *int, username string, action models.AuditAction, keyID *int, details, ipAddress, userAgent string, success bool) {
+	auditLog := &models.AuditLog{
+		UserID:     userID,
+		Username:   username,
+		Action:     action,
+		Resource:   models.ResourceAPIKey,
+		ResourceID: keyID,
+		Details:    details,
+		IPAddress:  ipAddress,
+		UserAgent:  userAgent,
+		Success:    success,
+	}
+
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		fmt.Printf("Failed to create audit log: %v\n", err)
+	}
+}
+
+// uniqueScopes removes duplicate scopes while keeping their order
+func uniqueScopes(scopes []models.APIKeyScope) []models.APIKeyScope {
+	seen := make(map[models.APIKeyScope]bool, len(scopes))
+	unique := make([]models.APIKeyScope, 0, len(scopes))
+	for _, scope := range scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			unique = append(unique, scope)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+// MockAPIKeyRepository is a mock implementation of APIKeyRepository
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) Create(key *models.APIKey) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) GetByID(id int) (*models.APIKey, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetByHash(keyHash string) (*models.APIKey, error) {
+	args := m.Called(keyHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetAll() ([]models.APIKey, error) {
+	args := m.Called()
+	return args.Get(0).([]models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Revoke(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) UpdateLastUsed(id int, usedAt time.Time) error {
+	args := m.Called(id, usedAt)
+	return args.Error(0)
+}
+
+func TestAPIKeyService_CreateAPIKey(t *testing.T) {
+	keyRepo := new(MockAPIKeyRepository)
+	userRepo := new(MockUserRepository)
+	auditRepo := new(MockAuditRepository)
+	service := NewAPIKeyService(keyRepo, userRepo, newTestRoleRepository(), auditRepo)
+
+	ownerID := 2
+	userRepo.On("GetByID", ownerID).Return(&models.User{ID: ownerID, Username: "editor", Role: models.AdminRole, IsActive: true}, nil)
+	keyRepo.On("Create", mock.AnythingOfType("*models.APIKey")).Return(nil)
+	auditRepo.On("Create", mock.Anything).Return(nil)
+
+	req := models.CreateAPIKeyRequest{
+		Name:   "Payroll integration",
+		UserID: &ownerID,
+		Scopes: []models.APIKeyScope{models.ScopeReadHolidays, models.ScopeReadHolidays, models.ScopeWriteHolidays},
+	}
+	created, err := service.CreateAPIKey(req, &models.User{ID: 1, Username: "admin", Role: models.SuperAdminRole}, "127.0.0.1", "test")
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(created.Key, apiKeyPrefix))
+	assert.True(t, strings.HasPrefix(created.Key, created.Prefix))
+
+	stored := keyRepo.Calls[0].Arguments.Get(0).(*models.APIKey)
+	assert.Equal(t, hashToken(created.Key), stored.KeyHash)
+	assert.NotContains(t, stored.KeyHash, created.Key)
+	assert.Equal(t, ownerID, stored.UserID)
+	assert.Equal(t, []models.APIKeyScope{models.ScopeReadHolidays, models.ScopeWriteHolidays}, stored.Scopes)
+	assert.Equal(t, []string{"API_KEY_CREATE:true"}, auditActions(auditRepo))
+
+	// Expiry in the past is rejected before anything is stored
+	past := time.Now().Add(-time.Hour)
+	req.ExpiresAt = &past
+	_, err = service.CreateAPIKey(req, &models.User{ID: 1, Username: "admin", Role: models.SuperAdminRole}, "", "")
+	assert.Error(t, err)
+	keyRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestAPIKeyService_CreateAPIKeyRefusesMorePrivilegedOwner(t *testing.T) {
+	keyRepo := new(MockAPIKeyRepository)
+	userRepo := new(MockUserRepository)
+	auditRepo := new(MockAuditRepository)
+	service := NewAPIKeyService(keyRepo, userRepo, newTestRoleRepository(), auditRepo)
+
+	ownerID := 1
+	userRepo.On("GetByID", ownerID).Return(&models.User{ID: ownerID, Username: "root", Role: models.SuperAdminRole, IsActive: true}, nil)
+	auditRepo.On("Create", mock.Anything).Return(nil)
+
+	// A key acts with its owner's permissions, so an admin cannot issue one for a super admin
+	req := models.CreateAPIKeyRequest{Name: "Escalation", UserID: &ownerID, Scopes: []models.APIKeyScope{models.ScopeReadAudit}}
+	_, err := service.CreateAPIKey(req, &models.User{ID: 2, Username: "editor", Role: models.AdminRole}, "127.0.0.1", "test")
+
+	assert.EqualError(t, err, "cannot manage a user with permissions beyond your own: cannot grant the audit:verify permission, which you do not have")
+	keyRepo.AssertNotCalled(t, "Create", mock.Anything)
+	assert.Equal(t, []string{"API_KEY_CREATE:false"}, auditActions(auditRepo))
+}
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name      string
+		key       *models.APIKey
+		ownerErr  error
+		expectErr bool
+		audit     string
+	}{
+		{name: "valid key", key: &models.APIKey{ID: 5, UserID: 2, Username: "editor", ExpiresAt: &future}, audit: "API_KEY_USE:true"},
+		{name: "unknown key", expectErr: true, audit: "API_KEY_USE:false"},
+		{name: "expired key", key: &models.APIKey{ID: 5, UserID: 2, ExpiresAt: &past}, expectErr: true, audit: "API_KEY_USE:false"},
+		{name: "revoked key", key: &models.APIKey{ID: 5, UserID: 2, RevokedAt: &past}, expectErr: true, audit: "API_KEY_USE:false"},
+		{name: "inactive owner", key: &models.APIKey{ID: 5, UserID: 2}, ownerErr: fmt.Errorf("user not found"), expectErr: true, audit: "API_KEY_USE:false"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyRepo := new(MockAPIKeyRepository)
+			userRepo := new(MockUserRepository)
+			auditRepo := new(MockAuditRepository)
+			service := NewAPIKeyService(keyRepo, userRepo, newTestRoleRepository(), auditRepo)
+
+			if tt.key != nil {
+				keyRepo.On("GetByHash", hashToken("hk_secret")).Return(tt.key, nil)
+			} else {
+				keyRepo.On("GetByHash", mock.Anything).Return(nil, fmt.Errorf("api key not found"))
+			}
+			if tt.ownerErr != nil {
+				userRepo.On("GetByID", 2).Return(nil, tt.ownerErr)
+			} else {
+				userRepo.On("GetByID", 2).Return(&models.User{ID: 2, Username: "editor", Role: models.AdminRole, IsActive: true}, nil)
+			}
+			keyRepo.On("UpdateLastUsed", 5, mock.AnythingOfType("time.Time")).Return(nil)
+			auditRepo.On("Create", mock.Anything).Return(nil)
+
+			key, owner, err := service.Authenticate("hk_secret", "GET /api/v1/admin/holidays/1", "127.0.0.1", "test")
+
+			if tt.expectErr {
+				assert.Error(t, err)
+				assert.Nil(t, key)
+				assert.Nil(t, owner)
+				keyRepo.AssertNotCalled(t, "UpdateLastUsed", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "editor", owner.Username)
+				assert.True(t, models.HasPermission(owner.Permissions, models.PermissionHolidayDelete))
+				assert.NotNil(t, key.LastUsedAt)
+			}
+			assert.Equal(t, []string{tt.audit}, auditActions(auditRepo))
+		})
+	}
+}
+
+func TestAPIKeyService_RevokeAPIKey(t *testing.T) {
+	keyRepo := new(MockAPIKeyRepository)
+	auditRepo := new(MockAuditRepository)
+	service := NewAPIKeyService(keyRepo, new(MockUserRepository), newTestRoleRepository(), auditRepo)
+
+	revokedAt := time.Now()
+	keyRepo.On("GetByID", 1).Return(&models.APIKey{ID: 1, Name: "old", Prefix: "hk_12345678"}, nil)
+	keyRepo.On("GetByID", 2).Return(&models.APIKey{ID: 2, RevokedAt: &revokedAt}, nil)
+	keyRepo.On("Revoke", 1).Return(nil)
+	auditRepo.On("Create", mock.Anything).Return(nil)
+
+	admin := &models.User{ID: 1, Username: "admin", Role: models.SuperAdminRole}
+	assert.NoError(t, service.RevokeAPIKey(1, admin, "", ""))
+	assert.Error(t, service.RevokeAPIKey(2, admin, "", ""))
+	keyRepo.AssertNotCalled(t, "Revoke", 2)
+	assert.Equal(t, []string{"API_KEY_REVOKE:true"}, auditActions(auditRepo))
+}
@@ -1,7 +1,9 @@
 package services
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/ilramdhan/holidayapi/internal/models"
@@ -13,9 +15,14 @@ type AuditService interface {
 	LogAction(userID *int, username string, action models.AuditAction, resource models.AuditResource, resourceID *int, details, ipAddress, userAgent string, success bool) error
 	GetAuditLogs(filter models.AuditLogFilter) (*models.AuditLogResponse, error)
 	GetUserAuditLogs(userID int, limit, offset int) ([]models.AuditLog, error)
-	CleanupOldLogs(daysToKeep int) error
+	CleanupOldLogs(daysToKeep int) (int64, error)
+	VerifyChain() (*models.AuditChainVerification, error)
+	ExportAuditLogs(filter models.AuditLogFilter, format string, exportedBy *models.User, ipAddress, userAgent string, write func(log *models.AuditLog) error) (int, error)
 }
 
+// errAuditChainBroken stops the walk of the audit chain at the first broken link
+var errAuditChainBroken = errors.New("audit chain broken")
+
 // auditService implements AuditService
 type auditService struct {
 	auditRepo repository.AuditRepository
@@ -93,18 +100,179 @@ func (s *auditService) GetUserAuditLogs(userID int, limit, offset int) ([]models
 	return logs, nil
 }
 
-// CleanupOldLogs removes audit logs older than specified days
-func (s *auditService) CleanupOldLogs(daysToKeep int) error {
+// CleanupOldLogs removes audit logs older than specified days and returns how many were removed
+func (s *auditService) CleanupOldLogs(daysToKeep int) (int64, error) {
 	if daysToKeep <= 0 {
-		daysToKeep = 90 // Default to 90 days
+		return 0, fmt.Errorf("audit retention must keep at least one day, got %d", daysToKeep)
 	}
 
 	// Calculate cutoff date
 	cutoffDate := time.Now().AddDate(0, 0, -daysToKeep)
 
-	if err := s.auditRepo.DeleteOldLogs(cutoffDate); err != nil {
-		return fmt.Errorf("failed to cleanup old audit logs: %w", err)
+	deleted, err := s.auditRepo.DeleteOldLogs(cutoffDate)
+	if err != nil {
+		return 0, fmt.Errorf("failed to cleanup old audit logs: %w", err)
 	}
 
-	return nil
+	return deleted, nil
+}
+
+// ExportAuditLogs passes every audit log matching the filter to write, oldest first, without a page
+// size cap. The export itself is then recorded as an audit event with the number of entries written.
+func (s *auditService) ExportAuditLogs(filter models.AuditLogFilter, format string, exportedBy *models.User, ipAddress, userAgent string, write func(log *models.AuditLog) error) (int, error) {
+	exported := 0
+	err := s.auditRepo.Walk(filter, func(log *models.AuditLog) error {
+		if err := write(log); err != nil {
+			return err
+		}
+		exported++
+		return nil
+	})
+
+	details := fmt.Sprintf("Exported %d audit logs as %s%s", exported, format, describeAuditFilter(filter))
+	if err != nil {
+		details = fmt.Sprintf("Audit log export as %s failed after %d entries%s: %v", format, exported, describeAuditFilter(filter), err)
+	}
+	if logErr := s.LogAction(&exportedBy.ID, exportedBy.Username, models.ActionAuditExport, models.ResourceSystem, nil,
+		details, ipAddress, userAgent, err == nil); logErr != nil {
+		fmt.Printf("Failed to create audit log: %v\n", logErr)
+	}
+
+	if err != nil {
+		return exported, fmt.Errorf("failed to export audit logs: %w", err)
+	}
+	return exported, nil
+}
+
+// describeAuditFilter lists the conditions of a filter for audit details, empty when there are none
+func describeAuditFilter(filter models.AuditLogFilter) string {
+	var conditions []string
+	if filter.UserID != nil {
+		conditions = append(conditions, fmt.Sprintf("user_id=%d", *filter.UserID))
+	}
+	if filter.Action != nil {
+		conditions = append(conditions, fmt.Sprintf("action=%s", *filter.Action))
+	}
+	if filter.Resource != nil {
+		conditions = append(conditions, fmt.Sprintf("resource=%s", *filter.Resource))
+	}
+	if filter.Success != nil {
+		conditions = append(conditions, fmt.Sprintf("success=%t", *filter.Success))
+	}
+	if filter.StartDate != nil {
+		conditions = append(conditions, "start_date="+filter.StartDate.Format("2006-01-02"))
+	}
+	if filter.EndDate != nil {
+		conditions = append(conditions, "end_date="+filter.EndDate.Format("2006-01-02"))
+	}
+	if filter.Limit > 0 {
+		conditions = append(conditions, fmt.Sprintf("limit=%d", filter.Limit))
+	}
+	if filter.Offset > 0 {
+		conditions = append(conditions, fmt.Sprintf("offset=%d", filter.Offset))
+	}
+
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(conditions, ", ") + ")"
+}
+
+// VerifyChain walks the audit chain from the oldest entry and reports the first broken link.
+// Entries written before the chain was introduced are counted but not checked. The oldest
+// chained entry must link to the entry removed by the latest retention checkpoint, or to the
+// genesis hash when retention has not removed any chained entry, so deleting a prefix of the
+// chain is detected. Checkpoints are links of the chain too: where a link does not match, the
+// chain may continue through a checkpoint whose hash matches its content, and the latest
+// checkpoint must be reached this way, so it cannot be forged or replaced.
+func (s *auditService) VerifyChain() (*models.AuditChainVerification, error) {
+	checkpoints, err := s.auditRepo.GetChainCheckpoints()
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify audit chain: %w", err)
+	}
+
+	result := &models.AuditChainVerification{Valid: true}
+
+	anchor, anchorName := models.AuditChainGenesis, "the genesis hash"
+	if len(checkpoints) > 0 {
+		result.Checkpoint = &checkpoints[len(checkpoints)-1]
+		anchor, anchorName = result.Checkpoint.LastDeletedHash, "the retention checkpoint"
+	}
+
+	// spliced maps a hash to the checkpoint appended after it
+	spliced := make(map[string]*models.AuditChainCheckpoint, len(checkpoints))
+	for i := range checkpoints {
+		spliced[checkpoints[i].PrevHash] = &checkpoints[i]
+	}
+
+	// follow returns the hash the chain continues from after any checkpoints appended to hash
+	checkpointLinked := false
+	follow := func(hash string) string {
+		for range checkpoints {
+			checkpoint := spliced[hash]
+			if checkpoint == nil || checkpoint.ComputeHash() != checkpoint.Hash {
+				break
+			}
+			if checkpoint == result.Checkpoint {
+				checkpointLinked = true
+			}
+			hash = checkpoint.Hash
+		}
+		return hash
+	}
+
+	broken := func(log *models.AuditLog, reason string) error {
+		id := log.ID
+		result.Valid = false
+		result.BrokenAtID = &id
+		result.Reason = reason
+		return errAuditChainBroken
+	}
+
+	err = s.auditRepo.Walk(models.AuditLogFilter{}, func(log *models.AuditLog) error {
+		if log.Hash == "" {
+			if result.FirstID == nil {
+				result.Unchained++
+				return nil
+			}
+			return broken(log, "entry has no hash")
+		}
+
+		if result.FirstID == nil {
+			id := log.ID
+			result.FirstID = &id
+			if log.PrevHash != anchor && log.PrevHash != follow(anchor) {
+				return broken(log, "previous hash does not match "+anchorName)
+			}
+		} else if log.PrevHash != result.LastHash && log.PrevHash != follow(result.LastHash) {
+			return broken(log, "previous hash does not match the preceding entry")
+		}
+
+		if log.ComputeHash() != log.Hash {
+			return broken(log, "hash does not match the entry's content")
+		}
+
+		result.Checked++
+		result.LastHash = log.Hash
+		return nil
+	})
+	if err != nil && !errors.Is(err, errAuditChainBroken) {
+		return nil, fmt.Errorf("failed to verify audit chain: %w", err)
+	}
+
+	if result.Valid && result.Checkpoint != nil {
+		tail := anchor
+		if result.FirstID != nil {
+			tail = result.LastHash
+		}
+		follow(tail)
+
+		if !checkpointLinked {
+			result.Valid = false
+			result.Reason = "the retention checkpoint is not linked into the chain"
+		}
+	}
+
+	result.VerifiedAt = time.Now()
+	return result, nil
 }
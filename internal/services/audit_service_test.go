@@ -0,0 +1,292 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+// auditChain builds a legacy entry followed by n chained entries, as the repository writes them
+func auditChain(n int) []models.AuditLog {
+	logs := []models.AuditLog{{ID: 1, Username: "admin", Action: models.ActionUserCreate, Resource: models.ResourceUser}}
+
+	prevHash := models.AuditChainGenesis
+	created := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		log := models.AuditLog{
+			ID:        i + 2,
+			Username:  "admin",
+			Action:    models.ActionLogin,
+			Resource:  models.ResourceAuth,
+			Success:   true,
+			CreatedAt: created.Add(time.Duration(i) * time.Minute),
+			PrevHash:  prevHash,
+		}
+		log.Hash = log.ComputeHash()
+		prevHash = log.Hash
+		logs = append(logs, log)
+	}
+
+	return logs
+}
+
+// walkLogs makes the mock repository walk the given entries
+func walkLogs(repo *MockAuditRepository, logs []models.AuditLog) {
+	repo.On("Walk", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		fn := args.Get(1).(func(log *models.AuditLog) error)
+		for i := range logs {
+			if err := fn(&logs[i]); err != nil {
+				return
+			}
+		}
+	}).Return(nil)
+}
+
+func TestAuditService_VerifyChain(t *testing.T) {
+	// retained keeps the entries after the first n chained ones, with the checkpoint retention
+	// appends to the chain for them
+	retained := func(logs []models.AuditLog, n int) ([]models.AuditLog, models.AuditChainCheckpoint) {
+		last := logs[n]
+		checkpoint := models.AuditChainCheckpoint{
+			LastDeletedID:   last.ID,
+			LastDeletedHash: last.Hash,
+			CreatedAt:       logs[len(logs)-1].CreatedAt.Add(time.Hour),
+			PrevHash:        logs[len(logs)-1].Hash,
+		}
+		checkpoint.Hash = checkpoint.ComputeHash()
+		return logs[n+1:], checkpoint
+	}
+
+	// appended adds an entry linked to prevHash after the given entries
+	appended := func(logs []models.AuditLog, prevHash string) []models.AuditLog {
+		log := logs[len(logs)-1]
+		log.ID++
+		log.CreatedAt = log.CreatedAt.Add(2 * time.Hour)
+		log.PrevHash = prevHash
+		log.Hash = log.ComputeHash()
+		return append(logs, log)
+	}
+
+	// retainedTwice runs retention, writes an entry and runs retention again
+	retainedTwice := func(logs []models.AuditLog) ([]models.AuditLog, []models.AuditChainCheckpoint) {
+		kept, first := retained(logs, 1)
+		kept, second := retained(appended(kept, first.Hash), 0)
+		return kept, []models.AuditChainCheckpoint{first, second}
+	}
+
+	// rewritten deletes one more entry after retention and entries linked to its checkpoint,
+	// rewriting the checkpoint to cover it
+	rewritten := func(logs []models.AuditLog) ([]models.AuditLog, models.AuditChainCheckpoint) {
+		kept, checkpoint := retained(logs, 1)
+		kept = appended(kept, checkpoint.Hash)
+		checkpoint.LastDeletedID, checkpoint.LastDeletedHash = kept[0].ID, kept[0].Hash
+		checkpoint.Hash = checkpoint.ComputeHash()
+		return kept[1:], checkpoint
+	}
+
+	tests := []struct {
+		name        string
+		tamper      func(logs []models.AuditLog) []models.AuditLog
+		checkpoints func(logs []models.AuditLog) []models.AuditChainCheckpoint
+		valid       bool
+		brokenAtID  int
+		reason      string
+	}{
+		{
+			name:   "intact",
+			tamper: func(logs []models.AuditLog) []models.AuditLog { return logs },
+			valid:  true,
+		},
+		{
+			name: "oldest entries removed by retention",
+			tamper: func(logs []models.AuditLog) []models.AuditLog {
+				kept, _ := retained(logs, 1)
+				return kept
+			},
+			checkpoints: func(logs []models.AuditLog) []models.AuditChainCheckpoint {
+				_, checkpoint := retained(logs, 1)
+				return []models.AuditChainCheckpoint{checkpoint}
+			},
+			valid: true,
+		},
+		{
+			name: "entries written after retention",
+			tamper: func(logs []models.AuditLog) []models.AuditLog {
+				kept, checkpoint := retained(logs, 1)
+				return appended(kept, checkpoint.Hash)
+			},
+			checkpoints: func(logs []models.AuditLog) []models.AuditChainCheckpoint {
+				_, checkpoint := retained(logs, 1)
+				return []models.AuditChainCheckpoint{checkpoint}
+			},
+			valid: true,
+		},
+		{
+			name: "several retention runs",
+			tamper: func(logs []models.AuditLog) []models.AuditLog {
+				kept, _ := retainedTwice(logs)
+				return kept
+			},
+			checkpoints: func(logs []models.AuditLog) []models.AuditChainCheckpoint {
+				_, checkpoints := retainedTwice(logs)
+				return checkpoints
+			},
+			valid: true,
+		},
+		{
+			name: "oldest entries deleted without a checkpoint",
+			tamper: func(logs []models.AuditLog) []models.AuditLog {
+				return logs[2:]
+			},
+			brokenAtID: 3,
+			reason:     "previous hash does not match the genesis hash",
+		},
+		{
+			name: "more entries deleted than the checkpoint covers",
+			tamper: func(logs []models.AuditLog) []models.AuditLog {
+				return logs[3:]
+			},
+			checkpoints: func(logs []models.AuditLog) []models.AuditChainCheckpoint {
+				_, checkpoint := retained(logs, 1)
+				return []models.AuditChainCheckpoint{checkpoint}
+			},
+			brokenAtID: 4,
+			reason:     "previous hash does not match the retention checkpoint",
+		},
+		{
+			name: "forged checkpoint",
+			tamper: func(logs []models.AuditLog) []models.AuditLog {
+				return logs[3:]
+			},
+			checkpoints: func(logs []models.AuditLog) []models.AuditChainCheckpoint {
+				checkpoint := models.AuditChainCheckpoint{
+					LastDeletedID:   logs[2].ID,
+					LastDeletedHash: logs[2].Hash,
+					CreatedAt:       logs[2].CreatedAt,
+					PrevHash:        models.AuditChainGenesis,
+				}
+				checkpoint.Hash = checkpoint.ComputeHash()
+				return []models.AuditChainCheckpoint{checkpoint}
+			},
+			reason: "the retention checkpoint is not linked into the chain",
+		},
+		{
+			name: "checkpoint rewritten after entries linked to it",
+			tamper: func(logs []models.AuditLog) []models.AuditLog {
+				kept, _ := rewritten(logs)
+				return kept
+			},
+			checkpoints: func(logs []models.AuditLog) []models.AuditChainCheckpoint {
+				_, checkpoint := rewritten(logs)
+				return []models.AuditChainCheckpoint{checkpoint}
+			},
+			brokenAtID: 6,
+			reason:     "previous hash does not match the preceding entry",
+		},
+		{
+			name: "content rewritten",
+			tamper: func(logs []models.AuditLog) []models.AuditLog {
+				logs[2].Success = false
+				return logs
+			},
+			brokenAtID: 3,
+			reason:     "hash does not match the entry's content",
+		},
+		{
+			name: "entry deleted",
+			tamper: func(logs []models.AuditLog) []models.AuditLog {
+				return append(logs[:2], logs[3:]...)
+			},
+			brokenAtID: 4,
+			reason:     "previous hash does not match the preceding entry",
+		},
+		{
+			name: "unchained entry inserted",
+			tamper: func(logs []models.AuditLog) []models.AuditLog {
+				logs[3].Hash, logs[3].PrevHash = "", ""
+				return logs
+			},
+			brokenAtID: 4,
+			reason:     "entry has no hash",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockAuditRepository)
+			var checkpoints []models.AuditChainCheckpoint
+			if tt.checkpoints != nil {
+				checkpoints = tt.checkpoints(auditChain(4))
+			}
+			mockRepo.On("GetChainCheckpoints").Return(checkpoints, nil)
+			walkLogs(mockRepo, tt.tamper(auditChain(4)))
+
+			result, err := NewAuditService(mockRepo).VerifyChain()
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.valid, result.Valid)
+			assert.Equal(t, tt.reason, result.Reason)
+			if tt.valid {
+				assert.Nil(t, result.BrokenAtID)
+				assert.NotEmpty(t, result.LastHash)
+			}
+			if tt.brokenAtID != 0 {
+				require.NotNil(t, result.BrokenAtID)
+				assert.Equal(t, tt.brokenAtID, *result.BrokenAtID)
+			} else {
+				assert.Nil(t, result.BrokenAtID)
+			}
+			if len(checkpoints) > 0 {
+				assert.Equal(t, &checkpoints[len(checkpoints)-1], result.Checkpoint)
+			}
+		})
+	}
+}
+
+func TestAuditService_ExportAuditLogs(t *testing.T) {
+	mockRepo := new(MockAuditRepository)
+	admin := &models.User{ID: 1, Username: "admin", Role: models.SuperAdminRole}
+	action := models.ActionLogin
+	filter := models.AuditLogFilter{Action: &action}
+
+	logs := auditChain(3)
+	walkLogs(mockRepo, logs)
+	mockRepo.On("Create", mock.MatchedBy(func(log *models.AuditLog) bool {
+		return log.Action == models.ActionAuditExport && log.Success && *log.UserID == 1 &&
+			log.Details == "Exported 4 audit logs as csv (action=LOGIN)"
+	})).Return(nil)
+
+	var written []int
+	exported, err := NewAuditService(mockRepo).ExportAuditLogs(filter, "csv", admin, "127.0.0.1", "test", func(log *models.AuditLog) error {
+		written = append(written, log.ID)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, exported)
+	assert.Equal(t, []int{1, 2, 3, 4}, written)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuditService_CleanupOldLogs(t *testing.T) {
+	mockRepo := new(MockAuditRepository)
+	service := NewAuditService(mockRepo)
+
+	mockRepo.On("DeleteOldLogs", mock.MatchedBy(func(cutoff time.Time) bool {
+		return time.Since(cutoff) > 29*24*time.Hour && time.Since(cutoff) < 31*24*time.Hour
+	})).Return(int64(12), nil)
+
+	deleted, err := service.CleanupOldLogs(30)
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), deleted)
+
+	// A non-positive retention would delete everything, so it is rejected
+	_, err = service.CleanupOldLogs(0)
+	assert.EqualError(t, err, "audit retention must keep at least one day, got 0")
+	mockRepo.AssertNumberOfCalls(t, "DeleteOldLogs", 1)
+}
@@ -1,9 +1,13 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/ilramdhan/holidayapi/internal/config"
+	"github.com/ilramdhan/holidayapi/internal/metrics"
 	"github.com/ilramdhan/holidayapi/internal/models"
 	"github.com/ilramdhan/holidayapi/internal/repository"
 )
@@ -12,54 +16,96 @@ import (
 type UserRepository interface {
 	Create(user *models.User) error
 	GetByID(id int) (*models.User, error)
+	GetByIDIncludingInactive(id int) (*models.User, error)
 	GetByUsername(username string) (*models.User, error)
 	GetByEmail(email string) (*models.User, error)
 	Update(id int, user *models.User) error
 	Delete(id int) error
 	UpdateLastLogin(id int) error
 	GetAll() ([]models.User, error)
+	GetAllIncludingInactive() ([]models.User, error)
+	CountActiveByRole(role models.UserRole) (int, error)
 	HashPassword(password string) (string, error)
 	CheckPassword(hashedPassword, password string) error
 	ChangePassword(userID int, newPassword string) error
+	SetTemporaryPassword(userID int, password string) error
 }
 
 // AuthService handles authentication operations
 type AuthService interface {
-	Login(req models.LoginRequest, ipAddress, userAgent string) (*models.AuthResponse, error)
+	Login(req models.LoginRequest, ipAddress, userAgent string) (*models.AuthResponse, *models.MFAChallenge, error)
+	CompleteMFALogin(req models.MFALoginRequest, ipAddress, userAgent string) (*models.AuthResponse, error)
+	EnrollMFALogin(req models.MFATokenRequest, ipAddress, userAgent string) (*models.MFAEnrollment, error)
 	Register(req models.RegisterRequest, createdBy *models.User) (*models.User, error)
-	RefreshToken(req models.RefreshTokenRequest) (*models.AuthResponse, error)
+	RefreshToken(req models.RefreshTokenRequest, ipAddress, userAgent string) (*models.AuthResponse, error)
+	Logout(userID int, req models.LogoutRequest, ipAddress, userAgent string) error
+	RevokeUserSessions(userID int, revokedBy *models.User, ipAddress, userAgent string) (int64, error)
 	ChangePassword(userID int, req models.ChangePasswordRequest) error
 	GetUserProfile(userID int) (*models.UserResponse, error)
-	UpdateUserProfile(userID int, req models.UpdateUserRequest) (*models.UserResponse, error)
-	GetAllUsers() ([]models.UserResponse, error)
+	GetUser(userID int) (*models.UserResponse, error)
+	UpdateUserProfile(userID int, req models.UpdateUserRequest, updatedBy *models.User, ipAddress, userAgent string) (*models.UserResponse, error)
+	ReactivateUser(userID int, reactivatedBy *models.User, ipAddress, userAgent string) (*models.UserResponse, error)
+	ResetUserPassword(userID int, req models.AdminResetPasswordRequest, resetBy *models.User, ipAddress, userAgent string) error
+	GetAllUsers(includeInactive bool) ([]models.UserResponse, error)
 	DeleteUser(userID int, deletedBy *models.User) error
+	PurgeExpiredTokens() (int64, error)
 }
 
 // authService implements AuthService
 type authService struct {
-	userRepo   UserRepository
-	auditRepo  repository.AuditRepository
-	jwtService JWTService
+	userRepo         UserRepository
+	auditRepo        repository.AuditRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	mfaRepo          repository.MFARepository
+	roleRepo         repository.RoleRepository
+	lockout          LoginLockoutService
+	jwtService       JWTService
+	mfaCfg           config.MFAConfig
 }
 
 // NewAuthService creates a new auth service
-func NewAuthService(userRepo UserRepository, auditRepo repository.AuditRepository, jwtService JWTService) AuthService {
+func NewAuthService(userRepo UserRepository, auditRepo repository.AuditRepository, refreshTokenRepo repository.RefreshTokenRepository, mfaRepo repository.MFARepository, roleRepo repository.RoleRepository, lockout LoginLockoutService, jwtService JWTService, mfaCfg config.MFAConfig) AuthService {
 	return &authService{
-		userRepo:   userRepo,
-		auditRepo:  auditRepo,
-		jwtService: jwtService,
+		userRepo:         userRepo,
+		auditRepo:        auditRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		mfaRepo:          mfaRepo,
+		roleRepo:         roleRepo,
+		lockout:          lockout,
+		jwtService:       jwtService,
+		mfaCfg:           mfaCfg,
 	}
 }
 
-// Login authenticates a user and returns tokens
-func (s *authService) Login(req models.LoginRequest, ipAddress, userAgent string) (*models.AuthResponse, error) {
+// Login checks a user's credentials. Users without MFA get tokens right away; users who
+// have MFA enabled, or whose role requires it, get an MFA challenge to answer with CompleteMFALogin.
+func (s *authService) Login(req models.LoginRequest, ipAddress, userAgent string) (*models.AuthResponse, *models.MFAChallenge, error) {
+	authResponse, challenge, err := s.login(req, ipAddress, userAgent)
+	// A login waiting for its second factor is counted when that step completes
+	if challenge == nil {
+		metrics.IncLogin(err == nil)
+	}
+	return authResponse, challenge, err
+}
+
+// login performs the credential checks for Login and issues either tokens or an MFA challenge
+func (s *authService) login(req models.LoginRequest, ipAddress, userAgent string) (*models.AuthResponse, *models.MFAChallenge, error) {
+	// Refuse locked usernames and IP addresses before looking the user up, so the
+	// response is the same whether or not the username exists
+	if err := s.lockout.Check(req.Username, ipAddress); err != nil {
+		s.logAudit(nil, req.Username, models.ActionLoginFailed, models.ResourceAuth,
+			"Login failed: locked out after too many failed attempts", ipAddress, userAgent, false)
+		return nil, nil, err
+	}
+
 	// Get user by username
 	user, err := s.userRepo.GetByUsername(req.Username)
 	if err != nil {
 		// Log failed login attempt
 		s.logAudit(nil, req.Username, models.ActionLoginFailed, models.ResourceAuth,
 			fmt.Sprintf("Login failed: user not found"), ipAddress, userAgent, false)
-		return nil, fmt.Errorf("invalid credentials")
+		s.lockout.RecordFailure(req.Username, ipAddress, userAgent)
+		return nil, nil, fmt.Errorf("invalid credentials")
 	}
 
 	// Check password
@@ -67,41 +113,135 @@ func (s *authService) Login(req models.LoginRequest, ipAddress, userAgent string
 		// Log failed login attempt
 		s.logAudit(&user.ID, user.Username, models.ActionLoginFailed, models.ResourceAuth,
 			fmt.Sprintf("Login failed: invalid password"), ipAddress, userAgent, false)
-		return nil, fmt.Errorf("invalid credentials")
+		s.lockout.RecordFailure(req.Username, ipAddress, userAgent)
+		return nil, nil, fmt.Errorf("invalid credentials")
 	}
 
 	// Check if user is active
 	if !user.IsActive {
 		s.logAudit(&user.ID, user.Username, models.ActionLoginFailed, models.ResourceAuth,
 			fmt.Sprintf("Login failed: account deactivated"), ipAddress, userAgent, false)
-		return nil, fmt.Errorf("account is deactivated")
+		return nil, nil, fmt.Errorf("account is deactivated")
 	}
 
-	// Generate tokens
-	authResponse, err := s.jwtService.GenerateTokens(user)
+	// Ask for the second factor before issuing tokens
+	challenge, err := s.mfaChallenge(user, ipAddress, userAgent)
 	if err != nil {
-		s.logAudit(&user.ID, user.Username, models.ActionLoginFailed, models.ResourceAuth,
-			fmt.Sprintf("Login failed: token generation error"), ipAddress, userAgent, false)
-		return nil, fmt.Errorf("failed to generate tokens: %w", err)
+		return nil, nil, err
+	}
+	if challenge != nil {
+		return nil, challenge, nil
 	}
 
-	// Update last login
-	if err := s.userRepo.UpdateLastLogin(user.ID); err != nil {
-		// Log but don't fail the login
-		fmt.Printf("Failed to update last login for user %d: %v\n", user.ID, err)
+	authResponse, err := s.completeLogin(user, ipAddress, userAgent)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Log successful login
-	s.logAudit(&user.ID, user.Username, models.ActionLogin, models.ResourceAuth,
-		"User logged in successfully", ipAddress, userAgent, true)
+	return authResponse, nil, nil
+}
 
-	return authResponse, nil
+// CompleteMFALogin answers the MFA challenge of a login with an authenticator code or a
+// recovery code and issues tokens. When the challenge required enrollment, the code confirms
+// the secret from EnrollMFALogin and the response carries the new recovery codes.
+func (s *authService) CompleteMFALogin(req models.MFALoginRequest, ipAddress, userAgent string) (*models.AuthResponse, error) {
+	authResponse, err := s.completeMFALogin(req, ipAddress, userAgent)
+	metrics.IncLogin(err == nil)
+	return authResponse, err
+}
+
+// completeMFALogin performs the second factor checks for CompleteMFALogin
+func (s *authService) completeMFALogin(req models.MFALoginRequest, ipAddress, userAgent string) (*models.AuthResponse, error) {
+	user, err := s.mfaLoginUser(req.MFAToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// Wrong codes count as failed logins, so a stolen password cannot be used to guess them
+	if err := s.lockout.Check(user.Username, ipAddress); err != nil {
+		s.logAudit(&user.ID, user.Username, models.ActionMFAVerify, models.ResourceAuth,
+			"MFA verification failed: locked out after too many failed attempts", ipAddress, userAgent, false)
+		return nil, err
+	}
+
+	mfa, err := s.mfaRepo.Get(user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("mfa enrollment required")
+	}
+
+	if !mfa.Enabled {
+		codes, err := confirmMFAEnrollment(s.mfaRepo, mfa, req.Code)
+		if err != nil {
+			s.logAudit(&user.ID, user.Username, models.ActionMFAEnable, models.ResourceAuth,
+				fmt.Sprintf("MFA activation during login failed: %v", err), ipAddress, userAgent, false)
+			s.lockout.RecordFailure(user.Username, ipAddress, userAgent)
+			return nil, err
+		}
+
+		s.logAudit(&user.ID, user.Username, models.ActionMFAEnable, models.ResourceAuth,
+			"MFA enabled during login", ipAddress, userAgent, true)
+
+		authResponse, err := s.completeLogin(user, ipAddress, userAgent)
+		if err != nil {
+			return nil, err
+		}
+
+		authResponse.RecoveryCodes = codes
+		return authResponse, nil
+	}
+
+	recovery, err := verifyMFACode(s.mfaRepo, mfa, req.Code)
+	if err != nil {
+		s.logAudit(&user.ID, user.Username, models.ActionMFAVerify, models.ResourceAuth,
+			fmt.Sprintf("MFA verification failed: %v", err), ipAddress, userAgent, false)
+		s.lockout.RecordFailure(user.Username, ipAddress, userAgent)
+		return nil, err
+	}
+
+	if recovery {
+		remaining, err := s.mfaRepo.CountRecoveryCodes(user.ID)
+		if err != nil {
+			fmt.Printf("Failed to count recovery codes for user %d: %v\n", user.ID, err)
+		}
+		s.logAudit(&user.ID, user.Username, models.ActionMFARecoveryCodeUsed, models.ResourceAuth,
+			fmt.Sprintf("Recovery code used, %d remaining", remaining), ipAddress, userAgent, true)
+	} else {
+		s.logAudit(&user.ID, user.Username, models.ActionMFAVerify, models.ResourceAuth,
+			"MFA code verified", ipAddress, userAgent, true)
+	}
+
+	return s.completeLogin(user, ipAddress, userAgent)
+}
+
+// EnrollMFALogin generates a secret for a user whose role requires MFA but who has not
+// set it up yet, so the pending login can be completed with a code from it
+func (s *authService) EnrollMFALogin(req models.MFATokenRequest, ipAddress, userAgent string) (*models.MFAEnrollment, error) {
+	user, err := s.mfaLoginUser(req.MFAToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.mfaCfg.RequiredFor(string(user.Role)) {
+		return nil, fmt.Errorf("mfa enrollment is not required for role %s", user.Role)
+	}
+
+	enrollment, err := startMFAEnrollment(s.mfaRepo, s.mfaCfg, user)
+	if err != nil {
+		s.logAudit(&user.ID, user.Username, models.ActionMFAEnroll, models.ResourceAuth,
+			fmt.Sprintf("MFA enrollment during login failed: %v", err), ipAddress, userAgent, false)
+		return nil, err
+	}
+
+	s.logAudit(&user.ID, user.Username, models.ActionMFAEnroll, models.ResourceAuth,
+		"MFA enrollment started during login", ipAddress, userAgent, true)
+
+	return enrollment, nil
 }
 
-// Register creates a new user (only super admin can register new users)
+// Register creates a new user with a role the creator may assign
 func (s *authService) Register(req models.RegisterRequest, createdBy *models.User) (*models.User, error) {
 	// Validate password strength
-	if err := s.validatePassword(req.Password); err != nil {
+	if err := validatePassword(req.Password); err != nil {
 		return nil, err
 	}
 
@@ -115,6 +255,10 @@ func (s *authService) Register(req models.RegisterRequest, createdBy *models.Use
 		return nil, fmt.Errorf("email already exists")
 	}
 
+	if err := s.ensureAssignable(createdBy, req.Role); err != nil {
+		return nil, err
+	}
+
 	// Create user
 	user := &models.User{
 		Username: req.Username,
@@ -136,20 +280,117 @@ func (s *authService) Register(req models.RegisterRequest, createdBy *models.Use
 	return user, nil
 }
 
-// RefreshToken generates new tokens using refresh token
-func (s *authService) RefreshToken(req models.RefreshTokenRequest) (*models.AuthResponse, error) {
-	authResponse, err := s.jwtService.RefreshTokens(req.RefreshToken, s.userRepo)
+// RefreshToken rotates a refresh token: the presented token is revoked and a new pair is issued.
+// Presenting a token that was already rotated revokes its whole family, since it
+// means the token was copied and either the client or an attacker is replaying it.
+func (s *authService) RefreshToken(req models.RefreshTokenRequest, ipAddress, userAgent string) (*models.AuthResponse, error) {
+	claims, err := s.jwtService.ValidateRefreshToken(req.RefreshToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh token: invalid refresh token: %w", err)
+	}
+
+	stored, err := s.refreshTokenRepo.GetByHash(hashToken(req.RefreshToken))
+	if err != nil || stored.UserID != claims.UserID {
+		s.logAudit(&claims.UserID, claims.Username, models.ActionTokenRefresh, models.ResourceAuth,
+			"Token refresh failed: refresh token not recognized", ipAddress, userAgent, false)
+		return nil, fmt.Errorf("failed to refresh token: refresh token not recognized")
+	}
+
+	if stored.RevokedAt != nil {
+		s.revokeReusedFamily(stored, claims, ipAddress, userAgent)
+		return nil, fmt.Errorf("failed to refresh token: refresh token has been revoked")
+	}
+
+	// Fetch fresh user data from database
+	user, err := s.userRepo.GetByID(claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh token: user not found: %w", err)
+	}
+
+	// Check if user is still active
+	if !user.IsActive {
+		return nil, fmt.Errorf("failed to refresh token: user account is deactivated")
+	}
+
+	// Reload permissions so role changes take effect on the next refresh
+	user.Permissions, err = rolePermissions(s.roleRepo, user.Role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh token: %w", err)
+	}
+
+	authResponse, err := s.jwtService.GenerateTokens(user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh token: %w", err)
+	}
+
+	next := newRefreshTokenRecord(user.ID, stored.FamilyID, authResponse)
+	rotated, err := s.refreshTokenRepo.Rotate(stored.ID, next)
 	if err != nil {
 		return nil, fmt.Errorf("failed to refresh token: %w", err)
 	}
+	if !rotated {
+		// Another request rotated the same token first
+		s.revokeReusedFamily(stored, claims, ipAddress, userAgent)
+		return nil, fmt.Errorf("failed to refresh token: refresh token has been revoked")
+	}
 
 	// Log token refresh
-	s.logAudit(nil, authResponse.User.Username, models.ActionTokenRefresh, models.ResourceAuth,
-		"Token refreshed successfully", "", "", true)
+	s.logAudit(&user.ID, user.Username, models.ActionTokenRefresh, models.ResourceAuth,
+		"Token refreshed successfully", ipAddress, userAgent, true)
 
 	return authResponse, nil
 }
 
+// Logout revokes the session the given refresh token belongs to
+func (s *authService) Logout(userID int, req models.LogoutRequest, ipAddress, userAgent string) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+
+	stored, err := s.refreshTokenRepo.GetByHash(hashToken(req.RefreshToken))
+	if err != nil || stored.UserID != userID {
+		s.logAudit(&userID, user.Username, models.ActionLogout, models.ResourceAuth,
+			"Logout failed: refresh token not recognized", ipAddress, userAgent, false)
+		return fmt.Errorf("refresh token not recognized")
+	}
+
+	if err := s.refreshTokenRepo.RevokeFamily(stored.FamilyID); err != nil {
+		return fmt.Errorf("failed to logout: %w", err)
+	}
+
+	s.logAudit(&userID, user.Username, models.ActionLogout, models.ResourceAuth,
+		"User logged out", ipAddress, userAgent, true)
+
+	return nil
+}
+
+// RevokeUserSessions revokes every refresh token of a user, signing them out everywhere
+func (s *authService) RevokeUserSessions(userID int, revokedBy *models.User, ipAddress, userAgent string) (int64, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return 0, fmt.Errorf("user not found: %w", err)
+	}
+
+	if err := ensureManageable(s.roleRepo, revokedBy, user); err != nil {
+		s.logAudit(&revokedBy.ID, revokedBy.Username, models.ActionLogout, models.ResourceUser,
+			fmt.Sprintf("Revoking sessions of user %s refused: %v", user.Username, err), ipAddress, userAgent, false)
+		return 0, err
+	}
+
+	revoked, err := s.refreshTokenRepo.RevokeAllForUser(userID)
+	if err != nil {
+		s.logAudit(&revokedBy.ID, revokedBy.Username, models.ActionLogout, models.ResourceUser,
+			fmt.Sprintf("Failed to revoke sessions of user: %s", user.Username), ipAddress, userAgent, false)
+		return 0, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	s.logAudit(&revokedBy.ID, revokedBy.Username, models.ActionLogout, models.ResourceUser,
+		fmt.Sprintf("Revoked all sessions of user: %s (%d refresh tokens)", user.Username, revoked), ipAddress, userAgent, true)
+
+	return revoked, nil
+}
+
 // ChangePassword changes user's password
 func (s *authService) ChangePassword(userID int, req models.ChangePasswordRequest) error {
 	// Get user
@@ -166,10 +407,15 @@ func (s *authService) ChangePassword(userID int, req models.ChangePasswordReques
 	}
 
 	// Validate new password
-	if err := s.validatePassword(req.NewPassword); err != nil {
+	if err := validatePassword(req.NewPassword); err != nil {
 		return err
 	}
 
+	// A temporary password set by an administrator has to be replaced by one only the user knows
+	if user.MustChangePassword && s.userRepo.CheckPassword(user.Password, req.NewPassword) == nil {
+		return fmt.Errorf("new password must differ from the temporary password")
+	}
+
 	// Change password
 	if err := s.userRepo.ChangePassword(userID, req.NewPassword); err != nil {
 		s.logAudit(&userID, user.Username, models.ActionPasswordChange, models.ResourceUser,
@@ -191,41 +437,156 @@ func (s *authService) GetUserProfile(userID int) (*models.UserResponse, error) {
 		return nil, fmt.Errorf("user not found: %w", err)
 	}
 
+	user.Permissions, err = rolePermissions(s.roleRepo, user.Role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get permissions: %w", err)
+	}
+
 	return user.ToUserResponse(), nil
 }
 
-// UpdateUserProfile updates user profile
-func (s *authService) UpdateUserProfile(userID int, req models.UpdateUserRequest) (*models.UserResponse, error) {
-	user, err := s.userRepo.GetByID(userID)
+// GetUser gets any user by ID, including deactivated accounts
+func (s *authService) GetUser(userID int) (*models.UserResponse, error) {
+	user, err := s.userRepo.GetByIDIncludingInactive(userID)
 	if err != nil {
 		return nil, fmt.Errorf("user not found: %w", err)
 	}
 
-	// Update fields if provided
-	if req.Email != nil {
-		user.Email = *req.Email
+	return user.ToUserResponse(), nil
+}
+
+// UpdateUserProfile changes a user's email, role or active state.
+// It refuses to demote or deactivate the last active super admin.
+func (s *authService) UpdateUserProfile(userID int, req models.UpdateUserRequest, updatedBy *models.User, ipAddress, userAgent string) (*models.UserResponse, error) {
+	user, err := s.userRepo.GetByIDIncludingInactive(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	if err := ensureManageable(s.roleRepo, updatedBy, user); err != nil {
+		s.logAudit(&updatedBy.ID, updatedBy.Username, models.ActionUserUpdate, models.ResourceUser,
+			fmt.Sprintf("Update of user %s refused: %v", user.Username, err), ipAddress, userAgent, false)
+		return nil, err
+	}
+
+	// Update fields if provided, describing each change for the audit log
+	updated := *user
+	var changes []string
+	if req.Email != nil && *req.Email != user.Email {
+		if existing, err := s.userRepo.GetByEmail(*req.Email); err == nil && existing.ID != userID {
+			return nil, fmt.Errorf("email already exists")
+		}
+		updated.Email = *req.Email
+		changes = append(changes, fmt.Sprintf("email %s -> %s", user.Email, updated.Email))
+	}
+	if req.Role != nil && *req.Role != user.Role {
+		if err := s.ensureAssignable(updatedBy, *req.Role); err != nil {
+			s.logAudit(&updatedBy.ID, updatedBy.Username, models.ActionUserUpdate, models.ResourceUser,
+				fmt.Sprintf("Update of user %s refused: %v", user.Username, err), ipAddress, userAgent, false)
+			return nil, err
+		}
+		updated.Role = *req.Role
+		changes = append(changes, fmt.Sprintf("role %s -> %s", user.Role, updated.Role))
+	}
+	if req.IsActive != nil && *req.IsActive != user.IsActive {
+		updated.IsActive = *req.IsActive
+		changes = append(changes, fmt.Sprintf("is_active %t -> %t", user.IsActive, updated.IsActive))
 	}
-	if req.Role != nil {
-		user.Role = *req.Role
+
+	if len(changes) == 0 {
+		return user.ToUserResponse(), nil
 	}
-	if req.IsActive != nil {
-		user.IsActive = *req.IsActive
+
+	if updated.Role != models.SuperAdminRole || !updated.IsActive {
+		if err := s.ensureOtherSuperAdmin(user); err != nil {
+			s.logAudit(&updatedBy.ID, updatedBy.Username, models.ActionUserUpdate, models.ResourceUser,
+				fmt.Sprintf("Update of user %s refused: %v", user.Username, err), ipAddress, userAgent, false)
+			return nil, err
+		}
 	}
 
-	if err := s.userRepo.Update(userID, user); err != nil {
+	if err := s.userRepo.Update(userID, &updated); err != nil {
 		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
 
 	// Log user update
-	s.logAudit(&userID, user.Username, models.ActionUserUpdate, models.ResourceUser,
-		"User profile updated", "", "", true)
+	s.logAudit(&updatedBy.ID, updatedBy.Username, models.ActionUserUpdate, models.ResourceUser,
+		fmt.Sprintf("Updated user: %s (%s)", user.Username, strings.Join(changes, ", ")), ipAddress, userAgent, true)
+
+	return updated.ToUserResponse(), nil
+}
+
+// ReactivateUser restores a deactivated user account
+func (s *authService) ReactivateUser(userID int, reactivatedBy *models.User, ipAddress, userAgent string) (*models.UserResponse, error) {
+	user, err := s.userRepo.GetByIDIncludingInactive(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	if user.IsActive {
+		return nil, fmt.Errorf("user is already active")
+	}
+
+	if err := ensureManageable(s.roleRepo, reactivatedBy, user); err != nil {
+		s.logAudit(&reactivatedBy.ID, reactivatedBy.Username, models.ActionUserUpdate, models.ResourceUser,
+			fmt.Sprintf("Reactivation of user %s refused: %v", user.Username, err), ipAddress, userAgent, false)
+		return nil, err
+	}
+
+	user.IsActive = true
+	if err := s.userRepo.Update(userID, user); err != nil {
+		return nil, fmt.Errorf("failed to reactivate user: %w", err)
+	}
+
+	s.logAudit(&reactivatedBy.ID, reactivatedBy.Username, models.ActionUserUpdate, models.ResourceUser,
+		fmt.Sprintf("Reactivated user: %s", user.Username), ipAddress, userAgent, true)
 
 	return user.ToUserResponse(), nil
 }
 
-// GetAllUsers gets all users (admin only)
-func (s *authService) GetAllUsers() ([]models.UserResponse, error) {
-	users, err := s.userRepo.GetAll()
+// ResetUserPassword sets a temporary password for a user. The user must
+// replace it before the API accepts their tokens again, and all their sessions are revoked.
+func (s *authService) ResetUserPassword(userID int, req models.AdminResetPasswordRequest, resetBy *models.User, ipAddress, userAgent string) error {
+	if err := validatePassword(req.TemporaryPassword); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+
+	if err := ensureManageable(s.roleRepo, resetBy, user); err != nil {
+		s.logAudit(&resetBy.ID, resetBy.Username, models.ActionUserUpdate, models.ResourceUser,
+			fmt.Sprintf("Password reset of user %s refused: %v", user.Username, err), ipAddress, userAgent, false)
+		return err
+	}
+
+	if err := s.userRepo.SetTemporaryPassword(userID, req.TemporaryPassword); err != nil {
+		s.logAudit(&resetBy.ID, resetBy.Username, models.ActionUserUpdate, models.ResourceUser,
+			fmt.Sprintf("Password reset of user %s failed: database error", user.Username), ipAddress, userAgent, false)
+		return fmt.Errorf("failed to reset password: %w", err)
+	}
+
+	revoked, err := s.refreshTokenRepo.RevokeAllForUser(userID)
+	if err != nil {
+		fmt.Printf("Failed to revoke sessions of user %d: %v\n", userID, err)
+	}
+
+	s.logAudit(&resetBy.ID, resetBy.Username, models.ActionUserUpdate, models.ResourceUser,
+		fmt.Sprintf("Reset password of user: %s, change required at next login, %d sessions revoked", user.Username, revoked), ipAddress, userAgent, true)
+
+	return nil
+}
+
+// GetAllUsers gets all active users, or also the deactivated ones when includeInactive is set (admin only)
+func (s *authService) GetAllUsers(includeInactive bool) ([]models.UserResponse, error) {
+	getAll := s.userRepo.GetAll
+	if includeInactive {
+		getAll = s.userRepo.GetAllIncludingInactive
+	}
+
+	users, err := getAll()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get users: %w", err)
 	}
@@ -245,6 +606,18 @@ func (s *authService) DeleteUser(userID int, deletedBy *models.User) error {
 		return fmt.Errorf("user not found: %w", err)
 	}
 
+	if err := ensureManageable(s.roleRepo, deletedBy, user); err != nil {
+		s.logAudit(&deletedBy.ID, deletedBy.Username, models.ActionUserDelete, models.ResourceUser,
+			fmt.Sprintf("Deletion of user %s refused: %v", user.Username, err), "", "", false)
+		return err
+	}
+
+	if err := s.ensureOtherSuperAdmin(user); err != nil {
+		s.logAudit(&deletedBy.ID, deletedBy.Username, models.ActionUserDelete, models.ResourceUser,
+			fmt.Sprintf("Deletion of user %s refused: %v", user.Username, err), "", "", false)
+		return err
+	}
+
 	if err := s.userRepo.Delete(userID); err != nil {
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
@@ -256,8 +629,171 @@ func (s *authService) DeleteUser(userID int, deletedBy *models.User) error {
 	return nil
 }
 
+// ensureAssignable checks that a role exists and grants nothing the assigning user lacks,
+// so holding user:manage does not allow promoting anyone, oneself included, above one's own role
+func (s *authService) ensureAssignable(assignedBy *models.User, role models.UserRole) error {
+	permissions, err := rolePermissions(s.roleRepo, role)
+	if err != nil {
+		return fmt.Errorf("role %s does not exist", role)
+	}
+
+	return ensureGrantable(s.roleRepo, assignedBy, permissions)
+}
+
+// ensureOtherSuperAdmin refuses to take away the role or account of the last active super admin,
+// which would leave nobody able to manage users
+func (s *authService) ensureOtherSuperAdmin(user *models.User) error {
+	if user.Role != models.SuperAdminRole || !user.IsActive {
+		return nil
+	}
+
+	count, err := s.userRepo.CountActiveByRole(models.SuperAdminRole)
+	if err != nil {
+		return fmt.Errorf("failed to count super admins: %w", err)
+	}
+	if count <= 1 {
+		return fmt.Errorf("cannot demote or deactivate the last active super admin")
+	}
+
+	return nil
+}
+
+// PurgeExpiredTokens deletes refresh tokens that can no longer be used and returns how many were deleted
+func (s *authService) PurgeExpiredTokens() (int64, error) {
+	deleted, err := s.refreshTokenRepo.DeleteExpired(time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge expired refresh tokens: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// completeLogin starts a new session for a user who passed every login check
+func (s *authService) completeLogin(user *models.User, ipAddress, userAgent string) (*models.AuthResponse, error) {
+	// Generate tokens, starting a new refresh token family for this session
+	familyID, err := generateRandomID(16)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate tokens: %w", err)
+	}
+
+	authResponse, err := s.issueTokens(user, familyID)
+	if err != nil {
+		s.logAudit(&user.ID, user.Username, models.ActionLoginFailed, models.ResourceAuth,
+			fmt.Sprintf("Login failed: token generation error"), ipAddress, userAgent, false)
+		return nil, fmt.Errorf("failed to generate tokens: %w", err)
+	}
+
+	// Update last login
+	if err := s.userRepo.UpdateLastLogin(user.ID); err != nil {
+		// Log but don't fail the login
+		fmt.Printf("Failed to update last login for user %d: %v\n", user.ID, err)
+	}
+	s.lockout.RecordSuccess(user.Username, ipAddress)
+
+	// Log successful login
+	s.logAudit(&user.ID, user.Username, models.ActionLogin, models.ResourceAuth,
+		"User logged in successfully", ipAddress, userAgent, true)
+
+	return authResponse, nil
+}
+
+// mfaChallenge returns the challenge a login must answer before tokens are issued, or nil
+// when the user has no MFA enabled and their role does not require it
+func (s *authService) mfaChallenge(user *models.User, ipAddress, userAgent string) (*models.MFAChallenge, error) {
+	enabled := false
+	mfa, err := s.mfaRepo.Get(user.ID)
+	if err == nil {
+		enabled = mfa.Enabled
+	} else if !errors.Is(err, repository.ErrMFANotFound) {
+		return nil, fmt.Errorf("failed to check mfa: %w", err)
+	}
+
+	if !enabled && !s.mfaCfg.RequiredFor(string(user.Role)) {
+		return nil, nil
+	}
+
+	token, err := s.jwtService.GenerateMFAToken(user, s.mfaCfg.ChallengeTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	details := "MFA code requested"
+	if !enabled {
+		details = fmt.Sprintf("MFA enrollment required for role %s", user.Role)
+	}
+	s.logAudit(&user.ID, user.Username, models.ActionMFAChallenge, models.ResourceAuth,
+		details, ipAddress, userAgent, true)
+
+	return &models.MFAChallenge{
+		MFARequired:        true,
+		MFAToken:           token,
+		EnrollmentRequired: !enabled,
+		ExpiresIn:          int64(s.mfaCfg.ChallengeTTL.Seconds()),
+	}, nil
+}
+
+// mfaLoginUser returns the still active user an MFA token was issued to
+func (s *authService) mfaLoginUser(mfaToken string) (*models.User, error) {
+	claims, err := s.jwtService.ValidateMFAToken(mfaToken)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mfa token: %w", err)
+	}
+
+	user, err := s.userRepo.GetByID(claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	if !user.IsActive {
+		return nil, fmt.Errorf("account is deactivated")
+	}
+
+	return user, nil
+}
+
+// issueTokens generates a token pair and records the refresh token in the given family
+func (s *authService) issueTokens(user *models.User, familyID string) (*models.AuthResponse, error) {
+	// Permissions are read from the role on every issue, so a refresh picks up role changes
+	permissions, err := rolePermissions(s.roleRepo, user.Role)
+	if err != nil {
+		return nil, err
+	}
+	user.Permissions = permissions
+
+	authResponse, err := s.jwtService.GenerateTokens(user)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.refreshTokenRepo.Create(newRefreshTokenRecord(user.ID, familyID, authResponse)); err != nil {
+		return nil, err
+	}
+
+	return authResponse, nil
+}
+
+// revokeReusedFamily revokes a token family after one of its rotated tokens was replayed
+func (s *authService) revokeReusedFamily(stored *models.RefreshToken, claims *models.JWTClaims, ipAddress, userAgent string) {
+	if err := s.refreshTokenRepo.RevokeFamily(stored.FamilyID); err != nil {
+		fmt.Printf("Failed to revoke refresh token family %s: %v\n", stored.FamilyID, err)
+	}
+
+	s.logAudit(&claims.UserID, claims.Username, models.ActionTokenRefresh, models.ResourceAuth,
+		"Token refresh failed: reuse of a revoked refresh token detected, session revoked", ipAddress, userAgent, false)
+}
+
+// newRefreshTokenRecord builds the stored record for the refresh token in an auth response
+func newRefreshTokenRecord(userID int, familyID string, authResponse *models.AuthResponse) *models.RefreshToken {
+	return &models.RefreshToken{
+		UserID:    userID,
+		TokenHash: hashToken(authResponse.RefreshToken),
+		FamilyID:  familyID,
+		ExpiresAt: time.Now().Add(time.Duration(authResponse.RefreshExpiresIn) * time.Second),
+	}
+}
+
 // validatePassword validates password strength
-func (s *authService) validatePassword(password string) error {
+func validatePassword(password string) error {
 	if len(password) < 8 {
 		return fmt.Errorf("password must be at least 8 characters long")
 	}
@@ -0,0 +1,570 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ilramdhan/holidayapi/internal/config"
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/repository"
+)
+
+// MockUserRepository is a mock implementation of UserRepository
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) Create(user *models.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetByID(id int) (*models.User, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByIDIncludingInactive(id int) (*models.User, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByUsername(username string) (*models.User, error) {
+	args := m.Called(username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByEmail(email string) (*models.User, error) {
+	args := m.Called(email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) Update(id int, user *models.User) error {
+	args := m.Called(id, user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Delete(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateLastLogin(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetAll() ([]models.User, error) {
+	args := m.Called()
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetAllIncludingInactive() ([]models.User, error) {
+	args := m.Called()
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) CountActiveByRole(role models.UserRole) (int, error) {
+	args := m.Called(role)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockUserRepository) HashPassword(password string) (string, error) {
+	args := m.Called(password)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockUserRepository) CheckPassword(hashedPassword, password string) error {
+	args := m.Called(hashedPassword, password)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) ChangePassword(userID int, newPassword string) error {
+	args := m.Called(userID, newPassword)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) SetTemporaryPassword(userID int, password string) error {
+	args := m.Called(userID, password)
+	return args.Error(0)
+}
+
+// MockAuditRepository is a mock implementation of AuditRepository
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) Create(log *models.AuditLog) error {
+	args := m.Called(log)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) GetAll(filter models.AuditLogFilter) ([]models.AuditLog, int, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]models.AuditLog), args.Int(1), args.Error(2)
+}
+
+func (m *MockAuditRepository) GetByUserID(userID int, limit, offset int) ([]models.AuditLog, error) {
+	args := m.Called(userID, limit, offset)
+	return args.Get(0).([]models.AuditLog), args.Error(1)
+}
+
+func (m *MockAuditRepository) DeleteOldLogs(olderThan time.Time) (int64, error) {
+	args := m.Called(olderThan)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAuditRepository) Walk(filter models.AuditLogFilter, fn func(log *models.AuditLog) error) error {
+	args := m.Called(filter, fn)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) GetChainCheckpoints() ([]models.AuditChainCheckpoint, error) {
+	args := m.Called()
+	return args.Get(0).([]models.AuditChainCheckpoint), args.Error(1)
+}
+
+// MockRefreshTokenRepository is a mock implementation of RefreshTokenRepository
+type MockRefreshTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockRefreshTokenRepository) Create(token *models.RefreshToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) GetByHash(tokenHash string) (*models.RefreshToken, error) {
+	args := m.Called(tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) Rotate(oldID int, next *models.RefreshToken) (bool, error) {
+	args := m.Called(oldID, next)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) RevokeFamily(familyID string) error {
+	args := m.Called(familyID)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) RevokeAllForUser(userID int) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) DeleteExpired(before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockMFARepository is a mock implementation of MFARepository
+type MockMFARepository struct {
+	mock.Mock
+}
+
+func (m *MockMFARepository) Get(userID int) (*models.UserMFA, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserMFA), args.Error(1)
+}
+
+func (m *MockMFARepository) SavePending(mfa *models.UserMFA) error {
+	args := m.Called(mfa)
+	return args.Error(0)
+}
+
+func (m *MockMFARepository) Enable(userID int, step int64, recoveryCodeHashes []string) (bool, error) {
+	args := m.Called(userID, step, recoveryCodeHashes)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMFARepository) UseStep(userID int, step int64) (bool, error) {
+	args := m.Called(userID, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMFARepository) UseRecoveryCode(userID int, codeHash string) (bool, error) {
+	args := m.Called(userID, codeHash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMFARepository) ReplaceRecoveryCodes(userID int, codeHashes []string) error {
+	args := m.Called(userID, codeHashes)
+	return args.Error(0)
+}
+
+func (m *MockMFARepository) CountRecoveryCodes(userID int) (int, error) {
+	args := m.Called(userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockMFARepository) Delete(userID int) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+// newTestAuthService wires an auth service with mocks and a real JWT service for users without MFA
+func newTestAuthService() (AuthService, JWTService, *MockUserRepository, *MockRefreshTokenRepository, *MockAuditRepository) {
+	mfaRepo := new(MockMFARepository)
+	mfaRepo.On("Get", mock.Anything).Return(nil, repository.ErrMFANotFound)
+
+	return newTestAuthServiceWithMFA(mfaRepo, config.MFAConfig{})
+}
+
+// newTestAuthServiceWithMFA wires an auth service with mocks, the given MFA repository and MFA configuration
+func newTestAuthServiceWithMFA(mfaRepo *MockMFARepository, mfaCfg config.MFAConfig) (AuthService, JWTService, *MockUserRepository, *MockRefreshTokenRepository, *MockAuditRepository) {
+	userRepo := new(MockUserRepository)
+	auditRepo := new(MockAuditRepository)
+	tokenRepo := new(MockRefreshTokenRepository)
+	jwtService := NewJWTService("test-secret", 15*time.Minute, 24*time.Hour)
+
+	auditRepo.On("Create", mock.Anything).Return(nil)
+
+	// Lockouts are disabled, so the lockout repository is never used
+	lockout := NewLoginLockoutService(new(MockLoginLockoutRepository), auditRepo, config.LockoutConfig{})
+
+	return NewAuthService(userRepo, auditRepo, tokenRepo, mfaRepo, newTestRoleRepository(), lockout, jwtService, mfaCfg), jwtService, userRepo, tokenRepo, auditRepo
+}
+
+// auditActions returns the actions and outcomes recorded on the audit mock
+func auditActions(auditRepo *MockAuditRepository) []string {
+	var actions []string
+	for _, call := range auditRepo.Calls {
+		log := call.Arguments.Get(0).(*models.AuditLog)
+		actions = append(actions, fmt.Sprintf("%s:%t", log.Action, log.Success))
+	}
+	return actions
+}
+
+func TestAuthService_LoginStoresRefreshToken(t *testing.T) {
+	service, _, userRepo, tokenRepo, _ := newTestAuthService()
+
+	user := &models.User{ID: 1, Username: "admin", Password: "hash", Role: models.SuperAdminRole, IsActive: true}
+	userRepo.On("GetByUsername", "admin").Return(user, nil)
+	userRepo.On("CheckPassword", "hash", "secret").Return(nil)
+	userRepo.On("UpdateLastLogin", 1).Return(nil)
+	tokenRepo.On("Create", mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+
+	resp, challenge, err := service.Login(models.LoginRequest{Username: "admin", Password: "secret"}, "127.0.0.1", "test")
+
+	assert.NoError(t, err)
+	assert.Nil(t, challenge)
+	stored := tokenRepo.Calls[0].Arguments.Get(0).(*models.RefreshToken)
+	assert.Equal(t, 1, stored.UserID)
+	assert.Equal(t, hashToken(resp.RefreshToken), stored.TokenHash)
+	assert.NotEqual(t, resp.RefreshToken, stored.TokenHash)
+	assert.NotEmpty(t, stored.FamilyID)
+	assert.True(t, stored.ExpiresAt.After(time.Now().Add(23*time.Hour)))
+}
+
+func TestAuthService_RefreshTokenRotates(t *testing.T) {
+	service, jwtService, userRepo, tokenRepo, _ := newTestAuthService()
+
+	// The token was issued before the user's role granted any permissions
+	user := &models.User{ID: 1, Username: "editor", Role: models.AdminRole, IsActive: true}
+	issued, err := jwtService.GenerateTokens(user)
+	assert.NoError(t, err)
+
+	stored := &models.RefreshToken{ID: 10, UserID: 1, TokenHash: hashToken(issued.RefreshToken), FamilyID: "family-1"}
+	tokenRepo.On("GetByHash", hashToken(issued.RefreshToken)).Return(stored, nil)
+	userRepo.On("GetByID", 1).Return(user, nil)
+	tokenRepo.On("Rotate", 10, mock.AnythingOfType("*models.RefreshToken")).Return(true, nil)
+
+	resp, err := service.RefreshToken(models.RefreshTokenRequest{RefreshToken: issued.RefreshToken}, "127.0.0.1", "test")
+
+	assert.NoError(t, err)
+	assert.NotEqual(t, issued.RefreshToken, resp.RefreshToken)
+	next := tokenRepo.Calls[1].Arguments.Get(1).(*models.RefreshToken)
+	assert.Equal(t, "family-1", next.FamilyID)
+	assert.Equal(t, hashToken(resp.RefreshToken), next.TokenHash)
+	tokenRepo.AssertNotCalled(t, "RevokeFamily", mock.Anything)
+
+	claims, err := jwtService.ValidateAccessToken(resp.AccessToken)
+	assert.NoError(t, err)
+	assert.Contains(t, claims.Permissions, models.PermissionHolidayDelete)
+	assert.NotContains(t, claims.Permissions, models.PermissionUserManage)
+}
+
+func TestAuthService_RefreshTokenReuseRevokesFamily(t *testing.T) {
+	tests := []struct {
+		name    string
+		revoked bool
+		rotated bool
+	}{
+		{name: "token already rotated", revoked: true},
+		{name: "concurrent rotation wins the race", revoked: false, rotated: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, jwtService, userRepo, tokenRepo, auditRepo := newTestAuthService()
+
+			user := &models.User{ID: 1, Username: "admin", Role: models.SuperAdminRole, IsActive: true}
+			issued, err := jwtService.GenerateTokens(user)
+			assert.NoError(t, err)
+
+			stored := &models.RefreshToken{ID: 10, UserID: 1, FamilyID: "family-1"}
+			if tt.revoked {
+				revokedAt := time.Now().Add(-time.Minute)
+				stored.RevokedAt = &revokedAt
+			}
+			tokenRepo.On("GetByHash", hashToken(issued.RefreshToken)).Return(stored, nil)
+			userRepo.On("GetByID", 1).Return(user, nil)
+			tokenRepo.On("Rotate", 10, mock.Anything).Return(tt.rotated, nil)
+			tokenRepo.On("RevokeFamily", "family-1").Return(nil)
+
+			resp, err := service.RefreshToken(models.RefreshTokenRequest{RefreshToken: issued.RefreshToken}, "127.0.0.1", "test")
+
+			assert.Error(t, err)
+			assert.Nil(t, resp)
+			tokenRepo.AssertCalled(t, "RevokeFamily", "family-1")
+			assert.Equal(t, []string{"TOKEN_REFRESH:false"}, auditActions(auditRepo))
+		})
+	}
+}
+
+func TestAuthService_RefreshTokenUnknown(t *testing.T) {
+	service, jwtService, _, tokenRepo, _ := newTestAuthService()
+
+	// Signed correctly but never persisted, e.g. issued before tokens were stored
+	issued, err := jwtService.GenerateTokens(&models.User{ID: 1, Username: "admin", Role: models.AdminRole})
+	assert.NoError(t, err)
+	tokenRepo.On("GetByHash", mock.Anything).Return(nil, fmt.Errorf("refresh token not found"))
+
+	resp, err := service.RefreshToken(models.RefreshTokenRequest{RefreshToken: issued.RefreshToken}, "", "")
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	tokenRepo.AssertNotCalled(t, "Rotate", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	service, _, userRepo, tokenRepo, auditRepo := newTestAuthService()
+
+	userRepo.On("GetByID", 1).Return(&models.User{ID: 1, Username: "admin", IsActive: true}, nil)
+	tokenRepo.On("GetByHash", hashToken("mine")).Return(&models.RefreshToken{ID: 3, UserID: 1, FamilyID: "family-1"}, nil)
+	tokenRepo.On("GetByHash", hashToken("theirs")).Return(&models.RefreshToken{ID: 4, UserID: 2, FamilyID: "family-2"}, nil)
+	tokenRepo.On("RevokeFamily", "family-1").Return(nil)
+
+	// Another user's token cannot be revoked
+	err := service.Logout(1, models.LogoutRequest{RefreshToken: "theirs"}, "", "")
+	assert.Error(t, err)
+	tokenRepo.AssertNotCalled(t, "RevokeFamily", "family-2")
+
+	err = service.Logout(1, models.LogoutRequest{RefreshToken: "mine"}, "", "")
+	assert.NoError(t, err)
+	tokenRepo.AssertCalled(t, "RevokeFamily", "family-1")
+	assert.Equal(t, []string{"LOGOUT:false", "LOGOUT:true"}, auditActions(auditRepo))
+}
+
+func TestAuthService_RevokeUserSessions(t *testing.T) {
+	service, _, userRepo, tokenRepo, auditRepo := newTestAuthService()
+
+	userRepo.On("GetByID", 2).Return(&models.User{ID: 2, Username: "editor", Role: models.AdminRole, IsActive: true}, nil)
+	tokenRepo.On("RevokeAllForUser", 2).Return(int64(3), nil)
+
+	revoked, err := service.RevokeUserSessions(2, &models.User{ID: 1, Username: "admin", Role: models.SuperAdminRole}, "127.0.0.1", "test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), revoked)
+	log := auditRepo.Calls[0].Arguments.Get(0).(*models.AuditLog)
+	assert.Equal(t, models.ActionLogout, log.Action)
+	assert.Equal(t, "admin", log.Username)
+	assert.Contains(t, log.Details, "editor")
+}
+
+func TestAuthService_UpdateUserProfileKeepsSuperAdmin(t *testing.T) {
+	admin := models.AdminRole
+	inactive := false
+	otherEmail := "other@example.com"
+
+	tests := []struct {
+		name        string
+		req         models.UpdateUserRequest
+		superAdmins int
+		expectedErr string
+		expected    string
+	}{
+		{name: "demote last super admin", req: models.UpdateUserRequest{Role: &admin}, superAdmins: 1, expectedErr: "cannot demote or deactivate the last active super admin", expected: "USER_UPDATE:false"},
+		{name: "deactivate last super admin", req: models.UpdateUserRequest{IsActive: &inactive}, superAdmins: 1, expectedErr: "cannot demote or deactivate the last active super admin", expected: "USER_UPDATE:false"},
+		{name: "demote one of two super admins", req: models.UpdateUserRequest{Role: &admin}, superAdmins: 2, expected: "USER_UPDATE:true"},
+		{name: "change email of last super admin", req: models.UpdateUserRequest{Email: &otherEmail}, superAdmins: 1, expected: "USER_UPDATE:true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _, userRepo, _, auditRepo := newTestAuthService()
+
+			userRepo.On("GetByIDIncludingInactive", 2).Return(&models.User{ID: 2, Username: "root", Email: "root@example.com", Role: models.SuperAdminRole, IsActive: true}, nil)
+			userRepo.On("GetByEmail", otherEmail).Return(nil, fmt.Errorf("user not found"))
+			userRepo.On("CountActiveByRole", models.SuperAdminRole).Return(tt.superAdmins, nil)
+			userRepo.On("Update", 2, mock.AnythingOfType("*models.User")).Return(nil)
+
+			user, err := service.UpdateUserProfile(2, tt.req, &models.User{ID: 1, Username: "admin", Role: models.SuperAdminRole}, "127.0.0.1", "test")
+
+			assert.Equal(t, []string{tt.expected}, auditActions(auditRepo))
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, user)
+			if tt.req.Email != nil {
+				userRepo.AssertNotCalled(t, "CountActiveByRole", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestAuthService_UpdateUserProfileRefusesEscalation(t *testing.T) {
+	service, _, userRepo, _, auditRepo := newTestAuthService()
+	superAdmin := models.SuperAdminRole
+	missing := models.UserRole("auditor")
+
+	userRepo.On("GetByIDIncludingInactive", 3).Return(&models.User{ID: 3, Username: "viewer", Role: models.AdminRole, IsActive: true}, nil)
+
+	// An admin holding user:manage still cannot hand out permissions beyond their own role
+	editor := &models.User{ID: 2, Username: "editor", Role: models.AdminRole}
+	_, err := service.UpdateUserProfile(3, models.UpdateUserRequest{Role: &superAdmin}, editor, "127.0.0.1", "test")
+	assert.EqualError(t, err, "cannot grant the audit:verify permission, which you do not have")
+
+	_, err = service.UpdateUserProfile(3, models.UpdateUserRequest{Role: &missing}, editor, "127.0.0.1", "test")
+	assert.EqualError(t, err, "role auditor does not exist")
+
+	userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	assert.Equal(t, []string{"USER_UPDATE:false", "USER_UPDATE:false"}, auditActions(auditRepo))
+}
+
+func TestAuthService_RefusesActingOnMorePrivilegedUser(t *testing.T) {
+	service, _, userRepo, tokenRepo, auditRepo := newTestAuthService()
+	otherEmail := "other@example.com"
+
+	root := &models.User{ID: 1, Username: "root", Email: "root@example.com", Role: models.SuperAdminRole, IsActive: true}
+	userRepo.On("GetByID", 1).Return(root, nil)
+	userRepo.On("GetByIDIncludingInactive", 1).Return(root, nil)
+
+	// An admin granted user:manage still cannot take over an account holding permissions they lack
+	editor := &models.User{ID: 2, Username: "editor", Role: models.AdminRole}
+	expectedErr := "cannot manage a user with permissions beyond your own: cannot grant the audit:verify permission, which you do not have"
+
+	err := service.ResetUserPassword(1, models.AdminResetPasswordRequest{TemporaryPassword: "TempPass123!"}, editor, "127.0.0.1", "test")
+	assert.EqualError(t, err, expectedErr)
+
+	_, err = service.UpdateUserProfile(1, models.UpdateUserRequest{Email: &otherEmail}, editor, "127.0.0.1", "test")
+	assert.EqualError(t, err, expectedErr)
+
+	_, err = service.RevokeUserSessions(1, editor, "127.0.0.1", "test")
+	assert.EqualError(t, err, expectedErr)
+
+	err = service.DeleteUser(1, editor)
+	assert.EqualError(t, err, expectedErr)
+
+	userRepo.AssertNotCalled(t, "SetTemporaryPassword", mock.Anything, mock.Anything)
+	userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	userRepo.AssertNotCalled(t, "Delete", mock.Anything)
+	tokenRepo.AssertNotCalled(t, "RevokeAllForUser", mock.Anything)
+	assert.Equal(t, []string{"USER_UPDATE:false", "USER_UPDATE:false", "LOGOUT:false", "USER_DELETE:false"}, auditActions(auditRepo))
+}
+
+func TestAuthService_GetAllUsers(t *testing.T) {
+	service, _, userRepo, _, _ := newTestAuthService()
+
+	admin := models.User{ID: 1, Username: "admin", Role: models.SuperAdminRole, IsActive: true}
+	deactivated := models.User{ID: 2, Username: "editor", Role: models.AdminRole}
+	userRepo.On("GetAll").Return([]models.User{admin}, nil)
+	userRepo.On("GetAllIncludingInactive").Return([]models.User{admin, deactivated}, nil)
+
+	users, err := service.GetAllUsers(false)
+	assert.NoError(t, err)
+	assert.Len(t, users, 1)
+
+	// Deactivated users are only listed when asked for, marked by is_active
+	users, err = service.GetAllUsers(true)
+	assert.NoError(t, err)
+	if assert.Len(t, users, 2) {
+		assert.True(t, users[0].IsActive)
+		assert.False(t, users[1].IsActive)
+	}
+}
+
+func TestAuthService_ReactivateUser(t *testing.T) {
+	service, _, userRepo, _, auditRepo := newTestAuthService()
+
+	userRepo.On("GetByIDIncludingInactive", 2).Return(&models.User{ID: 2, Username: "editor", Role: models.AdminRole, IsActive: false}, nil)
+	userRepo.On("GetByIDIncludingInactive", 3).Return(&models.User{ID: 3, Username: "active", IsActive: true}, nil)
+	userRepo.On("Update", 2, mock.AnythingOfType("*models.User")).Return(nil)
+
+	user, err := service.ReactivateUser(2, &models.User{ID: 1, Username: "admin", Role: models.SuperAdminRole}, "127.0.0.1", "test")
+	assert.NoError(t, err)
+	assert.True(t, user.IsActive)
+
+	_, err = service.ReactivateUser(3, &models.User{ID: 1, Username: "admin", Role: models.SuperAdminRole}, "127.0.0.1", "test")
+	assert.EqualError(t, err, "user is already active")
+	assert.Equal(t, []string{"USER_UPDATE:true"}, auditActions(auditRepo))
+}
+
+func TestAuthService_ResetUserPassword(t *testing.T) {
+	service, jwtService, userRepo, tokenRepo, auditRepo := newTestAuthService()
+
+	user := &models.User{ID: 2, Username: "editor", Role: models.AdminRole, IsActive: true}
+	userRepo.On("GetByID", 2).Return(user, nil)
+	userRepo.On("SetTemporaryPassword", 2, "TempPass123!").Return(nil)
+	tokenRepo.On("RevokeAllForUser", 2).Return(int64(2), nil)
+
+	err := service.ResetUserPassword(2, models.AdminResetPasswordRequest{TemporaryPassword: "weakpassword"}, &models.User{ID: 1, Username: "admin", Role: models.SuperAdminRole}, "127.0.0.1", "test")
+	assert.EqualError(t, err, "password must contain at least one uppercase letter")
+
+	err = service.ResetUserPassword(2, models.AdminResetPasswordRequest{TemporaryPassword: "TempPass123!"}, &models.User{ID: 1, Username: "admin", Role: models.SuperAdminRole}, "127.0.0.1", "test")
+	assert.NoError(t, err)
+	tokenRepo.AssertCalled(t, "RevokeAllForUser", 2)
+	assert.Equal(t, []string{"USER_UPDATE:true"}, auditActions(auditRepo))
+
+	// Tokens issued while the temporary password is in place say so
+	user.MustChangePassword = true
+	issued, err := jwtService.GenerateTokens(user)
+	assert.NoError(t, err)
+	claims, err := jwtService.ValidateAccessToken(issued.AccessToken)
+	assert.NoError(t, err)
+	assert.True(t, claims.PasswordChangeRequired)
+}
+
+func TestAuthService_ChangeTemporaryPassword(t *testing.T) {
+	service, _, userRepo, _, _ := newTestAuthService()
+
+	userRepo.On("GetByID", 2).Return(&models.User{ID: 2, Username: "editor", Password: "hash", IsActive: true, MustChangePassword: true}, nil)
+	userRepo.On("CheckPassword", "hash", "TempPass123!").Return(nil)
+	userRepo.On("CheckPassword", "hash", "NewPass123!").Return(fmt.Errorf("mismatch"))
+	userRepo.On("ChangePassword", 2, "NewPass123!").Return(nil)
+
+	// The temporary password cannot simply be kept
+	err := service.ChangePassword(2, models.ChangePasswordRequest{CurrentPassword: "TempPass123!", NewPassword: "TempPass123!"})
+	assert.EqualError(t, err, "new password must differ from the temporary password")
+
+	err = service.ChangePassword(2, models.ChangePasswordRequest{CurrentPassword: "TempPass123!", NewPassword: "NewPass123!"})
+	assert.NoError(t, err)
+	userRepo.AssertCalled(t, "ChangePassword", 2, "NewPass123!")
+}
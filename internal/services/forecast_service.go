@@ -0,0 +1,126 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ilramdhan/holidayapi/internal/calendar"
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/repository"
+)
+
+const (
+	// maxForecastYears is the longest range of years a forecast may cover
+	maxForecastYears = 10
+	// minForecastYear and maxForecastYear bound the years the lunar computations are accurate for
+	minForecastYear = 1900
+	maxForecastYear = 2100
+	// forecastMatchDays is how far a recorded holiday may be from its computed date and still
+	// confirm it, covering decrees that settle on a different day than the estimate
+	forecastMatchDays = 7
+)
+
+// ForecastService projects holidays for years that have not been recorded yet
+type ForecastService interface {
+	ForecastHolidays(fromYear, toYear int, scope models.HolidayScope) (*models.HolidayForecastResponse, error)
+}
+
+// forecastService implements ForecastService
+type forecastService struct {
+	repo repository.HolidayRepository
+}
+
+// NewForecastService creates a new forecast service
+func NewForecastService(repo repository.HolidayRepository) ForecastService {
+	return &forecastService{repo: repo}
+}
+
+// ForecastHolidays returns the recorded holidays of the years plus computed entries for the
+// national holidays that have not been recorded yet, sorted by date. Computed entries are
+// provisional, so a scope selecting other statuses only returns recorded holidays.
+func (s *forecastService) ForecastHolidays(fromYear, toYear int, scope models.HolidayScope) (*models.HolidayForecastResponse, error) {
+	if fromYear < minForecastYear || toYear > maxForecastYear {
+		return nil, fmt.Errorf("years must be between %d and %d", minForecastYear, maxForecastYear)
+	}
+	if toYear < fromYear {
+		return nil, fmt.Errorf("to_year must not be before from_year")
+	}
+	if toYear-fromYear+1 > maxForecastYears {
+		return nil, fmt.Errorf("a forecast covers at most %d years", maxForecastYears)
+	}
+
+	start := time.Date(fromYear, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(toYear, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	// Cancelled holidays are loaded too: a cancelled holiday still settles its computed date
+	all := scope
+	all.Statuses = models.AllHolidayStatuses
+
+	recorded, err := s.repo.GetByDateRange(start, end, nil, all)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holidays: %w", err)
+	}
+
+	response := &models.HolidayForecastResponse{
+		FromYear: fromYear,
+		ToYear:   toYear,
+		Province: scope.Province,
+		Holidays: []models.ForecastHoliday{},
+	}
+
+	for _, holiday := range recorded {
+		if !scope.MatchesStatus(holiday.Status) {
+			continue
+		}
+		response.Holidays = append(response.Holidays, models.ForecastHoliday{Holiday: holiday})
+		response.Confirmed++
+	}
+
+	if !scope.MatchesStatus(models.StatusProvisional) {
+		return response, nil
+	}
+
+	for year := fromYear; year <= toYear; year++ {
+		for _, computed := range calendar.Holidays(year) {
+			if isRecorded(computed, recorded) {
+				continue
+			}
+			response.Holidays = append(response.Holidays, models.ForecastHoliday{
+				Holiday: models.Holiday{
+					Name:        computed.Name,
+					Date:        computed.Date,
+					Type:        models.NationalHoliday,
+					Description: computed.Description,
+					IsActive:    true,
+					Status:      models.StatusProvisional,
+				},
+				Computed: true,
+				Calendar: string(computed.System),
+			})
+			response.Computed++
+		}
+	}
+
+	sort.SliceStable(response.Holidays, func(i, j int) bool {
+		return response.Holidays[i].Date.Before(response.Holidays[j].Date)
+	})
+
+	return response, nil
+}
+
+// isRecorded reports whether a recorded national holiday with the same name lies
+// within forecastMatchDays of the computed date
+func isRecorded(computed calendar.Holiday, recorded []models.Holiday) bool {
+	window := forecastMatchDays * 24 * time.Hour
+	for _, holiday := range recorded {
+		if holiday.Type != models.NationalHoliday || holiday.Name != computed.Name {
+			continue
+		}
+		diff := holiday.Date.Sub(computed.Date)
+		if diff <= window && diff >= -window {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+func TestForecastService_ForecastHolidays(t *testing.T) {
+	allStatuses := models.HolidayScope{Statuses: models.AllHolidayStatuses}
+
+	t.Run("recorded holidays confirm computed ones", func(t *testing.T) {
+		mockRepo := new(MockHolidayRepository)
+		service := NewForecastService(mockRepo)
+
+		recorded := []models.Holiday{
+			{ID: 1, Name: "Tahun Baru Masehi", Date: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), Type: models.NationalHoliday, Status: models.StatusOfficial},
+			// Decreed a day later than the computed 2027-03-10
+			{ID: 2, Name: "Hari Raya Idul Fitri", Date: time.Date(2027, 3, 11, 0, 0, 0, 0, time.UTC), Type: models.NationalHoliday, Status: models.StatusOfficial},
+			{ID: 3, Name: "Cuti Bersama Idul Fitri", Date: time.Date(2027, 3, 12, 0, 0, 0, 0, time.UTC), Type: models.CollectiveLeave, Status: models.StatusProvisional},
+		}
+		mockRepo.On("GetByDateRange", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2027, 12, 31, 0, 0, 0, 0, time.UTC),
+			(*models.HolidayType)(nil), allStatuses).Return(recorded, nil)
+
+		response, err := service.ForecastHolidays(2027, 2027, models.HolidayScope{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, response.Confirmed)
+		// 18 computed holidays (Isra Mikraj falls twice in 2027), two of them recorded
+		assert.Equal(t, 16, response.Computed)
+		assert.Len(t, response.Holidays, 19)
+
+		for i, holiday := range response.Holidays {
+			if i > 0 {
+				assert.False(t, holiday.Date.Before(response.Holidays[i-1].Date))
+			}
+			if holiday.Name == "Hari Raya Idul Fitri" || holiday.Name == "Tahun Baru Masehi" {
+				assert.False(t, holiday.Computed, holiday.Name)
+				assert.NotZero(t, holiday.ID)
+			}
+		}
+
+		imlek := response.Holidays[2]
+		assert.Equal(t, "Tahun Baru Imlek", imlek.Name)
+		assert.True(t, imlek.Computed)
+		assert.Equal(t, "chinese", imlek.Calendar)
+		assert.Equal(t, models.StatusProvisional, imlek.Status)
+		assert.Zero(t, imlek.ID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("status filter", func(t *testing.T) {
+		mockRepo := new(MockHolidayRepository)
+		service := NewForecastService(mockRepo)
+
+		recorded := []models.Holiday{
+			{ID: 1, Name: "Tahun Baru Masehi", Date: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), Type: models.NationalHoliday, Status: models.StatusOfficial},
+			{ID: 2, Name: "Hari Raya Natal", Date: time.Date(2027, 12, 25, 0, 0, 0, 0, time.UTC), Type: models.NationalHoliday, Status: models.StatusCancelled},
+		}
+		mockRepo.On("GetByDateRange", mock.Anything, mock.Anything, (*models.HolidayType)(nil), allStatuses).Return(recorded, nil)
+
+		// The cancelled Christmas is hidden, and its computed date does not come back
+		response, err := service.ForecastHolidays(2027, 2027, models.HolidayScope{})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, response.Confirmed)
+		assert.Equal(t, 16, response.Computed)
+
+		// Only ratified dates: no computed entries at all
+		response, err = service.ForecastHolidays(2027, 2027, models.HolidayScope{Statuses: []models.HolidayStatus{models.StatusOfficial}})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, response.Confirmed)
+		assert.Equal(t, 0, response.Computed)
+		assert.Len(t, response.Holidays, 1)
+	})
+
+	t.Run("multi-year range", func(t *testing.T) {
+		mockRepo := new(MockHolidayRepository)
+		service := NewForecastService(mockRepo)
+		mockRepo.On("GetByDateRange", mock.Anything, mock.Anything, (*models.HolidayType)(nil), allStatuses).Return([]models.Holiday{}, nil)
+
+		response, err := service.ForecastHolidays(2028, 2030, models.HolidayScope{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, response.Confirmed)
+		assert.Equal(t, 2028, response.Holidays[0].Date.Year())
+		assert.Equal(t, 2030, response.Holidays[len(response.Holidays)-1].Date.Year())
+	})
+
+	t.Run("invalid ranges", func(t *testing.T) {
+		service := NewForecastService(new(MockHolidayRepository))
+
+		_, err := service.ForecastHolidays(2030, 2028, models.HolidayScope{})
+		assert.Error(t, err)
+
+		_, err = service.ForecastHolidays(2025, 2040, models.HolidayScope{})
+		assert.Error(t, err)
+
+		_, err = service.ForecastHolidays(1800, 1801, models.HolidayScope{})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"github.com/ilramdhan/holidayapi/internal/events"
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/repository"
+)
+
+// maxChangeBatch caps the number of changes returned by a single GetChangesSince call
+const maxChangeBatch = 1000
+
+// HolidayChangeService reads the persisted holiday change feed and publishes changes on the event bus.
+// Changes are persisted by HolidayRepository together with the mutation they describe.
+type HolidayChangeService interface {
+	Publish(changes ...models.HolidayChange)
+	GetChangesSince(sequence int64, limit int) ([]models.HolidayChange, error)
+	Subscribe() (<-chan models.HolidayChange, func())
+}
+
+// holidayChangeService implements HolidayChangeService
+type holidayChangeService struct {
+	repo repository.HolidayChangeRepository
+	bus  *events.Bus
+}
+
+// NewHolidayChangeService creates a new holiday change service
+func NewHolidayChangeService(repo repository.HolidayChangeRepository, bus *events.Bus) HolidayChangeService {
+	return &holidayChangeService{
+		repo: repo,
+		bus:  bus,
+	}
+}
+
+// Publish sends already persisted changes to live subscribers.
+// Callers must publish in sequence order.
+func (s *holidayChangeService) Publish(changes ...models.HolidayChange) {
+	for _, change := range changes {
+		s.bus.Publish(change)
+	}
+}
+
+// GetChangesSince returns changes after the given sequence, oldest first
+func (s *holidayChangeService) GetChangesSince(sequence int64, limit int) ([]models.HolidayChange, error) {
+	if limit <= 0 || limit > maxChangeBatch {
+		limit = maxChangeBatch
+	}
+	if sequence < 0 {
+		sequence = 0
+	}
+
+	return s.repo.GetSince(sequence, limit)
+}
+
+// Subscribe registers a live subscriber on the event bus
+func (s *holidayChangeService) Subscribe() (<-chan models.HolidayChange, func()) {
+	return s.bus.Subscribe()
+}
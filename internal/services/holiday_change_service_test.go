@@ -0,0 +1,148 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ilramdhan/holidayapi/internal/events"
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+// MockHolidayChangeRepository is a mock implementation of HolidayChangeRepository
+type MockHolidayChangeRepository struct {
+	mock.Mock
+}
+
+func (m *MockHolidayChangeRepository) GetSince(sequence int64, limit int) ([]models.HolidayChange, error) {
+	args := m.Called(sequence, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.HolidayChange), args.Error(1)
+}
+
+// MockHolidayChangeService is a mock implementation of HolidayChangeService
+type MockHolidayChangeService struct {
+	mock.Mock
+}
+
+func (m *MockHolidayChangeService) Publish(changes ...models.HolidayChange) {
+	m.Called(changes)
+}
+
+func (m *MockHolidayChangeService) GetChangesSince(sequence int64, limit int) ([]models.HolidayChange, error) {
+	args := m.Called(sequence, limit)
+	return args.Get(0).([]models.HolidayChange), args.Error(1)
+}
+
+func (m *MockHolidayChangeService) Subscribe() (<-chan models.HolidayChange, func()) {
+	args := m.Called()
+	return args.Get(0).(<-chan models.HolidayChange), args.Get(1).(func())
+}
+
+func TestHolidayChangeService_Publish(t *testing.T) {
+	service := NewHolidayChangeService(new(MockHolidayChangeRepository), events.NewBus(4))
+
+	live, unsubscribe := service.Subscribe()
+	defer unsubscribe()
+
+	service.Publish(
+		models.HolidayChange{Sequence: 41, Type: models.ChangeHolidayCreated, Holiday: models.Holiday{ID: 3, Name: "Hari Raya Nyepi"}},
+		models.HolidayChange{Sequence: 42, Type: models.ChangeHolidayUpdated, Holiday: models.Holiday{ID: 4}},
+	)
+
+	published := <-live
+	assert.Equal(t, int64(41), published.Sequence)
+	assert.Equal(t, "Hari Raya Nyepi", published.Holiday.Name)
+	published = <-live
+	assert.Equal(t, int64(42), published.Sequence)
+}
+
+func TestHolidayChangeService_GetChangesSince(t *testing.T) {
+	mockRepo := new(MockHolidayChangeRepository)
+	service := NewHolidayChangeService(mockRepo, events.NewBus(1))
+
+	mockRepo.On("GetSince", int64(0), maxChangeBatch).Return([]models.HolidayChange{}, nil)
+	mockRepo.On("GetSince", int64(7), 50).Return([]models.HolidayChange{{Sequence: 8}}, nil)
+
+	changes, err := service.GetChangesSince(-1, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	changes, err = service.GetChangesSince(7, 50)
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestHolidayService_PublishesChanges(t *testing.T) {
+	date := time.Date(2025, 8, 17, 0, 0, 0, 0, time.UTC)
+
+	t.Run("create", func(t *testing.T) {
+		mockRepo := new(MockHolidayRepository)
+		mockChanges := new(MockHolidayChangeService)
+		service := NewHolidayService(mockRepo, mockChanges)
+
+		change := &models.HolidayChange{Sequence: 1, Type: models.ChangeHolidayCreated}
+		mockRepo.On("GetByDate", date, models.HolidayScope{Statuses: models.AllHolidayStatuses}).Return([]models.Holiday{}, nil)
+		mockRepo.On("Create", mock.AnythingOfType("*models.Holiday")).Return(change, nil)
+		mockChanges.On("Publish", []models.HolidayChange{*change}).Return()
+
+		_, err := service.CreateHoliday(models.CreateHolidayRequest{Name: "Hari Kemerdekaan RI", Date: "2025-08-17", Type: models.NationalHoliday})
+
+		assert.NoError(t, err)
+		mockChanges.AssertExpectations(t)
+	})
+
+	t.Run("import publishes in sequence order", func(t *testing.T) {
+		mockRepo := new(MockHolidayRepository)
+		mockChanges := new(MockHolidayChangeService)
+		service := NewHolidayService(mockRepo, mockChanges)
+
+		changes := []models.HolidayChange{{Sequence: 7, Type: models.ChangeHolidayCreated}, {Sequence: 8, Type: models.ChangeHolidayCreated}}
+		mockRepo.On("GetByDateRange", mock.Anything, mock.Anything, (*models.HolidayType)(nil), models.HolidayScope{Statuses: models.AllHolidayStatuses}).Return([]models.Holiday{}, nil)
+		mockRepo.On("BulkSave", mock.Anything, mock.Anything).Return(changes, nil)
+		mockChanges.On("Publish", changes).Return()
+
+		_, err := service.ImportHolidays([]models.CreateHolidayRequest{
+			{Name: "Hari Kemerdekaan RI", Date: "2025-08-17", Type: models.NationalHoliday},
+			{Name: "Hari Natal", Date: "2025-12-25", Type: models.NationalHoliday},
+		}, false)
+
+		assert.NoError(t, err)
+		mockChanges.AssertExpectations(t)
+	})
+
+	t.Run("failed delete is reported and not published", func(t *testing.T) {
+		mockRepo := new(MockHolidayRepository)
+		mockChanges := new(MockHolidayChangeService)
+		service := NewHolidayService(mockRepo, mockChanges)
+
+		// The change is written in the transaction of the deletion, so a failure undoes both
+		mockRepo.On("GetByID", 5).Return(&models.Holiday{ID: 5, Name: "Hari Kemerdekaan RI", Date: date, IsActive: true}, nil)
+		mockRepo.On("Delete", 5).Return(nil, fmt.Errorf("failed to record holiday change: database is locked"))
+
+		err := service.DeleteHoliday(5)
+
+		assert.EqualError(t, err, "failed to record holiday change: database is locked")
+		mockChanges.AssertNotCalled(t, "Publish", mock.Anything)
+	})
+
+	t.Run("missing holiday is not deleted", func(t *testing.T) {
+		mockRepo := new(MockHolidayRepository)
+		mockChanges := new(MockHolidayChangeService)
+		service := NewHolidayService(mockRepo, mockChanges)
+
+		mockRepo.On("GetByID", 5).Return((*models.Holiday)(nil), fmt.Errorf("holiday not found"))
+
+		err := service.DeleteHoliday(5)
+
+		assert.EqualError(t, err, "holiday not found")
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything)
+		mockChanges.AssertNotCalled(t, "Publish", mock.Anything)
+	})
+}
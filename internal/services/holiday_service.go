@@ -2,8 +2,11 @@ package services
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/go-playground/validator/v10"
+
 	"github.com/ilramdhan/holidayapi/internal/models"
 	"github.com/ilramdhan/holidayapi/internal/repository"
 )
@@ -13,55 +16,81 @@ type HolidayService interface {
 	CreateHoliday(req models.CreateHolidayRequest) (*models.Holiday, error)
 	GetHolidayByID(id int) (*models.Holiday, error)
 	GetHolidays(filter models.HolidayFilter) (*models.HolidayResponse, error)
+	ListHolidays(filter models.HolidayFilter) ([]models.Holiday, error)
 	UpdateHoliday(id int, req models.UpdateHolidayRequest) (*models.Holiday, error)
 	DeleteHoliday(id int) error
-	GetHolidaysThisYear() ([]models.Holiday, error)
-	GetHolidaysThisMonth() ([]models.Holiday, error)
-	GetHolidayToday() (*models.Holiday, error)
-	GetUpcomingHolidays(limit int) ([]models.Holiday, error)
-	GetHolidaysByYear(year int) ([]models.Holiday, error)
-	GetHolidaysByMonth(year, month int) ([]models.Holiday, error)
-	GetHolidaysByType(holidayType models.HolidayType) ([]models.Holiday, error)
+	GetHolidaysThisYear(scope models.HolidayScope) ([]models.Holiday, error)
+	GetHolidaysThisMonth(scope models.HolidayScope) ([]models.Holiday, error)
+	GetHolidayToday(scope models.HolidayScope) ([]models.Holiday, error)
+	GetUpcomingHolidays(limit int, scope models.HolidayScope) ([]models.Holiday, error)
+	GetHolidaysByYear(year int, scope models.HolidayScope) ([]models.Holiday, error)
+	GetHolidaysByMonth(year, month int, scope models.HolidayScope) ([]models.Holiday, error)
+	GetHolidaysByType(holidayType models.HolidayType, scope models.HolidayScope) ([]models.Holiday, error)
+	ImportHolidays(rows []models.CreateHolidayRequest, dryRun bool) (*models.HolidayImportResult, error)
+	GetHolidayHistory(id int) ([]models.HolidayRevision, error)
+	RollbackHoliday(id int, revision int) (*models.Holiday, error)
 }
 
 // holidayService implements HolidayService
 type holidayService struct {
-	repo repository.HolidayRepository
+	repo      repository.HolidayRepository
+	changes   HolidayChangeService
+	validator *validator.Validate
+
+	// mu serializes mutations so their changes are published in sequence order
+	mu sync.Mutex
 }
 
-// NewHolidayService creates a new holiday service
-func NewHolidayService(repo repository.HolidayRepository) HolidayService {
-	return &holidayService{repo: repo}
+// NewHolidayService creates a new holiday service.
+// Creates, updates and deletes are recorded in the change feed by the repository and published
+// to live subscribers through changes, which may be nil to disable publication.
+func NewHolidayService(repo repository.HolidayRepository, changes HolidayChangeService) HolidayService {
+	return &holidayService{
+		repo:      repo,
+		changes:   changes,
+		validator: validator.New(),
+	}
 }
 
 // CreateHoliday creates a new holiday
 func (s *holidayService) CreateHoliday(req models.CreateHolidayRequest) (*models.Holiday, error) {
 	// Parse date
-	date, err := time.Parse("2006-01-02", req.Date)
+	date, err := parseHolidayDate(req.Date)
 	if err != nil {
-		return nil, fmt.Errorf("invalid date format, use YYYY-MM-DD: %w", err)
+		return nil, err
 	}
 
-	// Check if holiday already exists on this date
-	existing, err := s.repo.GetByDate(date)
+	province, err := normalizeHolidayRegion(req.Type, req.Province)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check existing holiday: %w", err)
-	}
-	if existing != nil {
-		return nil, fmt.Errorf("holiday already exists on date %s", req.Date)
+		return nil, err
 	}
 
 	holiday := &models.Holiday{
 		Name:        req.Name,
 		Date:        date,
 		Type:        req.Type,
+		Province:    province,
 		Description: req.Description,
+		Status:      req.Status,
+	}
+	if err := applyDecree(holiday, req.DecreeNumber, req.DecreeDate); err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureUnique(holiday, 0); err != nil {
+		return nil, err
 	}
 
-	if err := s.repo.Create(holiday); err != nil {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	change, err := s.repo.Create(holiday)
+	if err != nil {
 		return nil, fmt.Errorf("failed to create holiday: %w", err)
 	}
 
+	s.publish(*change)
+
 	return holiday, nil
 }
 
@@ -97,6 +126,19 @@ func (s *holidayService) GetHolidays(filter models.HolidayFilter) (*models.Holid
 	}, nil
 }
 
+// ListHolidays retrieves every holiday matching the filter without pagination
+func (s *holidayService) ListHolidays(filter models.HolidayFilter) ([]models.Holiday, error) {
+	filter.Limit = 0
+	filter.Offset = 0
+
+	holidays, _, err := s.repo.GetAll(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list holidays: %w", err)
+	}
+
+	return holidays, nil
+}
+
 // UpdateHoliday updates a holiday
 func (s *holidayService) UpdateHoliday(id int, req models.UpdateHolidayRequest) (*models.Holiday, error) {
 	// Get existing holiday
@@ -104,21 +146,30 @@ func (s *holidayService) UpdateHoliday(id int, req models.UpdateHolidayRequest)
 	if err != nil {
 		return nil, err
 	}
+	identity := holidayIdentity(existing)
 
 	// Update fields if provided
 	if req.Name != nil {
 		existing.Name = *req.Name
 	}
 	if req.Date != nil {
-		date, err := time.Parse("2006-01-02", *req.Date)
+		date, err := parseHolidayDate(*req.Date)
 		if err != nil {
-			return nil, fmt.Errorf("invalid date format, use YYYY-MM-DD: %w", err)
+			return nil, err
 		}
 		existing.Date = date
 	}
 	if req.Type != nil {
 		existing.Type = *req.Type
 	}
+	if req.Province != nil {
+		existing.Province = req.Province
+	}
+	province, err := normalizeHolidayRegion(existing.Type, existing.Province)
+	if err != nil {
+		return nil, err
+	}
+	existing.Province = province
 	if req.Description != nil {
 		existing.Description = *req.Description
 	}
@@ -126,44 +177,79 @@ func (s *holidayService) UpdateHoliday(id int, req models.UpdateHolidayRequest)
 		existing.IsActive = *req.IsActive
 	}
 
-	if err := s.repo.Update(id, existing); err != nil {
+	if req.Status != nil && *req.Status != existing.Status {
+		if err := changeStatus(existing, *req.Status, req.DecreeNumber, req.DecreeDate); err != nil {
+			return nil, err
+		}
+	}
+	if err := applyDecree(existing, req.DecreeNumber, req.DecreeDate); err != nil {
+		return nil, err
+	}
+
+	if existing.IsActive && holidayIdentity(existing) != identity {
+		if err := s.ensureUnique(existing, id); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	change, err := s.repo.Update(id, existing)
+	if err != nil {
 		return nil, fmt.Errorf("failed to update holiday: %w", err)
 	}
 
+	s.publish(*change)
+
 	return existing, nil
 }
 
 // DeleteHoliday deletes a holiday
 func (s *holidayService) DeleteHoliday(id int) error {
-	return s.repo.Delete(id)
+	if _, err := s.repo.GetByID(id); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	change, err := s.repo.Delete(id)
+	if err != nil {
+		return err
+	}
+
+	s.publish(*change)
+
+	return nil
 }
 
 // GetHolidaysThisYear gets holidays for current year
-func (s *holidayService) GetHolidaysThisYear() ([]models.Holiday, error) {
+func (s *holidayService) GetHolidaysThisYear(scope models.HolidayScope) ([]models.Holiday, error) {
 	year := time.Now().Year()
-	filter := models.HolidayFilter{Year: &year}
+	filter := models.HolidayFilter{HolidayScope: scope, Year: &year}
 	holidays, _, err := s.repo.GetAll(filter)
 	return holidays, err
 }
 
 // GetHolidaysThisMonth gets holidays for current month
-func (s *holidayService) GetHolidaysThisMonth() ([]models.Holiday, error) {
+func (s *holidayService) GetHolidaysThisMonth(scope models.HolidayScope) ([]models.Holiday, error) {
 	now := time.Now()
 	year := now.Year()
 	month := int(now.Month())
-	filter := models.HolidayFilter{Year: &year, Month: &month}
+	filter := models.HolidayFilter{HolidayScope: scope, Year: &year, Month: &month}
 	holidays, _, err := s.repo.GetAll(filter)
 	return holidays, err
 }
 
-// GetHolidayToday gets today's holiday if any
-func (s *holidayService) GetHolidayToday() (*models.Holiday, error) {
+// GetHolidayToday gets today's holidays, nationwide ones first
+func (s *holidayService) GetHolidayToday(scope models.HolidayScope) ([]models.Holiday, error) {
 	today := time.Now()
-	return s.repo.GetByDate(today)
+	return s.repo.GetByDate(today, scope)
 }
 
 // GetUpcomingHolidays gets upcoming holidays
-func (s *holidayService) GetUpcomingHolidays(limit int) ([]models.Holiday, error) {
+func (s *holidayService) GetUpcomingHolidays(limit int, scope models.HolidayScope) ([]models.Holiday, error) {
 	if limit <= 0 {
 		limit = 10
 	}
@@ -171,7 +257,7 @@ func (s *holidayService) GetUpcomingHolidays(limit int) ([]models.Holiday, error
 	today := time.Now()
 	endDate := today.AddDate(1, 0, 0) // Next year
 	
-	holidays, err := s.repo.GetByDateRange(today, endDate, nil)
+	holidays, err := s.repo.GetByDateRange(today, endDate, nil, scope)
 	if err != nil {
 		return nil, err
 	}
@@ -185,22 +271,384 @@ func (s *holidayService) GetUpcomingHolidays(limit int) ([]models.Holiday, error
 }
 
 // GetHolidaysByYear gets holidays by specific year
-func (s *holidayService) GetHolidaysByYear(year int) ([]models.Holiday, error) {
-	filter := models.HolidayFilter{Year: &year}
+func (s *holidayService) GetHolidaysByYear(year int, scope models.HolidayScope) ([]models.Holiday, error) {
+	filter := models.HolidayFilter{HolidayScope: scope, Year: &year}
 	holidays, _, err := s.repo.GetAll(filter)
 	return holidays, err
 }
 
 // GetHolidaysByMonth gets holidays by specific month
-func (s *holidayService) GetHolidaysByMonth(year, month int) ([]models.Holiday, error) {
-	filter := models.HolidayFilter{Year: &year, Month: &month}
+func (s *holidayService) GetHolidaysByMonth(year, month int, scope models.HolidayScope) ([]models.Holiday, error) {
+	filter := models.HolidayFilter{HolidayScope: scope, Year: &year, Month: &month}
 	holidays, _, err := s.repo.GetAll(filter)
 	return holidays, err
 }
 
 // GetHolidaysByType gets holidays by type
-func (s *holidayService) GetHolidaysByType(holidayType models.HolidayType) ([]models.Holiday, error) {
-	filter := models.HolidayFilter{Type: &holidayType}
+func (s *holidayService) GetHolidaysByType(holidayType models.HolidayType, scope models.HolidayScope) ([]models.Holiday, error) {
+	filter := models.HolidayFilter{HolidayScope: scope, Type: &holidayType}
 	holidays, _, err := s.repo.GetAll(filter)
 	return holidays, err
 }
+
+// ImportHolidays validates rows like CreateHoliday and diffs them against stored holidays.
+// Unless dryRun is set, the creates and updates are committed in a single transaction,
+// and only when no row is invalid or conflicting.
+func (s *holidayService) ImportHolidays(rows []models.CreateHolidayRequest, dryRun bool) (*models.HolidayImportResult, error) {
+	result := &models.HolidayImportResult{
+		DryRun: dryRun,
+		Total:  len(rows),
+		Rows:   make([]models.HolidayImportRow, 0, len(rows)),
+	}
+
+	// Parse and validate every row first so existing holidays can be loaded in one query per region
+	dates := make([]time.Time, len(rows))
+	provinces := make([]*string, len(rows))
+	regions := make(map[string]*string)
+	var minDate, maxDate time.Time
+	for i, row := range rows {
+		planned := models.HolidayImportRow{Row: i + 1, Holiday: row}
+
+		if err := s.validator.Struct(row); err != nil {
+			planned.Action = models.ImportInvalid
+			planned.Error = err.Error()
+		} else if date, err := parseHolidayDate(row.Date); err != nil {
+			planned.Action = models.ImportInvalid
+			planned.Error = err.Error()
+		} else if province, err := normalizeHolidayRegion(row.Type, row.Province); err != nil {
+			planned.Action = models.ImportInvalid
+			planned.Error = err.Error()
+		} else {
+			dates[i] = date
+			provinces[i] = province
+			planned.Holiday.Province = province
+			regions[regionKey(province)] = province
+			if minDate.IsZero() || date.Before(minDate) {
+				minDate = date
+			}
+			if maxDate.IsZero() || date.After(maxDate) {
+				maxDate = date
+			}
+		}
+
+		result.Rows = append(result.Rows, planned)
+	}
+
+	existingByKey := make(map[string]models.Holiday)
+	for _, province := range regions {
+		scope := models.HolidayScope{Province: province, Statuses: models.AllHolidayStatuses}
+		existing, err := s.repo.GetByDateRange(minDate, maxDate, nil, scope)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load existing holidays: %w", err)
+		}
+		for _, holiday := range existing {
+			existingByKey[holidayIdentity(&holiday)] = holiday
+		}
+	}
+
+	var creates, updates []*models.Holiday
+	seenRows := make(map[string]int)
+
+	for i := range result.Rows {
+		planned := &result.Rows[i]
+		if planned.Action == models.ImportInvalid {
+			result.Invalid++
+			continue
+		}
+
+		row := rows[i]
+		key := identityKey(dates[i], provinces[i], row.Name, row.Type)
+
+		// A holiday is identified by name, date, type and region; a file may not list the same one twice
+		if firstRow, ok := seenRows[key]; ok {
+			planned.Action = models.ImportConflict
+			planned.Error = fmt.Sprintf("holiday %q on %s is already listed in row %d", row.Name, row.Date, firstRow)
+			result.Conflicts++
+			continue
+		}
+		seenRows[key] = planned.Row
+
+		existing, ok := existingByKey[key]
+		if !ok {
+			created := &models.Holiday{
+				Name:        row.Name,
+				Date:        dates[i],
+				Type:        row.Type,
+				Province:    provinces[i],
+				Description: row.Description,
+				Status:      row.Status,
+			}
+			if err := applyDecree(created, row.DecreeNumber, row.DecreeDate); err != nil {
+				planned.Action = models.ImportInvalid
+				planned.Error = err.Error()
+				result.Invalid++
+				continue
+			}
+			planned.Action = models.ImportCreate
+			creates = append(creates, created)
+			result.Created++
+			continue
+		}
+
+		existingID := existing.ID
+		planned.HolidayID = &existingID
+
+		// Rows are checked like UpdateHoliday; a row without a status keeps the stored one
+		updated := existing
+		updated.Description = row.Description
+		err := applyDecree(&updated, row.DecreeNumber, row.DecreeDate)
+		if err == nil && row.Status != "" && row.Status != existing.Status {
+			err = changeStatus(&updated, row.Status, row.DecreeNumber, row.DecreeDate)
+		}
+		if err != nil {
+			planned.Action = models.ImportInvalid
+			planned.Error = err.Error()
+			result.Invalid++
+			continue
+		}
+
+		changes := holidayChanges(&existing, &updated)
+		if len(changes) == 0 {
+			planned.Action = models.ImportUnchanged
+			result.Unchanged++
+			continue
+		}
+
+		planned.Action = models.ImportUpdate
+		planned.Changes = changes
+		updates = append(updates, &updated)
+		result.Updated++
+	}
+
+	if dryRun || result.Conflicts > 0 || result.Invalid > 0 {
+		return result, nil
+	}
+
+	if len(creates) > 0 || len(updates) > 0 {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		changes, err := s.repo.BulkSave(creates, updates)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import holidays: %w", err)
+		}
+
+		s.publish(changes...)
+	}
+
+	// Report the IDs assigned to newly created holidays
+	createIndex := 0
+	for i := range result.Rows {
+		if result.Rows[i].Action == models.ImportCreate {
+			id := creates[createIndex].ID
+			result.Rows[i].HolidayID = &id
+			createIndex++
+		}
+	}
+
+	result.Committed = true
+	return result, nil
+}
+
+// GetHolidayHistory retrieves every revision of a holiday, including deleted holidays
+func (s *holidayService) GetHolidayHistory(id int) ([]models.HolidayRevision, error) {
+	revisions, err := s.repo.GetRevisions(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holiday history: %w", err)
+	}
+	if len(revisions) == 0 {
+		return nil, fmt.Errorf("holiday not found")
+	}
+
+	return revisions, nil
+}
+
+// RollbackHoliday restores a holiday to the state of an earlier revision.
+// Restoring a revision taken before a deletion brings the holiday back.
+func (s *holidayService) RollbackHoliday(id int, revision int) (*models.Holiday, error) {
+	target, err := s.repo.GetRevision(id, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	holiday := target.Holiday()
+
+	// The restored holiday must not clash with another one of the same name, date, type and region
+	if holiday.IsActive {
+		if err := s.ensureUnique(&holiday, id); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	change, err := s.repo.Rollback(&holiday, revision)
+	if err != nil {
+		return nil, fmt.Errorf("failed to roll back holiday: %w", err)
+	}
+
+	s.publish(*change)
+
+	if !holiday.IsActive {
+		return &holiday, nil
+	}
+
+	return s.repo.GetByID(id)
+}
+
+// publish sends changes recorded by the repository to live subscribers. Callers hold mu.
+func (s *holidayService) publish(changes ...models.HolidayChange) {
+	if s.changes == nil {
+		return
+	}
+	s.changes.Publish(changes...)
+}
+
+// ensureUnique rejects a holiday whose name, date, type and region match another stored holiday.
+// Different holidays may share a date. excludeID skips the holiday being changed.
+func (s *holidayService) ensureUnique(holiday *models.Holiday, excludeID int) error {
+	existing, err := s.repo.GetByDate(holiday.Date, models.HolidayScope{
+		Province: holiday.Province,
+		Statuses: models.AllHolidayStatuses,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to check existing holiday: %w", err)
+	}
+
+	key := holidayIdentity(holiday)
+	for i := range existing {
+		if existing[i].ID != excludeID && holidayIdentity(&existing[i]) == key {
+			return fmt.Errorf("holiday %q already exists on date %s", holiday.Name, holiday.Date.Format(dateLayout))
+		}
+	}
+
+	return nil
+}
+
+// holidayIdentity returns the key that must be unique among active holidays: date, region, name and type
+func holidayIdentity(holiday *models.Holiday) string {
+	return identityKey(holiday.Date, holiday.Province, holiday.Name, holiday.Type)
+}
+
+// identityKey builds a holiday identity key from its parts
+func identityKey(date time.Time, province *string, name string, holidayType models.HolidayType) string {
+	return date.Format(dateLayout) + "|" + regionKey(province) + "|" + name + "|" + string(holidayType)
+}
+
+// normalizeHolidayRegion validates the province of a holiday and returns its canonical code.
+// Regional holidays must name a province; other types apply nationwide. An empty province means nationwide.
+func normalizeHolidayRegion(holidayType models.HolidayType, province *string) (*string, error) {
+	var normalized *string
+	if province != nil && *province != "" {
+		code, ok := models.NormalizeProvinceCode(*province)
+		if !ok {
+			return nil, fmt.Errorf("invalid province code %q, use an ISO 3166-2:ID code such as ID-BA", *province)
+		}
+		normalized = &code
+	}
+
+	if holidayType == models.RegionalHoliday && normalized == nil {
+		return nil, fmt.Errorf("regional holidays require a province")
+	}
+	if holidayType != models.RegionalHoliday && normalized != nil {
+		return nil, fmt.Errorf("only regional holidays can have a province")
+	}
+
+	return normalized, nil
+}
+
+// sameProvince reports whether two optional province codes are equal
+func sameProvince(a, b *string) bool {
+	return regionKey(a) == regionKey(b)
+}
+
+// regionKey returns a map key for an optional province, empty for nationwide
+func regionKey(province *string) string {
+	if province == nil {
+		return ""
+	}
+	return *province
+}
+
+// applyDecree sets the legal basis of a holiday's status from optional request fields.
+// Nil fields are left unchanged and empty strings clear them.
+func applyDecree(holiday *models.Holiday, number, date *string) error {
+	if number != nil {
+		holiday.DecreeNumber = nil
+		if *number != "" {
+			value := *number
+			holiday.DecreeNumber = &value
+		}
+	}
+
+	if date != nil {
+		holiday.DecreeDate = nil
+		if *date != "" {
+			parsed, err := time.Parse(dateLayout, *date)
+			if err != nil {
+				return fmt.Errorf("invalid decree_date format, use YYYY-MM-DD: %w", err)
+			}
+			holiday.DecreeDate = &parsed
+		}
+	}
+
+	return nil
+}
+
+// changeStatus moves a holiday to another status, which must be allowed from its current status
+// and name the decree behind it
+func changeStatus(holiday *models.Holiday, status models.HolidayStatus, decreeNumber, decreeDate *string) error {
+	if !holiday.Status.CanTransitionTo(status) {
+		return fmt.Errorf("invalid status transition from %s to %s", holiday.Status, status)
+	}
+	if decreeNumber == nil || *decreeNumber == "" || decreeDate == nil || *decreeDate == "" {
+		return fmt.Errorf("a status change requires decree_number and decree_date")
+	}
+
+	holiday.Status = status
+	return nil
+}
+
+// holidayChanges describes the fields an import may change that differ between two versions of a holiday
+func holidayChanges(before, after *models.Holiday) map[string]models.FieldChange {
+	changes := make(map[string]models.FieldChange)
+	if before.Description != after.Description {
+		changes["description"] = models.FieldChange{From: before.Description, To: after.Description}
+	}
+	if before.Status != after.Status {
+		changes["status"] = models.FieldChange{From: before.Status, To: after.Status}
+	}
+	if from, to := optionalString(before.DecreeNumber), optionalString(after.DecreeNumber); from != to {
+		changes["decree_number"] = models.FieldChange{From: from, To: to}
+	}
+	if from, to := optionalDate(before.DecreeDate), optionalDate(after.DecreeDate); from != to {
+		changes["decree_date"] = models.FieldChange{From: from, To: to}
+	}
+	return changes
+}
+
+// optionalString returns the value of an optional string, or an empty string when it is not set
+func optionalString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// optionalDate formats an optional date as YYYY-MM-DD, or an empty string when it is not set
+func optionalDate(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.Format(dateLayout)
+}
+
+// parseHolidayDate parses a YYYY-MM-DD holiday date
+func parseHolidayDate(value string) (time.Time, error) {
+	date, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date format, use YYYY-MM-DD: %w", err)
+	}
+	return date, nil
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -15,9 +16,12 @@ type MockHolidayRepository struct {
 	mock.Mock
 }
 
-func (m *MockHolidayRepository) Create(holiday *models.Holiday) error {
+func (m *MockHolidayRepository) Create(holiday *models.Holiday) (*models.HolidayChange, error) {
 	args := m.Called(holiday)
-	return args.Error(0)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.HolidayChange), args.Error(1)
 }
 
 func (m *MockHolidayRepository) GetByID(id int) (*models.Holiday, error) {
@@ -30,32 +34,67 @@ func (m *MockHolidayRepository) GetAll(filter models.HolidayFilter) ([]models.Ho
 	return args.Get(0).([]models.Holiday), args.Int(1), args.Error(2)
 }
 
-func (m *MockHolidayRepository) Update(id int, holiday *models.Holiday) error {
+func (m *MockHolidayRepository) Update(id int, holiday *models.Holiday) (*models.HolidayChange, error) {
 	args := m.Called(id, holiday)
-	return args.Error(0)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.HolidayChange), args.Error(1)
 }
 
-func (m *MockHolidayRepository) Delete(id int) error {
+func (m *MockHolidayRepository) Delete(id int) (*models.HolidayChange, error) {
 	args := m.Called(id)
-	return args.Error(0)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.HolidayChange), args.Error(1)
 }
 
-func (m *MockHolidayRepository) GetByDate(date time.Time) (*models.Holiday, error) {
-	args := m.Called(date)
+func (m *MockHolidayRepository) GetByDate(date time.Time, scope models.HolidayScope) ([]models.Holiday, error) {
+	args := m.Called(date, scope)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*models.Holiday), args.Error(1)
+	return args.Get(0).([]models.Holiday), args.Error(1)
 }
 
-func (m *MockHolidayRepository) GetByDateRange(startDate, endDate time.Time, holidayType *models.HolidayType) ([]models.Holiday, error) {
-	args := m.Called(startDate, endDate, holidayType)
+func (m *MockHolidayRepository) GetByDateRange(startDate, endDate time.Time, holidayType *models.HolidayType, scope models.HolidayScope) ([]models.Holiday, error) {
+	args := m.Called(startDate, endDate, holidayType, scope)
 	return args.Get(0).([]models.Holiday), args.Error(1)
 }
 
+func (m *MockHolidayRepository) BulkSave(creates []*models.Holiday, updates []*models.Holiday) ([]models.HolidayChange, error) {
+	args := m.Called(creates, updates)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.HolidayChange), args.Error(1)
+}
+
+func (m *MockHolidayRepository) GetRevisions(holidayID int) ([]models.HolidayRevision, error) {
+	args := m.Called(holidayID)
+	return args.Get(0).([]models.HolidayRevision), args.Error(1)
+}
+
+func (m *MockHolidayRepository) GetRevision(holidayID, revision int) (*models.HolidayRevision, error) {
+	args := m.Called(holidayID, revision)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.HolidayRevision), args.Error(1)
+}
+
+func (m *MockHolidayRepository) Rollback(holiday *models.Holiday, revision int) (*models.HolidayChange, error) {
+	args := m.Called(holiday, revision)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.HolidayChange), args.Error(1)
+}
+
 func TestHolidayService_CreateHoliday(t *testing.T) {
 	mockRepo := new(MockHolidayRepository)
-	service := NewHolidayService(mockRepo)
+	service := NewHolidayService(mockRepo, nil)
 
 	tests := []struct {
 		name        string
@@ -73,11 +112,42 @@ func TestHolidayService_CreateHoliday(t *testing.T) {
 			},
 			setupMock: func() {
 				date, _ := time.Parse("2006-01-02", "2024-12-25")
-				mockRepo.On("GetByDate", date).Return((*models.Holiday)(nil), nil)
-				mockRepo.On("Create", mock.AnythingOfType("*models.Holiday")).Return(nil)
+				mockRepo.On("GetByDate", date, models.HolidayScope{Statuses: models.AllHolidayStatuses}).Return([]models.Holiday{}, nil)
+				mockRepo.On("Create", mock.AnythingOfType("*models.Holiday")).Return(&models.HolidayChange{}, nil)
+			},
+			expectError: false,
+		},
+		{
+			name: "another holiday on the same date",
+			request: models.CreateHolidayRequest{
+				Name: "Maulid Nabi Muhammad SAW",
+				Date: "2024-08-17",
+				Type: models.NationalHoliday,
+			},
+			setupMock: func() {
+				date, _ := time.Parse("2006-01-02", "2024-08-17")
+				mockRepo.On("GetByDate", date, models.HolidayScope{Statuses: models.AllHolidayStatuses}).Return([]models.Holiday{
+					{ID: 1, Name: "Hari Kemerdekaan RI", Date: date, Type: models.NationalHoliday},
+				}, nil)
+				mockRepo.On("Create", mock.AnythingOfType("*models.Holiday")).Return(&models.HolidayChange{}, nil)
 			},
 			expectError: false,
 		},
+		{
+			name: "same name, date and type",
+			request: models.CreateHolidayRequest{
+				Name: "Hari Kemerdekaan RI",
+				Date: "2024-08-17",
+				Type: models.NationalHoliday,
+			},
+			setupMock: func() {
+				date, _ := time.Parse("2006-01-02", "2024-08-17")
+				mockRepo.On("GetByDate", date, models.HolidayScope{Statuses: models.AllHolidayStatuses}).Return([]models.Holiday{
+					{ID: 1, Name: "Hari Kemerdekaan RI", Date: date, Type: models.NationalHoliday},
+				}, nil)
+			},
+			expectError: true,
+		},
 		{
 			name: "invalid date format",
 			request: models.CreateHolidayRequest{
@@ -113,31 +183,29 @@ func TestHolidayService_CreateHoliday(t *testing.T) {
 
 func TestHolidayService_GetHolidayToday(t *testing.T) {
 	mockRepo := new(MockHolidayRepository)
-	service := NewHolidayService(mockRepo)
+	service := NewHolidayService(mockRepo, nil)
 
 	today := time.Now()
-	expectedHoliday := &models.Holiday{
-		ID:   1,
-		Name: "Today's Holiday",
-		Date: today,
-		Type: models.NationalHoliday,
+	expectedHolidays := []models.Holiday{
+		{ID: 1, Name: "Today's Holiday", Date: today, Type: models.NationalHoliday},
+		{ID: 2, Name: "Another Holiday", Date: today, Type: models.NationalHoliday},
 	}
 
 	mockRepo.On("GetByDate", mock.MatchedBy(func(date time.Time) bool {
 		return date.Format("2006-01-02") == today.Format("2006-01-02")
-	})).Return(expectedHoliday, nil)
+	}), models.HolidayScope{}).Return(expectedHolidays, nil)
 
-	holiday, err := service.GetHolidayToday()
+	holidays, err := service.GetHolidayToday(models.HolidayScope{})
 
 	assert.NoError(t, err)
-	assert.NotNil(t, holiday)
-	assert.Equal(t, expectedHoliday.Name, holiday.Name)
+	assert.Len(t, holidays, 2)
+	assert.Equal(t, expectedHolidays[0].Name, holidays[0].Name)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestHolidayService_GetHolidaysByYear(t *testing.T) {
 	mockRepo := new(MockHolidayRepository)
-	service := NewHolidayService(mockRepo)
+	service := NewHolidayService(mockRepo, nil)
 
 	year := 2024
 	expectedHolidays := []models.Holiday{
@@ -149,10 +217,358 @@ func TestHolidayService_GetHolidaysByYear(t *testing.T) {
 		return filter.Year != nil && *filter.Year == year
 	})).Return(expectedHolidays, len(expectedHolidays), nil)
 
-	holidays, err := service.GetHolidaysByYear(year)
+	holidays, err := service.GetHolidaysByYear(year, models.HolidayScope{})
 
 	assert.NoError(t, err)
 	assert.Len(t, holidays, 2)
 	assert.Equal(t, expectedHolidays[0].Name, holidays[0].Name)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestHolidayService_ImportHolidays(t *testing.T) {
+	existing := []models.Holiday{
+		{ID: 1, Name: "Tahun Baru Masehi", Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Type: models.NationalHoliday, Description: "Tahun Baru"},
+		{ID: 2, Name: "Tahun Baru Imlek", Date: time.Date(2025, 1, 29, 0, 0, 0, 0, time.UTC), Type: models.NationalHoliday},
+		{ID: 3, Name: "Hari Buruh Internasional", Date: time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC), Type: models.NationalHoliday},
+	}
+
+	rows := []models.CreateHolidayRequest{
+		{Name: "Tahun Baru Masehi", Date: "2025-01-01", Type: models.NationalHoliday, Description: "Tahun Baru"},
+		{Name: "Tahun Baru Imlek", Date: "2025-01-29", Type: models.NationalHoliday, Description: "Tahun Baru Imlek 2576 Kongzili"},
+		{Name: "Isra Mikraj Nabi Muhammad SAW", Date: "2025-01-27", Type: models.NationalHoliday},
+		{Name: "Hari Lainnya", Date: "2025-05-01", Type: models.NationalHoliday},
+		{Name: "Hari Lainnya", Date: "2025-05-01", Type: models.NationalHoliday},
+		{Name: "Cuti Bersama Imlek", Date: "2025-01-28", Type: "unknown"},
+	}
+
+	mockRepo := new(MockHolidayRepository)
+	service := NewHolidayService(mockRepo, nil)
+
+	mockRepo.On("GetByDateRange", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC), (*models.HolidayType)(nil), models.HolidayScope{Statuses: models.AllHolidayStatuses}).
+		Return(existing, nil)
+
+	result, err := service.ImportHolidays(rows, true)
+
+	assert.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.False(t, result.Committed)
+	assert.Equal(t, models.ImportUnchanged, result.Rows[0].Action)
+	assert.Equal(t, models.ImportUpdate, result.Rows[1].Action)
+	assert.Contains(t, result.Rows[1].Changes, "description")
+	assert.Equal(t, models.ImportCreate, result.Rows[2].Action)
+	assert.Equal(t, models.ImportCreate, result.Rows[3].Action)
+	assert.Equal(t, models.ImportConflict, result.Rows[4].Action)
+	assert.Equal(t, models.ImportInvalid, result.Rows[5].Action)
+	assert.Equal(t, 2, result.Created)
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, 1, result.Unchanged)
+	assert.Equal(t, 1, result.Conflicts)
+	assert.Equal(t, 1, result.Invalid)
+	mockRepo.AssertNotCalled(t, "BulkSave", mock.Anything, mock.Anything)
+}
+
+func TestHolidayService_ImportHolidaysCommits(t *testing.T) {
+	rows := []models.CreateHolidayRequest{
+		{Name: "Hari Raya Idul Fitri", Date: "2025-03-31", Type: models.NationalHoliday},
+		{Name: "Cuti Bersama Idul Fitri", Date: "2025-04-02", Type: models.CollectiveLeave},
+	}
+
+	mockRepo := new(MockHolidayRepository)
+	service := NewHolidayService(mockRepo, nil)
+
+	mockRepo.On("GetByDateRange", mock.Anything, mock.Anything, (*models.HolidayType)(nil), models.HolidayScope{Statuses: models.AllHolidayStatuses}).Return([]models.Holiday{}, nil)
+	mockRepo.On("BulkSave", mock.MatchedBy(func(creates []*models.Holiday) bool {
+		return len(creates) == 2
+	}), mock.Anything).Return([]models.HolidayChange{}, nil)
+
+	result, err := service.ImportHolidays(rows, false)
+
+	assert.NoError(t, err)
+	assert.True(t, result.Committed)
+	assert.Equal(t, 2, result.Created)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestHolidayService_ImportHolidaysRejectsDuplicateRows(t *testing.T) {
+	rows := []models.CreateHolidayRequest{
+		{Name: "Hari Raya Idul Fitri", Date: "2025-03-31", Type: models.NationalHoliday},
+		{Name: "Hari Raya Idul Fitri", Date: "2025-03-31", Type: models.NationalHoliday, Description: "Salinan"},
+	}
+
+	mockRepo := new(MockHolidayRepository)
+	service := NewHolidayService(mockRepo, nil)
+
+	mockRepo.On("GetByDateRange", mock.Anything, mock.Anything, (*models.HolidayType)(nil), models.HolidayScope{Statuses: models.AllHolidayStatuses}).Return([]models.Holiday{}, nil)
+
+	result, err := service.ImportHolidays(rows, false)
+
+	assert.NoError(t, err)
+	assert.False(t, result.Committed)
+	assert.Equal(t, models.ImportConflict, result.Rows[1].Action)
+	mockRepo.AssertNotCalled(t, "BulkSave", mock.Anything, mock.Anything)
+}
+
+func TestHolidayService_ImportHolidaysStatusChanges(t *testing.T) {
+	decreeNumber := "SKB 3 Menteri No. 1/2025"
+	decreeDate := "2024-10-14"
+	empty := ""
+
+	tests := []struct {
+		name           string
+		existing       models.HolidayStatus
+		row            models.CreateHolidayRequest
+		expectedAction models.ImportAction
+		expectedError  string
+	}{
+		{
+			name:           "status only change with decree",
+			existing:       models.StatusProvisional,
+			row:            models.CreateHolidayRequest{Status: models.StatusOfficial, DecreeNumber: &decreeNumber, DecreeDate: &decreeDate},
+			expectedAction: models.ImportUpdate,
+		},
+		{
+			name:           "status change without decree",
+			existing:       models.StatusProvisional,
+			row:            models.CreateHolidayRequest{Status: models.StatusOfficial, DecreeNumber: &empty},
+			expectedAction: models.ImportInvalid,
+			expectedError:  "a status change requires decree_number and decree_date",
+		},
+		{
+			name:           "illegal transition",
+			existing:       models.StatusOfficial,
+			row:            models.CreateHolidayRequest{Status: models.StatusProvisional, DecreeNumber: &decreeNumber, DecreeDate: &decreeDate},
+			expectedAction: models.ImportInvalid,
+			expectedError:  "invalid status transition from official to provisional",
+		},
+		{
+			name:           "row without status keeps the stored one",
+			existing:       models.StatusProvisional,
+			row:            models.CreateHolidayRequest{},
+			expectedAction: models.ImportUnchanged,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := tt.row
+			row.Name = "Hari Raya Idul Adha"
+			row.Date = "2025-06-06"
+			row.Type = models.NationalHoliday
+
+			existing := models.Holiday{ID: 4, Name: "Hari Raya Idul Adha", Date: time.Date(2025, 6, 6, 0, 0, 0, 0, time.UTC), Type: models.NationalHoliday, Status: tt.existing, IsActive: true}
+
+			mockRepo := new(MockHolidayRepository)
+			service := NewHolidayService(mockRepo, nil)
+
+			mockRepo.On("GetByDateRange", mock.Anything, mock.Anything, (*models.HolidayType)(nil), models.HolidayScope{Statuses: models.AllHolidayStatuses}).Return([]models.Holiday{existing}, nil)
+			mockRepo.On("BulkSave", mock.Anything, mock.Anything).Return([]models.HolidayChange{}, nil)
+
+			result, err := service.ImportHolidays([]models.CreateHolidayRequest{row}, false)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedAction, result.Rows[0].Action)
+			assert.Equal(t, tt.expectedError, result.Rows[0].Error)
+			if tt.expectedAction != models.ImportUpdate {
+				mockRepo.AssertNotCalled(t, "BulkSave", mock.Anything, mock.Anything)
+				return
+			}
+
+			assert.Equal(t, map[string]models.FieldChange{
+				"status":        {From: models.StatusProvisional, To: models.StatusOfficial},
+				"decree_number": {From: "", To: decreeNumber},
+				"decree_date":   {From: "", To: decreeDate},
+			}, result.Rows[0].Changes)
+			mockRepo.AssertCalled(t, "BulkSave", mock.Anything, mock.MatchedBy(func(updates []*models.Holiday) bool {
+				return len(updates) == 1 && updates[0].Status == models.StatusOfficial && *updates[0].DecreeNumber == decreeNumber
+			}))
+		})
+	}
+}
+
+func TestHolidayService_CreateRegionalHoliday(t *testing.T) {
+	bali := "ID-BA"
+	lowercase := "ba"
+	date := time.Date(2025, 3, 30, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		request     models.CreateHolidayRequest
+		existing    []models.Holiday
+		expectError bool
+	}{
+		{
+			name:    "regional holiday with normalized province",
+			request: models.CreateHolidayRequest{Name: "Ngembak Geni", Date: "2025-03-30", Type: models.RegionalHoliday, Province: &lowercase},
+		},
+		{
+			name:     "national holiday on the same date does not conflict",
+			request:  models.CreateHolidayRequest{Name: "Ngembak Geni", Date: "2025-03-30", Type: models.RegionalHoliday, Province: &bali},
+			existing: []models.Holiday{{ID: 1, Name: "Hari Raya Idul Fitri", Date: date, Type: models.NationalHoliday}},
+		},
+		{
+			name:     "another holiday in the same province does not conflict",
+			request:  models.CreateHolidayRequest{Name: "Ngembak Geni", Date: "2025-03-30", Type: models.RegionalHoliday, Province: &bali},
+			existing: []models.Holiday{{ID: 2, Name: "Hari Jadi", Date: date, Type: models.RegionalHoliday, Province: &bali}},
+		},
+		{
+			name:        "same holiday in the same province conflicts",
+			request:     models.CreateHolidayRequest{Name: "Ngembak Geni", Date: "2025-03-30", Type: models.RegionalHoliday, Province: &bali},
+			existing:    []models.Holiday{{ID: 2, Name: "Ngembak Geni", Date: date, Type: models.RegionalHoliday, Province: &bali}},
+			expectError: true,
+		},
+		{
+			name:        "regional holiday without province",
+			request:     models.CreateHolidayRequest{Name: "Ngembak Geni", Date: "2025-03-30", Type: models.RegionalHoliday},
+			expectError: true,
+		},
+		{
+			name:        "national holiday with province",
+			request:     models.CreateHolidayRequest{Name: "Hari Raya Nyepi", Date: "2025-03-29", Type: models.NationalHoliday, Province: &bali},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockHolidayRepository)
+			service := NewHolidayService(mockRepo, nil)
+
+			mockRepo.On("GetByDate", date, models.HolidayScope{Province: &bali, Statuses: models.AllHolidayStatuses}).Return(tt.existing, nil)
+			mockRepo.On("Create", mock.AnythingOfType("*models.Holiday")).Return(&models.HolidayChange{}, nil)
+
+			holiday, err := service.CreateHoliday(tt.request)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, bali, *holiday.Province)
+		})
+	}
+}
+
+func TestHolidayService_RollbackHoliday(t *testing.T) {
+	date := time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC)
+	revision := &models.HolidayRevision{
+		HolidayID: 7, Revision: 1, Operation: models.RevisionCreate,
+		Name: "Hari Raya Idul Fitri", Date: date, Type: models.NationalHoliday, IsActive: true,
+	}
+
+	t.Run("restores the revision", func(t *testing.T) {
+		mockRepo := new(MockHolidayRepository)
+		service := NewHolidayService(mockRepo, nil)
+
+		restored := &models.Holiday{ID: 7, Name: "Hari Raya Idul Fitri", Date: date, Type: models.NationalHoliday, IsActive: true}
+		mockRepo.On("GetRevision", 7, 1).Return(revision, nil)
+		mockRepo.On("GetByDate", date, models.HolidayScope{Statuses: models.AllHolidayStatuses}).Return([]models.Holiday{*restored}, nil)
+		mockRepo.On("Rollback", mock.MatchedBy(func(h *models.Holiday) bool {
+			return h.ID == 7 && h.Name == "Hari Raya Idul Fitri" && h.IsActive
+		}), 1).Return(&models.HolidayChange{}, nil)
+		mockRepo.On("GetByID", 7).Return(restored, nil)
+
+		holiday, err := service.RollbackHoliday(7, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, restored, holiday)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects a clash with another holiday", func(t *testing.T) {
+		mockRepo := new(MockHolidayRepository)
+		service := NewHolidayService(mockRepo, nil)
+
+		mockRepo.On("GetRevision", 7, 1).Return(revision, nil)
+		mockRepo.On("GetByDate", date, models.HolidayScope{Statuses: models.AllHolidayStatuses}).Return([]models.Holiday{
+			{ID: 8, Name: "Cuti Bersama Idul Fitri", Date: date, Type: models.CollectiveLeave},
+			{ID: 9, Name: "Hari Raya Idul Fitri", Date: date, Type: models.NationalHoliday},
+		}, nil)
+
+		holiday, err := service.RollbackHoliday(7, 1)
+
+		assert.EqualError(t, err, `holiday "Hari Raya Idul Fitri" already exists on date 2024-04-10`)
+		assert.Nil(t, holiday)
+		mockRepo.AssertNotCalled(t, "Rollback", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown revision", func(t *testing.T) {
+		mockRepo := new(MockHolidayRepository)
+		service := NewHolidayService(mockRepo, nil)
+
+		mockRepo.On("GetRevision", 7, 5).Return(nil, fmt.Errorf("holiday revision not found"))
+
+		_, err := service.RollbackHoliday(7, 5)
+
+		assert.EqualError(t, err, "holiday revision not found")
+	})
+}
+
+func TestHolidayService_UpdateHolidayStatus(t *testing.T) {
+	date := time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC)
+	official := models.StatusOfficial
+	revised := models.StatusRevised
+	provisional := models.StatusProvisional
+	decree := "SKB 3 Menteri No. 1497/2025"
+	decreeDate := "2025-10-14"
+
+	tests := []struct {
+		name        string
+		current     models.HolidayStatus
+		request     models.UpdateHolidayRequest
+		expectError string
+	}{
+		{
+			name:    "provisional to official with decree",
+			current: models.StatusProvisional,
+			request: models.UpdateHolidayRequest{Status: &official, DecreeNumber: &decree, DecreeDate: &decreeDate},
+		},
+		{
+			name:    "revised again by a later decree",
+			current: models.StatusRevised,
+			request: models.UpdateHolidayRequest{Status: &revised, DecreeNumber: &decree, DecreeDate: &decreeDate},
+		},
+		{
+			name:        "status change without decree",
+			current:     models.StatusProvisional,
+			request:     models.UpdateHolidayRequest{Status: &official},
+			expectError: "a status change requires decree_number and decree_date",
+		},
+		{
+			name:        "official back to provisional",
+			current:     models.StatusOfficial,
+			request:     models.UpdateHolidayRequest{Status: &provisional, DecreeNumber: &decree, DecreeDate: &decreeDate},
+			expectError: "invalid status transition from official to provisional",
+		},
+		{
+			name:        "provisional straight to revised",
+			current:     models.StatusProvisional,
+			request:     models.UpdateHolidayRequest{Status: &revised, DecreeNumber: &decree, DecreeDate: &decreeDate},
+			expectError: "invalid status transition from provisional to revised",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockHolidayRepository)
+			service := NewHolidayService(mockRepo, nil)
+
+			mockRepo.On("GetByID", 5).Return(&models.Holiday{
+				ID: 5, Name: "Hari Raya Idul Fitri", Date: date, Type: models.NationalHoliday, IsActive: true, Status: tt.current,
+			}, nil)
+			mockRepo.On("Update", 5, mock.AnythingOfType("*models.Holiday")).Return(&models.HolidayChange{}, nil)
+
+			holiday, err := service.UpdateHoliday(5, tt.request)
+
+			if tt.expectError != "" {
+				assert.EqualError(t, err, tt.expectError)
+				mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, *tt.request.Status, holiday.Status)
+			assert.Equal(t, decree, *holiday.DecreeNumber)
+			assert.Equal(t, decreeDate, holiday.DecreeDate.Format("2006-01-02"))
+		})
+	}
+}
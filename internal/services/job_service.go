@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/repository"
+	"github.com/ilramdhan/holidayapi/internal/scheduler"
+)
+
+// systemUsername is recorded as the actor of audit entries made by background jobs
+const systemUsername = "system"
+
+// JobService handles background job registration, status and manual runs
+type JobService interface {
+	AddMaintenanceJob(job scheduler.Job)
+	ListJobs() []scheduler.Status
+	GetJob(name string) (*scheduler.Status, error)
+	TriggerJob(name string, triggeredBy *models.User, ipAddress, userAgent string) error
+}
+
+// jobService implements JobService
+type jobService struct {
+	jobs      *scheduler.Scheduler
+	auditRepo repository.AuditRepository
+}
+
+// NewJobService creates a new job service on top of a scheduler
+func NewJobService(jobs *scheduler.Scheduler, auditRepo repository.AuditRepository) JobService {
+	return &jobService{
+		jobs:      jobs,
+		auditRepo: auditRepo,
+	}
+}
+
+// AddMaintenanceJob registers a job whose every run is recorded in the audit log as a system action
+func (s *jobService) AddMaintenanceJob(job scheduler.Job) {
+	run := job.Run
+	job.Run = func(ctx context.Context) (string, error) {
+		started := time.Now()
+		result, err := run(ctx)
+		took := time.Since(started).Round(time.Millisecond)
+
+		if err != nil {
+			s.logAudit(nil, models.ActionJobRun,
+				fmt.Sprintf("Job %s failed after %s: %v", job.Name, took, err), "", "", false)
+		} else {
+			s.logAudit(nil, models.ActionJobRun,
+				fmt.Sprintf("Job %s finished in %s: %s", job.Name, took, result), "", "", true)
+		}
+
+		return result, err
+	}
+
+	s.jobs.Add(job)
+}
+
+// ListJobs returns the status of every registered job
+func (s *jobService) ListJobs() []scheduler.Status {
+	return s.jobs.Statuses()
+}
+
+// GetJob returns the status of a job by name
+func (s *jobService) GetJob(name string) (*scheduler.Status, error) {
+	status, found := s.jobs.Status(name)
+	if !found {
+		return nil, fmt.Errorf("job not found")
+	}
+
+	return &status, nil
+}
+
+// TriggerJob asks a job to run now; the run itself happens in the background
+func (s *jobService) TriggerJob(name string, triggeredBy *models.User, ipAddress, userAgent string) error {
+	if err := s.jobs.Trigger(name); err != nil {
+		s.logAudit(triggeredBy, models.ActionJobTrigger,
+			fmt.Sprintf("Failed to trigger job %s: %v", name, err), ipAddress, userAgent, false)
+		return err
+	}
+
+	s.logAudit(triggeredBy, models.ActionJobTrigger,
+		fmt.Sprintf("Triggered job: %s", name), ipAddress, userAgent, true)
+
+	return nil
+}
+
+// logAudit logs an audit entry for a job; a nil user records the entry as made by the system
+func (s *jobService) logAudit(user *models.User, action models.AuditAction, details, ipAddress, userAgent string, success bool) {
+	auditLog := &models.AuditLog{
+		Username:  systemUsername,
+		Action:    action,
+		Resource:  models.ResourceSystem,
+		Details:   details,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		Success:   success,
+	}
+	if user != nil {
+		auditLog.UserID = &user.ID
+		auditLog.Username = user.Username
+	}
+
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		fmt.Printf("Failed to create audit log: %v\n", err)
+	}
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/scheduler"
+)
+
+func TestJobService_MaintenanceJobRunsAreAudited(t *testing.T) {
+	mockAudit := new(MockAuditRepository)
+	jobs := scheduler.New()
+	service := NewJobService(jobs, mockAudit)
+
+	service.AddMaintenanceJob(scheduler.Job{Name: "audit-retention", Interval: time.Hour, Run: func(ctx context.Context) (string, error) {
+		return "deleted 4 audit logs older than 90 days", nil
+	}})
+	service.AddMaintenanceJob(scheduler.Job{Name: "disabled", Interval: 0, Run: func(ctx context.Context) (string, error) {
+		return "", nil
+	}})
+
+	recorded := make(chan *models.AuditLog, 1)
+	mockAudit.On("Create", mock.AnythingOfType("*models.AuditLog")).
+		Run(func(args mock.Arguments) { recorded <- args.Get(0).(*models.AuditLog) }).
+		Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		jobs.Wait()
+	}()
+	jobs.Start(ctx)
+
+	select {
+	case log := <-recorded:
+		assert.Nil(t, log.UserID)
+		assert.Equal(t, "system", log.Username)
+		assert.Equal(t, models.ActionJobRun, log.Action)
+		assert.Equal(t, models.ResourceSystem, log.Resource)
+		assert.True(t, log.Success)
+		assert.True(t, strings.HasPrefix(log.Details, "Job audit-retention finished in "))
+		assert.True(t, strings.HasSuffix(log.Details, ": deleted 4 audit logs older than 90 days"))
+	case <-time.After(time.Second):
+		t.Fatal("job run was not audited")
+	}
+
+	statuses := service.ListJobs()
+	require.Len(t, statuses, 1, "a job without an interval is disabled")
+	assert.Equal(t, "audit-retention", statuses[0].Name)
+}
+
+func TestJobService_TriggerJob(t *testing.T) {
+	mockAudit := new(MockAuditRepository)
+	jobs := scheduler.New()
+	service := NewJobService(jobs, mockAudit)
+	admin := &models.User{ID: 1, Username: "superadmin", Role: models.SuperAdminRole}
+
+	jobs.Add(scheduler.Job{Name: "token-purge", Interval: time.Hour, Run: func(ctx context.Context) (string, error) {
+		return "", nil
+	}})
+
+	mockAudit.On("Create", mock.MatchedBy(func(log *models.AuditLog) bool {
+		return log.Action == models.ActionJobTrigger && log.Success && *log.UserID == 1 && log.Details == "Triggered job: token-purge"
+	})).Return(nil).Once()
+	mockAudit.On("Create", mock.MatchedBy(func(log *models.AuditLog) bool {
+		return log.Action == models.ActionJobTrigger && !log.Success
+	})).Return(nil).Once()
+
+	require.NoError(t, service.TriggerJob("token-purge", admin, "127.0.0.1", "test"))
+	assert.EqualError(t, service.TriggerJob("missing", admin, "127.0.0.1", "test"), "job not found")
+
+	status, err := service.GetJob("token-purge")
+	require.NoError(t, err)
+	assert.Equal(t, 0, status.Runs)
+
+	_, err = service.GetJob("missing")
+	assert.Error(t, err)
+
+	mockAudit.AssertExpectations(t)
+}
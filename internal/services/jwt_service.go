@@ -14,7 +14,8 @@ type JWTService interface {
 	GenerateTokens(user *models.User) (*models.AuthResponse, error)
 	ValidateAccessToken(tokenString string) (*models.JWTClaims, error)
 	ValidateRefreshToken(tokenString string) (*models.JWTClaims, error)
-	RefreshTokens(refreshToken string, userRepo UserRepository) (*models.AuthResponse, error)
+	GenerateMFAToken(user *models.User, ttl time.Duration) (string, error)
+	ValidateMFAToken(tokenString string) (*models.JWTClaims, error)
 }
 
 // jwtService implements JWTService
@@ -48,31 +49,52 @@ func (s *jwtService) GenerateTokens(user *models.User) (*models.AuthResponse, er
 	}
 
 	return &models.AuthResponse{
-		User:         user.ToUserResponse(),
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiresIn:    int64(s.accessTokenTTL.Seconds()),
-		TokenType:    "Bearer",
+		User:             user.ToUserResponse(),
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		ExpiresIn:        int64(s.accessTokenTTL.Seconds()),
+		RefreshExpiresIn: int64(s.refreshTokenTTL.Seconds()),
+		TokenType:        "Bearer",
 	}, nil
 }
 
 // generateToken generates a JWT token
 func (s *jwtService) generateToken(user *models.User, tokenType string, ttl time.Duration) (string, error) {
+	// A random token ID keeps tokens unique even when issued within the same second
+	jti, err := generateRandomID(16)
+	if err != nil {
+		return "", err
+	}
+
 	now := time.Now()
 	claims := jwt.MapClaims{
-		"user_id":  user.ID,
-		"username": user.Username,
-		"role":     user.Role,
-		"type":     tokenType,
-		"iat":      now.Unix(),
-		"exp":      now.Add(ttl).Unix(),
-		"iss":      "holidayapi",
+		"jti":         jti,
+		"user_id":     user.ID,
+		"username":    user.Username,
+		"role":        user.Role,
+		"permissions": user.Permissions,
+		"type":        tokenType,
+		"iat":         now.Unix(),
+		"exp":         now.Add(ttl).Unix(),
+		"iss":         "holidayapi",
+		// Set while the user still has to replace a temporary password
+		"password_change_required": user.MustChangePassword,
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString(s.secretKey)
 }
 
+// GenerateMFAToken generates the short-lived token that identifies a login waiting for its second factor.
+// It has its own type, so it cannot be used as an access or refresh token.
+func (s *jwtService) GenerateMFAToken(user *models.User, ttl time.Duration) (string, error) {
+	token, err := s.generateToken(user, "mfa", ttl)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate mfa token: %w", err)
+	}
+	return token, nil
+}
+
 // ValidateAccessToken validates an access token
 func (s *jwtService) ValidateAccessToken(tokenString string) (*models.JWTClaims, error) {
 	return s.validateToken(tokenString, "access")
@@ -83,6 +105,11 @@ func (s *jwtService) ValidateRefreshToken(tokenString string) (*models.JWTClaims
 	return s.validateToken(tokenString, "refresh")
 }
 
+// ValidateMFAToken validates an MFA token
+func (s *jwtService) ValidateMFAToken(tokenString string) (*models.JWTClaims, error) {
+	return s.validateToken(tokenString, "mfa")
+}
+
 // validateToken validates a JWT token
 func (s *jwtService) validateToken(tokenString, expectedType string) (*models.JWTClaims, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
@@ -133,31 +160,25 @@ func (s *jwtService) validateToken(tokenString, expectedType string) (*models.JW
 		return nil, fmt.Errorf("invalid role claim")
 	}
 
-	return &models.JWTClaims{
-		UserID:   int(userID),
-		Username: username,
-		Role:     models.UserRole(role),
-		Type:     tokenType,
-	}, nil
-}
-
-// RefreshTokens generates new tokens using a refresh token
-func (s *jwtService) RefreshTokens(refreshToken string, userRepo UserRepository) (*models.AuthResponse, error) {
-	claims, err := s.ValidateRefreshToken(refreshToken)
-	if err != nil {
-		return nil, fmt.Errorf("invalid refresh token: %w", err)
-	}
-
-	// Fetch fresh user data from database
-	user, err := userRepo.GetByID(claims.UserID)
-	if err != nil {
-		return nil, fmt.Errorf("user not found: %w", err)
+	// Absent in tokens issued before roles had permissions, which then grant none
+	var permissions []models.Permission
+	if values, ok := claims["permissions"].([]interface{}); ok {
+		for _, value := range values {
+			if permission, ok := value.(string); ok {
+				permissions = append(permissions, models.Permission(permission))
+			}
+		}
 	}
 
-	// Check if user is still active
-	if !user.IsActive {
-		return nil, fmt.Errorf("user account is deactivated")
-	}
+	// Absent in tokens issued before forced password changes existed
+	passwordChangeRequired, _ := claims["password_change_required"].(bool)
 
-	return s.GenerateTokens(user)
+	return &models.JWTClaims{
+		UserID:                 int(userID),
+		Username:               username,
+		Role:                   models.UserRole(role),
+		Permissions:            permissions,
+		Type:                   tokenType,
+		PasswordChangeRequired: passwordChangeRequired,
+	}, nil
 }
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ilramdhan/holidayapi/internal/config"
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/repository"
+)
+
+// LoginLockedError is returned for logins refused because of too many failed attempts.
+// The message is the same whichever lock applies and whether or not the username exists.
+type LoginLockedError struct {
+	RetryAfter time.Duration // Time left until the lock is lifted
+}
+
+func (e *LoginLockedError) Error() string {
+	return "too many failed login attempts, try again later"
+}
+
+// LoginLockoutService tracks failed logins per username and per IP address and locks them out
+// for a while after too many, doubling the lock each time it is applied again
+type LoginLockoutService interface {
+	Check(username, ipAddress string) error
+	RecordFailure(username, ipAddress, userAgent string)
+	RecordSuccess(username, ipAddress string)
+	ListLocks() ([]models.LoginLockout, error)
+	ClearLock(id int, clearedBy *models.User, ipAddress, userAgent string) error
+	PurgeStale() (int64, error)
+}
+
+// loginLockoutService implements LoginLockoutService
+type loginLockoutService struct {
+	lockoutRepo repository.LoginLockoutRepository
+	auditRepo   repository.AuditRepository
+	cfg         config.LockoutConfig
+}
+
+// NewLoginLockoutService creates a new login lockout service
+func NewLoginLockoutService(lockoutRepo repository.LoginLockoutRepository, auditRepo repository.AuditRepository, cfg config.LockoutConfig) LoginLockoutService {
+	return &loginLockoutService{
+		lockoutRepo: lockoutRepo,
+		auditRepo:   auditRepo,
+		cfg:         cfg,
+	}
+}
+
+// lockoutKey identifies the failed login record of one username or IP address
+type lockoutKey struct {
+	scope       models.LockoutScope
+	key         string
+	maxAttempts int
+}
+
+// Check returns a *LoginLockedError when the username or the IP address is locked. Lookup
+// failures are logged and do not block the login.
+func (s *loginLockoutService) Check(username, ipAddress string) error {
+	now := time.Now()
+	var retryAfter time.Duration
+
+	for _, k := range s.keys(username, ipAddress) {
+		lockout, err := s.lockoutRepo.Get(k.scope, k.key)
+		if err != nil {
+			if !errors.Is(err, repository.ErrLoginLockoutNotFound) {
+				fmt.Printf("Failed to check login lockout of %s %s: %v\n", k.scope, k.key, err)
+			}
+			continue
+		}
+
+		if lockout.IsLocked(now) {
+			if left := lockout.LockedUntil.Sub(now); left > retryAfter {
+				retryAfter = left
+			}
+		}
+	}
+
+	if retryAfter > 0 {
+		return &LoginLockedError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// RecordFailure counts a failed login against the username and the IP address and locks
+// either once it reaches its limit. Errors are logged rather than failing the login request.
+func (s *loginLockoutService) RecordFailure(username, ipAddress, userAgent string) {
+	now := time.Now()
+
+	for _, k := range s.keys(username, ipAddress) {
+		lockout, err := s.lockoutRepo.RecordFailure(k.scope, k.key, now, now.Add(-s.cfg.ResetAfter))
+		if err != nil {
+			fmt.Printf("Failed to record failed login of %s %s: %v\n", k.scope, k.key, err)
+			continue
+		}
+
+		if lockout.FailedAttempts < k.maxAttempts {
+			continue
+		}
+
+		lockouts := lockout.Lockouts + 1
+		duration := s.lockDuration(lockouts)
+		if err := s.lockoutRepo.Lock(lockout.ID, lockouts, now.Add(duration)); err != nil {
+			fmt.Printf("Failed to lock login of %s %s: %v\n", k.scope, k.key, err)
+			continue
+		}
+
+		s.logAudit(nil, username, models.ActionLoginLocked,
+			fmt.Sprintf("Login locked for %s %s for %s after %d failed attempts", k.scope, k.key, duration, lockout.FailedAttempts),
+			ipAddress, userAgent, true)
+	}
+}
+
+// RecordSuccess forgets the failed logins of a username after it logged in. The IP address
+// record decays instead of being reset: each successful login from it takes one failure off its
+// count, so users behind a shared address who mistype now and then are not locked out, while one
+// valid account still cannot wipe out the failures of guessing at others. A lock in force stays.
+func (s *loginLockoutService) RecordSuccess(username, ipAddress string) {
+	if s.cfg.MaxAttempts > 0 {
+		key := normalizeLockoutUsername(username)
+		if err := s.lockoutRepo.Delete(models.LockoutScopeUsername, key); err != nil {
+			fmt.Printf("Failed to clear failed logins of username %s: %v\n", key, err)
+		}
+	}
+
+	if s.cfg.IPMaxAttempts > 0 && ipAddress != "" {
+		if err := s.lockoutRepo.ForgiveFailure(models.LockoutScopeIP, ipAddress); err != nil {
+			fmt.Printf("Failed to forgive failed login of ip %s: %v\n", ipAddress, err)
+		}
+	}
+}
+
+// ListLocks returns the usernames and IP addresses that are currently locked
+func (s *loginLockoutService) ListLocks() ([]models.LoginLockout, error) {
+	lockouts, err := s.lockoutRepo.GetLocked(time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get login locks: %w", err)
+	}
+	return lockouts, nil
+}
+
+// ClearLock lifts a lock and forgets the failed logins it counted
+func (s *loginLockoutService) ClearLock(id int, clearedBy *models.User, ipAddress, userAgent string) error {
+	lockout, err := s.lockoutRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.lockoutRepo.DeleteByID(id); err != nil {
+		s.logAudit(&clearedBy.ID, clearedBy.Username, models.ActionLoginUnlock,
+			fmt.Sprintf("Failed to clear login lock of %s %s", lockout.Scope, lockout.Key), ipAddress, userAgent, false)
+		return err
+	}
+
+	s.logAudit(&clearedBy.ID, clearedBy.Username, models.ActionLoginUnlock,
+		fmt.Sprintf("Cleared login lock of %s %s", lockout.Scope, lockout.Key), ipAddress, userAgent, true)
+
+	return nil
+}
+
+// PurgeStale deletes the records of usernames and IP addresses that are not locked and
+// have not failed a login within the reset period, and returns how many were deleted
+func (s *loginLockoutService) PurgeStale() (int64, error) {
+	now := time.Now()
+
+	deleted, err := s.lockoutRepo.DeleteStale(now.Add(-s.cfg.ResetAfter), now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge failed login records: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// keys returns the records a login attempt counts against, skipping disabled lockouts
+func (s *loginLockoutService) keys(username, ipAddress string) []lockoutKey {
+	var keys []lockoutKey
+	if s.cfg.MaxAttempts > 0 {
+		keys = append(keys, lockoutKey{models.LockoutScopeUsername, normalizeLockoutUsername(username), s.cfg.MaxAttempts})
+	}
+	if s.cfg.IPMaxAttempts > 0 && ipAddress != "" {
+		keys = append(keys, lockoutKey{models.LockoutScopeIP, ipAddress, s.cfg.IPMaxAttempts})
+	}
+	return keys
+}
+
+// lockDuration returns how long the nth lock lasts: the base duration doubled for each
+// earlier lock, capped at the maximum duration
+func (s *loginLockoutService) lockDuration(lockouts int) time.Duration {
+	duration := s.cfg.BaseDuration
+	for i := 1; i < lockouts && duration < s.cfg.MaxDuration; i++ {
+		duration *= 2
+	}
+	if s.cfg.MaxDuration > 0 && duration > s.cfg.MaxDuration {
+		duration = s.cfg.MaxDuration
+	}
+	return duration
+}
+
+// normalizeLockoutUsername makes differently typed forms of a username share one record
+func normalizeLockoutUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+// logAudit logs an audit entry
+func (s *loginLockoutService) logAudit(userID *int, username string, action models.AuditAction, details, ipAddress, userAgent string, success bool) {
+	auditLog := &models.AuditLog{
+		UserID:    userID,
+		Username:  username,
+		Action:    action,
+		Resource:  models.ResourceAuth,
+		Details:   details,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		Success:   success,
+	}
+
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		fmt.Printf("Failed to create audit log: %v\n", err)
+	}
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilramdhan/holidayapi/internal/config"
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/repository"
+)
+
+// MockLoginLockoutRepository is a mock implementation of LoginLockoutRepository
+type MockLoginLockoutRepository struct {
+	mock.Mock
+}
+
+func (m *MockLoginLockoutRepository) Get(scope models.LockoutScope, key string) (*models.LoginLockout, error) {
+	args := m.Called(scope, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoginLockout), args.Error(1)
+}
+
+func (m *MockLoginLockoutRepository) GetByID(id int) (*models.LoginLockout, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoginLockout), args.Error(1)
+}
+
+func (m *MockLoginLockoutRepository) GetLocked(now time.Time) ([]models.LoginLockout, error) {
+	args := m.Called(now)
+	return args.Get(0).([]models.LoginLockout), args.Error(1)
+}
+
+func (m *MockLoginLockoutRepository) RecordFailure(scope models.LockoutScope, key string, at, resetBefore time.Time) (*models.LoginLockout, error) {
+	args := m.Called(scope, key, at, resetBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoginLockout), args.Error(1)
+}
+
+func (m *MockLoginLockoutRepository) Lock(id, lockouts int, until time.Time) error {
+	args := m.Called(id, lockouts, until)
+	return args.Error(0)
+}
+
+func (m *MockLoginLockoutRepository) ForgiveFailure(scope models.LockoutScope, key string) error {
+	args := m.Called(scope, key)
+	return args.Error(0)
+}
+
+func (m *MockLoginLockoutRepository) Delete(scope models.LockoutScope, key string) error {
+	args := m.Called(scope, key)
+	return args.Error(0)
+}
+
+func (m *MockLoginLockoutRepository) DeleteByID(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockLoginLockoutRepository) DeleteStale(before, now time.Time) (int64, error) {
+	args := m.Called(before, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// testLockoutConfig locks a username after 3 failures and an IP address after 10
+var testLockoutConfig = config.LockoutConfig{
+	MaxAttempts:   3,
+	IPMaxAttempts: 10,
+	BaseDuration:  time.Minute,
+	MaxDuration:   time.Hour,
+	ResetAfter:    24 * time.Hour,
+}
+
+func TestLoginLockoutService_LockDuration(t *testing.T) {
+	service := &loginLockoutService{cfg: testLockoutConfig}
+
+	assert.Equal(t, time.Minute, service.lockDuration(1))
+	assert.Equal(t, 2*time.Minute, service.lockDuration(2))
+	assert.Equal(t, 4*time.Minute, service.lockDuration(3))
+	assert.Equal(t, time.Hour, service.lockDuration(8))
+	assert.Equal(t, time.Hour, service.lockDuration(100))
+}
+
+func TestLoginLockoutService_RecordFailure(t *testing.T) {
+	lockoutRepo := new(MockLoginLockoutRepository)
+	auditRepo := new(MockAuditRepository)
+	auditRepo.On("Create", mock.Anything).Return(nil)
+
+	// The username reaches its limit for the second time; the IP address is still below its limit
+	lockoutRepo.On("RecordFailure", models.LockoutScopeUsername, "admin", mock.Anything, mock.Anything).
+		Return(&models.LoginLockout{ID: 1, Scope: models.LockoutScopeUsername, Key: "admin", FailedAttempts: 3, Lockouts: 1}, nil)
+	lockoutRepo.On("RecordFailure", models.LockoutScopeIP, "10.0.0.1", mock.Anything, mock.Anything).
+		Return(&models.LoginLockout{ID: 2, Scope: models.LockoutScopeIP, Key: "10.0.0.1", FailedAttempts: 4}, nil)
+	lockoutRepo.On("Lock", 1, 2, mock.AnythingOfType("time.Time")).Return(nil)
+
+	service := NewLoginLockoutService(lockoutRepo, auditRepo, testLockoutConfig)
+	before := time.Now()
+	service.RecordFailure(" Admin ", "10.0.0.1", "test")
+
+	lock := lockoutRepo.Calls[1]
+	require.Equal(t, "Lock", lock.Method)
+	until := lock.Arguments.Get(2).(time.Time)
+	assert.WithinDuration(t, before.Add(2*time.Minute), until, time.Second)
+	lockoutRepo.AssertNotCalled(t, "Lock", 2, mock.Anything, mock.Anything)
+	assert.Equal(t, []string{"LOGIN_LOCKED:true"}, auditActions(auditRepo))
+}
+
+func TestLoginLockoutService_RecordSuccess(t *testing.T) {
+	t.Run("clears the username and decays the IP address", func(t *testing.T) {
+		lockoutRepo := new(MockLoginLockoutRepository)
+		lockoutRepo.On("Delete", models.LockoutScopeUsername, "admin").Return(nil)
+		lockoutRepo.On("ForgiveFailure", models.LockoutScopeIP, "10.0.0.1").Return(nil)
+
+		NewLoginLockoutService(lockoutRepo, new(MockAuditRepository), testLockoutConfig).RecordSuccess(" Admin ", "10.0.0.1")
+
+		lockoutRepo.AssertExpectations(t)
+		lockoutRepo.AssertNotCalled(t, "Delete", models.LockoutScopeIP, mock.Anything)
+	})
+
+	t.Run("skips disabled lockouts", func(t *testing.T) {
+		lockoutRepo := new(MockLoginLockoutRepository)
+		lockoutRepo.On("Delete", models.LockoutScopeUsername, "admin").Return(nil)
+
+		cfg := testLockoutConfig
+		cfg.IPMaxAttempts = 0
+		NewLoginLockoutService(lockoutRepo, new(MockAuditRepository), cfg).RecordSuccess("admin", "10.0.0.1")
+
+		lockoutRepo.AssertExpectations(t)
+		lockoutRepo.AssertNotCalled(t, "ForgiveFailure", mock.Anything, mock.Anything)
+	})
+}
+
+func TestLoginLockoutService_Check(t *testing.T) {
+	lockedUntil := time.Now().Add(90 * time.Second)
+	expired := time.Now().Add(-time.Second)
+
+	tests := []struct {
+		name       string
+		username   *models.LoginLockout
+		ip         *models.LoginLockout
+		retryAfter time.Duration
+	}{
+		{name: "no failures"},
+		{name: "below the limit", username: &models.LoginLockout{FailedAttempts: 2}},
+		{name: "lock expired", username: &models.LoginLockout{LockedUntil: &expired}},
+		{name: "username locked", username: &models.LoginLockout{LockedUntil: &lockedUntil}, retryAfter: 90 * time.Second},
+		{name: "ip locked", ip: &models.LoginLockout{LockedUntil: &lockedUntil}, retryAfter: 90 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lockoutRepo := new(MockLoginLockoutRepository)
+			for scope, lockout := range map[models.LockoutScope]*models.LoginLockout{
+				models.LockoutScopeUsername: tt.username,
+				models.LockoutScopeIP:       tt.ip,
+			} {
+				if lockout == nil {
+					lockoutRepo.On("Get", scope, mock.Anything).Return(nil, repository.ErrLoginLockoutNotFound)
+				} else {
+					lockoutRepo.On("Get", scope, mock.Anything).Return(lockout, nil)
+				}
+			}
+
+			service := NewLoginLockoutService(lockoutRepo, new(MockAuditRepository), testLockoutConfig)
+			err := service.Check("admin", "10.0.0.1")
+
+			if tt.retryAfter == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			var locked *LoginLockedError
+			require.True(t, errors.As(err, &locked))
+			assert.InDelta(t, tt.retryAfter.Seconds(), locked.RetryAfter.Seconds(), 1)
+		})
+	}
+}
+
+func TestAuthService_LoginLockedOut(t *testing.T) {
+	lockedUntil := time.Now().Add(time.Minute)
+
+	lockoutRepo := new(MockLoginLockoutRepository)
+	lockoutRepo.On("Get", models.LockoutScopeUsername, "admin").Return(&models.LoginLockout{LockedUntil: &lockedUntil}, nil)
+	lockoutRepo.On("Get", models.LockoutScopeUsername, "nobody").Return(&models.LoginLockout{LockedUntil: &lockedUntil}, nil)
+	lockoutRepo.On("Get", models.LockoutScopeIP, "127.0.0.1").Return(nil, repository.ErrLoginLockoutNotFound)
+
+	userRepo := new(MockUserRepository)
+	auditRepo := new(MockAuditRepository)
+	auditRepo.On("Create", mock.Anything).Return(nil)
+	lockout := NewLoginLockoutService(lockoutRepo, auditRepo, testLockoutConfig)
+	service := NewAuthService(userRepo, auditRepo, new(MockRefreshTokenRepository), new(MockMFARepository), newTestRoleRepository(), lockout,
+		NewJWTService("test-secret", 15*time.Minute, 24*time.Hour), config.MFAConfig{})
+
+	// Existing and unknown usernames get the same answer, and the password is not checked
+	_, _, existingErr := service.Login(models.LoginRequest{Username: "admin", Password: "secret"}, "127.0.0.1", "test")
+	_, _, unknownErr := service.Login(models.LoginRequest{Username: "nobody", Password: "secret"}, "127.0.0.1", "test")
+
+	var locked *LoginLockedError
+	require.True(t, errors.As(existingErr, &locked))
+	assert.Equal(t, existingErr.Error(), unknownErr.Error())
+	userRepo.AssertNotCalled(t, "GetByUsername", mock.Anything)
+	userRepo.AssertNotCalled(t, "CheckPassword", mock.Anything, mock.Anything)
+	assert.Equal(t, []string{"LOGIN_FAILED:false", "LOGIN_FAILED:false"}, auditActions(auditRepo))
+}
+
+func TestAuthService_LoginRecordsFailures(t *testing.T) {
+	lockoutRepo := new(MockLoginLockoutRepository)
+	lockoutRepo.On("Get", mock.Anything, mock.Anything).Return(nil, repository.ErrLoginLockoutNotFound)
+	lockoutRepo.On("RecordFailure", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(&models.LoginLockout{ID: 1, FailedAttempts: 1}, nil)
+	lockoutRepo.On("Delete", models.LockoutScopeUsername, "admin").Return(nil)
+	lockoutRepo.On("ForgiveFailure", models.LockoutScopeIP, "127.0.0.1").Return(nil)
+
+	userRepo := new(MockUserRepository)
+	tokenRepo := new(MockRefreshTokenRepository)
+	auditRepo := new(MockAuditRepository)
+	auditRepo.On("Create", mock.Anything).Return(nil)
+	mfaRepo := new(MockMFARepository)
+	mfaRepo.On("Get", 1).Return(nil, repository.ErrMFANotFound)
+	lockout := NewLoginLockoutService(lockoutRepo, auditRepo, testLockoutConfig)
+	service := NewAuthService(userRepo, auditRepo, tokenRepo, mfaRepo, newTestRoleRepository(), lockout,
+		NewJWTService("test-secret", 15*time.Minute, 24*time.Hour), config.MFAConfig{})
+
+	user := &models.User{ID: 1, Username: "admin", Password: "hash", Role: models.SuperAdminRole, IsActive: true}
+	mockLogin(userRepo, tokenRepo, user)
+	userRepo.On("CheckPassword", "hash", "wrong").Return(fmt.Errorf("mismatch"))
+
+	_, _, err := service.Login(models.LoginRequest{Username: "admin", Password: "wrong"}, "127.0.0.1", "test")
+	assert.EqualError(t, err, "invalid credentials")
+	lockoutRepo.AssertCalled(t, "RecordFailure", models.LockoutScopeUsername, "admin", mock.Anything, mock.Anything)
+	lockoutRepo.AssertCalled(t, "RecordFailure", models.LockoutScopeIP, "127.0.0.1", mock.Anything, mock.Anything)
+
+	// A successful login forgets the failures of the username and takes one off the IP address
+	_, _, err = service.Login(models.LoginRequest{Username: "admin", Password: "secret"}, "127.0.0.1", "test")
+	require.NoError(t, err)
+	lockoutRepo.AssertCalled(t, "Delete", models.LockoutScopeUsername, "admin")
+	lockoutRepo.AssertNotCalled(t, "Delete", models.LockoutScopeIP, mock.Anything)
+	lockoutRepo.AssertCalled(t, "ForgiveFailure", models.LockoutScopeIP, "127.0.0.1")
+}
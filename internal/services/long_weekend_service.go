@@ -0,0 +1,144 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/repository"
+)
+
+const (
+	// minLongWeekendDays is the shortest run of days off reported as a long weekend
+	minLongWeekendDays = 3
+	// minBridgedBreakDays is the shortest break worth taking a bridge day for
+	minBridgedBreakDays = 4
+	// longWeekendMarginDays extends the loaded range so breaks spanning New Year are complete
+	longWeekendMarginDays = 21
+)
+
+// LongWeekendService finds long weekends and bridge days on top of the holiday data
+type LongWeekendService interface {
+	FindLongWeekends(year int, opts models.WorkdayOptions) (*models.LongWeekendResponse, error)
+}
+
+// longWeekendService implements LongWeekendService
+type longWeekendService struct {
+	repo repository.HolidayRepository
+}
+
+// NewLongWeekendService creates a new long weekend service
+func NewLongWeekendService(repo repository.HolidayRepository) LongWeekendService {
+	return &longWeekendService{repo: repo}
+}
+
+// offRun is a maximal run of consecutive days off, as indexes into the loaded days
+type offRun struct {
+	start, end int
+	holidays   []models.Holiday
+}
+
+// FindLongWeekends merges weekends and holidays into runs of days off and returns those
+// overlapping the year that are long weekends or can be extended with a bridge day
+func (s *longWeekendService) FindLongWeekends(year int, opts models.WorkdayOptions) (*models.LongWeekendResponse, error) {
+	if year < 1 || year > 9999 {
+		return nil, fmt.Errorf("year must be between 1 and 9999")
+	}
+
+	yearStart := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	yearEnd := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	first := yearStart.AddDate(0, 0, -longWeekendMarginDays)
+	last := yearEnd.AddDate(0, 0, longWeekendMarginDays)
+
+	daysOff, err := loadDaysOff(s.repo, first, last, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var days []time.Time
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+
+	isOff := func(i int) bool {
+		if i < 0 || i >= len(days) {
+			return false
+		}
+		_, holiday := daysOff[days[i].Format(dateLayout)]
+		return holiday || isWeekend(days[i])
+	}
+
+	var runs []offRun
+	for i := 0; i < len(days); i++ {
+		if !isOff(i) {
+			continue
+		}
+		run := offRun{start: i, end: i, holidays: []models.Holiday{}}
+		for isOff(run.end + 1) {
+			run.end++
+		}
+		for d := run.start; d <= run.end; d++ {
+			run.holidays = append(run.holidays, daysOff[days[d].Format(dateLayout)]...)
+		}
+		runs = append(runs, run)
+		i = run.end
+	}
+
+	response := &models.LongWeekendResponse{
+		Year:                   year,
+		IncludeCollectiveLeave: opts.IncludeCollectiveLeave,
+		Province:               opts.Province,
+		LongWeekends:           []models.LongWeekend{},
+	}
+
+	for i, run := range runs {
+		if days[run.end].Before(yearStart) || days[run.start].After(yearEnd) {
+			continue
+		}
+
+		// A plain Saturday and Sunday is not a long weekend, nor a reason for a bridge day
+		if len(run.holidays) == 0 {
+			continue
+		}
+
+		weekend := models.LongWeekend{
+			StartDate:  days[run.start].Format(dateLayout),
+			EndDate:    days[run.end].Format(dateLayout),
+			Days:       run.end - run.start + 1,
+			Holidays:   run.holidays,
+			BridgeDays: []models.BridgeDay{},
+		}
+
+		// A bridge day is a single working day between this run and a neighbouring one.
+		// Between two runs with holidays it is reported once, with the earlier run.
+		if i > 0 && runs[i-1].end == run.start-2 && len(runs[i-1].holidays) == 0 {
+			weekend.BridgeDays = append(weekend.BridgeDays, bridgeDay(days, runs[i-1], run))
+		}
+		if i < len(runs)-1 && runs[i+1].start == run.end+2 {
+			weekend.BridgeDays = append(weekend.BridgeDays, bridgeDay(days, run, runs[i+1]))
+		}
+		bridges := weekend.BridgeDays[:0]
+		for _, bridge := range weekend.BridgeDays {
+			if bridge.Days >= minBridgedBreakDays {
+				bridges = append(bridges, bridge)
+			}
+		}
+		weekend.BridgeDays = bridges
+
+		if weekend.Days >= minLongWeekendDays || len(weekend.BridgeDays) > 0 {
+			response.LongWeekends = append(response.LongWeekends, weekend)
+		}
+	}
+
+	return response, nil
+}
+
+// bridgeDay describes the working day between two runs of days off and the break it creates
+func bridgeDay(days []time.Time, before, after offRun) models.BridgeDay {
+	return models.BridgeDay{
+		Date:      days[before.end+1].Format(dateLayout),
+		StartDate: days[before.start].Format(dateLayout),
+		EndDate:   days[after.end].Format(dateLayout),
+		Days:      after.end - before.start + 1,
+	}
+}
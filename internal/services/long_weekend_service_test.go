@@ -0,0 +1,79 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+func TestLongWeekendService_FindLongWeekends(t *testing.T) {
+	holidays := []models.Holiday{
+		// Thursday and Friday
+		{ID: 1, Name: "Kenaikan Isa Almasih", Date: time.Date(2024, 5, 9, 0, 0, 0, 0, time.UTC), Type: models.NationalHoliday},
+		{ID: 2, Name: "Cuti Bersama Kenaikan Isa Almasih", Date: time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC), Type: models.CollectiveLeave},
+		// Saturday, already a day off
+		{ID: 3, Name: "Hari Kemerdekaan Republik Indonesia", Date: time.Date(2024, 8, 17, 0, 0, 0, 0, time.UTC), Type: models.NationalHoliday},
+		// Tuesday to Thursday
+		{ID: 4, Name: "Cuti Bersama Natal", Date: time.Date(2024, 12, 24, 0, 0, 0, 0, time.UTC), Type: models.CollectiveLeave},
+		{ID: 5, Name: "Hari Raya Natal", Date: time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC), Type: models.NationalHoliday},
+		{ID: 6, Name: "Cuti Bersama Natal", Date: time.Date(2024, 12, 26, 0, 0, 0, 0, time.UTC), Type: models.CollectiveLeave},
+	}
+
+	t.Run("collective leave counted as day off", func(t *testing.T) {
+		mockRepo := new(MockHolidayRepository)
+		service := NewLongWeekendService(mockRepo)
+		mockRepo.On("GetByDateRange", mock.Anything, mock.Anything, (*models.HolidayType)(nil), models.HolidayScope{}).Return(holidays, nil)
+
+		response, err := service.FindLongWeekends(2024, models.WorkdayOptions{IncludeCollectiveLeave: true})
+
+		assert.NoError(t, err)
+		assert.Len(t, response.LongWeekends, 2)
+
+		ascension := response.LongWeekends[0]
+		assert.Equal(t, "2024-05-09", ascension.StartDate)
+		assert.Equal(t, "2024-05-12", ascension.EndDate)
+		assert.Equal(t, 4, ascension.Days)
+		assert.Len(t, ascension.Holidays, 2)
+		assert.Empty(t, ascension.BridgeDays)
+
+		christmas := response.LongWeekends[1]
+		assert.Equal(t, "2024-12-24", christmas.StartDate)
+		assert.Equal(t, 3, christmas.Days)
+		assert.Equal(t, []models.BridgeDay{
+			{Date: "2024-12-23", StartDate: "2024-12-21", EndDate: "2024-12-26", Days: 6},
+			{Date: "2024-12-27", StartDate: "2024-12-24", EndDate: "2024-12-29", Days: 6},
+		}, christmas.BridgeDays)
+	})
+
+	t.Run("collective leave counted as working day", func(t *testing.T) {
+		mockRepo := new(MockHolidayRepository)
+		service := NewLongWeekendService(mockRepo)
+		mockRepo.On("GetByDateRange", mock.Anything, mock.Anything, (*models.HolidayType)(nil), models.HolidayScope{}).Return(holidays, nil)
+
+		response, err := service.FindLongWeekends(2024, models.WorkdayOptions{IncludeCollectiveLeave: false})
+
+		// Christmas on a Wednesday is two working days away from either weekend, so only
+		// Ascension Thursday remains, with Friday as the harpitnas
+		assert.NoError(t, err)
+		assert.Len(t, response.LongWeekends, 1)
+
+		ascension := response.LongWeekends[0]
+		assert.Equal(t, "2024-05-09", ascension.StartDate)
+		assert.Equal(t, 1, ascension.Days)
+		assert.Equal(t, []models.BridgeDay{
+			{Date: "2024-05-10", StartDate: "2024-05-09", EndDate: "2024-05-12", Days: 4},
+		}, ascension.BridgeDays)
+	})
+
+	t.Run("invalid year", func(t *testing.T) {
+		service := NewLongWeekendService(new(MockHolidayRepository))
+
+		_, err := service.FindLongWeekends(0, models.WorkdayOptions{})
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,351 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ilramdhan/holidayapi/internal/config"
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/repository"
+	"github.com/ilramdhan/holidayapi/internal/totp"
+)
+
+const (
+	// mfaSkew is how many 30 second steps a code may be behind or ahead of the server clock
+	mfaSkew = 1
+	// recoveryCodeCount is how many recovery codes are issued at a time
+	recoveryCodeCount = 10
+)
+
+// MFAService manages TOTP two-factor authentication of signed-in users
+type MFAService interface {
+	GetStatus(userID int) (*models.MFAStatus, error)
+	Enroll(userID int, ipAddress, userAgent string) (*models.MFAEnrollment, error)
+	Enable(userID int, req models.MFACodeRequest, ipAddress, userAgent string) (*models.MFARecoveryCodesResponse, error)
+	Disable(userID int, req models.MFADisableRequest, ipAddress, userAgent string) error
+	RegenerateRecoveryCodes(userID int, req models.MFACodeRequest, ipAddress, userAgent string) (*models.MFARecoveryCodesResponse, error)
+	Reset(userID int, resetBy *models.User, ipAddress, userAgent string) error
+}
+
+// mfaService implements MFAService
+type mfaService struct {
+	mfaRepo   repository.MFARepository
+	userRepo  UserRepository
+	roleRepo  repository.RoleRepository
+	auditRepo repository.AuditRepository
+	cfg       config.MFAConfig
+}
+
+// NewMFAService creates a new MFA service
+func NewMFAService(mfaRepo repository.MFARepository, userRepo UserRepository, roleRepo repository.RoleRepository, auditRepo repository.AuditRepository, cfg config.MFAConfig) MFAService {
+	return &mfaService{
+		mfaRepo:   mfaRepo,
+		userRepo:  userRepo,
+		roleRepo:  roleRepo,
+		auditRepo: auditRepo,
+		cfg:       cfg,
+	}
+}
+
+// GetStatus describes the MFA setup of a user
+func (s *mfaService) GetStatus(userID int) (*models.MFAStatus, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	status := &models.MFAStatus{Required: s.cfg.RequiredFor(string(user.Role))}
+
+	mfa, err := s.mfaRepo.Get(userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMFANotFound) {
+			return status, nil
+		}
+		return nil, err
+	}
+
+	status.Enabled = mfa.Enabled
+	status.Pending = !mfa.Enabled
+	status.EnabledAt = mfa.EnabledAt
+
+	if mfa.Enabled {
+		if status.RecoveryCodesRemaining, err = s.mfaRepo.CountRecoveryCodes(userID); err != nil {
+			return nil, err
+		}
+	}
+
+	return status, nil
+}
+
+// Enroll generates a new secret for the user to add to an authenticator app.
+// MFA is only turned on once Enable confirms a code generated from it.
+func (s *mfaService) Enroll(userID int, ipAddress, userAgent string) (*models.MFAEnrollment, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	enrollment, err := startMFAEnrollment(s.mfaRepo, s.cfg, user)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logAudit(&user.ID, user.Username, models.ActionMFAEnroll, models.ResourceAuth,
+		"MFA enrollment started", ipAddress, userAgent, true)
+
+	return enrollment, nil
+}
+
+// Enable turns MFA on with a first code from the enrolled secret and returns the recovery codes
+func (s *mfaService) Enable(userID int, req models.MFACodeRequest, ipAddress, userAgent string) (*models.MFARecoveryCodesResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	mfa, err := s.mfaRepo.Get(userID)
+	if err != nil {
+		return nil, fmt.Errorf("mfa enrollment not started")
+	}
+
+	codes, err := confirmMFAEnrollment(s.mfaRepo, mfa, req.Code)
+	if err != nil {
+		s.logAudit(&user.ID, user.Username, models.ActionMFAEnable, models.ResourceAuth,
+			fmt.Sprintf("MFA activation failed: %v", err), ipAddress, userAgent, false)
+		return nil, err
+	}
+
+	s.logAudit(&user.ID, user.Username, models.ActionMFAEnable, models.ResourceAuth,
+		"MFA enabled", ipAddress, userAgent, true)
+
+	return &models.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable turns MFA off after checking the password and a code. Users whose role
+// requires MFA cannot turn it off.
+func (s *mfaService) Disable(userID int, req models.MFADisableRequest, ipAddress, userAgent string) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+
+	if s.cfg.RequiredFor(string(user.Role)) {
+		return fmt.Errorf("mfa is required for role %s", user.Role)
+	}
+
+	mfa, err := s.mfaRepo.Get(userID)
+	if err != nil || !mfa.Enabled {
+		return fmt.Errorf("mfa is not enabled")
+	}
+
+	if err := s.userRepo.CheckPassword(user.Password, req.Password); err != nil {
+		s.logAudit(&user.ID, user.Username, models.ActionMFADisable, models.ResourceAuth,
+			"MFA disable failed: invalid password", ipAddress, userAgent, false)
+		return fmt.Errorf("invalid credentials")
+	}
+
+	if _, err := verifyMFACode(s.mfaRepo, mfa, req.Code); err != nil {
+		s.logAudit(&user.ID, user.Username, models.ActionMFADisable, models.ResourceAuth,
+			"MFA disable failed: invalid code", ipAddress, userAgent, false)
+		return err
+	}
+
+	if err := s.mfaRepo.Delete(userID); err != nil {
+		return fmt.Errorf("failed to disable mfa: %w", err)
+	}
+
+	s.logAudit(&user.ID, user.Username, models.ActionMFADisable, models.ResourceAuth,
+		"MFA disabled", ipAddress, userAgent, true)
+
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes after checking an authenticator code
+func (s *mfaService) RegenerateRecoveryCodes(userID int, req models.MFACodeRequest, ipAddress, userAgent string) (*models.MFARecoveryCodesResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	mfa, err := s.mfaRepo.Get(userID)
+	if err != nil || !mfa.Enabled {
+		return nil, fmt.Errorf("mfa is not enabled")
+	}
+
+	if _, err := verifyMFACode(s.mfaRepo, mfa, req.Code); err != nil {
+		s.logAudit(&user.ID, user.Username, models.ActionMFARecoveryCodesRegenerate, models.ResourceAuth,
+			"Recovery code regeneration failed: invalid code", ipAddress, userAgent, false)
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.mfaRepo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, fmt.Errorf("failed to regenerate recovery codes: %w", err)
+	}
+
+	s.logAudit(&user.ID, user.Username, models.ActionMFARecoveryCodesRegenerate, models.ResourceAuth,
+		"Recovery codes regenerated", ipAddress, userAgent, true)
+
+	return &models.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Reset removes the MFA setup of another user, for example after a lost device.
+// If their role requires MFA they enroll again on their next login.
+func (s *mfaService) Reset(userID int, resetBy *models.User, ipAddress, userAgent string) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+
+	if err := ensureManageable(s.roleRepo, resetBy, user); err != nil {
+		s.logAudit(&resetBy.ID, resetBy.Username, models.ActionMFAReset, models.ResourceUser,
+			fmt.Sprintf("MFA reset of user %s refused: %v", user.Username, err), ipAddress, userAgent, false)
+		return err
+	}
+
+	if _, err := s.mfaRepo.Get(userID); err != nil {
+		return err
+	}
+
+	if err := s.mfaRepo.Delete(userID); err != nil {
+		return fmt.Errorf("failed to reset mfa: %w", err)
+	}
+
+	s.logAudit(&resetBy.ID, resetBy.Username, models.ActionMFAReset, models.ResourceUser,
+		fmt.Sprintf("Reset MFA of user: %s", user.Username), ipAddress, userAgent, true)
+
+	return nil
+}
+
+// logAudit logs an audit entry
+func (s *mfaService) logAudit(userID *int, username string, action models.AuditAction, resource models.AuditResource, details, ipAddress, userAgent string, success bool) {
+	auditLog := &models.AuditLog{
+		UserID:    userID,
+		Username:  username,
+		Action:    action,
+		Resource:  resource,
+		Details:   details,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		Success:   success,
+	}
+
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		fmt.Printf("Failed to create audit log: %v\n", err)
+	}
+}
+
+// startMFAEnrollment stores a new pending secret for a user and returns its provisioning details
+func startMFAEnrollment(repo repository.MFARepository, cfg config.MFAConfig, user *models.User) (*models.MFAEnrollment, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := repo.SavePending(&models.UserMFA{UserID: user.ID, Secret: secret}); err != nil {
+		return nil, err
+	}
+
+	return &models.MFAEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(cfg.Issuer, user.Username, secret),
+	}, nil
+}
+
+// confirmMFAEnrollment enables a pending enrollment with a code from its secret and returns new recovery codes
+func confirmMFAEnrollment(repo repository.MFARepository, mfa *models.UserMFA, code string) ([]string, error) {
+	if mfa.Enabled {
+		return nil, fmt.Errorf("mfa is already enabled")
+	}
+
+	step, ok := totp.Validate(mfa.Secret, code, time.Now(), mfaSkew)
+	if !ok {
+		return nil, fmt.Errorf("invalid mfa code")
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	enabled, err := repo.Enable(mfa.UserID, step, hashes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enable mfa: %w", err)
+	}
+	if !enabled {
+		return nil, fmt.Errorf("invalid mfa code")
+	}
+
+	return codes, nil
+}
+
+// verifyMFACode checks an authenticator code or recovery code of an enabled enrollment and
+// consumes it, so the same code is never accepted twice. It reports whether a recovery code was used.
+func verifyMFACode(repo repository.MFARepository, mfa *models.UserMFA, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+
+	if len(code) == totp.Digits && isDigits(code) {
+		step, ok := totp.Validate(mfa.Secret, code, time.Now(), mfaSkew)
+		if !ok {
+			return false, fmt.Errorf("invalid mfa code")
+		}
+
+		used, err := repo.UseStep(mfa.UserID, step)
+		if err != nil {
+			return false, fmt.Errorf("failed to verify mfa code: %w", err)
+		}
+		if !used {
+			return false, fmt.Errorf("invalid mfa code")
+		}
+		return false, nil
+	}
+
+	used, err := repo.UseRecoveryCode(mfa.UserID, hashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return false, fmt.Errorf("failed to verify mfa code: %w", err)
+	}
+	if !used {
+		return false, fmt.Errorf("invalid mfa code")
+	}
+
+	return true, nil
+}
+
+// generateRecoveryCodes returns a new set of recovery codes formatted as xxxxx-xxxxx and their hashes
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw, err := generateRandomID(5)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to generate recovery codes: %w", err)
+		}
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		hashes = append(hashes, hashToken(raw))
+	}
+
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode strips the separators and case a user may type a recovery code with
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// isDigits reports whether s consists of ASCII digits only
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
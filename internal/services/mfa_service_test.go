@@ -0,0 +1,236 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilramdhan/holidayapi/internal/config"
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/repository"
+	"github.com/ilramdhan/holidayapi/internal/totp"
+)
+
+// requireSuperAdminMFA is an MFA configuration that makes super admins use MFA
+var requireSuperAdminMFA = config.MFAConfig{
+	Issuer:        "Holiday API",
+	RequiredRoles: []string{string(models.SuperAdminRole)},
+	ChallengeTTL:  5 * time.Minute,
+}
+
+// mockLogin sets up a user whose password check passes and whose tokens can be stored
+func mockLogin(userRepo *MockUserRepository, tokenRepo *MockRefreshTokenRepository, user *models.User) {
+	userRepo.On("GetByUsername", user.Username).Return(user, nil)
+	userRepo.On("GetByID", user.ID).Return(user, nil)
+	userRepo.On("CheckPassword", user.Password, "secret").Return(nil)
+	userRepo.On("UpdateLastLogin", user.ID).Return(nil)
+	tokenRepo.On("Create", mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+}
+
+func TestAuthService_LoginWithMFA(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+
+	mfaRepo := new(MockMFARepository)
+	mfaRepo.On("Get", 1).Return(&models.UserMFA{UserID: 1, Secret: secret, Enabled: true}, nil)
+	mfaRepo.On("UseStep", 1, mock.Anything).Return(true, nil).Once()
+	mfaRepo.On("UseStep", 1, mock.Anything).Return(false, nil)
+	mfaRepo.On("UseRecoveryCode", 1, mock.Anything).Return(false, nil)
+
+	service, jwtService, userRepo, tokenRepo, auditRepo := newTestAuthServiceWithMFA(mfaRepo, config.MFAConfig{ChallengeTTL: 5 * time.Minute})
+	user := &models.User{ID: 1, Username: "editor", Password: "hash", Role: models.AdminRole, IsActive: true}
+	mockLogin(userRepo, tokenRepo, user)
+
+	resp, challenge, err := service.Login(models.LoginRequest{Username: "editor", Password: "secret"}, "127.0.0.1", "test")
+	require.NoError(t, err)
+	assert.Nil(t, resp)
+	require.NotNil(t, challenge)
+	assert.True(t, challenge.MFARequired)
+	assert.False(t, challenge.EnrollmentRequired)
+	assert.Equal(t, int64(300), challenge.ExpiresIn)
+	tokenRepo.AssertNotCalled(t, "Create", mock.Anything)
+
+	// The challenge token is not an access token
+	_, err = jwtService.ValidateAccessToken(challenge.MFAToken)
+	assert.Error(t, err)
+
+	_, err = service.CompleteMFALogin(models.MFALoginRequest{MFAToken: challenge.MFAToken, Code: "wrong-code"}, "127.0.0.1", "test")
+	assert.Error(t, err)
+
+	code, err := totp.Code(secret, time.Now())
+	require.NoError(t, err)
+	resp, err = service.CompleteMFALogin(models.MFALoginRequest{MFAToken: challenge.MFAToken, Code: code}, "127.0.0.1", "test")
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.AccessToken)
+	assert.Empty(t, resp.RecoveryCodes)
+
+	// The same code cannot be used twice
+	_, err = service.CompleteMFALogin(models.MFALoginRequest{MFAToken: challenge.MFAToken, Code: code}, "127.0.0.1", "test")
+	assert.EqualError(t, err, "invalid mfa code")
+
+	assert.Equal(t, []string{"MFA_CHALLENGE:true", "MFA_VERIFY:false", "MFA_VERIFY:true", "LOGIN:true", "MFA_VERIFY:false"}, auditActions(auditRepo))
+}
+
+func TestAuthService_LoginWithRecoveryCode(t *testing.T) {
+	mfaRepo := new(MockMFARepository)
+	mfaRepo.On("Get", 1).Return(&models.UserMFA{UserID: 1, Secret: "JBSWY3DPEHPK3PXP", Enabled: true}, nil)
+	mfaRepo.On("UseRecoveryCode", 1, hashToken("abcde12345")).Return(true, nil)
+	mfaRepo.On("CountRecoveryCodes", 1).Return(9, nil)
+
+	service, jwtService, userRepo, tokenRepo, auditRepo := newTestAuthServiceWithMFA(mfaRepo, config.MFAConfig{ChallengeTTL: time.Minute})
+	user := &models.User{ID: 1, Username: "editor", Password: "hash", Role: models.AdminRole, IsActive: true}
+	mockLogin(userRepo, tokenRepo, user)
+
+	mfaToken, err := jwtService.GenerateMFAToken(user, time.Minute)
+	require.NoError(t, err)
+
+	// Recovery codes are accepted regardless of case and separators
+	resp, err := service.CompleteMFALogin(models.MFALoginRequest{MFAToken: mfaToken, Code: "ABCDE-12345"}, "127.0.0.1", "test")
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.AccessToken)
+	assert.Equal(t, []string{"MFA_RECOVERY_CODE_USED:true", "LOGIN:true"}, auditActions(auditRepo))
+}
+
+func TestAuthService_LoginRequiresEnrollment(t *testing.T) {
+	var pending *models.UserMFA
+
+	mfaRepo := new(MockMFARepository)
+	mfaRepo.On("Get", 1).Return(nil, repository.ErrMFANotFound).Twice()
+	mfaRepo.On("SavePending", mock.AnythingOfType("*models.UserMFA")).Run(func(args mock.Arguments) {
+		pending = args.Get(0).(*models.UserMFA)
+	}).Return(nil)
+	mfaRepo.On("Enable", 1, mock.Anything, mock.Anything).Return(true, nil)
+
+	service, _, userRepo, tokenRepo, auditRepo := newTestAuthServiceWithMFA(mfaRepo, requireSuperAdminMFA)
+	user := &models.User{ID: 1, Username: "admin", Password: "hash", Role: models.SuperAdminRole, IsActive: true}
+	mockLogin(userRepo, tokenRepo, user)
+
+	_, challenge, err := service.Login(models.LoginRequest{Username: "admin", Password: "secret"}, "127.0.0.1", "test")
+	require.NoError(t, err)
+	require.NotNil(t, challenge)
+	assert.True(t, challenge.EnrollmentRequired)
+
+	// Without enrolling the login cannot be completed
+	_, err = service.CompleteMFALogin(models.MFALoginRequest{MFAToken: challenge.MFAToken, Code: "123456"}, "127.0.0.1", "test")
+	assert.EqualError(t, err, "mfa enrollment required")
+
+	enrollment, err := service.EnrollMFALogin(models.MFATokenRequest{MFAToken: challenge.MFAToken}, "127.0.0.1", "test")
+	require.NoError(t, err)
+	require.NotNil(t, pending)
+	assert.Equal(t, pending.Secret, enrollment.Secret)
+	assert.Contains(t, enrollment.ProvisioningURI, "otpauth://totp/Holiday%20API:admin?")
+
+	mfaRepo.On("Get", 1).Return(pending, nil)
+	code, err := totp.Code(enrollment.Secret, time.Now())
+	require.NoError(t, err)
+
+	resp, err := service.CompleteMFALogin(models.MFALoginRequest{MFAToken: challenge.MFAToken, Code: code}, "127.0.0.1", "test")
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.AccessToken)
+	assert.Len(t, resp.RecoveryCodes, recoveryCodeCount)
+
+	// Only hashes of the recovery codes are stored
+	hashes := mfaRepo.Calls[len(mfaRepo.Calls)-1].Arguments.Get(2).([]string)
+	assert.Equal(t, hashToken(normalizeRecoveryCode(resp.RecoveryCodes[0])), hashes[0])
+
+	assert.Equal(t, []string{"MFA_CHALLENGE:true", "MFA_ENROLL:true", "MFA_ENABLE:true", "LOGIN:true"}, auditActions(auditRepo))
+}
+
+func TestAuthService_EnrollMFALoginNotRequired(t *testing.T) {
+	mfaRepo := new(MockMFARepository)
+	service, jwtService, userRepo, _, _ := newTestAuthServiceWithMFA(mfaRepo, requireSuperAdminMFA)
+
+	user := &models.User{ID: 2, Username: "editor", Role: models.AdminRole, IsActive: true}
+	userRepo.On("GetByID", 2).Return(user, nil)
+
+	mfaToken, err := jwtService.GenerateMFAToken(user, time.Minute)
+	require.NoError(t, err)
+
+	_, err = service.EnrollMFALogin(models.MFATokenRequest{MFAToken: mfaToken}, "127.0.0.1", "test")
+	assert.EqualError(t, err, "mfa enrollment is not required for role admin")
+	mfaRepo.AssertNotCalled(t, "SavePending", mock.Anything)
+}
+
+func TestMFAService_Disable(t *testing.T) {
+	tests := []struct {
+		name        string
+		role        models.UserRole
+		code        string
+		expectedErr string
+	}{
+		{name: "required by role", role: models.SuperAdminRole, code: "abcde-12345", expectedErr: "mfa is required for role super_admin"},
+		{name: "invalid code", role: models.AdminRole, code: "fffff-fffff", expectedErr: "invalid mfa code"},
+		{name: "recovery code", role: models.AdminRole, code: "abcde-12345"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mfaRepo := new(MockMFARepository)
+			userRepo := new(MockUserRepository)
+			auditRepo := new(MockAuditRepository)
+			auditRepo.On("Create", mock.Anything).Return(nil)
+
+			userRepo.On("GetByID", 1).Return(&models.User{ID: 1, Username: "user", Password: "hash", Role: tt.role}, nil)
+			userRepo.On("CheckPassword", "hash", "secret").Return(nil)
+			mfaRepo.On("Get", 1).Return(&models.UserMFA{UserID: 1, Secret: "JBSWY3DPEHPK3PXP", Enabled: true}, nil)
+			mfaRepo.On("UseRecoveryCode", 1, hashToken("abcde12345")).Return(true, nil)
+			mfaRepo.On("UseRecoveryCode", 1, mock.Anything).Return(false, nil)
+			mfaRepo.On("Delete", 1).Return(nil)
+
+			service := NewMFAService(mfaRepo, userRepo, newTestRoleRepository(), auditRepo, requireSuperAdminMFA)
+			err := service.Disable(1, models.MFADisableRequest{Password: "secret", Code: tt.code}, "127.0.0.1", "test")
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				mfaRepo.AssertNotCalled(t, "Delete", 1)
+				return
+			}
+			assert.NoError(t, err)
+			mfaRepo.AssertCalled(t, "Delete", 1)
+			assert.Equal(t, []string{"MFA_DISABLE:true"}, auditActions(auditRepo))
+		})
+	}
+}
+
+func TestMFAService_GetStatus(t *testing.T) {
+	enabledAt := time.Now()
+	mfaRepo := new(MockMFARepository)
+	userRepo := new(MockUserRepository)
+
+	userRepo.On("GetByID", 1).Return(&models.User{ID: 1, Role: models.SuperAdminRole}, nil)
+	mfaRepo.On("Get", 1).Return(&models.UserMFA{UserID: 1, Enabled: true, EnabledAt: &enabledAt}, nil)
+	mfaRepo.On("CountRecoveryCodes", 1).Return(7, nil)
+
+	service := NewMFAService(mfaRepo, userRepo, newTestRoleRepository(), new(MockAuditRepository), requireSuperAdminMFA)
+	status, err := service.GetStatus(1)
+
+	require.NoError(t, err)
+	assert.Equal(t, &models.MFAStatus{Enabled: true, Required: true, EnabledAt: &enabledAt, RecoveryCodesRemaining: 7}, status)
+}
+
+func TestMFAService_ResetRefusesMorePrivilegedUser(t *testing.T) {
+	mfaRepo := new(MockMFARepository)
+	userRepo := new(MockUserRepository)
+	auditRepo := new(MockAuditRepository)
+	auditRepo.On("Create", mock.Anything).Return(nil)
+
+	userRepo.On("GetByID", 1).Return(&models.User{ID: 1, Username: "root", Role: models.SuperAdminRole}, nil)
+	userRepo.On("GetByID", 3).Return(&models.User{ID: 3, Username: "viewer", Role: models.AdminRole}, nil)
+	mfaRepo.On("Get", 3).Return(&models.UserMFA{UserID: 3, Enabled: true}, nil)
+	mfaRepo.On("Delete", 3).Return(nil)
+
+	service := NewMFAService(mfaRepo, userRepo, newTestRoleRepository(), auditRepo, requireSuperAdminMFA)
+	editor := &models.User{ID: 2, Username: "editor", Role: models.AdminRole}
+
+	// Removing a super admin's second factor would let an admin take over their account
+	err := service.Reset(1, editor, "127.0.0.1", "test")
+	assert.EqualError(t, err, "cannot manage a user with permissions beyond your own: cannot grant the audit:verify permission, which you do not have")
+	mfaRepo.AssertNotCalled(t, "Delete", 1)
+
+	require.NoError(t, service.Reset(3, editor, "127.0.0.1", "test"))
+	mfaRepo.AssertCalled(t, "Delete", 3)
+	assert.Equal(t, []string{"MFA_RESET:false", "MFA_RESET:true"}, auditActions(auditRepo))
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ilramdhan/holidayapi/internal/config"
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/notify"
+	"github.com/ilramdhan/holidayapi/internal/repository"
+)
+
+// PasswordResetService handles self-service password resets through emailed one-time links
+type PasswordResetService interface {
+	ForgotPassword(ctx context.Context, req models.ForgotPasswordRequest, ipAddress, userAgent string) error
+	ResetPassword(req models.ResetPasswordRequest, ipAddress, userAgent string) error
+	PurgeExpiredTokens() (int64, error)
+}
+
+// passwordResetService implements PasswordResetService
+type passwordResetService struct {
+	resetRepo        repository.PasswordResetRepository
+	userRepo         UserRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	auditRepo        repository.AuditRepository
+	notifier         notify.Notifier
+	cfg              config.PasswordResetConfig
+
+	// pending tracks reset requests still being processed in the background
+	pending sync.WaitGroup
+}
+
+// NewPasswordResetService creates a new password reset service
+func NewPasswordResetService(resetRepo repository.PasswordResetRepository, userRepo UserRepository, refreshTokenRepo repository.RefreshTokenRepository, auditRepo repository.AuditRepository, notifier notify.Notifier, cfg config.PasswordResetConfig) (PasswordResetService, error) {
+	if _, err := url.Parse(cfg.URL); err != nil {
+		return nil, fmt.Errorf("invalid PASSWORD_RESET_URL %q: %w", cfg.URL, err)
+	}
+
+	return &passwordResetService{
+		resetRepo:        resetRepo,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		auditRepo:        auditRepo,
+		notifier:         notifier,
+		cfg:              cfg,
+	}, nil
+}
+
+// ForgotPassword emails a reset link to the active user with the given email address and
+// invalidates links sent before. The request is processed in the background and always
+// succeeds, so neither the response nor its timing tells which addresses have accounts.
+func (s *passwordResetService) ForgotPassword(ctx context.Context, req models.ForgotPasswordRequest, ipAddress, userAgent string) error {
+	email := strings.TrimSpace(req.Email)
+
+	// The email outlives the request, so it must not be cancelled with it
+	sendCtx := context.WithoutCancel(ctx)
+
+	s.pending.Add(1)
+	go func() {
+		defer s.pending.Done()
+		s.sendResetLink(sendCtx, email, ipAddress, userAgent)
+	}()
+
+	return nil
+}
+
+// sendResetLink issues a reset token for the user with the given email address and emails it to them.
+// Failures are logged rather than returned, since nobody is waiting for the result.
+func (s *passwordResetService) sendResetLink(ctx context.Context, email, ipAddress, userAgent string) {
+	user, err := s.userRepo.GetByEmail(email)
+	if err != nil || !user.IsActive {
+		s.logAudit(nil, "", models.ActionPasswordResetRequest,
+			fmt.Sprintf("Password reset requested for unknown or deactivated email: %s", email), ipAddress, userAgent, false)
+		return
+	}
+
+	now := time.Now()
+	if _, err := s.resetRepo.InvalidateForUser(user.ID, now); err != nil {
+		fmt.Printf("Failed to invalidate password reset tokens of user %d: %v\n", user.ID, err)
+		return
+	}
+
+	rawToken, err := generateRandomID(32)
+	if err != nil {
+		fmt.Printf("Failed to generate password reset token for user %d: %v\n", user.ID, err)
+		return
+	}
+
+	token := &models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(rawToken),
+		ExpiresAt: now.Add(s.cfg.TokenTTL),
+	}
+	if err := s.resetRepo.Create(token); err != nil {
+		fmt.Printf("Failed to store password reset token for user %d: %v\n", user.ID, err)
+		return
+	}
+
+	msg := s.composeResetEmail(user, rawToken)
+	if err := s.notifier.Send(ctx, msg); err != nil {
+		fmt.Printf("Failed to send password reset email to user %d: %v\n", user.ID, err)
+		s.logAudit(&user.ID, user.Username, models.ActionPasswordResetRequest,
+			fmt.Sprintf("Failed to send password reset email: %v", err), ipAddress, userAgent, false)
+		return
+	}
+
+	s.logAudit(&user.ID, user.Username, models.ActionPasswordResetRequest,
+		"Password reset email sent", ipAddress, userAgent, true)
+}
+
+// ResetPassword sets a new password with a reset token. The token is used up, other
+// pending reset links of the user stop working and all their sessions are revoked.
+func (s *passwordResetService) ResetPassword(req models.ResetPasswordRequest, ipAddress, userAgent string) error {
+	// Check the password first, so a weak choice does not use up the link
+	if err := validatePassword(req.NewPassword); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	token, err := s.resetRepo.Use(hashToken(req.Token), now)
+	if err != nil {
+		if errors.Is(err, repository.ErrPasswordResetTokenNotFound) {
+			s.logAudit(nil, "", models.ActionPasswordReset,
+				"Password reset failed: invalid, used or expired token", ipAddress, userAgent, false)
+			return fmt.Errorf("invalid or expired password reset token")
+		}
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(token.UserID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+
+	if !user.IsActive {
+		s.logAudit(&user.ID, user.Username, models.ActionPasswordReset,
+			"Password reset failed: account deactivated", ipAddress, userAgent, false)
+		return fmt.Errorf("account is deactivated")
+	}
+
+	if err := s.userRepo.ChangePassword(user.ID, req.NewPassword); err != nil {
+		return fmt.Errorf("failed to reset password: %w", err)
+	}
+
+	if _, err := s.resetRepo.InvalidateForUser(user.ID, now); err != nil {
+		fmt.Printf("Failed to invalidate password reset tokens of user %d: %v\n", user.ID, err)
+	}
+
+	// Whoever knew the old password must not stay signed in
+	revoked, err := s.refreshTokenRepo.RevokeAllForUser(user.ID)
+	if err != nil {
+		fmt.Printf("Failed to revoke sessions of user %d: %v\n", user.ID, err)
+	}
+
+	s.logAudit(&user.ID, user.Username, models.ActionPasswordReset,
+		fmt.Sprintf("Password reset with emailed link, %d sessions revoked", revoked), ipAddress, userAgent, true)
+
+	return nil
+}
+
+// PurgeExpiredTokens deletes reset tokens past their expiry and returns how many were deleted
+func (s *passwordResetService) PurgeExpiredTokens() (int64, error) {
+	deleted, err := s.resetRepo.DeleteExpired(time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge expired password reset tokens: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// composeResetEmail builds the email carrying a user's reset link
+func (s *passwordResetService) composeResetEmail(user *models.User, rawToken string) notify.Message {
+	link, _ := url.Parse(s.cfg.URL)
+	query := link.Query()
+	query.Set("token", rawToken)
+	link.RawQuery = query.Encode()
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Hello %s,\n\n", user.Username)
+	body.WriteString("Someone asked to reset the password of your Holiday API account. To choose a new password, open this link:\n\n")
+	fmt.Fprintf(&body, "%s\n\n", link)
+	fmt.Fprintf(&body, "The link works once and expires in %s. If you did not ask for a reset, ignore this email; your password stays unchanged.\n", s.cfg.TokenTTL)
+
+	return notify.Message{
+		To:      []string{user.Email},
+		Subject: "Reset your Holiday API password",
+		Body:    body.String(),
+	}
+}
+
+// logAudit logs an audit entry
+func (s *passwordResetService) logAudit(userID *int, username string, action models.AuditAction, details, ipAddress, userAgent string, success bool) {
+	auditLog := &models.AuditLog{
+		UserID:    userID,
+		Username:  username,
+		Action:    action,
+		Resource:  models.ResourceAuth,
+		Details:   details,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		Success:   success,
+	}
+
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		fmt.Printf("Failed to create audit log: %v\n", err)
+	}
+}
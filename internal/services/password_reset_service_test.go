@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilramdhan/holidayapi/internal/config"
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/repository"
+)
+
+// MockPasswordResetRepository is a mock implementation of PasswordResetRepository
+type MockPasswordResetRepository struct {
+	mock.Mock
+}
+
+func (m *MockPasswordResetRepository) Create(token *models.PasswordResetToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetRepository) Use(tokenHash string, now time.Time) (*models.PasswordResetToken, error) {
+	args := m.Called(tokenHash, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PasswordResetToken), args.Error(1)
+}
+
+func (m *MockPasswordResetRepository) InvalidateForUser(userID int, now time.Time) (int64, error) {
+	args := m.Called(userID, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPasswordResetRepository) DeleteExpired(before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// testPasswordResetConfig sends links to a client page that already has a query
+var testPasswordResetConfig = config.PasswordResetConfig{
+	URL:      "https://app.example.com/reset?lang=id",
+	TokenTTL: time.Hour,
+}
+
+func TestPasswordResetService_ForgotPassword(t *testing.T) {
+	resetRepo := new(MockPasswordResetRepository)
+	userRepo := new(MockUserRepository)
+	auditRepo := new(MockAuditRepository)
+	auditRepo.On("Create", mock.Anything).Return(nil)
+	notifier := &recordingNotifier{}
+
+	user := &models.User{ID: 2, Username: "editor", Email: "editor@example.com", IsActive: true}
+	userRepo.On("GetByEmail", "editor@example.com").Return(user, nil)
+	resetRepo.On("InvalidateForUser", 2, mock.Anything).Return(int64(1), nil)
+	resetRepo.On("Create", mock.AnythingOfType("*models.PasswordResetToken")).Return(nil)
+
+	service, err := NewPasswordResetService(resetRepo, userRepo, new(MockRefreshTokenRepository), auditRepo, notifier, testPasswordResetConfig)
+	require.NoError(t, err)
+
+	err = service.ForgotPassword(context.Background(), models.ForgotPasswordRequest{Email: " editor@example.com "}, "127.0.0.1", "test")
+	require.NoError(t, err)
+	service.(*passwordResetService).pending.Wait()
+
+	require.Len(t, notifier.sent, 1)
+	assert.Equal(t, []string{"editor@example.com"}, notifier.sent[0].To)
+
+	// The link carries the token, and only its hash is stored
+	link := regexp.MustCompile(`https://\S+`).FindString(notifier.sent[0].Body)
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+	assert.Equal(t, "id", parsed.Query().Get("lang"))
+	rawToken := parsed.Query().Get("token")
+	require.NotEmpty(t, rawToken)
+
+	stored := resetRepo.Calls[1].Arguments.Get(0).(*models.PasswordResetToken)
+	assert.Equal(t, hashToken(rawToken), stored.TokenHash)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), stored.ExpiresAt, time.Minute)
+	assert.Equal(t, []string{"PASSWORD_RESET_REQUEST:true"}, auditActions(auditRepo))
+}
+
+func TestPasswordResetService_ForgotPasswordUnknownEmail(t *testing.T) {
+	resetRepo := new(MockPasswordResetRepository)
+	userRepo := new(MockUserRepository)
+	auditRepo := new(MockAuditRepository)
+	auditRepo.On("Create", mock.Anything).Return(nil)
+	notifier := &recordingNotifier{}
+
+	userRepo.On("GetByEmail", "nobody@example.com").Return(nil, fmt.Errorf("user not found"))
+	userRepo.On("GetByEmail", "former@example.com").Return(&models.User{ID: 3, Email: "former@example.com", IsActive: false}, nil)
+
+	service, err := NewPasswordResetService(resetRepo, userRepo, new(MockRefreshTokenRepository), auditRepo, notifier, testPasswordResetConfig)
+	require.NoError(t, err)
+
+	// Unknown and deactivated accounts get the same answer as existing ones, and no email
+	for _, email := range []string{"nobody@example.com", "former@example.com"} {
+		err := service.ForgotPassword(context.Background(), models.ForgotPasswordRequest{Email: email}, "127.0.0.1", "test")
+		assert.NoError(t, err)
+	}
+	service.(*passwordResetService).pending.Wait()
+	assert.Empty(t, notifier.sent)
+	resetRepo.AssertNotCalled(t, "Create", mock.Anything)
+	assert.Equal(t, []string{"PASSWORD_RESET_REQUEST:false", "PASSWORD_RESET_REQUEST:false"}, auditActions(auditRepo))
+}
+
+func TestPasswordResetService_ResetPassword(t *testing.T) {
+	tests := []struct {
+		name        string
+		token       string
+		password    string
+		expectedErr string
+	}{
+		{name: "valid token", token: "good-token", password: "NewPass123!"},
+		{name: "weak password", token: "good-token", password: "weakpassword", expectedErr: "password must contain at least one uppercase letter"},
+		{name: "used or expired token", token: "old-token", password: "NewPass123!", expectedErr: "invalid or expired password reset token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetRepo := new(MockPasswordResetRepository)
+			userRepo := new(MockUserRepository)
+			tokenRepo := new(MockRefreshTokenRepository)
+			auditRepo := new(MockAuditRepository)
+			auditRepo.On("Create", mock.Anything).Return(nil)
+
+			resetRepo.On("Use", hashToken("good-token"), mock.Anything).Return(&models.PasswordResetToken{ID: 1, UserID: 2}, nil)
+			resetRepo.On("Use", hashToken("old-token"), mock.Anything).Return(nil, repository.ErrPasswordResetTokenNotFound)
+			resetRepo.On("InvalidateForUser", 2, mock.Anything).Return(int64(0), nil)
+			userRepo.On("GetByID", 2).Return(&models.User{ID: 2, Username: "editor", IsActive: true}, nil)
+			userRepo.On("ChangePassword", 2, "NewPass123!").Return(nil)
+			tokenRepo.On("RevokeAllForUser", 2).Return(int64(2), nil)
+
+			service, err := NewPasswordResetService(resetRepo, userRepo, tokenRepo, auditRepo, &recordingNotifier{}, testPasswordResetConfig)
+			require.NoError(t, err)
+
+			err = service.ResetPassword(models.ResetPasswordRequest{Token: tt.token, NewPassword: tt.password}, "127.0.0.1", "test")
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				userRepo.AssertNotCalled(t, "ChangePassword", mock.Anything, mock.Anything)
+				if tt.token == "good-token" {
+					// A rejected password leaves the link usable
+					resetRepo.AssertNotCalled(t, "Use", mock.Anything, mock.Anything)
+				}
+				return
+			}
+			require.NoError(t, err)
+			tokenRepo.AssertCalled(t, "RevokeAllForUser", 2)
+			assert.Equal(t, []string{"PASSWORD_RESET:true"}, auditActions(auditRepo))
+		})
+	}
+}
@@ -0,0 +1,524 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/ilramdhan/holidayapi/internal/config"
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/notify"
+	"github.com/ilramdhan/holidayapi/internal/repository"
+)
+
+// defaultReminderWeekday is the day next_week reminders are sent when none is given
+const defaultReminderWeekday = time.Friday
+
+// ReminderService manages reminder rules and distribution lists and sends the reminders that are due
+type ReminderService interface {
+	CreateRule(req models.CreateReminderRuleRequest, createdBy *models.User, ipAddress, userAgent string) (*models.ReminderRule, error)
+	ListRules() ([]models.ReminderRule, error)
+	GetRule(id int) (*models.ReminderRule, error)
+	UpdateRule(id int, req models.UpdateReminderRuleRequest, updatedBy *models.User, ipAddress, userAgent string) (*models.ReminderRule, error)
+	DeleteRule(id int, deletedBy *models.User, ipAddress, userAgent string) error
+	CreateList(req models.DistributionListRequest, createdBy *models.User, ipAddress, userAgent string) (*models.DistributionList, error)
+	ListLists() ([]models.DistributionList, error)
+	UpdateList(id int, req models.DistributionListRequest, updatedBy *models.User, ipAddress, userAgent string) (*models.DistributionList, error)
+	DeleteList(id int, deletedBy *models.User, ipAddress, userAgent string) error
+	SendDue(ctx context.Context, now time.Time) (int, error)
+}
+
+// reminderService implements ReminderService
+type reminderService struct {
+	reminderRepo repository.ReminderRepository
+	listRepo     repository.DistributionListRepository
+	holidayRepo  repository.HolidayRepository
+	auditRepo    repository.AuditRepository
+	notifier     notify.Notifier
+	location     *time.Location
+	sendAt       time.Duration // Offset from local midnight
+}
+
+// NewReminderService creates a new reminder service. It fails on an unknown time zone or a malformed send time.
+func NewReminderService(reminderRepo repository.ReminderRepository, listRepo repository.DistributionListRepository, holidayRepo repository.HolidayRepository, auditRepo repository.AuditRepository, notifier notify.Notifier, cfg config.ReminderConfig) (ReminderService, error) {
+	location, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid REMINDER_TIMEZONE %q: %w", cfg.Timezone, err)
+	}
+
+	sendTime, err := time.Parse("15:04", cfg.SendTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid REMINDER_SEND_TIME %q, use HH:MM", cfg.SendTime)
+	}
+
+	return &reminderService{
+		reminderRepo: reminderRepo,
+		listRepo:     listRepo,
+		holidayRepo:  holidayRepo,
+		auditRepo:    auditRepo,
+		notifier:     notifier,
+		location:     location,
+		sendAt:       time.Duration(sendTime.Hour())*time.Hour + time.Duration(sendTime.Minute())*time.Minute,
+	}, nil
+}
+
+// CreateRule adds a reminder rule
+func (s *reminderService) CreateRule(req models.CreateReminderRuleRequest, createdBy *models.User, ipAddress, userAgent string) (*models.ReminderRule, error) {
+	rule := &models.ReminderRule{
+		Name:         strings.TrimSpace(req.Name),
+		Kind:         req.Kind,
+		DaysBefore:   req.DaysBefore,
+		SendWeekday:  defaultReminderWeekday,
+		HolidayTypes: req.HolidayTypes,
+		Province:     req.Province,
+		Recipients:   req.Recipients,
+		IsActive:     true,
+		CreatedBy:    &createdBy.ID,
+	}
+	if req.SendWeekday != nil {
+		rule.SendWeekday = time.Weekday(*req.SendWeekday)
+	}
+
+	if err := s.normalizeRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.reminderRepo.Create(rule); err != nil {
+		s.logAudit(createdBy, models.ActionReminderCreate, models.ResourceReminder, nil,
+			fmt.Sprintf("Failed to create reminder %q", rule.Name), ipAddress, userAgent, false)
+		return nil, fmt.Errorf("failed to create reminder: %w", err)
+	}
+
+	s.logAudit(createdBy, models.ActionReminderCreate, models.ResourceReminder, &rule.ID,
+		fmt.Sprintf("Reminder %q created for %s", rule.Name, models.JoinList(rule.Recipients)), ipAddress, userAgent, true)
+
+	return rule, nil
+}
+
+// ListRules retrieves all reminder rules
+func (s *reminderService) ListRules() ([]models.ReminderRule, error) {
+	rules, err := s.reminderRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reminders: %w", err)
+	}
+	return rules, nil
+}
+
+// GetRule retrieves a reminder rule by ID
+func (s *reminderService) GetRule(id int) (*models.ReminderRule, error) {
+	return s.reminderRepo.GetByID(id)
+}
+
+// UpdateRule changes a reminder rule
+func (s *reminderService) UpdateRule(id int, req models.UpdateReminderRuleRequest, updatedBy *models.User, ipAddress, userAgent string) (*models.ReminderRule, error) {
+	rule, err := s.reminderRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		rule.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Kind != nil {
+		rule.Kind = *req.Kind
+	}
+	if req.DaysBefore != nil {
+		rule.DaysBefore = *req.DaysBefore
+	}
+	if req.SendWeekday != nil {
+		rule.SendWeekday = time.Weekday(*req.SendWeekday)
+	}
+	if req.HolidayTypes != nil {
+		rule.HolidayTypes = req.HolidayTypes
+	}
+	if req.Province != nil {
+		rule.Province = req.Province
+		if *req.Province == "" {
+			rule.Province = nil
+		}
+	}
+	if req.Recipients != nil {
+		rule.Recipients = req.Recipients
+	}
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+
+	if err := s.normalizeRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.reminderRepo.Update(rule); err != nil {
+		s.logAudit(updatedBy, models.ActionReminderUpdate, models.ResourceReminder, &id,
+			fmt.Sprintf("Failed to update reminder %q", rule.Name), ipAddress, userAgent, false)
+		return nil, fmt.Errorf("failed to update reminder: %w", err)
+	}
+
+	s.logAudit(updatedBy, models.ActionReminderUpdate, models.ResourceReminder, &id,
+		fmt.Sprintf("Reminder %q updated, recipients: %s, active: %t", rule.Name, models.JoinList(rule.Recipients), rule.IsActive),
+		ipAddress, userAgent, true)
+
+	return rule, nil
+}
+
+// DeleteRule removes a reminder rule
+func (s *reminderService) DeleteRule(id int, deletedBy *models.User, ipAddress, userAgent string) error {
+	rule, err := s.reminderRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.reminderRepo.Delete(id); err != nil {
+		s.logAudit(deletedBy, models.ActionReminderDelete, models.ResourceReminder, &id,
+			fmt.Sprintf("Failed to delete reminder %q", rule.Name), ipAddress, userAgent, false)
+		return err
+	}
+
+	s.logAudit(deletedBy, models.ActionReminderDelete, models.ResourceReminder, &id,
+		fmt.Sprintf("Reminder %q deleted", rule.Name), ipAddress, userAgent, true)
+
+	return nil
+}
+
+// CreateList adds a distribution list
+func (s *reminderService) CreateList(req models.DistributionListRequest, createdBy *models.User, ipAddress, userAgent string) (*models.DistributionList, error) {
+	list := &models.DistributionList{
+		Name:    strings.TrimSpace(req.Name),
+		Members: uniqueStrings(req.Members),
+	}
+
+	if _, err := s.listRepo.GetByName(list.Name); err == nil {
+		return nil, fmt.Errorf("distribution list %q already exists", list.Name)
+	}
+
+	if err := s.listRepo.Create(list); err != nil {
+		s.logAudit(createdBy, models.ActionDistributionListCreate, models.ResourceDistributionList, nil,
+			fmt.Sprintf("Failed to create distribution list %q", list.Name), ipAddress, userAgent, false)
+		return nil, fmt.Errorf("failed to create distribution list: %w", err)
+	}
+
+	s.logAudit(createdBy, models.ActionDistributionListCreate, models.ResourceDistributionList, &list.ID,
+		fmt.Sprintf("Distribution list %q created with %d members", list.Name, len(list.Members)), ipAddress, userAgent, true)
+
+	return list, nil
+}
+
+// ListLists retrieves all distribution lists
+func (s *reminderService) ListLists() ([]models.DistributionList, error) {
+	lists, err := s.listRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get distribution lists: %w", err)
+	}
+	return lists, nil
+}
+
+// UpdateList replaces the name and members of a distribution list.
+// A list cannot be renamed while reminder rules send to it by name.
+func (s *reminderService) UpdateList(id int, req models.DistributionListRequest, updatedBy *models.User, ipAddress, userAgent string) (*models.DistributionList, error) {
+	list, err := s.listRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name != list.Name {
+		if _, err := s.listRepo.GetByName(name); err == nil {
+			return nil, fmt.Errorf("distribution list %q already exists", name)
+		}
+		if err := s.ensureListUnused(list.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	list.Name = name
+	list.Members = uniqueStrings(req.Members)
+
+	if err := s.listRepo.Update(list); err != nil {
+		s.logAudit(updatedBy, models.ActionDistributionListUpdate, models.ResourceDistributionList, &id,
+			fmt.Sprintf("Failed to update distribution list %q", list.Name), ipAddress, userAgent, false)
+		return nil, fmt.Errorf("failed to update distribution list: %w", err)
+	}
+
+	s.logAudit(updatedBy, models.ActionDistributionListUpdate, models.ResourceDistributionList, &id,
+		fmt.Sprintf("Distribution list %q updated with %d members", list.Name, len(list.Members)), ipAddress, userAgent, true)
+
+	return list, nil
+}
+
+// DeleteList removes a distribution list that no reminder rule sends to
+func (s *reminderService) DeleteList(id int, deletedBy *models.User, ipAddress, userAgent string) error {
+	list, err := s.listRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.ensureListUnused(list.Name); err != nil {
+		return err
+	}
+
+	if err := s.listRepo.Delete(id); err != nil {
+		s.logAudit(deletedBy, models.ActionDistributionListDelete, models.ResourceDistributionList, &id,
+			fmt.Sprintf("Failed to delete distribution list %q", list.Name), ipAddress, userAgent, false)
+		return err
+	}
+
+	s.logAudit(deletedBy, models.ActionDistributionListDelete, models.ResourceDistributionList, &id,
+		fmt.Sprintf("Distribution list %q deleted", list.Name), ipAddress, userAgent, true)
+
+	return nil
+}
+
+// SendDue sends today's reminders once the configured send time has passed and returns how many emails were sent.
+// Each active rule is handled at most once per local day; a rule whose email fails is retried on the next call.
+func (s *reminderService) SendDue(ctx context.Context, now time.Time) (int, error) {
+	local := now.In(s.location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, s.location)
+	if local.Sub(midnight) < s.sendAt {
+		return 0, nil
+	}
+
+	// Holiday dates are stored as UTC midnight of the calendar day
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+
+	rules, err := s.reminderRepo.GetAll()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get reminders: %w", err)
+	}
+
+	sent := 0
+	for i := range rules {
+		if ctx.Err() != nil {
+			break
+		}
+
+		rule := &rules[i]
+		if !rule.IsActive || (rule.LastRunOn != nil && rule.LastRunOn.Format("2006-01-02") >= today.Format("2006-01-02")) {
+			continue
+		}
+
+		start, end, ok := reminderWindow(rule, today)
+		if !ok {
+			continue
+		}
+
+		holidays, err := s.upcomingHolidays(rule, start, end)
+		if err != nil {
+			fmt.Printf("Failed to check reminder %q: %v\n", rule.Name, err)
+			continue
+		}
+
+		if len(holidays) > 0 {
+			recipients, err := s.resolveRecipients(rule.Recipients)
+			if err != nil {
+				fmt.Printf("Failed to resolve recipients of reminder %q: %v\n", rule.Name, err)
+				continue
+			}
+
+			msg := composeReminder(rule, holidays, start, end)
+			msg.To = recipients
+			if err := s.notifier.Send(ctx, msg); err != nil {
+				fmt.Printf("Failed to send reminder %q: %v\n", rule.Name, err)
+				continue
+			}
+			sent++
+		}
+
+		if err := s.reminderRepo.MarkRun(rule.ID, today); err != nil {
+			fmt.Printf("Failed to mark reminder %q as run: %v\n", rule.Name, err)
+		}
+	}
+
+	return sent, nil
+}
+
+// upcomingHolidays returns the holidays between start and end that the rule announces
+func (s *reminderService) upcomingHolidays(rule *models.ReminderRule, start, end time.Time) ([]models.Holiday, error) {
+	holidays, err := s.holidayRepo.GetByDateRange(start, end, nil, models.HolidayScope{Province: rule.Province})
+	if err != nil {
+		return nil, err
+	}
+
+	matching := []models.Holiday{}
+	for _, holiday := range holidays {
+		if rule.MatchesType(holiday.Type) {
+			matching = append(matching, holiday)
+		}
+	}
+	return matching, nil
+}
+
+// resolveRecipients expands distribution list names into their members, without duplicates
+func (s *reminderService) resolveRecipients(recipients []string) ([]string, error) {
+	addresses := []string{}
+	for _, recipient := range recipients {
+		if strings.Contains(recipient, "@") {
+			addresses = append(addresses, recipient)
+			continue
+		}
+
+		list, err := s.listRepo.GetByName(recipient)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, list.Members...)
+	}
+	return uniqueStrings(addresses), nil
+}
+
+// normalizeRule validates a rule's province and recipients, which must be email addresses or existing list names
+func (s *reminderService) normalizeRule(rule *models.ReminderRule) error {
+	if rule.Kind == models.ReminderNextWeek {
+		rule.DaysBefore = 0
+	}
+
+	if rule.Province != nil {
+		code, ok := models.NormalizeProvinceCode(*rule.Province)
+		if !ok {
+			return fmt.Errorf("invalid province code %q", *rule.Province)
+		}
+		rule.Province = &code
+	}
+
+	rule.Recipients = uniqueStrings(rule.Recipients)
+	for _, recipient := range rule.Recipients {
+		if strings.Contains(recipient, "@") {
+			if address, err := mail.ParseAddress(recipient); err != nil || address.Address != recipient {
+				return fmt.Errorf("invalid recipient %q, use a plain email address or a distribution list name", recipient)
+			}
+			continue
+		}
+		if _, err := s.listRepo.GetByName(recipient); err != nil {
+			return fmt.Errorf("invalid recipient %q, no distribution list has that name", recipient)
+		}
+	}
+
+	return nil
+}
+
+// ensureListUnused fails when a reminder rule sends to the named distribution list
+func (s *reminderService) ensureListUnused(name string) error {
+	rules, err := s.reminderRepo.GetAll()
+	if err != nil {
+		return fmt.Errorf("failed to get reminders: %w", err)
+	}
+
+	for _, rule := range rules {
+		for _, recipient := range rule.Recipients {
+			if recipient == name {
+				return fmt.Errorf("distribution list %q is in use by reminder %q", name, rule.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// logAudit logs an audit entry for a reminder rule or distribution list
+func (s *reminderService) logAudit(user *models.User, action models.AuditAction, resource models.AuditResource, resourceID *int, details, ipAddress, userAgent string, success bool) {
+	auditLog := &models.AuditLog{
+		UserID:     &user.ID,
+		Username:   user.Username,
+		Action:     action,
+		Resource:   resource,
+		ResourceID: resourceID,
+		Details:    details,
+		IPAddress:  ipAddress,
+		UserAgent:  userAgent,
+		Success:    success,
+	}
+
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		fmt.Printf("Failed to create audit log: %v\n", err)
+	}
+}
+
+// reminderWindow returns the dates a rule announces today, or false when the rule does not run today
+func reminderWindow(rule *models.ReminderRule, today time.Time) (time.Time, time.Time, bool) {
+	switch rule.Kind {
+	case models.ReminderDaysBefore:
+		day := today.AddDate(0, 0, rule.DaysBefore)
+		return day, day, true
+	case models.ReminderNextWeek:
+		if today.Weekday() != rule.SendWeekday {
+			return time.Time{}, time.Time{}, false
+		}
+		daysToMonday := (8 - int(today.Weekday())) % 7
+		if daysToMonday == 0 {
+			daysToMonday = 7
+		}
+		monday := today.AddDate(0, 0, daysToMonday)
+		return monday, monday.AddDate(0, 0, 6), true
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// composeReminder renders the email announcing the holidays a rule found
+func composeReminder(rule *models.ReminderRule, holidays []models.Holiday, start, end time.Time) notify.Message {
+	var when string
+	switch {
+	case rule.Kind == models.ReminderNextWeek:
+		when = fmt.Sprintf("next week (%s - %s)", start.Format("2 Jan"), end.Format("2 Jan 2006"))
+	case rule.DaysBefore == 0:
+		when = "today"
+	case rule.DaysBefore == 1:
+		when = "tomorrow"
+	default:
+		when = fmt.Sprintf("in %d days", rule.DaysBefore)
+	}
+
+	var subject string
+	if len(holidays) == 1 {
+		subject = fmt.Sprintf("%s %s", holidays[0].Name, when)
+		if rule.Kind == models.ReminderDaysBefore {
+			subject += fmt.Sprintf(" (%s)", holidays[0].Date.Format("Monday, 2 January 2006"))
+		}
+	} else {
+		subject = fmt.Sprintf("%d holidays %s", len(holidays), when)
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Holidays %s:\n\n", when)
+	for _, holiday := range holidays {
+		fmt.Fprintf(&body, "- %s: %s (%s)\n", holiday.Date.Format("Monday, 2 January 2006"), holiday.Name, holidayTypeLabel(holiday))
+		if holiday.Status == models.StatusProvisional {
+			body.WriteString("  Provisional, not yet confirmed by decree\n")
+		}
+		if holiday.Description != "" {
+			fmt.Fprintf(&body, "  %s\n", holiday.Description)
+		}
+	}
+	fmt.Fprintf(&body, "\nYou receive this email through the reminder rule %q.\n", rule.Name)
+
+	return notify.Message{Subject: subject, Body: body.String()}
+}
+
+// holidayTypeLabel describes a holiday's type for reminder emails
+func holidayTypeLabel(holiday models.Holiday) string {
+	switch holiday.Type {
+	case models.NationalHoliday:
+		return "national holiday"
+	case models.CollectiveLeave:
+		return "cuti bersama"
+	case models.RegionalHoliday:
+		if holiday.Province != nil {
+			return "regional holiday, " + models.ProvinceName(*holiday.Province)
+		}
+		return "regional holiday"
+	}
+	return string(holiday.Type)
+}
+
+// uniqueStrings removes empty and duplicate values while keeping their order
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value != "" && !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,287 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilramdhan/holidayapi/internal/config"
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/notify"
+)
+
+// MockReminderRepository is a mock implementation of ReminderRepository
+type MockReminderRepository struct {
+	mock.Mock
+}
+
+func (m *MockReminderRepository) Create(rule *models.ReminderRule) error {
+	args := m.Called(rule)
+	return args.Error(0)
+}
+
+func (m *MockReminderRepository) GetByID(id int) (*models.ReminderRule, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReminderRule), args.Error(1)
+}
+
+func (m *MockReminderRepository) GetAll() ([]models.ReminderRule, error) {
+	args := m.Called()
+	return args.Get(0).([]models.ReminderRule), args.Error(1)
+}
+
+func (m *MockReminderRepository) Update(rule *models.ReminderRule) error {
+	args := m.Called(rule)
+	return args.Error(0)
+}
+
+func (m *MockReminderRepository) Delete(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockReminderRepository) MarkRun(id int, day time.Time) error {
+	args := m.Called(id, day)
+	return args.Error(0)
+}
+
+// MockDistributionListRepository is a mock implementation of DistributionListRepository
+type MockDistributionListRepository struct {
+	mock.Mock
+}
+
+func (m *MockDistributionListRepository) Create(list *models.DistributionList) error {
+	args := m.Called(list)
+	return args.Error(0)
+}
+
+func (m *MockDistributionListRepository) GetByID(id int) (*models.DistributionList, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DistributionList), args.Error(1)
+}
+
+func (m *MockDistributionListRepository) GetByName(name string) (*models.DistributionList, error) {
+	args := m.Called(name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DistributionList), args.Error(1)
+}
+
+func (m *MockDistributionListRepository) GetAll() ([]models.DistributionList, error) {
+	args := m.Called()
+	return args.Get(0).([]models.DistributionList), args.Error(1)
+}
+
+func (m *MockDistributionListRepository) Update(list *models.DistributionList) error {
+	args := m.Called(list)
+	return args.Error(0)
+}
+
+func (m *MockDistributionListRepository) Delete(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// recordingNotifier keeps sent messages instead of delivering them
+type recordingNotifier struct {
+	sent []notify.Message
+	err  error
+}
+
+func (n *recordingNotifier) Send(ctx context.Context, msg notify.Message) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.sent = append(n.sent, msg)
+	return nil
+}
+
+var testReminderConfig = config.ReminderConfig{SendTime: "08:00", Timezone: "Asia/Jakarta"}
+
+// jakartaTime returns a wall clock time in Asia/Jakarta (UTC+7)
+func jakartaTime(year int, month time.Month, day, hour int) time.Time {
+	return time.Date(year, month, day, hour, 0, 0, 0, time.FixedZone("WIB", 7*60*60))
+}
+
+func utcDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func stringPtr(v string) *string { return &v }
+
+func TestReminderService_SendDue(t *testing.T) {
+	friday := utcDate(2025, 3, 28)
+	yesterday := utcDate(2025, 3, 27)
+
+	idulFitri := models.Holiday{ID: 1, Name: "Hari Raya Idul Fitri 1446 H", Date: utcDate(2025, 3, 31), Type: models.NationalHoliday, Status: models.StatusOfficial}
+	cutiBersama := models.Holiday{ID: 2, Name: "Cuti Bersama Idul Fitri", Date: utcDate(2025, 4, 2), Type: models.CollectiveLeave, Status: models.StatusOfficial}
+
+	rules := []models.ReminderRule{
+		{ID: 1, Name: "Holiday in 3 days", Kind: models.ReminderDaysBefore, DaysBefore: 3, Recipients: []string{"all-staff", "ceo@example.com"}, IsActive: true, LastRunOn: &yesterday},
+		{ID: 2, Name: "Cuti bersama next week", Kind: models.ReminderNextWeek, SendWeekday: time.Friday, HolidayTypes: []models.HolidayType{models.CollectiveLeave}, Recipients: []string{"hr@example.com"}, IsActive: true},
+		{ID: 3, Name: "Already sent", Kind: models.ReminderDaysBefore, DaysBefore: 1, Recipients: []string{"hr@example.com"}, IsActive: true, LastRunOn: &friday},
+		{ID: 4, Name: "Paused", Kind: models.ReminderDaysBefore, DaysBefore: 3, Recipients: []string{"hr@example.com"}},
+		{ID: 5, Name: "Monday digest", Kind: models.ReminderNextWeek, SendWeekday: time.Monday, Recipients: []string{"hr@example.com"}, IsActive: true},
+		{ID: 6, Name: "Nothing tomorrow", Kind: models.ReminderDaysBefore, DaysBefore: 1, Recipients: []string{"hr@example.com"}, IsActive: true},
+	}
+
+	reminderRepo := new(MockReminderRepository)
+	listRepo := new(MockDistributionListRepository)
+	holidayRepo := new(MockHolidayRepository)
+	notifier := &recordingNotifier{}
+
+	reminderRepo.On("GetAll").Return(rules, nil)
+	listRepo.On("GetByName", "all-staff").Return(&models.DistributionList{Name: "all-staff", Members: []string{"hr@example.com", "ceo@example.com", "ops@example.com"}}, nil)
+	holidayRepo.On("GetByDateRange", utcDate(2025, 3, 31), utcDate(2025, 3, 31), (*models.HolidayType)(nil), models.HolidayScope{}).Return([]models.Holiday{idulFitri}, nil)
+	holidayRepo.On("GetByDateRange", utcDate(2025, 3, 31), utcDate(2025, 4, 6), (*models.HolidayType)(nil), models.HolidayScope{}).Return([]models.Holiday{idulFitri, cutiBersama}, nil)
+	holidayRepo.On("GetByDateRange", utcDate(2025, 3, 29), utcDate(2025, 3, 29), (*models.HolidayType)(nil), models.HolidayScope{}).Return([]models.Holiday{}, nil)
+	reminderRepo.On("MarkRun", 1, friday).Return(nil)
+	reminderRepo.On("MarkRun", 2, friday).Return(nil)
+	reminderRepo.On("MarkRun", 6, friday).Return(nil)
+
+	service, err := NewReminderService(reminderRepo, listRepo, holidayRepo, new(MockAuditRepository), notifier, testReminderConfig)
+	require.NoError(t, err)
+
+	// 07:00 in Jakarta is before the send time
+	sent, err := service.SendDue(context.Background(), jakartaTime(2025, 3, 28, 7))
+	require.NoError(t, err)
+	assert.Equal(t, 0, sent)
+	reminderRepo.AssertNotCalled(t, "GetAll")
+
+	// 09:00 in Jakarta is 02:00 UTC, still Friday the 28th
+	sent, err = service.SendDue(context.Background(), jakartaTime(2025, 3, 28, 9).UTC())
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+
+	require.Len(t, notifier.sent, 2)
+	assert.Equal(t, "Hari Raya Idul Fitri 1446 H in 3 days (Monday, 31 March 2025)", notifier.sent[0].Subject)
+	assert.Equal(t, []string{"hr@example.com", "ceo@example.com", "ops@example.com"}, notifier.sent[0].To)
+	assert.Contains(t, notifier.sent[0].Body, "- Monday, 31 March 2025: Hari Raya Idul Fitri 1446 H (national holiday)")
+
+	assert.Equal(t, "Cuti Bersama Idul Fitri next week (31 Mar - 6 Apr 2025)", notifier.sent[1].Subject)
+	assert.Equal(t, []string{"hr@example.com"}, notifier.sent[1].To)
+	assert.NotContains(t, notifier.sent[1].Body, "1446 H", "only cuti bersama is announced")
+
+	reminderRepo.AssertExpectations(t)
+	holidayRepo.AssertExpectations(t)
+	reminderRepo.AssertNotCalled(t, "MarkRun", 3, mock.Anything)
+	reminderRepo.AssertNotCalled(t, "MarkRun", 4, mock.Anything)
+	reminderRepo.AssertNotCalled(t, "MarkRun", 5, mock.Anything)
+}
+
+func TestReminderService_SendDueRetriesFailedEmail(t *testing.T) {
+	rule := models.ReminderRule{ID: 1, Name: "Holiday tomorrow", Kind: models.ReminderDaysBefore, DaysBefore: 1, Recipients: []string{"hr@example.com"}, IsActive: true}
+	nyepi := models.Holiday{ID: 3, Name: "Hari Suci Nyepi", Date: utcDate(2025, 3, 29), Type: models.NationalHoliday}
+
+	reminderRepo := new(MockReminderRepository)
+	holidayRepo := new(MockHolidayRepository)
+	notifier := &recordingNotifier{err: fmt.Errorf("connection refused")}
+
+	reminderRepo.On("GetAll").Return([]models.ReminderRule{rule}, nil)
+	holidayRepo.On("GetByDateRange", utcDate(2025, 3, 29), utcDate(2025, 3, 29), (*models.HolidayType)(nil), models.HolidayScope{}).Return([]models.Holiday{nyepi}, nil)
+
+	service, err := NewReminderService(reminderRepo, new(MockDistributionListRepository), holidayRepo, new(MockAuditRepository), notifier, testReminderConfig)
+	require.NoError(t, err)
+
+	sent, err := service.SendDue(context.Background(), jakartaTime(2025, 3, 28, 9))
+	require.NoError(t, err)
+	assert.Equal(t, 0, sent)
+	reminderRepo.AssertNotCalled(t, "MarkRun", mock.Anything, mock.Anything)
+}
+
+func TestReminderService_CreateRule(t *testing.T) {
+	admin := &models.User{ID: 1, Username: "admin", Role: models.SuperAdminRole}
+
+	tests := []struct {
+		name          string
+		req           models.CreateReminderRuleRequest
+		expectedError string
+	}{
+		{
+			name: "recipients and lists",
+			req: models.CreateReminderRuleRequest{
+				Name: "Holiday in 7 days", Kind: models.ReminderDaysBefore, DaysBefore: 7,
+				Province: stringPtr("ba"), Recipients: []string{"all-staff", "hr@example.com", "hr@example.com"},
+			},
+		},
+		{
+			name:          "unknown list",
+			req:           models.CreateReminderRuleRequest{Name: "x", Kind: models.ReminderDaysBefore, Recipients: []string{"nobody"}},
+			expectedError: `invalid recipient "nobody", no distribution list has that name`,
+		},
+		{
+			name:          "display name instead of address",
+			req:           models.CreateReminderRuleRequest{Name: "x", Kind: models.ReminderDaysBefore, Recipients: []string{"HR <hr@example.com>"}},
+			expectedError: `invalid recipient "HR <hr@example.com>", use a plain email address or a distribution list name`,
+		},
+		{
+			name:          "unknown province",
+			req:           models.CreateReminderRuleRequest{Name: "x", Kind: models.ReminderDaysBefore, Province: stringPtr("ID-XX"), Recipients: []string{"hr@example.com"}},
+			expectedError: `invalid province code "ID-XX"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reminderRepo := new(MockReminderRepository)
+			listRepo := new(MockDistributionListRepository)
+			auditRepo := new(MockAuditRepository)
+
+			listRepo.On("GetByName", "all-staff").Return(&models.DistributionList{ID: 1, Name: "all-staff"}, nil)
+			listRepo.On("GetByName", mock.Anything).Return(nil, fmt.Errorf("distribution list not found"))
+			reminderRepo.On("Create", mock.AnythingOfType("*models.ReminderRule")).Return(nil)
+			auditRepo.On("Create", mock.AnythingOfType("*models.AuditLog")).Return(nil)
+
+			service, err := NewReminderService(reminderRepo, listRepo, new(MockHolidayRepository), auditRepo, &recordingNotifier{}, testReminderConfig)
+			require.NoError(t, err)
+
+			rule, err := service.CreateRule(tt.req, admin, "127.0.0.1", "test")
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				reminderRepo.AssertNotCalled(t, "Create", mock.Anything)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "ID-BA", *rule.Province)
+			assert.Equal(t, time.Friday, rule.SendWeekday)
+			assert.Equal(t, []string{"all-staff", "hr@example.com"}, rule.Recipients)
+			auditRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReminderService_DeleteListInUse(t *testing.T) {
+	reminderRepo := new(MockReminderRepository)
+	listRepo := new(MockDistributionListRepository)
+
+	listRepo.On("GetByID", 1).Return(&models.DistributionList{ID: 1, Name: "all-staff"}, nil)
+	reminderRepo.On("GetAll").Return([]models.ReminderRule{{ID: 4, Name: "Holiday in 7 days", Recipients: []string{"all-staff"}}}, nil)
+
+	service, err := NewReminderService(reminderRepo, listRepo, new(MockHolidayRepository), new(MockAuditRepository), &recordingNotifier{}, testReminderConfig)
+	require.NoError(t, err)
+
+	err = service.DeleteList(1, &models.User{ID: 1, Username: "admin"}, "127.0.0.1", "test")
+	assert.EqualError(t, err, `distribution list "all-staff" is in use by reminder "Holiday in 7 days"`)
+	listRepo.AssertNotCalled(t, "Delete", mock.Anything)
+}
+
+func TestNewReminderService_InvalidConfig(t *testing.T) {
+	_, err := NewReminderService(nil, nil, nil, nil, nil, config.ReminderConfig{SendTime: "8am", Timezone: "Asia/Jakarta"})
+	assert.Error(t, err)
+
+	_, err = NewReminderService(nil, nil, nil, nil, nil, config.ReminderConfig{SendTime: "08:00", Timezone: "Asia/Atlantis"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/repository"
+)
+
+// roleNamePattern restricts role names to lowercase identifiers such as "auditor" or "holiday_editor"
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// RoleService manages custom roles and the permissions they grant
+type RoleService interface {
+	ListPermissions() []models.Permission
+	ListRoles() ([]models.Role, error)
+	GetRole(id int) (*models.Role, error)
+	CreateRole(req models.CreateRoleRequest, createdBy *models.User, ipAddress, userAgent string) (*models.Role, error)
+	UpdateRole(id int, req models.UpdateRoleRequest, updatedBy *models.User, ipAddress, userAgent string) (*models.Role, error)
+	DeleteRole(id int, deletedBy *models.User, ipAddress, userAgent string) error
+}
+
+// roleService implements RoleService
+type roleService struct {
+	roleRepo  repository.RoleRepository
+	auditRepo repository.AuditRepository
+}
+
+// NewRoleService creates a new role service
+func NewRoleService(roleRepo repository.RoleRepository, auditRepo repository.AuditRepository) RoleService {
+	return &roleService{
+		roleRepo:  roleRepo,
+		auditRepo: auditRepo,
+	}
+}
+
+// ListPermissions returns every permission a role can grant
+func (s *roleService) ListPermissions() []models.Permission {
+	return models.AllPermissions
+}
+
+// ListRoles returns all roles, built-in roles first
+func (s *roleService) ListRoles() ([]models.Role, error) {
+	roles, err := s.roleRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range roles {
+		if roles[i].Name == models.SuperAdminRole {
+			roles[i].Permissions = models.AllPermissions
+		}
+	}
+
+	return roles, nil
+}
+
+// GetRole retrieves a role by ID
+func (s *roleService) GetRole(id int) (*models.Role, error) {
+	role, err := s.roleRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if role.Name == models.SuperAdminRole {
+		role.Permissions = models.AllPermissions
+	}
+
+	return role, nil
+}
+
+// CreateRole defines a custom role. Only permissions the creator holds can be granted.
+func (s *roleService) CreateRole(req models.CreateRoleRequest, createdBy *models.User, ipAddress, userAgent string) (*models.Role, error) {
+	name := models.UserRole(strings.TrimSpace(string(req.Name)))
+	if !roleNamePattern.MatchString(string(name)) {
+		return nil, fmt.Errorf("role name must start with a letter and contain only lowercase letters, digits and underscores")
+	}
+
+	permissions, err := uniquePermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ensureGrantable(s.roleRepo, createdBy, permissions); err != nil {
+		s.logAudit(createdBy, models.ActionRoleCreate, nil,
+			fmt.Sprintf("Creation of role %s refused: %v", name, err), ipAddress, userAgent, false)
+		return nil, err
+	}
+
+	if _, err := s.roleRepo.GetByName(name); err == nil {
+		return nil, fmt.Errorf("role already exists")
+	}
+
+	role := &models.Role{
+		Name:        name,
+		Description: req.Description,
+		Permissions: permissions,
+	}
+	if err := s.roleRepo.Create(role); err != nil {
+		return nil, err
+	}
+
+	s.logAudit(createdBy, models.ActionRoleCreate, &role.ID,
+		fmt.Sprintf("Role %s created with permissions: %s", role.Name, models.JoinPermissions(role.Permissions)), ipAddress, userAgent, true)
+
+	return role, nil
+}
+
+// UpdateRole changes the description or permissions of a role. Tokens issued before keep the
+// old permissions until they are refreshed.
+func (s *roleService) UpdateRole(id int, req models.UpdateRoleRequest, updatedBy *models.User, ipAddress, userAgent string) (*models.Role, error) {
+	role, err := s.roleRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Description != nil {
+		role.Description = *req.Description
+	}
+	if len(req.Permissions) > 0 {
+		if role.Name == models.SuperAdminRole {
+			return nil, fmt.Errorf("the super_admin role always holds every permission")
+		}
+
+		permissions, err := uniquePermissions(req.Permissions)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := ensureGrantable(s.roleRepo, updatedBy, permissions); err != nil {
+			s.logAudit(updatedBy, models.ActionRoleUpdate, &id,
+				fmt.Sprintf("Update of role %s refused: %v", role.Name, err), ipAddress, userAgent, false)
+			return nil, err
+		}
+		role.Permissions = permissions
+	}
+
+	if err := s.roleRepo.Update(role); err != nil {
+		return nil, err
+	}
+
+	s.logAudit(updatedBy, models.ActionRoleUpdate, &id,
+		fmt.Sprintf("Role %s updated, permissions: %s", role.Name, models.JoinPermissions(role.Permissions)), ipAddress, userAgent, true)
+
+	if role.Name == models.SuperAdminRole {
+		role.Permissions = models.AllPermissions
+	}
+
+	return role, nil
+}
+
+// DeleteRole removes a custom role that no user is assigned to
+func (s *roleService) DeleteRole(id int, deletedBy *models.User, ipAddress, userAgent string) error {
+	role, err := s.roleRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	if role.IsSystem {
+		return fmt.Errorf("built-in roles cannot be deleted")
+	}
+
+	users, err := s.roleRepo.CountUsers(role.Name)
+	if err != nil {
+		return err
+	}
+	if users > 0 {
+		return fmt.Errorf("role is still assigned to %d users", users)
+	}
+
+	if err := s.roleRepo.Delete(id); err != nil {
+		return err
+	}
+
+	s.logAudit(deletedBy, models.ActionRoleDelete, &id,
+		fmt.Sprintf("Role %s deleted", role.Name), ipAddress, userAgent, true)
+
+	return nil
+}
+
+// logAudit logs an audit entry for a role
+func (s *roleService) logAudit(user *models.User, action models.AuditAction, roleID *int, details, ipAddress, userAgent string, success bool) {
+	auditLog := &models.AuditLog{
+		UserID:     &user.ID,
+		Username:   user.Username,
+		Action:     action,
+		Resource:   models.ResourceRole,
+		ResourceID: roleID,
+		Details:    details,
+		IPAddress:  ipAddress,
+		UserAgent:  userAgent,
+		Success:    success,
+	}
+
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		fmt.Printf("Failed to create audit log: %v\n", err)
+	}
+}
+
+// rolePermissions returns the permissions granted by the named role. The super_admin role
+// holds every permission, including ones added after it was stored.
+func rolePermissions(roleRepo repository.RoleRepository, name models.UserRole) ([]models.Permission, error) {
+	if name == models.SuperAdminRole {
+		return models.AllPermissions, nil
+	}
+
+	role, err := roleRepo.GetByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("role %s not found: %w", name, err)
+	}
+
+	return role.Permissions, nil
+}
+
+// ensureGrantable refuses to let a user hand out permissions they do not hold themselves,
+// whether by defining a role or by assigning one
+func ensureGrantable(roleRepo repository.RoleRepository, actor *models.User, permissions []models.Permission) error {
+	held, err := rolePermissions(roleRepo, actor.Role)
+	if err != nil {
+		return err
+	}
+
+	for _, permission := range permissions {
+		if !models.HasPermission(held, permission) {
+			return fmt.Errorf("cannot grant the %s permission, which you do not have", permission)
+		}
+	}
+
+	return nil
+}
+
+// ensureManageable refuses to let a user act on an account whose role holds permissions they lack,
+// so holding user:manage does not allow taking over a more privileged account
+func ensureManageable(roleRepo repository.RoleRepository, actor, target *models.User) error {
+	permissions, err := rolePermissions(roleRepo, target.Role)
+	if err != nil {
+		return err
+	}
+
+	if err := ensureGrantable(roleRepo, actor, permissions); err != nil {
+		return fmt.Errorf("cannot manage a user with permissions beyond your own: %w", err)
+	}
+
+	return nil
+}
+
+// uniquePermissions validates permissions and drops duplicates, keeping their order
+func uniquePermissions(permissions []models.Permission) ([]models.Permission, error) {
+	unique := []models.Permission{}
+	for _, permission := range permissions {
+		if !permission.IsValid() {
+			return nil, fmt.Errorf("unknown permission: %s", permission)
+		}
+		if !models.HasPermission(unique, permission) {
+			unique = append(unique, permission)
+		}
+	}
+	return unique, nil
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+// MockRoleRepository is a mock implementation of RoleRepository
+type MockRoleRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoleRepository) Create(role *models.Role) error {
+	args := m.Called(role)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) GetByID(id int) (*models.Role, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) GetByName(name models.UserRole) (*models.Role, error) {
+	args := m.Called(name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) GetAll() ([]models.Role, error) {
+	args := m.Called()
+	return args.Get(0).([]models.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) Update(role *models.Role) error {
+	args := m.Called(role)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) Delete(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) CountUsers(name models.UserRole) (int, error) {
+	args := m.Called(name)
+	return args.Int(0), args.Error(1)
+}
+
+// newTestRoleRepository returns a role repository mock holding only the built-in admin role as migrated
+func newTestRoleRepository() *MockRoleRepository {
+	roleRepo := new(MockRoleRepository)
+	roleRepo.On("GetByName", models.AdminRole).Return(&models.Role{
+		ID:       2,
+		Name:     models.AdminRole,
+		IsSystem: true,
+		Permissions: []models.Permission{
+			models.PermissionHolidayRead, models.PermissionHolidayCreate, models.PermissionHolidayUpdate,
+			models.PermissionHolidayDelete, models.PermissionAuditRead, models.PermissionUserRead,
+		},
+	}, nil)
+	roleRepo.On("GetByName", mock.Anything).Return(nil, fmt.Errorf("role not found"))
+	return roleRepo
+}
+
+func TestRoleService_CreateRole(t *testing.T) {
+	superAdmin := &models.User{ID: 1, Username: "root", Role: models.SuperAdminRole}
+	admin := &models.User{ID: 2, Username: "editor", Role: models.AdminRole}
+
+	tests := []struct {
+		name        string
+		req         models.CreateRoleRequest
+		createdBy   *models.User
+		expectedErr string
+	}{
+		{name: "auditor", req: models.CreateRoleRequest{Name: "auditor", Permissions: []models.Permission{"audit:read", "holiday:read", "audit:read"}}, createdBy: superAdmin},
+		{name: "unknown permission", req: models.CreateRoleRequest{Name: "auditor", Permissions: []models.Permission{"audit:write"}}, createdBy: superAdmin, expectedErr: "unknown permission: audit:write"},
+		{name: "invalid name", req: models.CreateRoleRequest{Name: "Audit Team", Permissions: []models.Permission{"audit:read"}}, createdBy: superAdmin, expectedErr: "role name must start with a letter and contain only lowercase letters, digits and underscores"},
+		{name: "existing name", req: models.CreateRoleRequest{Name: "admin", Permissions: []models.Permission{"audit:read"}}, createdBy: superAdmin, expectedErr: "role already exists"},
+		{name: "beyond own permissions", req: models.CreateRoleRequest{Name: "manager", Permissions: []models.Permission{"holiday:read", "user:manage"}}, createdBy: admin, expectedErr: "cannot grant the user:manage permission, which you do not have"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roleRepo := newTestRoleRepository()
+			auditRepo := new(MockAuditRepository)
+			auditRepo.On("Create", mock.Anything).Return(nil)
+			roleRepo.On("Create", mock.AnythingOfType("*models.Role")).Return(nil)
+
+			service := NewRoleService(roleRepo, auditRepo)
+			role, err := service.CreateRole(tt.req, tt.createdBy, "127.0.0.1", "test")
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				roleRepo.AssertNotCalled(t, "Create", mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []models.Permission{models.PermissionAuditRead, models.PermissionHolidayRead}, role.Permissions)
+			assert.Equal(t, []string{"ROLE_CREATE:true"}, auditActions(auditRepo))
+		})
+	}
+}
+
+func TestRoleService_UpdateRole(t *testing.T) {
+	roleRepo := newTestRoleRepository()
+	auditRepo := new(MockAuditRepository)
+	auditRepo.On("Create", mock.Anything).Return(nil)
+	roleRepo.On("GetByID", 1).Return(&models.Role{ID: 1, Name: models.SuperAdminRole, IsSystem: true}, nil)
+	roleRepo.On("GetByID", 3).Return(&models.Role{ID: 3, Name: "auditor", Permissions: []models.Permission{models.PermissionAuditRead}}, nil)
+	roleRepo.On("Update", mock.AnythingOfType("*models.Role")).Return(nil)
+
+	service := NewRoleService(roleRepo, auditRepo)
+	superAdmin := &models.User{ID: 1, Username: "root", Role: models.SuperAdminRole}
+
+	_, err := service.UpdateRole(1, models.UpdateRoleRequest{Permissions: []models.Permission{models.PermissionAuditRead}}, superAdmin, "", "")
+	assert.EqualError(t, err, "the super_admin role always holds every permission")
+
+	role, err := service.UpdateRole(3, models.UpdateRoleRequest{Permissions: []models.Permission{models.PermissionAuditRead, models.PermissionAuditVerify}}, superAdmin, "", "")
+	require.NoError(t, err)
+	assert.True(t, role.HasPermission(models.PermissionAuditVerify))
+	assert.Equal(t, []string{"ROLE_UPDATE:true"}, auditActions(auditRepo))
+}
+
+func TestRoleService_DeleteRole(t *testing.T) {
+	roleRepo := newTestRoleRepository()
+	auditRepo := new(MockAuditRepository)
+	auditRepo.On("Create", mock.Anything).Return(nil)
+	roleRepo.On("GetByID", 2).Return(&models.Role{ID: 2, Name: models.AdminRole, IsSystem: true}, nil)
+	roleRepo.On("GetByID", 3).Return(&models.Role{ID: 3, Name: "auditor"}, nil)
+	roleRepo.On("GetByID", 4).Return(&models.Role{ID: 4, Name: "editor"}, nil)
+	roleRepo.On("CountUsers", models.UserRole("auditor")).Return(2, nil)
+	roleRepo.On("CountUsers", models.UserRole("editor")).Return(0, nil)
+	roleRepo.On("Delete", 4).Return(nil)
+
+	service := NewRoleService(roleRepo, auditRepo)
+	superAdmin := &models.User{ID: 1, Username: "root", Role: models.SuperAdminRole}
+
+	assert.EqualError(t, service.DeleteRole(2, superAdmin, "", ""), "built-in roles cannot be deleted")
+	assert.EqualError(t, service.DeleteRole(3, superAdmin, "", ""), "role is still assigned to 2 users")
+	assert.NoError(t, service.DeleteRole(4, superAdmin, "", ""))
+	roleRepo.AssertNotCalled(t, "Delete", 3)
+	assert.Equal(t, []string{"ROLE_DELETE:true"}, auditActions(auditRepo))
+}
@@ -0,0 +1,372 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ilramdhan/holidayapi/internal/config"
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/repository"
+)
+
+const (
+	// WebhookSignatureHeader carries the HMAC-SHA256 signature of a delivery, "sha256=<hex>"
+	WebhookSignatureHeader = "X-Holiday-Signature"
+	// WebhookTimestampHeader carries the Unix time the delivery was signed at
+	WebhookTimestampHeader = "X-Holiday-Timestamp"
+	// WebhookEventHeader carries the event type of a delivery
+	WebhookEventHeader = "X-Holiday-Event"
+	// WebhookDeliveryHeader carries the delivery ID, which stays the same across retries
+	WebhookDeliveryHeader = "X-Holiday-Delivery"
+
+	// webhookSecretPrefix marks generated webhook secrets
+	webhookSecretPrefix = "whsec_"
+	// webhookBatchSize caps the deliveries attempted in one dispatch pass
+	webhookBatchSize = 100
+	// maxWebhookRetryDelay caps the exponential backoff between attempts
+	maxWebhookRetryDelay = 6 * time.Hour
+	// maxWebhookErrorLength caps the receiver response kept in a delivery's last_error
+	maxWebhookErrorLength = 512
+)
+
+// WebhookService manages webhook subscriptions and delivers queued holiday changes to them
+type WebhookService interface {
+	CreateWebhook(req models.CreateWebhookRequest, createdBy *models.User, ipAddress, userAgent string) (*models.CreateWebhookResponse, error)
+	ListWebhooks() ([]models.WebhookSubscription, error)
+	GetWebhook(id int) (*models.WebhookSubscription, error)
+	UpdateWebhook(id int, req models.UpdateWebhookRequest, updatedBy *models.User, ipAddress, userAgent string) (*models.WebhookSubscription, error)
+	DeleteWebhook(id int, deletedBy *models.User, ipAddress, userAgent string) error
+	GetDeliveries(id, limit, offset int) (*models.WebhookDeliveryResponse, error)
+	DeliverDue(ctx context.Context) (int, error)
+}
+
+// webhookService implements WebhookService
+type webhookService struct {
+	webhookRepo repository.WebhookRepository
+	auditRepo   repository.AuditRepository
+	cfg         config.WebhookConfig
+	client      *http.Client
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(webhookRepo repository.WebhookRepository, auditRepo repository.AuditRepository, cfg config.WebhookConfig) WebhookService {
+	return &webhookService{
+		webhookRepo: webhookRepo,
+		auditRepo:   auditRepo,
+		cfg:         cfg,
+		client:      &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// CreateWebhook adds a subscription. A secret is generated when none is given; it is returned only here.
+func (s *webhookService) CreateWebhook(req models.CreateWebhookRequest, createdBy *models.User, ipAddress, userAgent string) (*models.CreateWebhookResponse, error) {
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		random, err := generateRandomID(32)
+		if err != nil {
+			return nil, err
+		}
+		secret = webhookSecretPrefix + random
+	}
+
+	subscription := &models.WebhookSubscription{
+		URL:       req.URL,
+		Events:    uniqueEvents(req.Events),
+		Secret:    secret,
+		IsActive:  true,
+		CreatedBy: &createdBy.ID,
+	}
+
+	if err := s.webhookRepo.Create(subscription); err != nil {
+		s.logAudit(createdBy, models.ActionWebhookCreate, nil,
+			fmt.Sprintf("Failed to create webhook for %s", req.URL), ipAddress, userAgent, false)
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	s.logAudit(createdBy, models.ActionWebhookCreate, &subscription.ID,
+		fmt.Sprintf("Webhook %s created for events: %s", subscription.URL, models.JoinWebhookEvents(subscription.Events)),
+		ipAddress, userAgent, true)
+
+	return &models.CreateWebhookResponse{WebhookSubscription: subscription, Secret: secret}, nil
+}
+
+// ListWebhooks retrieves all subscriptions without their secrets
+func (s *webhookService) ListWebhooks() ([]models.WebhookSubscription, error) {
+	subscriptions, err := s.webhookRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+	return subscriptions, nil
+}
+
+// GetWebhook retrieves a subscription by ID
+func (s *webhookService) GetWebhook(id int) (*models.WebhookSubscription, error) {
+	return s.webhookRepo.GetByID(id)
+}
+
+// UpdateWebhook changes a subscription. Reactivating it resumes its pending deliveries.
+func (s *webhookService) UpdateWebhook(id int, req models.UpdateWebhookRequest, updatedBy *models.User, ipAddress, userAgent string) (*models.WebhookSubscription, error) {
+	subscription, err := s.webhookRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		if err := validateWebhookURL(*req.URL); err != nil {
+			return nil, err
+		}
+		subscription.URL = *req.URL
+	}
+	if len(req.Events) > 0 {
+		subscription.Events = uniqueEvents(req.Events)
+	}
+	if req.Secret != nil {
+		subscription.Secret = *req.Secret
+	}
+	if req.IsActive != nil {
+		subscription.IsActive = *req.IsActive
+	}
+
+	if err := s.webhookRepo.Update(subscription); err != nil {
+		s.logAudit(updatedBy, models.ActionWebhookUpdate, &id,
+			fmt.Sprintf("Failed to update webhook %s", subscription.URL), ipAddress, userAgent, false)
+		return nil, fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	details := fmt.Sprintf("Webhook %s updated, events: %s, active: %t", subscription.URL,
+		models.JoinWebhookEvents(subscription.Events), subscription.IsActive)
+	if req.Secret != nil {
+		details += ", secret rotated"
+	}
+	s.logAudit(updatedBy, models.ActionWebhookUpdate, &id, details, ipAddress, userAgent, true)
+
+	return subscription, nil
+}
+
+// DeleteWebhook removes a subscription and its delivery history
+func (s *webhookService) DeleteWebhook(id int, deletedBy *models.User, ipAddress, userAgent string) error {
+	subscription, err := s.webhookRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.webhookRepo.Delete(id); err != nil {
+		s.logAudit(deletedBy, models.ActionWebhookDelete, &id,
+			fmt.Sprintf("Failed to delete webhook %s", subscription.URL), ipAddress, userAgent, false)
+		return err
+	}
+
+	s.logAudit(deletedBy, models.ActionWebhookDelete, &id,
+		fmt.Sprintf("Webhook %s deleted", subscription.URL), ipAddress, userAgent, true)
+
+	return nil
+}
+
+// GetDeliveries retrieves the delivery history of a subscription, newest first
+func (s *webhookService) GetDeliveries(id, limit, offset int) (*models.WebhookDeliveryResponse, error) {
+	if _, err := s.webhookRepo.GetByID(id); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	deliveries, total, err := s.webhookRepo.GetDeliveries(id, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+
+	return &models.WebhookDeliveryResponse{
+		Data:   deliveries,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}, nil
+}
+
+// DeliverDue attempts every delivery that is due and returns how many succeeded
+func (s *webhookService) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := s.webhookRepo.GetDueDeliveries(time.Now(), webhookBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get due webhook deliveries: %w", err)
+	}
+
+	subscriptions := make(map[int]*models.WebhookSubscription)
+	delivered := 0
+
+	for i := range deliveries {
+		if ctx.Err() != nil {
+			break
+		}
+
+		delivery := &deliveries[i]
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			subscription, err = s.webhookRepo.GetByID(delivery.SubscriptionID)
+			if err != nil {
+				// Deleted since the batch was read; its deliveries are gone too
+				continue
+			}
+			subscriptions[delivery.SubscriptionID] = subscription
+		}
+
+		s.attempt(ctx, subscription, delivery)
+		if err := s.webhookRepo.UpdateDelivery(delivery); err != nil {
+			fmt.Printf("Failed to update webhook delivery %d: %v\n", delivery.ID, err)
+			continue
+		}
+		if delivery.Status == models.DeliveryDelivered {
+			delivered++
+		}
+	}
+
+	return delivered, nil
+}
+
+// attempt sends a delivery once and updates it with the outcome and, on failure, the next attempt time
+func (s *webhookService) attempt(ctx context.Context, subscription *models.WebhookSubscription, delivery *models.WebhookDelivery) {
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.ResponseStatus = nil
+
+	err := s.send(ctx, subscription, delivery)
+	if err == nil {
+		delivery.Status = models.DeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+		delivery.LastError = nil
+		return
+	}
+
+	message := err.Error()
+	delivery.LastError = &message
+
+	if delivery.Attempts >= s.cfg.MaxAttempts {
+		delivery.Status = models.DeliveryFailed
+		delivery.NextAttemptAt = nil
+		return
+	}
+
+	next := now.Add(webhookRetryDelay(s.cfg.RetryBaseDelay, delivery.Attempts))
+	delivery.NextAttemptAt = &next
+}
+
+// send posts a delivery's payload to the subscription URL, signed with its secret
+func (s *webhookService) send(ctx context.Context, subscription *models.WebhookSubscription, delivery *models.WebhookDelivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "HolidayAPI-Webhook/1.0")
+	req.Header.Set(WebhookEventHeader, string(delivery.EventType))
+	req.Header.Set(WebhookDeliveryHeader, strconv.Itoa(delivery.ID))
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(subscription.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	status := resp.StatusCode
+	delivery.ResponseStatus = &status
+
+	if status >= 200 && status < 300 {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebhookErrorLength))
+	if text := strings.TrimSpace(string(body)); text != "" {
+		return fmt.Errorf("receiver responded with %d: %s", status, text)
+	}
+	return fmt.Errorf("receiver responded with %d", status)
+}
+
+// logAudit logs an audit entry for a webhook subscription
+func (s *webhookService) logAudit(user *models.User, action models.AuditAction, webhookID *int, details, ipAddress, userAgent string, success bool) {
+	auditLog := &models.AuditLog{
+		UserID:     &user.ID,
+		Username:   user.Username,
+		Action:     action,
+		Resource:   models.ResourceWebhook,
+		ResourceID: webhookID,
+		Details:    details,
+		IPAddress:  ipAddress,
+		UserAgent:  userAgent,
+		Success:    success,
+	}
+
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		fmt.Printf("Failed to create audit log: %v\n", err)
+	}
+}
+
+// SignWebhookPayload returns the signature header value for a delivery: the hex HMAC-SHA256,
+// keyed with the subscription secret, of the timestamp, a dot and the request body
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookRetryDelay returns the wait after the given number of failed attempts: base, 2×base, 4×base, ...
+func webhookRetryDelay(base time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxWebhookRetryDelay {
+			return maxWebhookRetryDelay
+		}
+	}
+	return delay
+}
+
+// validateWebhookURL accepts absolute http and https URLs only
+func validateWebhookURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook url %q, use an absolute http or https URL", value)
+	}
+	return nil
+}
+
+// uniqueEvents removes duplicate event types while keeping their order
+func uniqueEvents(events []models.HolidayChangeType) []models.HolidayChangeType {
+	seen := make(map[models.HolidayChangeType]bool, len(events))
+	unique := make([]models.HolidayChangeType, 0, len(events))
+	for _, event := range events {
+		if !seen[event] {
+			seen[event] = true
+			unique = append(unique, event)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ilramdhan/holidayapi/internal/config"
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+// MockWebhookRepository is a mock implementation of WebhookRepository
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) Create(subscription *models.WebhookSubscription) error {
+	args := m.Called(subscription)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetByID(id int) (*models.WebhookSubscription, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetAll() ([]models.WebhookSubscription, error) {
+	args := m.Called()
+	return args.Get(0).([]models.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) Update(subscription *models.WebhookSubscription) error {
+	args := m.Called(subscription)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) Delete(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetDeliveries(subscriptionID, limit, offset int) ([]models.WebhookDelivery, int, error) {
+	args := m.Called(subscriptionID, limit, offset)
+	return args.Get(0).([]models.WebhookDelivery), args.Int(1), args.Error(2)
+}
+
+func (m *MockWebhookRepository) GetDueDeliveries(now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	args := m.Called(now, limit)
+	return args.Get(0).([]models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) UpdateDelivery(delivery *models.WebhookDelivery) error {
+	args := m.Called(delivery)
+	return args.Error(0)
+}
+
+// testWebhookConfig retries quickly and gives up after three attempts
+var testWebhookConfig = config.WebhookConfig{
+	PollInterval:   time.Second,
+	Timeout:        5 * time.Second,
+	MaxAttempts:    3,
+	RetryBaseDelay: time.Minute,
+}
+
+func TestWebhookService_CreateWebhook(t *testing.T) {
+	mockRepo := new(MockWebhookRepository)
+	mockAudit := new(MockAuditRepository)
+	service := NewWebhookService(mockRepo, mockAudit, testWebhookConfig)
+	admin := &models.User{ID: 1, Username: "admin", Role: models.SuperAdminRole}
+
+	mockRepo.On("Create", mock.AnythingOfType("*models.WebhookSubscription")).Return(nil)
+	mockAudit.On("Create", mock.MatchedBy(func(log *models.AuditLog) bool {
+		return log.Action == models.ActionWebhookCreate && log.Success && !strings.Contains(log.Details, "whsec_")
+	})).Return(nil)
+
+	created, err := service.CreateWebhook(models.CreateWebhookRequest{
+		URL:    "https://payroll.example.com/hooks",
+		Events: []models.HolidayChangeType{models.ChangeHolidayCreated, models.ChangeHolidayCreated},
+	}, admin, "127.0.0.1", "test")
+
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(created.Secret, webhookSecretPrefix))
+	assert.Equal(t, created.Secret, created.WebhookSubscription.Secret)
+	assert.Equal(t, []models.HolidayChangeType{models.ChangeHolidayCreated}, created.Events)
+	assert.True(t, created.IsActive)
+
+	_, err = service.CreateWebhook(models.CreateWebhookRequest{
+		URL:    "ftp://payroll.example.com/hooks",
+		Events: []models.HolidayChangeType{models.ChangeHolidayCreated},
+	}, admin, "127.0.0.1", "test")
+	assert.Error(t, err)
+
+	mockRepo.AssertNumberOfCalls(t, "Create", 1)
+	mockAudit.AssertExpectations(t)
+}
+
+func TestWebhookService_DeliverDue(t *testing.T) {
+	payload := []byte(`{"sequence":9,"type":"holiday.updated","holiday_id":3}`)
+	secret := "payroll-shared-secret"
+
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+
+	tests := []struct {
+		name           string
+		status         int
+		attempts       int
+		expectedStatus models.WebhookDeliveryStatus
+		expectedNext   time.Duration
+		delivered      int
+	}{
+		{name: "receiver accepts", status: http.StatusNoContent, expectedStatus: models.DeliveryDelivered, delivered: 1},
+		{name: "first failure waits the base delay", status: http.StatusServiceUnavailable, expectedStatus: models.DeliveryPending, expectedNext: time.Minute},
+		{name: "later failures back off exponentially", status: http.StatusInternalServerError, attempts: 1, expectedStatus: models.DeliveryPending, expectedNext: 2 * time.Minute},
+		{name: "last attempt gives up", status: http.StatusBadGateway, attempts: 2, expectedStatus: models.DeliveryFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := make(chan received, 1)
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				requests <- received{header: r.Header.Clone(), body: body}
+				w.WriteHeader(tt.status)
+			}))
+			defer receiver.Close()
+
+			mockRepo := new(MockWebhookRepository)
+			service := NewWebhookService(mockRepo, new(MockAuditRepository), testWebhookConfig)
+
+			subscription := &models.WebhookSubscription{ID: 4, URL: receiver.URL, Secret: secret, IsActive: true}
+			delivery := models.WebhookDelivery{
+				ID: 12, SubscriptionID: 4, ChangeSequence: 9, EventType: models.ChangeHolidayUpdated,
+				Payload: payload, Status: models.DeliveryPending, Attempts: tt.attempts,
+			}
+
+			var saved *models.WebhookDelivery
+			mockRepo.On("GetDueDeliveries", mock.AnythingOfType("time.Time"), webhookBatchSize).Return([]models.WebhookDelivery{delivery}, nil)
+			mockRepo.On("GetByID", 4).Return(subscription, nil)
+			mockRepo.On("UpdateDelivery", mock.AnythingOfType("*models.WebhookDelivery")).Run(func(args mock.Arguments) {
+				saved = args.Get(0).(*models.WebhookDelivery)
+			}).Return(nil)
+
+			before := time.Now()
+			delivered, err := service.DeliverDue(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.delivered, delivered)
+
+			got := <-requests
+			assert.Equal(t, payload, got.body)
+			assert.Equal(t, "holiday.updated", got.header.Get(WebhookEventHeader))
+			assert.Equal(t, "12", got.header.Get(WebhookDeliveryHeader))
+			timestamp, err := strconv.ParseInt(got.header.Get(WebhookTimestampHeader), 10, 64)
+			require.NoError(t, err)
+			assert.Equal(t, SignWebhookPayload(secret, timestamp, payload), got.header.Get(WebhookSignatureHeader))
+
+			require.NotNil(t, saved)
+			assert.Equal(t, tt.expectedStatus, saved.Status)
+			assert.Equal(t, tt.attempts+1, saved.Attempts)
+			assert.Equal(t, tt.status, *saved.ResponseStatus)
+			if tt.expectedNext > 0 {
+				require.NotNil(t, saved.NextAttemptAt)
+				assert.WithinDuration(t, before.Add(tt.expectedNext), *saved.NextAttemptAt, 5*time.Second)
+				assert.NotNil(t, saved.LastError)
+			} else {
+				assert.Nil(t, saved.NextAttemptAt)
+			}
+		})
+	}
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	// echo -n '1700000000.{"ok":true}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, "sha256=c1afc7c2df3db0690d7d75954610ed1a1d959ce96355ccb8c0a8bc09fd0cfc27",
+		SignWebhookPayload("secret", 1700000000, []byte(`{"ok":true}`)))
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, webhookRetryDelay(30*time.Second, 1))
+	assert.Equal(t, 4*time.Minute, webhookRetryDelay(30*time.Second, 4))
+	assert.Equal(t, maxWebhookRetryDelay, webhookRetryDelay(30*time.Second, 20))
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+	"github.com/ilramdhan/holidayapi/internal/repository"
+)
+
+const (
+	dateLayout = "2006-01-02"
+
+	// maxWorkdayRangeDays limits the span of a single working-day count (about 10 years)
+	maxWorkdayRangeDays = 3660
+	// maxWorkdayShift limits how many working days a date can be moved at once
+	maxWorkdayShift = 2500
+)
+
+// WorkdayRequestError is returned for working-day calculations refused because of their input,
+// as opposed to failures loading the holidays
+type WorkdayRequestError struct {
+	Reason string
+}
+
+func (e *WorkdayRequestError) Error() string {
+	return e.Reason
+}
+
+// WorkdayService handles business-day calculations on top of the holiday data
+type WorkdayService interface {
+	CountWorkdays(startDate, endDate time.Time, opts models.WorkdayOptions) (*models.WorkdayCountResponse, error)
+	AddWorkdays(date time.Time, days int, opts models.WorkdayOptions) (*models.WorkdayShiftResponse, error)
+	NextWorkday(date time.Time, opts models.WorkdayOptions) (*models.WorkdayShiftResponse, error)
+}
+
+// workdayService implements WorkdayService
+type workdayService struct {
+	repo repository.HolidayRepository
+}
+
+// NewWorkdayService creates a new workday service
+func NewWorkdayService(repo repository.HolidayRepository) WorkdayService {
+	return &workdayService{repo: repo}
+}
+
+// CountWorkdays counts working days between two dates (both inclusive)
+func (s *workdayService) CountWorkdays(startDate, endDate time.Time, opts models.WorkdayOptions) (*models.WorkdayCountResponse, error) {
+	startDate = truncateToDate(startDate)
+	endDate = truncateToDate(endDate)
+
+	if endDate.Before(startDate) {
+		return nil, &WorkdayRequestError{Reason: "end date must not be before start date"}
+	}
+
+	totalDays := int(endDate.Sub(startDate).Hours()/24) + 1
+	if totalDays > maxWorkdayRangeDays {
+		return nil, &WorkdayRequestError{Reason: fmt.Sprintf("date range must not exceed %d days", maxWorkdayRangeDays)}
+	}
+
+	daysOff, err := loadDaysOff(s.repo, startDate, endDate, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.WorkdayCountResponse{
+		StartDate:              startDate.Format(dateLayout),
+		EndDate:                endDate.Format(dateLayout),
+		TotalDays:              totalDays,
+		IncludeCollectiveLeave: opts.IncludeCollectiveLeave,
+		Province:               opts.Province,
+		Holidays:               []models.Holiday{},
+	}
+
+	for day := startDate; !day.After(endDate); day = day.AddDate(0, 0, 1) {
+		if isWeekend(day) {
+			response.WeekendDays++
+			continue
+		}
+		if holidays, ok := daysOff[day.Format(dateLayout)]; ok {
+			response.HolidayDays++
+			response.Holidays = append(response.Holidays, holidays...)
+			continue
+		}
+		response.WorkingDays++
+	}
+
+	return response, nil
+}
+
+// AddWorkdays moves a date forward (or backward for negative values) by working days
+func (s *workdayService) AddWorkdays(date time.Time, days int, opts models.WorkdayOptions) (*models.WorkdayShiftResponse, error) {
+	if days == 0 {
+		return nil, &WorkdayRequestError{Reason: "days must not be zero"}
+	}
+	if days > maxWorkdayShift || days < -maxWorkdayShift {
+		return nil, &WorkdayRequestError{Reason: fmt.Sprintf("days must be between -%d and %d", maxWorkdayShift, maxWorkdayShift)}
+	}
+
+	return s.shift(truncateToDate(date), days, opts)
+}
+
+// NextWorkday finds the first working day strictly after the given date
+func (s *workdayService) NextWorkday(date time.Time, opts models.WorkdayOptions) (*models.WorkdayShiftResponse, error) {
+	return s.shift(truncateToDate(date), 1, opts)
+}
+
+// shift walks working days from date, loading holidays window by window
+func (s *workdayService) shift(date time.Time, days int, opts models.WorkdayOptions) (*models.WorkdayShiftResponse, error) {
+	step := 1
+	remaining := days
+	if days < 0 {
+		step = -1
+		remaining = -days
+	}
+
+	// A window of twice the distance plus two weeks is almost always enough;
+	// if not, the next window is loaded from where the walk stopped.
+	window := remaining*2 + 14
+	skipped := []models.Holiday{}
+	current := date
+
+	for remaining > 0 {
+		from := current.AddDate(0, 0, step)
+		to := current.AddDate(0, 0, step*window)
+		if step < 0 {
+			from, to = to, from
+		}
+
+		daysOff, err := loadDaysOff(s.repo, from, to, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := 0; i < window && remaining > 0; i++ {
+			current = current.AddDate(0, 0, step)
+			if isWeekend(current) {
+				continue
+			}
+			if holidays, ok := daysOff[current.Format(dateLayout)]; ok {
+				skipped = append(skipped, holidays...)
+				continue
+			}
+			remaining--
+		}
+	}
+
+	return &models.WorkdayShiftResponse{
+		Date:                   date.Format(dateLayout),
+		Days:                   days,
+		ResultDate:             current.Format(dateLayout),
+		IncludeCollectiveLeave: opts.IncludeCollectiveLeave,
+		Province:               opts.Province,
+		SkippedHolidays:        skipped,
+	}, nil
+}
+
+// loadDaysOff loads the holidays the options count as days off, keyed by YYYY-MM-DD
+func loadDaysOff(repo repository.HolidayRepository, startDate, endDate time.Time, opts models.WorkdayOptions) (map[string][]models.Holiday, error) {
+	holidays, err := repo.GetByDateRange(startDate, endDate, nil, models.HolidayScope{Province: opts.Province, AsOf: opts.AsOf, Statuses: opts.Statuses})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holidays: %w", err)
+	}
+
+	daysOff := make(map[string][]models.Holiday)
+	for _, holiday := range holidays {
+		if holiday.Type == models.CollectiveLeave && !opts.IncludeCollectiveLeave {
+			continue
+		}
+		key := holiday.Date.Format(dateLayout)
+		daysOff[key] = append(daysOff[key], holiday)
+	}
+
+	return daysOff, nil
+}
+
+// isWeekend reports whether the date falls on Saturday or Sunday
+func isWeekend(date time.Time) bool {
+	weekday := date.Weekday()
+	return weekday == time.Saturday || weekday == time.Sunday
+}
+
+// truncateToDate strips the time of day, keeping the calendar date
+func truncateToDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
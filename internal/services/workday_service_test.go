@@ -0,0 +1,147 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ilramdhan/holidayapi/internal/models"
+)
+
+func TestWorkdayService_CountWorkdays(t *testing.T) {
+	// April 2024: Idul Fitri on 10-11, cuti bersama on 8, 9, 12 and 15
+	holidays := []models.Holiday{
+		{ID: 1, Name: "Cuti Bersama Idul Fitri", Date: time.Date(2024, 4, 8, 0, 0, 0, 0, time.UTC), Type: models.CollectiveLeave},
+		{ID: 2, Name: "Cuti Bersama Idul Fitri", Date: time.Date(2024, 4, 9, 0, 0, 0, 0, time.UTC), Type: models.CollectiveLeave},
+		{ID: 3, Name: "Hari Raya Idul Fitri", Date: time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC), Type: models.NationalHoliday},
+		{ID: 4, Name: "Hari Raya Idul Fitri (Hari Kedua)", Date: time.Date(2024, 4, 11, 0, 0, 0, 0, time.UTC), Type: models.NationalHoliday},
+		{ID: 5, Name: "Cuti Bersama Idul Fitri", Date: time.Date(2024, 4, 12, 0, 0, 0, 0, time.UTC), Type: models.CollectiveLeave},
+		{ID: 6, Name: "Cuti Bersama Idul Fitri", Date: time.Date(2024, 4, 15, 0, 0, 0, 0, time.UTC), Type: models.CollectiveLeave},
+	}
+
+	tests := []struct {
+		name            string
+		opts            models.WorkdayOptions
+		expectedWorking int
+		expectedHoliday int
+	}{
+		{
+			name:            "collective leave counted as day off",
+			opts:            models.WorkdayOptions{IncludeCollectiveLeave: true},
+			expectedWorking: 16,
+			expectedHoliday: 6,
+		},
+		{
+			name:            "collective leave counted as working day",
+			opts:            models.WorkdayOptions{IncludeCollectiveLeave: false},
+			expectedWorking: 20,
+			expectedHoliday: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockHolidayRepository)
+			service := NewWorkdayService(mockRepo)
+
+			start := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+			end := time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC)
+			mockRepo.On("GetByDateRange", start, end, (*models.HolidayType)(nil), models.HolidayScope{}).Return(holidays, nil)
+
+			result, err := service.CountWorkdays(start, end, tt.opts)
+
+			assert.NoError(t, err)
+			assert.Equal(t, 30, result.TotalDays)
+			assert.Equal(t, 8, result.WeekendDays)
+			assert.Equal(t, tt.expectedHoliday, result.HolidayDays)
+			assert.Equal(t, tt.expectedWorking, result.WorkingDays)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWorkdayService_CountWorkdaysInvalidRange(t *testing.T) {
+	mockRepo := new(MockHolidayRepository)
+	service := NewWorkdayService(mockRepo)
+
+	start := time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	result, err := service.CountWorkdays(start, end, models.WorkdayOptions{})
+
+	var invalid *WorkdayRequestError
+	assert.ErrorAs(t, err, &invalid)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "GetByDateRange", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWorkdayService_AddWorkdays(t *testing.T) {
+	christmasEve := models.Holiday{ID: 1, Name: "Cuti Bersama Natal", Date: time.Date(2024, 12, 24, 0, 0, 0, 0, time.UTC), Type: models.CollectiveLeave}
+	christmas := models.Holiday{ID: 2, Name: "Hari Raya Natal", Date: time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC), Type: models.NationalHoliday}
+	boxingDay := models.Holiday{ID: 3, Name: "Cuti Bersama Natal", Date: time.Date(2024, 12, 26, 0, 0, 0, 0, time.UTC), Type: models.CollectiveLeave}
+	newYear := models.Holiday{ID: 4, Name: "Tahun Baru Masehi", Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Type: models.NationalHoliday}
+
+	tests := []struct {
+		name     string
+		date     time.Time
+		days     int
+		opts     models.WorkdayOptions
+		expected string
+	}{
+		{
+			name:     "forward over christmas with collective leave",
+			date:     time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC),
+			days:     3,
+			opts:     models.WorkdayOptions{IncludeCollectiveLeave: true},
+			expected: "2024-12-30",
+		},
+		{
+			name:     "forward over christmas without collective leave",
+			date:     time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC),
+			days:     3,
+			opts:     models.WorkdayOptions{IncludeCollectiveLeave: false},
+			expected: "2024-12-26",
+		},
+		{
+			name:     "backward over new year",
+			date:     time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+			days:     -2,
+			opts:     models.WorkdayOptions{IncludeCollectiveLeave: true},
+			expected: "2024-12-30",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockHolidayRepository)
+			service := NewWorkdayService(mockRepo)
+
+			mockRepo.On("GetByDateRange", mock.Anything, mock.Anything, (*models.HolidayType)(nil), models.HolidayScope{}).
+				Return([]models.Holiday{christmasEve, christmas, boxingDay, newYear}, nil)
+
+			result, err := service.AddWorkdays(tt.date, tt.days, tt.opts)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result.ResultDate)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWorkdayService_NextWorkday(t *testing.T) {
+	mockRepo := new(MockHolidayRepository)
+	service := NewWorkdayService(mockRepo)
+
+	// Friday before a Monday holiday
+	holiday := models.Holiday{ID: 1, Name: "Hari Raya Idul Adha", Date: time.Date(2024, 6, 17, 0, 0, 0, 0, time.UTC), Type: models.NationalHoliday}
+	mockRepo.On("GetByDateRange", mock.Anything, mock.Anything, (*models.HolidayType)(nil), models.HolidayScope{}).Return([]models.Holiday{holiday}, nil)
+
+	result, err := service.NextWorkday(time.Date(2024, 6, 14, 0, 0, 0, 0, time.UTC), models.WorkdayOptions{IncludeCollectiveLeave: true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-06-18", result.ResultDate)
+	assert.Len(t, result.SkippedHolidays, 1)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,85 @@
+package totp
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA-1 seed of the RFC 6238 Appendix B test vectors
+var rfcSecret = encoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestCode_RFC6238Vectors(t *testing.T) {
+	key, err := decodeSecret(rfcSecret)
+	require.NoError(t, err)
+
+	vectors := []struct {
+		unix int64
+		want string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+
+	for _, v := range vectors {
+		step := Step(time.Unix(v.unix, 0))
+		assert.Equal(t, v.want, code(key, uint64(step), 8), "time %d", v.unix)
+	}
+
+	// Six-digit codes are the last six digits of the eight-digit ones
+	got, err := Code(rfcSecret, time.Unix(59, 0))
+	require.NoError(t, err)
+	assert.Equal(t, "287082", got)
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+	now := time.Unix(1700000000, 0)
+
+	current, err := Code(secret, now)
+	require.NoError(t, err)
+	step, ok := Validate(secret, current, now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, Step(now), step)
+
+	// The previous code is accepted within the skew window, older ones are not
+	previous, err := Code(secret, now.Add(-Period))
+	require.NoError(t, err)
+	step, ok = Validate(secret, previous, now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, Step(now)-1, step)
+
+	stale, err := Code(secret, now.Add(-2*Period))
+	require.NoError(t, err)
+	if stale != current && stale != previous {
+		_, ok = Validate(secret, stale, now, 1)
+		assert.False(t, ok)
+	}
+
+	_, ok = Validate(secret, "12345", now, 1)
+	assert.False(t, ok)
+	_, ok = Validate("not base32!", current, now, 1)
+	assert.False(t, ok)
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("Holiday API", "admin", "JBSWY3DPEHPK3PXP")
+
+	parsed, err := url.Parse(uri)
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", parsed.Scheme)
+	assert.Equal(t, "totp", parsed.Host)
+	assert.Equal(t, "/Holiday API:admin", parsed.Path)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", parsed.Query().Get("secret"))
+	assert.Equal(t, "Holiday API", parsed.Query().Get("issuer"))
+	assert.Equal(t, "6", parsed.Query().Get("digits"))
+	assert.Equal(t, "30", parsed.Query().Get("period"))
+}
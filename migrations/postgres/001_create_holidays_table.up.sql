@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS holidays (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    date DATE NOT NULL,
+    type VARCHAR(50) NOT NULL CONSTRAINT holidays_type_check CHECK (type IN ('national', 'collective_leave')),
+    description TEXT,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes for better query performance
+CREATE INDEX idx_holidays_date ON holidays(date);
+CREATE INDEX idx_holidays_type ON holidays(type);
+CREATE INDEX idx_holidays_is_active ON holidays(is_active);
+CREATE INDEX idx_holidays_date_type ON holidays(date, type);
+
+-- Shared trigger function to update updated_at timestamps
+CREATE OR REPLACE FUNCTION set_updated_at() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Create trigger to update updated_at timestamp
+CREATE TRIGGER update_holidays_updated_at
+    BEFORE UPDATE ON holidays
+    FOR EACH ROW
+    EXECUTE FUNCTION set_updated_at();
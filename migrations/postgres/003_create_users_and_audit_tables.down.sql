@@ -0,0 +1,6 @@
+-- Drop trigger
+DROP TRIGGER IF EXISTS update_users_updated_at ON users;
+
+-- Drop tables (their indexes go with them)
+DROP TABLE IF EXISTS audit_logs;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,9 @@
+-- Remove regional holidays and the province column
+DELETE FROM holidays WHERE province IS NOT NULL OR type = 'regional';
+
+DROP INDEX IF EXISTS idx_holidays_province;
+ALTER TABLE holidays DROP COLUMN province;
+
+ALTER TABLE holidays DROP CONSTRAINT holidays_type_check;
+ALTER TABLE holidays ADD CONSTRAINT holidays_type_check
+    CHECK (type IN ('national', 'collective_leave'));
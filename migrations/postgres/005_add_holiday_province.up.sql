@@ -0,0 +1,8 @@
+-- Add regional holidays: a province column and the 'regional' holiday type
+ALTER TABLE holidays ADD COLUMN province VARCHAR(10); -- ISO 3166-2:ID code, NULL for nationwide holidays
+
+ALTER TABLE holidays DROP CONSTRAINT holidays_type_check;
+ALTER TABLE holidays ADD CONSTRAINT holidays_type_check
+    CHECK (type IN ('national', 'collective_leave', 'regional'));
+
+CREATE INDEX idx_holidays_province ON holidays(province);
@@ -0,0 +1,9 @@
+-- Remove the decree status of holidays
+ALTER TABLE holiday_revisions DROP COLUMN decree_date;
+ALTER TABLE holiday_revisions DROP COLUMN decree_number;
+ALTER TABLE holiday_revisions DROP COLUMN status;
+
+DROP INDEX IF EXISTS idx_holidays_status;
+ALTER TABLE holidays DROP COLUMN decree_date;
+ALTER TABLE holidays DROP COLUMN decree_number;
+ALTER TABLE holidays DROP COLUMN status;
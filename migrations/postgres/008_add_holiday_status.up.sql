@@ -0,0 +1,13 @@
+-- Add the decree status of holidays and the legal basis of the current status.
+-- Existing holidays were entered from published decrees, so they start as official.
+ALTER TABLE holidays ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'official'
+    CONSTRAINT holidays_status_check CHECK (status IN ('provisional', 'official', 'revised', 'cancelled'));
+ALTER TABLE holidays ADD COLUMN decree_number VARCHAR(100); -- e.g. the SKB number
+ALTER TABLE holidays ADD COLUMN decree_date DATE;
+
+CREATE INDEX idx_holidays_status ON holidays(status);
+
+-- Revisions snapshot the status too, so history and as_of queries include it
+ALTER TABLE holiday_revisions ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'official';
+ALTER TABLE holiday_revisions ADD COLUMN decree_number VARCHAR(100);
+ALTER TABLE holiday_revisions ADD COLUMN decree_date DATE;
@@ -0,0 +1,14 @@
+-- Create holiday_changes table
+-- Append-only change feed: every create, update and delete made through the API
+-- is stored with the holiday as JSON. The id is the change sequence that stream
+-- clients resume from with Last-Event-ID.
+CREATE TABLE IF NOT EXISTS holiday_changes (
+    id SERIAL PRIMARY KEY,
+    event_type VARCHAR(30) NOT NULL CHECK (event_type IN ('holiday.created', 'holiday.updated', 'holiday.deleted')),
+    holiday_id INTEGER NOT NULL REFERENCES holidays(id) ON DELETE CASCADE,
+    payload TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes for holiday_changes table
+CREATE INDEX idx_holiday_changes_created_at ON holiday_changes(created_at);
@@ -0,0 +1,6 @@
+-- Drop trigger
+DROP TRIGGER IF EXISTS update_webhook_subscriptions_updated_at ON webhook_subscriptions;
+
+-- Drop tables (their indexes go with them)
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
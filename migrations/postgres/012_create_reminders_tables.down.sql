@@ -0,0 +1,7 @@
+-- Drop triggers
+DROP TRIGGER IF EXISTS update_reminder_rules_updated_at ON reminder_rules;
+DROP TRIGGER IF EXISTS update_distribution_lists_updated_at ON distribution_lists;
+
+-- Drop tables
+DROP TABLE IF EXISTS reminder_rules;
+DROP TABLE IF EXISTS distribution_lists;
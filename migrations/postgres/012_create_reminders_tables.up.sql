@@ -0,0 +1,42 @@
+-- Create distribution_lists table
+-- Members are stored as a comma-separated list of email addresses.
+CREATE TABLE IF NOT EXISTS distribution_lists (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) UNIQUE NOT NULL,
+    members TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create trigger to update distribution_lists updated_at timestamp
+CREATE TRIGGER update_distribution_lists_updated_at
+    BEFORE UPDATE ON distribution_lists
+    FOR EACH ROW
+    EXECUTE FUNCTION set_updated_at();
+
+-- Create reminder_rules table
+-- A rule emails its recipients (email addresses or distribution list names, comma-separated)
+-- about upcoming holidays: days_before rules for holidays exactly that many days ahead,
+-- next_week rules on send_weekday (0 = Sunday) for holidays in the following Monday-Sunday.
+-- last_run_on is the local date the rule was last handled, so each rule runs at most once a day.
+CREATE TABLE IF NOT EXISTS reminder_rules (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(150) NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('days_before', 'next_week')),
+    days_before INTEGER NOT NULL DEFAULT 0,
+    send_weekday INTEGER NOT NULL DEFAULT 5,
+    holiday_types VARCHAR(100) NOT NULL DEFAULT '',
+    province VARCHAR(10),
+    recipients TEXT NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    last_run_on DATE,
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create trigger to update reminder_rules updated_at timestamp
+CREATE TRIGGER update_reminder_rules_updated_at
+    BEFORE UPDATE ON reminder_rules
+    FOR EACH ROW
+    EXECUTE FUNCTION set_updated_at();
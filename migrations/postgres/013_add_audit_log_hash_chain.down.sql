@@ -0,0 +1,3 @@
+-- Remove the audit log hash chain
+ALTER TABLE audit_logs DROP COLUMN hash;
+ALTER TABLE audit_logs DROP COLUMN prev_hash;
@@ -0,0 +1,5 @@
+-- Chain audit log entries: each entry stores the hash of its predecessor and a
+-- SHA-256 hash over its own canonical fields, so rewriting or removing an entry
+-- breaks every later link. Existing entries stay unchained (NULL hashes).
+ALTER TABLE audit_logs ADD COLUMN prev_hash VARCHAR(64);
+ALTER TABLE audit_logs ADD COLUMN hash VARCHAR(64);
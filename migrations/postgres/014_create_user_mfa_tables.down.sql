@@ -0,0 +1,3 @@
+-- Drop tables (their indexes go with them)
+DROP TABLE IF EXISTS mfa_recovery_codes;
+DROP TABLE IF EXISTS user_mfa;
@@ -0,0 +1,25 @@
+-- Create user_mfa table
+-- One TOTP enrollment per user. The secret is kept as given because every code is
+-- verified against it; enabled stays FALSE until the user confirms a first code.
+-- last_used_step is the newest accepted time step, so a code cannot be replayed.
+CREATE TABLE IF NOT EXISTS user_mfa (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret VARCHAR(64) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    enabled_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create mfa_recovery_codes table
+-- Only a SHA-256 hash of each single-use recovery code is stored.
+CREATE TABLE IF NOT EXISTS mfa_recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes for mfa_recovery_codes table
+CREATE INDEX idx_mfa_recovery_codes_user_id ON mfa_recovery_codes(user_id);
@@ -0,0 +1,6 @@
+-- Restore the two hard-coded roles; users of custom roles become admins
+UPDATE users SET role = 'admin' WHERE role NOT IN ('super_admin', 'admin');
+ALTER TABLE users ALTER COLUMN role TYPE VARCHAR(20);
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('super_admin', 'admin'));
+
+DROP TABLE IF EXISTS roles;
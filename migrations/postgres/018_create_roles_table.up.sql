@@ -0,0 +1,22 @@
+-- Create roles table
+-- A role grants a set of permissions, stored as a comma-separated list. The built-in
+-- super_admin and admin roles are system roles and cannot be deleted; super_admin
+-- always holds every permission.
+CREATE TABLE IF NOT EXISTS roles (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(50) UNIQUE NOT NULL,
+    description TEXT,
+    permissions TEXT NOT NULL,
+    is_system BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Insert the built-in roles with the access they had before roles were configurable
+INSERT INTO roles (name, description, permissions, is_system) VALUES
+('super_admin', 'Full access, including users, roles and system settings', 'holiday:read,holiday:create,holiday:update,holiday:delete,audit:read,audit:verify,user:read,user:manage,role:manage,apikey:manage,webhook:manage,reminder:manage,job:manage', TRUE),
+('admin', 'Manages holidays and reads audit logs', 'holiday:read,holiday:create,holiday:update,holiday:delete,audit:read,user:read', TRUE);
+
+-- Users may hold any role from the roles table
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ALTER COLUMN role TYPE VARCHAR(50);
@@ -0,0 +1,16 @@
+-- Create audit_chain_checkpoints table
+-- Audit retention deletes the oldest entries of the hash chain. Each run that removes
+-- chained entries records the id and hash of the last one removed, so verification
+-- can check that the oldest remaining entry links to it. The checkpoint is itself
+-- appended to the chain: it links to the chain head at the time of the run, and the
+-- next entry links to its hash, so a forged checkpoint breaks the chain.
+CREATE TABLE IF NOT EXISTS audit_chain_checkpoints (
+    id SERIAL PRIMARY KEY,
+    last_deleted_id INTEGER NOT NULL,
+    last_deleted_hash VARCHAR(64) NOT NULL,
+    prev_hash VARCHAR(64) NOT NULL,
+    hash VARCHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_audit_chain_checkpoints_prev_hash ON audit_chain_checkpoints(prev_hash);
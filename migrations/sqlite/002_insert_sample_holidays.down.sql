@@ -0,0 +1 @@
+DELETE FROM holidays WHERE date BETWEEN '2024-01-01' AND '2025-12-31';
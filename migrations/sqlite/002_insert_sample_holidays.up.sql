@@ -0,0 +1,44 @@
+-- Sample holidays for 2024 based on SKB 3 Menteri
+-- Libur Nasional (National Holidays)
+INSERT INTO holidays (name, date, type, description, is_active) VALUES
+('Tahun Baru Masehi', '2024-01-01', 'national', 'Hari libur nasional Tahun Baru Masehi', TRUE),
+('Isra Mikraj Nabi Muhammad SAW', '2024-02-08', 'national', 'Hari libur nasional Isra Mikraj', TRUE),
+('Tahun Baru Imlek', '2024-02-10', 'national', 'Hari libur nasional Tahun Baru Imlek 2575 Kongzili', TRUE),
+('Hari Raya Nyepi (Tahun Baru Saka)', '2024-03-11', 'national', 'Hari libur nasional Hari Raya Nyepi Tahun Baru Saka 1946', TRUE),
+('Wafat Isa Almasih', '2024-03-29', 'national', 'Hari libur nasional Wafat Isa Almasih', TRUE),
+('Hari Raya Idul Fitri', '2024-04-10', 'national', 'Hari libur nasional Hari Raya Idul Fitri 1445 Hijriah', TRUE),
+('Hari Raya Idul Fitri (Hari Kedua)', '2024-04-11', 'national', 'Hari libur nasional Hari Raya Idul Fitri 1445 Hijriah (Hari Kedua)', TRUE),
+('Hari Buruh Internasional', '2024-05-01', 'national', 'Hari libur nasional Hari Buruh Internasional', TRUE),
+('Kenaikan Isa Almasih', '2024-05-09', 'national', 'Hari libur nasional Kenaikan Isa Almasih', TRUE),
+('Hari Lahir Pancasila', '2024-06-01', 'national', 'Hari libur nasional Hari Lahir Pancasila', TRUE),
+('Hari Raya Waisak', '2024-05-23', 'national', 'Hari libur nasional Hari Raya Waisak 2568', TRUE),
+('Hari Raya Idul Adha', '2024-06-17', 'national', 'Hari libur nasional Hari Raya Idul Adha 1445 Hijriah', TRUE),
+('Tahun Baru Islam', '2024-07-07', 'national', 'Hari libur nasional Tahun Baru Islam 1446 Hijriah', TRUE),
+('Hari Kemerdekaan Republik Indonesia', '2024-08-17', 'national', 'Hari libur nasional HUT ke-79 Kemerdekaan RI', TRUE),
+('Maulid Nabi Muhammad SAW', '2024-09-16', 'national', 'Hari libur nasional Maulid Nabi Muhammad SAW', TRUE),
+('Hari Raya Natal', '2024-12-25', 'national', 'Hari libur nasional Hari Raya Natal', TRUE);
+
+-- Cuti Bersama (Collective Leave)
+INSERT INTO holidays (name, date, type, description, is_active) VALUES
+('Cuti Bersama Tahun Baru Imlek', '2024-02-09', 'collective_leave', 'Cuti bersama Tahun Baru Imlek', TRUE),
+('Cuti Bersama Hari Raya Nyepi', '2024-03-12', 'collective_leave', 'Cuti bersama Hari Raya Nyepi', TRUE),
+('Cuti Bersama Idul Fitri', '2024-04-08', 'collective_leave', 'Cuti bersama Idul Fitri (H-2)', TRUE),
+('Cuti Bersama Idul Fitri', '2024-04-09', 'collective_leave', 'Cuti bersama Idul Fitri (H-1)', TRUE),
+('Cuti Bersama Idul Fitri', '2024-04-12', 'collective_leave', 'Cuti bersama Idul Fitri (H+1)', TRUE),
+('Cuti Bersama Idul Fitri', '2024-04-15', 'collective_leave', 'Cuti bersama Idul Fitri (H+4)', TRUE),
+('Cuti Bersama Kenaikan Isa Almasih', '2024-05-10', 'collective_leave', 'Cuti bersama Kenaikan Isa Almasih', TRUE),
+('Cuti Bersama Hari Raya Waisak', '2024-05-24', 'collective_leave', 'Cuti bersama Hari Raya Waisak', TRUE),
+('Cuti Bersama Natal', '2024-12-24', 'collective_leave', 'Cuti bersama Hari Raya Natal', TRUE),
+('Cuti Bersama Natal', '2024-12-26', 'collective_leave', 'Cuti bersama Hari Raya Natal', TRUE);
+
+-- Sample holidays for 2025 (partial)
+INSERT INTO holidays (name, date, type, description, is_active) VALUES
+('Tahun Baru Masehi', '2025-01-01', 'national', 'Hari libur nasional Tahun Baru Masehi', TRUE),
+('Tahun Baru Imlek', '2025-01-29', 'national', 'Hari libur nasional Tahun Baru Imlek 2576 Kongzili', TRUE),
+('Hari Raya Nyepi (Tahun Baru Saka)', '2025-03-29', 'national', 'Hari libur nasional Hari Raya Nyepi Tahun Baru Saka 1947', TRUE),
+('Hari Raya Idul Fitri', '2025-03-31', 'national', 'Hari libur nasional Hari Raya Idul Fitri 1446 Hijriah', TRUE),
+('Hari Raya Idul Fitri (Hari Kedua)', '2025-04-01', 'national', 'Hari libur nasional Hari Raya Idul Fitri 1446 Hijriah (Hari Kedua)', TRUE),
+('Hari Buruh Internasional', '2025-05-01', 'national', 'Hari libur nasional Hari Buruh Internasional', TRUE),
+('Hari Lahir Pancasila', '2025-06-01', 'national', 'Hari libur nasional Hari Lahir Pancasila', TRUE),
+('Hari Kemerdekaan Republik Indonesia', '2025-08-17', 'national', 'Hari libur nasional HUT ke-80 Kemerdekaan RI', TRUE),
+('Hari Raya Natal', '2025-12-25', 'national', 'Hari libur nasional Hari Raya Natal', TRUE);
@@ -0,0 +1,7 @@
+-- Drop indexes for refresh_tokens
+DROP INDEX IF EXISTS idx_refresh_tokens_expires_at;
+DROP INDEX IF EXISTS idx_refresh_tokens_family_id;
+DROP INDEX IF EXISTS idx_refresh_tokens_user_id;
+
+-- Drop table
+DROP TABLE IF EXISTS refresh_tokens;
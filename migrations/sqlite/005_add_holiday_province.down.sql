@@ -0,0 +1,31 @@
+-- Remove regional holidays and the province column
+CREATE TABLE holidays_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL,
+    date DATE NOT NULL,
+    type VARCHAR(50) NOT NULL CHECK (type IN ('national', 'collective_leave')),
+    description TEXT,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO holidays_old (id, name, date, type, description, is_active, created_at, updated_at)
+SELECT id, name, date, type, description, is_active, created_at, updated_at
+FROM holidays
+WHERE province IS NULL AND type != 'regional';
+
+DROP TABLE holidays;
+ALTER TABLE holidays_old RENAME TO holidays;
+
+CREATE INDEX idx_holidays_date ON holidays(date);
+CREATE INDEX idx_holidays_type ON holidays(type);
+CREATE INDEX idx_holidays_is_active ON holidays(is_active);
+CREATE INDEX idx_holidays_date_type ON holidays(date, type);
+
+CREATE TRIGGER update_holidays_updated_at 
+    AFTER UPDATE ON holidays
+    FOR EACH ROW
+BEGIN
+    UPDATE holidays SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
@@ -0,0 +1,35 @@
+-- Add regional holidays: a province column and the 'regional' holiday type.
+-- SQLite cannot change a CHECK constraint in place, so the table is rebuilt.
+CREATE TABLE holidays_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL,
+    date DATE NOT NULL,
+    type VARCHAR(50) NOT NULL CHECK (type IN ('national', 'collective_leave', 'regional')),
+    province VARCHAR(10), -- ISO 3166-2:ID code, NULL for nationwide holidays
+    description TEXT,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Normalize dates written with a time part to YYYY-MM-DD on the way
+INSERT INTO holidays_new (id, name, date, type, province, description, is_active, created_at, updated_at)
+SELECT id, name, substr(date, 1, 10), type, NULL, description, is_active, created_at, updated_at FROM holidays;
+
+DROP TABLE holidays;
+ALTER TABLE holidays_new RENAME TO holidays;
+
+-- Recreate indexes
+CREATE INDEX idx_holidays_date ON holidays(date);
+CREATE INDEX idx_holidays_type ON holidays(type);
+CREATE INDEX idx_holidays_is_active ON holidays(is_active);
+CREATE INDEX idx_holidays_date_type ON holidays(date, type);
+CREATE INDEX idx_holidays_province ON holidays(province);
+
+-- Recreate trigger to update updated_at timestamp
+CREATE TRIGGER update_holidays_updated_at 
+    AFTER UPDATE ON holidays
+    FOR EACH ROW
+BEGIN
+    UPDATE holidays SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
@@ -0,0 +1,6 @@
+-- Drop indexes for api_keys
+DROP INDEX IF EXISTS idx_api_keys_revoked_at;
+DROP INDEX IF EXISTS idx_api_keys_user_id;
+
+-- Drop table
+DROP TABLE IF EXISTS api_keys;
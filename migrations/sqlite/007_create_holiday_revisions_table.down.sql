@@ -0,0 +1,5 @@
+-- Drop indexes for holiday_revisions
+DROP INDEX IF EXISTS idx_holiday_revisions_created_at;
+
+-- Drop table
+DROP TABLE IF EXISTS holiday_revisions;
@@ -0,0 +1,27 @@
+-- Create holiday_revisions table
+-- Append-only: every create, update, soft delete and rollback of a holiday stores
+-- a full snapshot of the row, numbered per holiday. Point-in-time queries read the
+-- latest revision of each holiday created at or before the requested time.
+CREATE TABLE IF NOT EXISTS holiday_revisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    holiday_id INTEGER NOT NULL,
+    revision INTEGER NOT NULL,
+    operation VARCHAR(20) NOT NULL CHECK (operation IN ('create', 'update', 'delete', 'rollback')),
+    source_revision INTEGER, -- the revision restored by a rollback
+    name VARCHAR(255) NOT NULL,
+    date DATE NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    province VARCHAR(10),
+    description TEXT,
+    is_active BOOLEAN NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (holiday_id, revision),
+    FOREIGN KEY (holiday_id) REFERENCES holidays(id) ON DELETE CASCADE
+);
+
+-- Create indexes for holiday_revisions table
+CREATE INDEX idx_holiday_revisions_created_at ON holiday_revisions(created_at);
+
+-- Start the history of existing holidays with their current state
+INSERT INTO holiday_revisions (holiday_id, revision, operation, name, date, type, province, description, is_active, created_at)
+SELECT id, 1, 'create', name, date, type, province, description, COALESCE(is_active, TRUE), created_at FROM holidays;
@@ -0,0 +1,2 @@
+-- Remove the unique holiday identity index
+DROP INDEX IF EXISTS idx_holidays_identity;
@@ -0,0 +1,6 @@
+-- Several holidays may share a date (e.g. a religious holiday on Independence Day).
+-- A holiday is identified by its name, date, type and region instead; deleted holidays
+-- are left out so they can be recreated.
+CREATE UNIQUE INDEX idx_holidays_identity
+    ON holidays(name, date, type, COALESCE(province, ''))
+    WHERE is_active = TRUE;
@@ -0,0 +1,5 @@
+-- Drop indexes for holiday_changes
+DROP INDEX IF EXISTS idx_holiday_changes_created_at;
+
+-- Drop table
+DROP TABLE IF EXISTS holiday_changes;
@@ -0,0 +1,15 @@
+-- Create holiday_changes table
+-- Append-only change feed: every create, update and delete made through the API
+-- is stored with the holiday as JSON. The id is the change sequence that stream
+-- clients resume from with Last-Event-ID.
+CREATE TABLE IF NOT EXISTS holiday_changes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    event_type VARCHAR(30) NOT NULL CHECK (event_type IN ('holiday.created', 'holiday.updated', 'holiday.deleted')),
+    holiday_id INTEGER NOT NULL,
+    payload TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (holiday_id) REFERENCES holidays(id) ON DELETE CASCADE
+);
+
+-- Create indexes for holiday_changes table
+CREATE INDEX idx_holiday_changes_created_at ON holiday_changes(created_at);
@@ -0,0 +1,3 @@
+-- Drop tables (their indexes go with them)
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
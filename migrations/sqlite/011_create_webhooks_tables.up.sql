@@ -0,0 +1,40 @@
+-- Create webhook_subscriptions table
+-- Events are stored as a comma-separated list of holiday change types. The secret
+-- is kept as given because every delivery is signed with it (HMAC-SHA256).
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    url TEXT NOT NULL,
+    events TEXT NOT NULL,
+    secret VARCHAR(128) NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by INTEGER,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Create webhook_deliveries table
+-- Outbox and delivery history: a pending row is queued for each matching subscription
+-- in the same transaction as the holiday change, then retried with exponential backoff
+-- until it is delivered or runs out of attempts.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    subscription_id INTEGER NOT NULL,
+    change_sequence INTEGER NOT NULL,
+    event_type VARCHAR(30) NOT NULL,
+    payload TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at DATETIME,
+    last_attempt_at DATETIME,
+    response_status INTEGER,
+    last_error TEXT,
+    delivered_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (subscription_id) REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    FOREIGN KEY (change_sequence) REFERENCES holiday_changes(id) ON DELETE CASCADE
+);
+
+-- Create indexes for webhook_deliveries table
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(status, next_attempt_at);
+CREATE INDEX idx_webhook_deliveries_subscription_id ON webhook_deliveries(subscription_id);
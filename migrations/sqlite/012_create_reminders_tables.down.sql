@@ -0,0 +1,3 @@
+-- Drop tables
+DROP TABLE IF EXISTS reminder_rules;
+DROP TABLE IF EXISTS distribution_lists;
@@ -0,0 +1,6 @@
+-- Drop indexes for mfa_recovery_codes
+DROP INDEX IF EXISTS idx_mfa_recovery_codes_user_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS mfa_recovery_codes;
+DROP TABLE IF EXISTS user_mfa;
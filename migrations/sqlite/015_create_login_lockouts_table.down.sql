@@ -0,0 +1,6 @@
+-- Drop indexes for login_lockouts
+DROP INDEX IF EXISTS idx_login_lockouts_last_failed_at;
+DROP INDEX IF EXISTS idx_login_lockouts_locked_until;
+
+-- Drop table
+DROP TABLE IF EXISTS login_lockouts;
@@ -0,0 +1,20 @@
+-- Create login_lockouts table
+-- Failed logins are counted per username and per client IP. The key of a username
+-- entry is the username as typed (lowercased), whether or not such a user exists.
+-- Reaching the threshold locks the key until locked_until and resets the count;
+-- lockouts counts the locks so far, each one twice as long as the one before.
+CREATE TABLE IF NOT EXISTS login_lockouts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    scope VARCHAR(10) NOT NULL CHECK (scope IN ('username', 'ip')),
+    lock_key VARCHAR(255) NOT NULL,
+    failed_attempts INTEGER NOT NULL DEFAULT 0,
+    lockouts INTEGER NOT NULL DEFAULT 0,
+    locked_until DATETIME,
+    last_failed_at DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (scope, lock_key)
+);
+
+-- Create indexes for login_lockouts table
+CREATE INDEX idx_login_lockouts_locked_until ON login_lockouts(locked_until);
+CREATE INDEX idx_login_lockouts_last_failed_at ON login_lockouts(last_failed_at);
@@ -0,0 +1,6 @@
+-- Drop indexes for password_reset_tokens
+DROP INDEX IF EXISTS idx_password_reset_tokens_expires_at;
+DROP INDEX IF EXISTS idx_password_reset_tokens_user_id;
+
+-- Drop table
+DROP TABLE IF EXISTS password_reset_tokens;
@@ -0,0 +1,2 @@
+-- Remove the forced password change flag
+ALTER TABLE users DROP COLUMN must_change_password;
@@ -0,0 +1,3 @@
+-- Flag users who must choose a new password before they can use the API again,
+-- e.g. after a super admin set a temporary password for them.
+ALTER TABLE users ADD COLUMN must_change_password BOOLEAN NOT NULL DEFAULT FALSE;
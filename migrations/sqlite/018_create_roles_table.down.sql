@@ -0,0 +1,9 @@
+-- Restore the two hard-coded roles; users of custom roles become admins
+DROP INDEX IF EXISTS idx_users_role;
+ALTER TABLE users ADD COLUMN role_name VARCHAR(20) NOT NULL DEFAULT 'admin' CHECK (role_name IN ('super_admin', 'admin'));
+UPDATE users SET role_name = CASE WHEN role = 'super_admin' THEN 'super_admin' ELSE 'admin' END;
+ALTER TABLE users DROP COLUMN role;
+ALTER TABLE users RENAME COLUMN role_name TO role;
+CREATE INDEX idx_users_role ON users(role);
+
+DROP TABLE IF EXISTS roles;
@@ -31,7 +31,7 @@ func WithHTTPClient(httpClient *http.Client) Option {
 	}
 }
 
-// WithAPIKey sets the API key for authentication
+// WithAPIKey sets a client API key issued by a super admin, sent as X-API-Key
 func WithAPIKey(apiKey string) Option {
 	return func(c *Client) {
 		c.apiKey = apiKey
@@ -56,13 +56,16 @@ func New(baseURL string, opts ...Option) *Client {
 
 // Holiday represents an Indonesian holiday
 type Holiday struct {
-	ID          int       `json:"id"`
-	Date        string    `json:"date"`
-	Name        string    `json:"name"`
-	Type        string    `json:"type"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID           int       `json:"id"`
+	Date         string    `json:"date"`
+	Name         string    `json:"name"`
+	Type         string    `json:"type"`
+	Province     string    `json:"province,omitempty"` // ISO 3166-2:ID code, set for regional holidays
+	Description  string    `json:"description"`
+	Status       string    `json:"status"`                  // provisional, official, revised or cancelled
+	DecreeNumber string    `json:"decree_number,omitempty"` // Decree behind the current status
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
 }
 
 // APIResponse represents the standard API response wrapper
@@ -139,16 +142,10 @@ func (c *Client) GetHolidaysByMonth(ctx context.Context, year, month int) ([]Hol
 	return c.executeHolidayRequest(req)
 }
 
-// GetTodayHoliday retrieves today's holiday if any
+// GetTodayHoliday retrieves today's holiday if any.
+// When several holidays fall on today it returns the first, nationwide ones first; use GetTodayHolidays to get all of them.
 func (c *Client) GetTodayHoliday(ctx context.Context) (*Holiday, error) {
-	url := fmt.Sprintf("%s/holidays/today", c.baseURL)
-	
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	holidays, err := c.executeHolidayRequest(req)
+	holidays, err := c.GetTodayHolidays(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -160,6 +157,18 @@ func (c *Client) GetTodayHoliday(ctx context.Context) (*Holiday, error) {
 	return &holidays[0], nil
 }
 
+// GetTodayHolidays retrieves every holiday on today's date, empty if today is not a holiday
+func (c *Client) GetTodayHolidays(ctx context.Context) ([]Holiday, error) {
+	url := fmt.Sprintf("%s/holidays/today", c.baseURL)
+	
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.executeHolidayRequest(req)
+}
+
 // GetUpcomingHolidays retrieves upcoming holidays
 func (c *Client) GetUpcomingHolidays(ctx context.Context, limit int) ([]Holiday, error) {
 	url := fmt.Sprintf("%s/holidays/upcoming?limit=%d", c.baseURL, limit)
@@ -7,13 +7,12 @@ REM Set environment variables
 set SERVER_HOST=localhost
 set SERVER_PORT=8080
 set DATABASE_PATH=./data/holidays.db
-set MIGRATIONS_PATH=./migrations
+set MIGRATIONS_PATH=./migrations/sqlite
 set RATE_LIMIT_RPM=60
 set RATE_LIMIT_BURST=10
 set JWT_SECRET_KEY=super-secret-jwt-key-for-development-change-in-production
 set JWT_ACCESS_TOKEN_TTL=15m
 set JWT_REFRESH_TOKEN_TTL=168h
-set ADMIN_API_KEY=admin-secret-key
 
 REM Run the application
 echo ==============================================
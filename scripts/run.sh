@@ -7,13 +7,12 @@ mkdir -p data
 export SERVER_HOST=localhost
 export SERVER_PORT=8080
 export DATABASE_PATH=./data/holidays.db
-export MIGRATIONS_PATH=./migrations
+export MIGRATIONS_PATH=./migrations/sqlite
 export RATE_LIMIT_RPM=60
 export RATE_LIMIT_BURST=10
 export JWT_SECRET_KEY=super-secret-jwt-key-for-development-change-in-production
 export JWT_ACCESS_TOKEN_TTL=15m
 export JWT_REFRESH_TOKEN_TTL=168h
-export ADMIN_API_KEY=admin-secret-key
 
 # Run the application
 echo "=============================================="